@@ -0,0 +1,590 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// BATCH MODE (many addresses, one JSON line or CSV row each)
+// ---------------------------------------------------------
+
+const (
+	// Addresses screened at once, unless BATCH_WORKERS says otherwise
+	defaultBatchWorkers = 4
+	// Time allowed per address, queueing for rate limits included
+	defaultBatchTimeout = 60 * time.Second
+)
+
+// batchSummary is logged once the batch is done.
+type batchSummary struct {
+	Total      int            `json:"total"`
+	Valid      int            `json:"valid"`
+	Invalid    int            `json:"invalid"`
+	Sanctioned int            `json:"sanctioned"`
+	Alerts     int            `json:"alerts"` // Profiles with at least one alert
+	Grades     map[string]int `json:"grades"`
+	Duplicates int            `json:"duplicates,omitempty"` // Skipped
+	Resumed    int            `json:"resumed,omitempty"`    // From the checkpoint, not screened again
+	Unfinished int            `json:"unfinished,omitempty"` // Provider errors; a resumed run retries them
+	Workers    int            `json:"workers"`
+	Duration   string         `json:"duration"`
+}
+
+// stdinPiped reports whether stdin is a pipe or file rather than a terminal.
+func stdinPiped() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice == 0
+}
+
+// readAddresses reads one address per line. Blank lines and # comments
+// are skipped, and so is anything after the first comma or whitespace, so
+// a CSV with the address first works as is.
+func readAddresses(r io.Reader) ([]string, error) {
+	var out []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.IndexAny(line, ", \t"); i >= 0 {
+			line = line[:i]
+		}
+		out = append(out, line)
+	}
+	return out, scanner.Err()
+}
+
+// batchAddresses reads the addresses of a batch from path ("" or "-" is
+// stdin).
+func batchAddresses(path string) []string {
+	in := io.Reader(os.Stdin)
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			logging.Fatal("Invalid batch file", "err", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	lines, err := readAddresses(in)
+	if err != nil {
+		logging.Fatal("Invalid batch input", "err", err)
+	}
+	if len(lines) == 0 {
+		logging.Fatal("Batch input has no addresses")
+	}
+	return lines
+}
+
+// runBatch screens the addresses (a batch file, or several arguments) with
+// a pool of workers, writes each profile to out in input order, and logs a
+// summary. Progress goes to stderr so stdout stays parseable. The workers
+// share the per-host rate limits (RATE_LIMITS), HTTP clients and caches,
+// so more of them never means more calls per second to a provider; they
+// only overlap the waiting, spread over the chains (see batchScheduler).
+// It returns the exit status of the worst profile (see exitStatus). With
+// a checkpoint, addresses it already holds are not screened again, and
+// every finished profile is added to it. progress follows the addresses
+// as they finish.
+func runBatch(lines []string, probe, testnet bool, chain validator.ChainStrategy, cache *profileCache, checkpoint *batchCheckpoint, progress *batchProgress, workers int, timeout time.Duration, out profileWriter, failAbove float64) int {
+	summary := batchSummary{Grades: map[string]int{}, Workers: workers}
+	seen := map[string]bool{}
+	var addresses []string
+	for _, address := range lines {
+		key := validator.CanonicalAddress(address)
+		if seen[key] {
+			summary.Duplicates++
+			continue
+		}
+		seen[key] = true
+		addresses = append(addresses, address)
+	}
+
+	start := time.Now()
+	slog.Info("📋 Screening batch", "addresses", len(addresses), "workers", workers)
+	if n := checkpoint.count(addresses); n > 0 {
+		slog.Info("📍 Resuming from checkpoint", "done", n, "remaining", len(addresses)-n)
+	}
+	screen := func(ctx context.Context, address string) *validator.WalletProfile {
+		if p := checkpoint.lookup(address); p != nil {
+			progress.add(address, p, true)
+			return p
+		}
+		p := cache.screen(ctx, address, func(ctx context.Context, address string) *validator.WalletProfile {
+			return screenAddress(ctx, address, probe, testnet, chain, timeout, slog.LevelInfo)
+		})
+		checkpoint.record(address, p)
+		progress.add(address, p, false)
+		return p
+	}
+	defer primeWatchlist(context.Background(), checkpoint.pending(addresses), chain)()
+	progress.begin(len(addresses))
+	code, why := exitClean, ""
+	screenPool(context.Background(), addresses, workers, chain, screen, func(p *validator.WalletProfile) {
+		if err := out.Write(p); err != nil {
+			slog.Error("Error writing output", "err", err)
+		}
+		summary.add(p)
+		if checkpoint != nil && !finished(p) {
+			summary.Unfinished++
+		}
+		if c, w := exitStatus(p, failAbove); exitRank[c] > exitRank[code] {
+			code, why = c, p.Address+": "+w
+		}
+	})
+	progress.end()
+	if err := out.Close(); err != nil {
+		slog.Error("Error writing output", "err", err)
+	}
+	summary.Duration = time.Since(start).Round(time.Millisecond).String()
+	summary.Resumed = checkpoint.resumed()
+	summary.log()
+	if summary.Unfinished > 0 {
+		slog.Warn("📍 Addresses with provider errors; run again with the same --checkpoint to retry them", "count", summary.Unfinished, "checkpoint", checkpoint.path)
+	}
+	if code != exitClean {
+		slog.Warn("🚦 Exit", "code", code, "reason", why)
+	}
+	return code
+}
+
+// primeWatchlist screens a batch's addresses for sanctions in one call
+// instead of one per address (validator.PrimeWatchlist). Names and
+// addresses no chain accepts are left out. On failure the addresses are
+// checked one at a time as usual. Call the returned func once the batch is
+// done.
+func primeWatchlist(ctx context.Context, addresses []string, chain validator.ChainStrategy) func() {
+	var valid []string
+	for _, address := range addresses {
+		if lane := laneName(address, chain); lane != "names" && lane != "invalid" {
+			valid = append(valid, address)
+		}
+	}
+	if len(valid) == 0 {
+		return func() {}
+	}
+	release, err := validator.PrimeWatchlist(ctx, valid)
+	if err != nil {
+		slog.Warn("⚠️ Batch sanctions lookup failed; checking addresses one at a time", "err", err)
+		return func() {}
+	}
+	return release
+}
+
+// screenPool screens the addresses with a pool of workers and calls emit
+// for each profile in input order, as soon as those before it are done.
+// Workers take addresses from a batchScheduler, so a mixed-chain batch
+// interleaves its chains instead of queueing on one provider's limit.
+// Once ctx is done, no more addresses are started.
+func screenPool(ctx context.Context, addresses []string, workers int, chain validator.ChainStrategy, screen func(ctx context.Context, address string) *validator.WalletProfile, emit func(*validator.WalletProfile)) {
+	type result struct {
+		i       int
+		profile *validator.WalletProfile
+	}
+	sched := newBatchScheduler(addresses, chain)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(addresses)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i, ctx, done, ok := sched.next(ctx)
+				if !ok {
+					return
+				}
+				p := screen(ctx, addresses[i])
+				done()
+				results <- result{i, p}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Emit in input order: hold results that finish early
+	pending := map[int]*validator.WalletProfile{}
+	next := 0
+	for r := range results {
+		pending[r.i] = r.profile
+		for pending[next] != nil {
+			emit(pending[next])
+			delete(pending, next)
+			next++
+		}
+	}
+}
+
+// add counts one profile.
+func (s *batchSummary) add(p *validator.WalletProfile) {
+	s.Total++
+	if !p.IsValid {
+		s.Invalid++
+		return
+	}
+	s.Valid++
+	s.Grades[p.RiskGrade]++
+	if len(p.Alerts) > 0 {
+		s.Alerts++
+	}
+	if sanctioned(p) {
+		s.Sanctioned++
+	}
+}
+
+// log writes the summary to stderr as one record, the count per grade
+// grouped under grades. Sanctions hits are also a warning of their own.
+func (s *batchSummary) log() {
+	grades := make([]string, 0, len(s.Grades))
+	for g := range s.Grades {
+		grades = append(grades, g)
+	}
+	sort.Strings(grades)
+	counts := make([]any, 0, len(grades))
+	for _, g := range grades {
+		counts = append(counts, slog.Int(g, s.Grades[g]))
+	}
+	slog.Info("✅ Batch done", "total", s.Total, "valid", s.Valid, "invalid", s.Invalid,
+		"sanctioned", s.Sanctioned, "alerts", s.Alerts, "duplicates", s.Duplicates,
+		"resumed", s.Resumed, "workers", s.Workers, "duration", s.Duration, slog.Group("grades", counts...))
+	if s.Sanctioned > 0 {
+		slog.Warn("🚨 Sanctioned addresses in batch", "count", s.Sanctioned)
+	}
+}
+
+// ---------------------------------------------------------
+// CHECKPOINTS (resuming an interrupted batch)
+// ---------------------------------------------------------
+
+// batchCheckpoint is an append-only JSON Lines file of finished profiles,
+// one line per input address as soon as it is done, so a batch stopped by
+// a crash, Ctrl+C or a provider ban resumes where it stopped. Profiles
+// with provider errors are left out, so the resumed run retries them.
+// Methods on a nil checkpoint do nothing.
+type batchCheckpoint struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]*validator.WalletProfile // By lowercased input address
+	hits int
+}
+
+// checkpointEntry is one line of the file.
+type checkpointEntry struct {
+	Input   string                   `json:"input"`
+	Profile *validator.WalletProfile `json:"profile"`
+}
+
+// openCheckpoint reads the profiles already in path (created if missing)
+// and opens it for appending. A torn last line, from a run killed
+// mid-write, is skipped.
+func openCheckpoint(path string) (*batchCheckpoint, error) {
+	c := &batchCheckpoint{path: path, done: map[string]*validator.WalletProfile{}}
+	if data, err := os.ReadFile(path); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 16<<20)
+		for scanner.Scan() {
+			var e checkpointEntry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Profile == nil {
+				slog.Debug("📍 Skipping unreadable checkpoint line", "path", path, "err", err)
+				continue
+			}
+			c.done[validator.CanonicalAddress(e.Input)] = e.Profile
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	c.f = f
+	return c, nil
+}
+
+// count is how many of addresses the checkpoint already holds.
+func (c *batchCheckpoint) count(addresses []string) int {
+	if c == nil {
+		return 0
+	}
+	n := 0
+	for _, a := range addresses {
+		if c.done[validator.CanonicalAddress(a)] != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// pending is the addresses the checkpoint doesn't hold yet.
+func (c *batchCheckpoint) pending(addresses []string) []string {
+	if c == nil {
+		return addresses
+	}
+	var out []string
+	for _, a := range addresses {
+		if c.done[validator.CanonicalAddress(a)] == nil {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// lookup returns the stored profile of address, nil if it isn't done.
+func (c *batchCheckpoint) lookup(address string) *validator.WalletProfile {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := c.done[validator.CanonicalAddress(address)]
+	if p != nil {
+		c.hits++
+	}
+	return p
+}
+
+// record appends the profile of address, if it finished.
+func (c *batchCheckpoint) record(address string, p *validator.WalletProfile) {
+	if c == nil || !finished(p) {
+		return
+	}
+	line, err := json.Marshal(checkpointEntry{Input: address, Profile: p})
+	if err != nil {
+		slog.Warn("⚠️ Checkpoint write failed", "address", address, "err", err)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.f.Write(append(line, '\n')); err != nil {
+		slog.Warn("⚠️ Checkpoint write failed", "path", c.path, "err", err)
+	}
+}
+
+// resumed is how many profiles came from the checkpoint.
+func (c *batchCheckpoint) resumed() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+func (c *batchCheckpoint) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.f.Close()
+}
+
+// finished reports whether a profile is final: screened without provider
+// errors (an invalid format is final too).
+func finished(p *validator.WalletProfile) bool {
+	return len(validator.ProviderErrors(p)) == 0
+}
+
+// ---------------------------------------------------------
+// PROGRESS (status and ETA of a long batch)
+// ---------------------------------------------------------
+
+// Time between progress logs, unless BATCH_PROGRESS_INTERVAL says otherwise
+const defaultProgressInterval = 10 * time.Second
+
+// batchProgress counts addresses as they finish, in any order, and logs
+// the status to stderr every interval. With a stream (--progress-json),
+// it also writes the status as one JSON line per finished address, and
+// a last one with event "done", for UIs wrapping a batch. Methods on a
+// nil batchProgress do nothing.
+type batchProgress struct {
+	interval time.Duration  // 0 = no logs
+	stream   io.WriteCloser // nil = no JSON
+	broken   bool           // A stream write failed; stop writing
+
+	mu     sync.Mutex
+	status progressStatus
+	fresh  int // Screened now, not resumed or cached; the ETA's basis
+	start  time.Time
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// progressStatus is one line of the --progress-json stream.
+type progressStatus struct {
+	Event          string         `json:"event"`             // "progress" or "done"
+	Address        string         `json:"address,omitempty"` // The one that just finished
+	Done           int            `json:"done"`
+	Total          int            `json:"total"`
+	Percent        float64        `json:"percent"`
+	Chains         map[string]int `json:"chains"` // Done per network
+	Invalid        int            `json:"invalid"`
+	Errors         int            `json:"errors"` // Profiles with provider errors
+	Reused         int            `json:"reused"` // From the checkpoint or profile cache
+	ElapsedSeconds float64        `json:"elapsed_seconds"`
+	ETASeconds     *float64       `json:"eta_seconds"` // Unknown until an address is screened
+}
+
+// begin starts the clock and the periodic logs for total addresses.
+func (b *batchProgress) begin(total int) {
+	if b == nil {
+		return
+	}
+	b.start = time.Now()
+	b.status = progressStatus{Event: "progress", Total: total, Chains: map[string]int{}}
+	b.stop = make(chan struct{})
+	if b.interval <= 0 {
+		return
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stop:
+				return
+			case <-ticker.C:
+				b.mu.Lock()
+				s := b.snapshot()
+				b.mu.Unlock()
+				s.log()
+			}
+		}
+	}()
+}
+
+// add counts the profile of an address that finished; reused means it
+// came from the checkpoint.
+func (b *batchProgress) add(address string, p *validator.WalletProfile, reused bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := &b.status
+	s.Done++
+	s.Chains[p.Network]++
+	if !p.IsValid {
+		s.Invalid++
+	}
+	if !finished(p) {
+		s.Errors++
+	}
+	if reused || p.Cached != nil {
+		s.Reused++
+	} else {
+		b.fresh++
+	}
+	s.Address = address
+	b.write(b.snapshot())
+}
+
+// end stops the logs and writes the "done" line.
+func (b *batchProgress) end() {
+	if b == nil {
+		return
+	}
+	close(b.stop)
+	b.wg.Wait()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.snapshot()
+	s.Event, s.Address = "done", ""
+	b.write(s)
+}
+
+// current is the status now.
+func (b *batchProgress) current() progressStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.snapshot()
+}
+
+// snapshot copies the status with the times filled in. The ETA assumes
+// the remaining addresses take as long as those screened so far.
+func (b *batchProgress) snapshot() progressStatus {
+	s := b.status
+	s.Chains = maps.Clone(b.status.Chains)
+	elapsed := time.Since(b.start)
+	s.ElapsedSeconds = math.Round(elapsed.Seconds()*10) / 10
+	if s.Total > 0 {
+		s.Percent = math.Round(float64(s.Done)/float64(s.Total)*1000) / 10
+	}
+	if b.fresh > 0 {
+		eta := math.Round(elapsed.Seconds() / float64(b.fresh) * float64(s.Total-s.Done))
+		s.ETASeconds = &eta
+	}
+	return s
+}
+
+// write adds a line to the stream, if there is one.
+func (b *batchProgress) write(s progressStatus) {
+	if b.stream == nil || b.broken {
+		return
+	}
+	line, err := json.Marshal(s)
+	if err == nil {
+		_, err = b.stream.Write(append(line, '\n'))
+	}
+	if err != nil {
+		slog.Warn("⚠️ Progress write failed", "err", err)
+		b.broken = true
+	}
+}
+
+// Close closes the stream.
+func (b *batchProgress) Close() error {
+	if b == nil || b.stream == nil {
+		return nil
+	}
+	return b.stream.Close()
+}
+
+// nopWriteCloser is a stream that isn't ours to close (stderr).
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// log writes the status to stderr as one record, the done count per
+// network grouped under chains.
+func (s progressStatus) log() {
+	networks := make([]string, 0, len(s.Chains))
+	for n := range s.Chains {
+		networks = append(networks, n)
+	}
+	sort.Strings(networks)
+	counts := make([]any, 0, len(networks))
+	for _, n := range networks {
+		counts = append(counts, slog.Int(n, s.Chains[n]))
+	}
+	eta := "unknown"
+	if s.ETASeconds != nil {
+		eta = (time.Duration(*s.ETASeconds) * time.Second).String()
+	}
+	slog.Info("⏳ Batch progress", "done", s.Done, "total", s.Total, "percent", s.Percent,
+		"errors", s.Errors, "invalid", s.Invalid, "reused", s.Reused, "eta", eta, slog.Group("chains", counts...))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// PROFILE CACHE (completed profiles, across CLI runs)
+// ---------------------------------------------------------
+
+// defaultProfileCacheTTL is how long a completed profile is reused when
+// PROFILE_CACHE_TTL is unset.
+const defaultProfileCacheTTL = 15 * time.Minute
+
+// profileCache serves the CLI's completed profiles (single address, batch,
+// tui) from a local file, so checking an address again within ttl spends
+// no API quota. Watch, monitor, diff and serve always screen afresh.
+type profileCache struct {
+	store   validator.Cache
+	ttl     time.Duration
+	scope   string // Everything besides the address that decides the profile
+	refresh bool   // --no-cache: always screen, but still store the result
+}
+
+// cachedProfile is one entry of the file.
+type cachedProfile struct {
+	StoredAt time.Time                `json:"stored_at"`
+	Profile  *validator.WalletProfile `json:"profile"`
+}
+
+// newProfileCache keys entries by chain (forced or auto-detected),
+// testnet, probe mode and the active risk rules, so a changed policy or
+// rules file never serves an old score.
+func newProfileCache(store validator.Cache, ttl time.Duration, chain string, testnet, probe, refresh bool) *profileCache {
+	rules, _ := json.Marshal(validator.ActiveRiskRules())
+	sum := sha256.Sum256(rules)
+	if chain == "" {
+		chain = "auto"
+	}
+	return &profileCache{
+		store:   store,
+		ttl:     ttl,
+		scope:   fmt.Sprintf("%s:testnet=%t:probe=%t:rules=%s", chain, testnet, probe, hex.EncodeToString(sum[:6])),
+		refresh: refresh,
+	}
+}
+
+// screen returns the cached profile of address if there is one, else
+// screens it and caches the result. Invalid or partial profiles (a
+// provider failed) are not cached. A nil cache always screens.
+func (c *profileCache) screen(ctx context.Context, address string, screen func(ctx context.Context, address string) *validator.WalletProfile) *validator.WalletProfile {
+	if c == nil {
+		return screen(ctx, address)
+	}
+	key := "profiler:profile:" + c.scope + ":" + address
+	if raw, ok := c.store.Get(key); ok && !c.refresh {
+		var entry cachedProfile
+		if err := json.Unmarshal(raw, &entry); err == nil && entry.Profile != nil {
+			age := time.Since(entry.StoredAt)
+			slog.Debug("💾 [CACHE] Profile hit", "address", address, "age", age.Round(time.Second))
+			entry.Profile.Cached = &validator.CachedProfile{StoredAt: entry.StoredAt, AgeSeconds: int64(age.Seconds())}
+			return entry.Profile
+		}
+	}
+	p := screen(ctx, address)
+	if ctx.Err() != nil || !p.IsValid || len(validator.ProviderErrors(p)) > 0 {
+		return p
+	}
+	if raw, err := json.Marshal(cachedProfile{StoredAt: time.Now().UTC(), Profile: p}); err == nil {
+		c.store.Set(key, raw, c.ttl)
+	}
+	return p
+}
@@ -1,300 +1,130 @@
 package main
 
 import (
-	"database/sql"
-	"encoding/xml"
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/piyushdaiya/crypto-profiler/internal/config"
+	"github.com/piyushdaiya/crypto-profiler/internal/httpapi"
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
 )
 
-var db *sql.DB
-
 func main() {
-	// Setup Logging
-	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
-	log.Println("🔹 [ENGINE] Starting Watchlist Engine...")
-
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./watchlist.db"
-	}
-
-	var err error
-	db, err = sql.Open("sqlite3", dbPath)
+	// Settings: the defaults, then crypto-profiler.yaml (or --config /
+	// CONFIG_FILE), then the environment; its engine section is for this binary
+	defaults := watchlist.DefaultSyncConfig()
+	cfg := &config.Config{}
+	cfg.Engine.DBPath = "./watchlist.db"
+	cfg.Engine.Port = "8080"
+	cfg.Engine.SyncInterval = 12 * time.Hour
+	cfg.Engine.OFACURL = defaults.SourceURL
+	cfg.Engine.HTTPTimeout = defaults.HTTPTimeout
+	configPath, configRequired := config.Path(os.Args[1:])
+	configLoaded, err := config.Load(cfg, configPath, configRequired)
 	if err != nil {
-		log.Fatal("❌ [ENGINE] DB Error:", err)
+		logging.Fatal("❌ [ENGINE] Config Error", "err", err)
 	}
-	defer db.Close()
-
-	if err := db.Ping(); err != nil {
-		log.Fatal("❌ [ENGINE] DB Ping Failed:", err)
-	}
-
-	initDB()
-
-	go func() {
-		log.Println("🔹 [ENGINE] Initializing Sync Loop...")
-		startSyncLoop()
-	}()
-
-	http.HandleFunc("/check", loggingMiddleware(checkAddressHandler))
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	log.Printf("✅ [ENGINE] Database Available & Listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
-}
-
-func loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		next(w, r)
-		log.Printf("📡 [REQ] %s %s took %v", r.Method, r.URL.Path, time.Since(start))
-	}
-}
-
-// --- DATABASE INIT ---
-func initDB() {
-	query := `
-	CREATE TABLE IF NOT EXISTS sanctioned_addresses (
-		address TEXT PRIMARY KEY,
-		currency TEXT,
-		source TEXT,
-		updated_at DATETIME
-	);
-	CREATE INDEX IF NOT EXISTS idx_address ON sanctioned_addresses(address);
-	CREATE TABLE IF NOT EXISTS metadata (key TEXT PRIMARY KEY, value TEXT);
-	`
-	if _, err := db.Exec(query); err != nil {
-		log.Fatal("❌ [ENGINE] Failed to create tables:", err)
-	}
-}
-
-func checkAddressHandler(w http.ResponseWriter, r *http.Request) {
-	address := r.URL.Query().Get("address")
-	if address == "" {
-		http.Error(w, "Missing address parameter", http.StatusBadRequest)
-		return
-	}
-
-	var currency, source string
-	err := db.QueryRow("SELECT currency, source FROM sanctioned_addresses WHERE address = ?", address).Scan(&currency, &source)
-
-	response := map[string]interface{}{
-		"sanctioned": false,
-		"address":    address,
-	}
+	// Flags default to the settings so Docker Compose and the CLI behave the same
+	flag.String("config", "", "Settings file (env CONFIG_FILE, default ./crypto-profiler.yaml if present); env vars override it")
+	syncInterval := flag.Duration("sync-interval", cfg.Engine.SyncInterval, "How often to check OFAC for updates (env SYNC_INTERVAL)")
+	sourceURL := flag.String("ofac-url", cfg.Engine.OFACURL, "OFAC SDN advanced XML URL or internal mirror (env OFAC_URL)")
+	httpTimeout := flag.Duration("http-timeout", cfg.Engine.HTTPTimeout, "Timeout for the OFAC download (env SYNC_HTTP_TIMEOUT)")
+	proxyURL := flag.String("proxy", cfg.Engine.Proxy, "Outbound proxy for OFAC downloads (env SYNC_PROXY_URL)")
+	flag.Parse()
 
+	// Setup Logging (stderr; LOG_LEVEL and LOG_FORMAT)
+	logCfg, err := logging.ParseConfig(cfg.Logging.Level, cfg.Logging.Format)
 	if err == nil {
-		response["sanctioned"] = true
-		response["currency"] = currency
-		response["source"] = source
+		err = logging.Setup(logCfg, os.Stderr)
 	}
-
-	// Simple manual JSON response
-	jsonStr := fmt.Sprintf(`{"sanctioned": %v`, response["sanctioned"])
-	if response["sanctioned"] == true {
-		jsonStr += fmt.Sprintf(`, "currency": "%s", "source": "%s"`, currency, source)
+	if err != nil {
+		logging.Fatal("❌ [ENGINE] Logging Config Error", "err", err)
 	}
-	jsonStr += `}`
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(jsonStr))
-}
-
-// --- SYNC ENGINE ---
-
-func startSyncLoop() {
-	for {
-		if shouldUpdate() {
-			log.Println("⬇️  [SYNC] Update Detected. Starting OFAC Download...")
-			if err := downloadAndParseOFAC(); err != nil {
-				log.Printf("❌ [SYNC] Download Failed: %v", err)
-			} else {
-				log.Println("✅ [SYNC] Database Update Complete.")
-			}
-		} else {
-			log.Println("✅ [SYNC] Database is up to date.")
-		}
-		time.Sleep(12 * time.Hour)
+	slog.Info("🔹 [ENGINE] Starting Watchlist Engine...")
+	if configLoaded {
+		slog.Info("🔹 [ENGINE] Config loaded", "file", configPath)
 	}
-}
 
-func shouldUpdate() bool {
-	url := "https://www.treasury.gov/ofac/downloads/sanctions/1.0/sdn_advanced.xml"
+	if *syncInterval <= 0 {
+		logging.Fatal("❌ [ENGINE] Sync interval must be positive")
+	}
 
-	var localLastMod string
-	_ = db.QueryRow("SELECT value FROM metadata WHERE key='last_modified'").Scan(&localLastMod)
+	dbPath := cfg.Engine.DBPath
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Head(url)
+	store, err := watchlist.Open(dbPath)
 	if err != nil {
-		log.Printf("⚠️ [SYNC] Could not check remote headers: %v", err)
-		return true // Fail open
+		logging.Fatal("❌ [ENGINE] DB Error", "err", err)
 	}
-	defer resp.Body.Close()
-
-	remoteLastMod := resp.Header.Get("Last-Modified")
-	return localLastMod != remoteLastMod
-}
-
-// --- XML STRUCTURES ---
-
-// Flattened Reference Value
-type FeatureTypeValue struct {
-	ID    string `xml:"ID,attr"`
-	Value string `xml:",chardata"`
-}
-
-// Distinct Party (The Sanctioned Person)
-type DistinctParty struct {
-	Profile []Profile `xml:"Profile"`
-}
-type Profile struct {
-	Feature []Feature `xml:"Feature"`
-}
-type Feature struct {
-	FeatureTypeID string           `xml:"FeatureTypeID,attr"` 
-	Version       []FeatureVersion `xml:"FeatureVersion"`
-}
-type FeatureVersion struct {
-	VersionDetail []VersionDetail `xml:"VersionDetail"`
-}
-type VersionDetail struct {
-	Value string `xml:",chardata"` 
-}
 
-func downloadAndParseOFAC() error {
-	url := "https://www.treasury.gov/ofac/downloads/sanctions/1.0/sdn_advanced.xml"
-
-	resp, err := http.Get(url)
-	if err != nil { return err }
-	defer resp.Body.Close()
-
-	lastMod := resp.Header.Get("Last-Modified")
-	log.Printf("🔹 [SYNC] Header Last-Modified: %s", lastMod)
-
-	decoder := xml.NewDecoder(resp.Body)
-	
-	// PRE-FILL MAP with known IDs provided by user
-	cryptoTypeMap := map[string]string{
-		"344":  "XBT",
-		"345":  "ETH",
-		"686":  "ZEC",
-		"687":  "DASH",
-		"688":  "BTG",
-		"689":  "ETC",
-		"706":  "BSV",
-		"726":  "BCH",
-		"746":  "XVG",
-		"992":  "TRX",
-		"998":  "USDC",
-		"1007": "ARB",
-		"1008": "BSC",
-		"1167": "SOL",
-		// Additional IDs often found in OFAC data
-		"573":  "XMR",
-		"572":  "LTC",
-	}
-	
-	tx, err := db.Begin()
-	if err != nil { return err }
-	
-	stmt, err := tx.Prepare("INSERT OR REPLACE INTO sanctioned_addresses(address, currency, source, updated_at) VALUES(?, ?, 'OFAC', ?)")
+	err = store.ConfigureSync(watchlist.SyncConfig{
+		SourceURL:   *sourceURL,
+		HTTPTimeout: *httpTimeout,
+		ProxyURL:    *proxyURL,
+	})
 	if err != nil {
-		tx.Rollback()
-		return err
+		logging.Fatal("❌ [ENGINE] Sync Config Error", "err", err)
 	}
-	defer stmt.Close()
+	slog.Info("🔹 [ENGINE] Sync configured", "source", *sourceURL, "interval", *syncInterval)
 
-	now := time.Now()
-	count := 0
-	loaded := 0
+	// Cancelled on SIGINT/SIGTERM (docker stop, Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("🔹 [SYNC] Parsing XML Stream...")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		slog.Info("🔹 [ENGINE] Initializing Sync Loop...")
+		store.SyncLoop(ctx, *syncInterval)
+	}()
 
-	for {
-		t, _ := decoder.Token()
-		if t == nil { break }
+	// Lookups, health and metrics, behind the middleware serve mode shares
+	mux := http.NewServeMux()
+	watchlist.Register(mux, store)
+	metrics := httpapi.NewMetrics("engine")
+	mux.Handle("GET /metrics", metrics)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
 
-		switch se := t.(type) {
-		case xml.StartElement:
-			
-			// STEP 1: Catch "FeatureTypeValue" (Dynamic Learning)
-			// We still listen for these to catch any NEW currencies OFAC might add in the future
-			if se.Name.Local == "FeatureTypeValue" {
-				var ft FeatureTypeValue
-				if err := decoder.DecodeElement(&ft, &se); err != nil {
-					continue
-				}
+	port := cfg.Engine.Port
 
-				if strings.Contains(ft.Value, "Digital Currency Address") {
-					parts := strings.Split(ft.Value, "-")
-					currency := "UNKNOWN"
-					if len(parts) > 1 {
-						currency = strings.TrimSpace(parts[1])
-					}
-					// Only add if we don't already have it hardcoded
-					if _, exists := cryptoTypeMap[ft.ID]; !exists {
-						cryptoTypeMap[ft.ID] = currency
-						log.Printf("🔹 [SYNC] Learned new currency: ID %s = %s", ft.ID, currency)
-					}
-				}
-			}
+	srv := &http.Server{Addr: ":" + port, Handler: httpapi.Handler(mux, nil, metrics)}
+	go func() {
+		slog.Info("✅ [ENGINE] Database Available & Listening", "port", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("❌ [ENGINE] HTTP Server Error", "err", err)
+			stop()
+		}
+	}()
 
-			// STEP 2: Scan Parties
-			if se.Name.Local == "DistinctParty" {
-				var p DistinctParty
-				if err := decoder.DecodeElement(&p, &se); err != nil { continue }
+	<-ctx.Done()
+	slog.Info("🔹 [ENGINE] Shutdown signal received. Draining...")
 
-				for _, profile := range p.Profile {
-					for _, feature := range profile.Feature {
-						// Is this FeatureID in our crypto map?
-						if currency, isCrypto := cryptoTypeMap[feature.FeatureTypeID]; isCrypto {
-							for _, v := range feature.Version {
-								for _, d := range v.VersionDetail {
-									addr := strings.TrimSpace(d.Value)
-									if len(addr) > 10 {
-										_, err = stmt.Exec(addr, currency, now)
-										if err == nil {
-											loaded++
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-				count++
-				if count%10000 == 0 {
-					log.Printf("🔹 [SYNC] Scanned %d Parties...", count)
-				}
-			}
-		}
+	// 1. Stop accepting requests, let in-flight checks finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("⚠️ [ENGINE] HTTP Shutdown", "err", err)
 	}
 
-	_, _ = tx.Exec("INSERT OR REPLACE INTO metadata(key, value) VALUES('last_modified', ?)", lastMod)
-	
-	if err := tx.Commit(); err != nil { return err }
+	// 2. Wait for the sync loop to roll back any open transaction
+	wg.Wait()
 
-	log.Printf("✅ [SYNC] Done. Scanned %d parties. Loaded %d sanctioned addresses.", count, loaded)
-	
-	if loaded == 0 {
-		log.Println("⚠️ [SYNC] WARNING: 0 addresses loaded. Double check FeatureType IDs.")
+	// 3. Close the DB last
+	if err := store.Close(); err != nil {
+		slog.Warn("⚠️ [ENGINE] DB Close", "err", err)
 	}
-	
-	return nil
-}
\ No newline at end of file
+	slog.Info("✅ [ENGINE] Stopped.")
+}
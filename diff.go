@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// DIFF (the current profile against the last stored run)
+// ---------------------------------------------------------
+
+// profileDiff is what changed since the last stored run of an address.
+type profileDiff struct {
+	Address           string                   `json:"address"`
+	Network           string                   `json:"network"`
+	PreviousAt        time.Time                `json:"previous_at"`
+	At                time.Time                `json:"at"`
+	Changes           []string                 `json:"changes"` // Readable, one per change
+	PreviousScore     float64                  `json:"previous_score"`
+	Score             float64                  `json:"score"`
+	ScoreDelta        float64                  `json:"score_delta"`
+	PreviousGrade     string                   `json:"previous_grade"`
+	Grade             string                   `json:"grade"`
+	PreviousBalance   string                   `json:"previous_balance,omitempty"`
+	Balance           string                   `json:"balance,omitempty"`
+	BalanceDelta      string                   `json:"balance_delta,omitempty"` // Same asset only, e.g. "-1.19 ETH"
+	BalanceUSDDelta   *float64                 `json:"balance_usd_delta,omitempty"`
+	NewTxs            int                      `json:"new_txs,omitempty"`
+	NewCounterparties []validator.Counterparty `json:"new_counterparties,omitempty"`
+	NewReasons        []validator.RiskReason   `json:"new_reasons,omitempty"`
+	ResolvedReasons   []validator.RiskReason   `json:"resolved_reasons,omitempty"`
+	Note              string                   `json:"note,omitempty"`
+}
+
+// diffRecord compares a fresh profile with the previous stored run.
+// Reasons are matched by rule, so a reason whose description only changed
+// its numbers is neither new nor resolved.
+func diffRecord(prev *validator.ScoreRecord, p *validator.WalletProfile, now time.Time) profileDiff {
+	d := profileDiff{
+		Address:       p.Address,
+		Network:       p.Network,
+		PreviousAt:    prev.Timestamp,
+		At:            now,
+		PreviousScore: prev.RiskScore,
+		Score:         p.RiskScore,
+		ScoreDelta:    p.RiskScore - prev.RiskScore,
+		PreviousGrade: prev.RiskGrade,
+		Grade:         p.RiskGrade,
+		Changes:       []string{},
+	}
+	if fmt.Sprintf("%.1f", prev.RiskScore) != fmt.Sprintf("%.1f", p.RiskScore) {
+		d.Changes = append(d.Changes, fmt.Sprintf("Score %.1f → %.1f (%+.1f)", prev.RiskScore, p.RiskScore, d.ScoreDelta))
+	}
+	if prev.RiskGrade != p.RiskGrade {
+		d.Changes = append(d.Changes, fmt.Sprintf("Grade %s → %s", prev.RiskGrade, p.RiskGrade))
+	}
+
+	// Records from before diff existed hold the score and reasons only
+	if prev.Counterparties == nil {
+		d.Note = "The previous run predates balance and counterparty history; only the score and reasons are compared"
+	} else {
+		d.PreviousBalance, d.Balance = prev.Balance, p.Balance
+		if prev.Balance != p.Balance && p.Balance != "" { // "" = lookup failed
+			change := fmt.Sprintf("Balance %s → %s", prev.Balance, p.Balance)
+			if delta := balanceDelta(prev, p); delta != "" {
+				d.BalanceDelta = delta
+				change += " (" + delta + ")"
+			}
+			d.Changes = append(d.Changes, change)
+		}
+		if prev.BalanceUSD != nil && p.BalanceUSD != nil {
+			delta := *p.BalanceUSD - *prev.BalanceUSD
+			d.BalanceUSDDelta = &delta
+		}
+		if n := p.TxCount - prev.TxCount; n > 0 {
+			d.NewTxs = n
+			d.Changes = append(d.Changes, fmt.Sprintf("%d New Txs (%d Total)", n, p.TxCount))
+		}
+		known := map[string]bool{}
+		for _, a := range prev.Counterparties {
+			known[strings.ToLower(a)] = true
+		}
+		for _, c := range validator.Counterparties(p) {
+			if !known[strings.ToLower(c.Address)] {
+				d.NewCounterparties = append(d.NewCounterparties, c)
+				d.Changes = append(d.Changes, fmt.Sprintf("New Counterparty %s: %s", c.Address, c.Why))
+			}
+		}
+	}
+
+	before, after := map[string]bool{}, map[string]bool{}
+	for _, r := range prev.RiskReasons {
+		before[r.RuleID] = true
+	}
+	for _, r := range p.RiskReasons {
+		after[r.RuleID] = true
+	}
+	for _, r := range sortedReasons(p) {
+		if !before[r.RuleID] {
+			d.NewReasons = append(d.NewReasons, r)
+			d.Changes = append(d.Changes, fmt.Sprintf("New Reason %+g %s: %s", r.Offset, r.RuleID, r.Description))
+		}
+	}
+	for _, r := range prev.RiskReasons {
+		if !after[r.RuleID] {
+			d.ResolvedReasons = append(d.ResolvedReasons, r)
+			d.Changes = append(d.Changes, fmt.Sprintf("Resolved Reason %+g %s: %s", r.Offset, r.RuleID, r.Description))
+		}
+	}
+	return d
+}
+
+// balanceDelta is the signed change in the native balance, or "" when the
+// two runs aren't in the same asset and units.
+func balanceDelta(prev *validator.ScoreRecord, p *validator.WalletProfile) string {
+	if prev.BalanceRaw == nil || p.BalanceRaw == nil || prev.Symbol != p.Symbol || prev.Decimals != p.Decimals {
+		return ""
+	}
+	delta := new(big.Int).Sub(p.BalanceRaw, prev.BalanceRaw)
+	sign := "+"
+	if delta.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + validator.FormatUnits(delta.Abs(delta), p.Decimals, p.Symbol)
+}
+
+// writeDiff prints d readably (human) or as indented JSON.
+func writeDiff(w io.Writer, d profileDiff, human bool) error {
+	if !human {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Address\t%s (%s)\n", d.Address, d.Network)
+	fmt.Fprintf(tw, "Since\t%s (%s ago)\n", d.PreviousAt.UTC().Format("2006-01-02 15:04 MST"), d.At.Sub(d.PreviousAt).Round(time.Minute))
+	if len(d.Changes) == 0 {
+		fmt.Fprintln(tw, "Changes\tNone")
+	}
+	for i, c := range d.Changes {
+		label := ""
+		if i == 0 {
+			label = "Changes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", label, c)
+	}
+	if d.Note != "" {
+		fmt.Fprintf(tw, "Note\t%s\n", d.Note)
+	}
+	return tw.Flush()
+}
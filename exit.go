@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// EXIT CODES (--fail-above: gate CI and payment flows on the result)
+// ---------------------------------------------------------
+
+const (
+	exitClean    = 0
+	exitReview   = 2 // Score above --fail-above, or the address could not be screened
+	exitCritical = 3 // Sanctions hit or CRITICAL alert (also ends watch mode)
+	exitProvider = 4 // A provider or the watchlist failed; the result is incomplete
+)
+
+// How bad each exit status is, for batches: a critical hit outranks a
+// failed lookup, which outranks a review (an incomplete clean result
+// can't be trusted).
+var exitRank = map[int]int{exitClean: 0, exitReview: 1, exitProvider: 2, exitCritical: 3}
+
+// exitStatus gates on one profile and says why. A negative failAbove
+// turns gating off: every profile exits 0.
+func exitStatus(p *validator.WalletProfile, failAbove float64) (int, string) {
+	if failAbove < 0 {
+		return exitClean, ""
+	}
+	if sanctioned(p) {
+		return exitCritical, "sanctioned"
+	}
+	if critical := criticalAlerts(p.Alerts); len(critical) > 0 {
+		return exitCritical, "CRITICAL alert " + critical[0].Trigger
+	}
+	if errs := validator.ProviderErrors(p); len(errs) > 0 {
+		return exitProvider, strings.Join(errs, "; ")
+	}
+	if !p.IsValid {
+		return exitReview, "not screened: " + p.ValidationDetails
+	}
+	if p.RiskScore > failAbove {
+		return exitReview, fmt.Sprintf("risk score %.1f above %g (%s)", p.RiskScore, failAbove, p.RiskGrade)
+	}
+	return exitClean, ""
+}
@@ -1,7 +1,33 @@
 module github.com/piyushdaiya/crypto-profiler
 
-go 1.23
+go 1.23.0
 
 require github.com/joho/godotenv v1.5.1
 
-require github.com/mattn/go-sqlite3 v1.14.33 // indirect
+require (
+	github.com/alicebob/miniredis/v2 v2.35.0
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/graph-gophers/graphql-go v1.7.0
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/nats-io/nats.go v1.48.0
+	github.com/redis/go-redis/v9 v9.14.0
+	github.com/segmentio/kafka-go v0.4.51
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.12
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
+)
+
+// ---------------------------------------------------------
+// GRAPHQL (profiles and watchlist data, with selectable fields)
+// ---------------------------------------------------------
+
+// The schema is the JSON contract: every JSON field of a type is a
+// GraphQL field of it, in camelCase (risk_score is riskScore), generated
+// from the Go types when the server starts. The Query type has:
+//
+//	profile(address: String!, chain: String, probe: Boolean): WalletProfile
+//	watchlistHit(address: String!): EngineResponse
+//	entity(name: String!, threshold: Float): NameScreening
+//
+// graphql-go parses, validates and executes queries against it.
+
+// Limits on a query, checked before anything is resolved
+const (
+	graphqlMaxDepth  = 15        // Nested selections; introspection needs about 13
+	graphqlMaxLength = 32 * 1024 // Bytes of query text
+)
+
+// graphqlRequest is the body of POST /v1/graphql.
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphqlError is one entry of a response's errors.
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+// POST /v1/graphql {"query": "{ profile(address: \"0x...\") { riskScore riskGrade } }"}
+// GET /v1/graphql?query=...
+// Resolves the query's root fields concurrently. A failed field is null
+// with its error listed, as GraphQL has it; a query that doesn't parse or
+// validate is a 400.
+func (s *profileServer) graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var req graphqlRequest
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if v := r.URL.Query().Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeGraphQLError(w, http.StatusBadRequest, "Invalid variables: "+err.Error())
+				return
+			}
+		}
+	case http.MethodPost:
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeGraphQLError(w, http.StatusBadRequest, "Missing query")
+		return
+	}
+
+	schema, err := s.graphqlSchema()
+	if err != nil {
+		slog.Error("🔴 [SERVE] GraphQL schema", "err", err)
+		writeGraphQLError(w, http.StatusInternalServerError, "GraphQL unavailable")
+		return
+	}
+	ctx := context.WithValue(r.Context(), graphqlLookupsKey{}, new(atomic.Int32))
+	resp := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if resp.Data == nil && len(resp.Errors) > 0 { // The query never ran
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			slog.Warn("⚠️ [SERVE] Write failed", "err", err)
+		}
+		return
+	}
+	writeJSON(w, resp)
+}
+
+func writeGraphQLError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string][]graphqlError{"errors": {{Message: message}}}); err != nil {
+		slog.Warn("⚠️ [SERVE] Write failed", "err", err)
+	}
+}
+
+// graphqlSchema builds the server's schema on first use.
+func (s *profileServer) graphqlSchema() (*graphql.Schema, error) {
+	s.graphqlOnce.Do(func() {
+		s.graphql, s.graphqlErr = s.buildGraphQLSchema()
+	})
+	return s.graphql, s.graphqlErr
+}
+
+// ---------------------------------------------------------
+// QUERY (the root fields)
+// ---------------------------------------------------------
+
+type profileArgs struct {
+	Address string
+	Chain   *string
+	Probe   *bool
+}
+
+type addressArgs struct {
+	Address string
+}
+
+type entityArgs struct {
+	Name      string
+	Threshold *float64
+}
+
+// graphqlLookupsKey holds a request's count of root lookups.
+type graphqlLookupsKey struct{}
+
+// lookup counts one root field against SERVE_MAX_BATCH: every root field
+// is resolved concurrently, so all of them count.
+func (s *profileServer) lookup(ctx context.Context) error {
+	n, _ := ctx.Value(graphqlLookupsKey{}).(*atomic.Int32)
+	if n != nil && int(n.Add(1)) > s.maxBatch {
+		return fmt.Errorf("Too many root fields (max %d)", s.maxBatch)
+	}
+	return nil
+}
+
+func (s *profileServer) buildGraphQLSchema() (*graphql.Schema, error) {
+	b := newGQLBuilder()
+	root := []gqlRootField{
+		{
+			name: "profile", args: "address: String!, chain: String, probe: Boolean",
+			argsType: reflect.TypeFor[profileArgs](), result: reflect.TypeFor[*validator.WalletProfile](),
+			resolve: func(ctx context.Context, a any) (any, error) {
+				args := a.(profileArgs)
+				address := strings.TrimSpace(args.Address)
+				if address == "" {
+					return nil, fmt.Errorf("Argument address of profile must not be empty")
+				}
+				var name string
+				if args.Chain != nil {
+					name = *args.Chain
+				}
+				chain, err := s.requestChain(name)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid chain: %v", err)
+				}
+				if err := s.lookup(ctx); err != nil {
+					return nil, err
+				}
+				return s.screen(ctx, address, args.Probe != nil && *args.Probe, chain, ""), nil
+			},
+		},
+		{
+			name: "watchlistHit", args: "address: String!",
+			argsType: reflect.TypeFor[addressArgs](), result: reflect.TypeFor[*validator.EngineResponse](),
+			resolve: func(ctx context.Context, a any) (any, error) {
+				address := strings.TrimSpace(a.(addressArgs).Address)
+				if address == "" {
+					return nil, fmt.Errorf("Argument address of watchlistHit must not be empty")
+				}
+				if err := s.lookup(ctx); err != nil {
+					return nil, err
+				}
+				return validator.EngineWatchlist{}.Check(ctx, address)
+			},
+		},
+		{
+			name: "entity", args: "name: String!, threshold: Float",
+			argsType: reflect.TypeFor[entityArgs](), result: reflect.TypeFor[*watchlist.NameScreening](),
+			resolve: func(ctx context.Context, a any) (any, error) {
+				args := a.(entityArgs)
+				name := strings.TrimSpace(args.Name)
+				if name == "" {
+					return nil, fmt.Errorf("Argument name of entity must not be empty")
+				}
+				threshold := 0.0
+				if args.Threshold != nil {
+					threshold = *args.Threshold
+				}
+				if threshold < 0 || threshold > 1 {
+					return nil, fmt.Errorf("Invalid threshold (0-1]")
+				}
+				if err := s.lookup(ctx); err != nil {
+					return nil, err
+				}
+				return validator.CheckName(ctx, name, threshold)
+			},
+		},
+	}
+	sdl, resolver, err := b.schema(root)
+	if err != nil {
+		return nil, err
+	}
+	return graphql.ParseSchema(sdl, resolver,
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxQueryLength(graphqlMaxLength),
+		graphql.MaxParallelism(max(s.maxBatch, 1)),
+	)
+}
+
+// ---------------------------------------------------------
+// SCHEMA (generated from the Go types)
+// ---------------------------------------------------------
+
+// graphql-go resolves a field from the resolver struct's field tagged
+// with its name, so each Go type gets a generated struct of the values in
+// GraphQL's types (int32 for Int, float64 for Float, pointers where null
+// is possible) and a conversion into it.
+
+// gqlType is how one Go type appears in the schema.
+type gqlType struct {
+	name string                            // In the SDL, e.g. "Float!" or "[RiskReason!]"
+	gen  reflect.Type                      // What graphql-go resolves it from
+	conv func(reflect.Value) reflect.Value // From the Go value to gen
+}
+
+// gqlJSON is a value without a GraphQL type of its own (times, big
+// numbers, maps), returned whole as the JSON scalar.
+type gqlJSON struct{ v any }
+
+func (j gqlJSON) MarshalJSON() ([]byte, error) { return json.Marshal(j.v) }
+
+func (gqlJSON) ImplementsGraphQLType(name string) bool { return name == "JSON" }
+
+func (j *gqlJSON) UnmarshalGraphQL(input any) error {
+	j.v = input
+	return nil
+}
+
+// gqlRootField is one field of the Query type.
+type gqlRootField struct {
+	name, args string
+	argsType   reflect.Type
+	result     reflect.Type
+	resolve    func(ctx context.Context, args any) (any, error)
+}
+
+var (
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+	contextType   = reflect.TypeFor[context.Context]()
+	errorType     = reflect.TypeFor[error]()
+	gqlName       = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+)
+
+type gqlBuilder struct {
+	sdl     strings.Builder
+	objects map[reflect.Type]*gqlType // nil while the type's fields are being built
+	names   map[string]bool
+}
+
+func newGQLBuilder() *gqlBuilder {
+	b := &gqlBuilder{objects: map[reflect.Type]*gqlType{}, names: map[string]bool{}}
+	for _, name := range []string{"Query", "JSON", "String", "Int", "Float", "Boolean", "ID"} {
+		b.names[name] = true
+	}
+	b.sdl.WriteString("scalar JSON\n\n")
+	return b
+}
+
+var gqlJSONType = &gqlType{
+	name: "JSON!",
+	gen:  reflect.TypeFor[gqlJSON](),
+	conv: func(v reflect.Value) reflect.Value { return reflect.ValueOf(gqlJSON{v.Interface()}) },
+}
+
+// scalar is a built-in scalar type converted with conv.
+func scalar[T any](name string, conv func(reflect.Value) T) *gqlType {
+	return &gqlType{name: name, gen: reflect.TypeFor[T](), conv: func(v reflect.Value) reflect.Value {
+		return reflect.ValueOf(conv(v))
+	}}
+}
+
+// schema returns the SDL and the Query resolver for the root fields.
+func (b *gqlBuilder) schema(root []gqlRootField) (string, any, error) {
+	var query strings.Builder
+	query.WriteString("type Query {\n")
+	var fields []reflect.StructField
+	var funcs []reflect.Value
+	for i, f := range root {
+		t := b.typeOf(f.result)
+		if t == nil {
+			return "", nil, fmt.Errorf("%s: %s has no GraphQL type", f.name, f.result)
+		}
+		fmt.Fprintf(&query, "  %s(%s): %s\n", f.name, f.args, t.name)
+		fnType := reflect.FuncOf([]reflect.Type{contextType, f.argsType}, []reflect.Type{t.gen, errorType}, false)
+		fields = append(fields, reflect.StructField{Name: "F" + strconv.Itoa(i), Type: fnType, Tag: reflect.StructTag(`graphql:"` + f.name + `"`)})
+		funcs = append(funcs, reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+			v, err := f.resolve(in[0].Interface().(context.Context), in[1].Interface())
+			if err != nil {
+				return []reflect.Value{reflect.Zero(t.gen), reflect.ValueOf(&err).Elem()}
+			}
+			if v == nil {
+				return []reflect.Value{reflect.Zero(t.gen), reflect.Zero(errorType)}
+			}
+			return []reflect.Value{t.conv(reflect.ValueOf(v)), reflect.Zero(errorType)}
+		}))
+	}
+	query.WriteString("}\n")
+
+	resolver := reflect.New(reflect.StructOf(fields))
+	for i, fn := range funcs {
+		resolver.Elem().Field(i).Set(fn)
+	}
+	return b.sdl.String() + query.String(), resolver.Interface(), nil
+}
+
+// typeOf maps t onto the schema, or returns nil for types JSON can't
+// encode either (channels, funcs).
+func (b *gqlBuilder) typeOf(t reflect.Type) *gqlType {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(b.typeOf(t.Elem()))
+	case reflect.Interface, reflect.Map:
+		return nullable(gqlJSONType)
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return gqlJSONType
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return scalar("String!", reflect.Value.String)
+	case reflect.Bool:
+		return scalar("Boolean!", reflect.Value.Bool)
+	case reflect.Float32, reflect.Float64:
+		return scalar("Float!", reflect.Value.Float)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return scalar("Int!", func(v reflect.Value) int32 { return int32(v.Int()) })
+	case reflect.Uint8, reflect.Uint16:
+		return scalar("Int!", func(v reflect.Value) int32 { return int32(v.Uint()) })
+	case reflect.Int64: // GraphQL's Int is 32 bits
+		return scalar("Float!", func(v reflect.Value) float64 { return float64(v.Int()) })
+	case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return scalar("Float!", func(v reflect.Value) float64 { return float64(v.Uint()) })
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 { // Base64, as JSON has it
+			return nullable(gqlJSONType)
+		}
+		return nullable(b.listOf(t.Elem()))
+	case reflect.Array:
+		return b.listOf(t.Elem())
+	case reflect.Struct:
+		return b.object(t)
+	}
+	return nil
+}
+
+// nullable makes t nullable: a nil Go value is null.
+func nullable(t *gqlType) *gqlType {
+	if t == nil || !strings.HasSuffix(t.name, "!") {
+		return t
+	}
+	wrap := t.gen.Kind() != reflect.Pointer
+	gen := t.gen
+	if wrap {
+		gen = reflect.PointerTo(gen)
+	}
+	return &gqlType{name: strings.TrimSuffix(t.name, "!"), gen: gen, conv: func(v reflect.Value) reflect.Value {
+		if v.IsNil() {
+			return reflect.Zero(gen)
+		}
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		out := t.conv(v)
+		if wrap {
+			p := reflect.New(t.gen)
+			p.Elem().Set(out)
+			out = p
+		}
+		return out
+	}}
+}
+
+// listOf is a list of elem.
+func (b *gqlBuilder) listOf(elem reflect.Type) *gqlType {
+	e := b.typeOf(elem)
+	if e == nil {
+		return nil
+	}
+	gen := reflect.SliceOf(e.gen)
+	return &gqlType{name: "[" + e.name + "]!", gen: gen, conv: func(v reflect.Value) reflect.Value {
+		out := reflect.MakeSlice(gen, v.Len(), v.Len())
+		for i := range v.Len() {
+			out.Index(i).Set(e.conv(v.Index(i)))
+		}
+		return out
+	}}
+}
+
+// gqlField is one field of an object type: where it is in the Go struct
+// (embedded structs are flattened, as encoding/json does) and its type.
+type gqlField struct {
+	name  string
+	index []int
+	typ   *gqlType
+}
+
+// object is the object type of struct t, written to the SDL the first
+// time it's seen. A type that contains itself is returned whole as JSON
+// where it recurs.
+func (b *gqlBuilder) object(t reflect.Type) *gqlType {
+	if o, seen := b.objects[t]; seen {
+		if o == nil {
+			return gqlJSONType
+		}
+		return o
+	}
+	b.objects[t] = nil
+	fields := b.fields(t, nil, map[string]bool{})
+	if len(fields) == 0 { // GraphQL objects need a field
+		delete(b.objects, t)
+		return gqlJSONType
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = "Object"
+	}
+	for i := 2; b.names[name]; i++ {
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+	b.names[name] = true
+
+	structFields := make([]reflect.StructField, len(fields))
+	fmt.Fprintf(&b.sdl, "type %s {\n", name)
+	for i, f := range fields {
+		structFields[i] = reflect.StructField{Name: "F" + strconv.Itoa(i), Type: f.typ.gen, Tag: reflect.StructTag(`graphql:"` + f.name + `"`)}
+		fmt.Fprintf(&b.sdl, "  %s: %s\n", f.name, f.typ.name)
+	}
+	b.sdl.WriteString("}\n\n")
+
+	gen := reflect.StructOf(structFields)
+	o := &gqlType{name: name + "!", gen: reflect.PointerTo(gen), conv: func(v reflect.Value) reflect.Value {
+		out := reflect.New(gen)
+		for i, f := range fields {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil { // Through a nil embedded pointer
+				fv = reflect.Zero(f.typ.gen)
+				out.Elem().Field(i).Set(fv)
+				continue
+			}
+			out.Elem().Field(i).Set(f.typ.conv(fv))
+		}
+		return out
+	}}
+	b.objects[t] = o
+	return o
+}
+
+// fields lists the JSON fields of struct t in camelCase, skipping names
+// GraphQL can't have and any already taken.
+func (b *gqlBuilder) fields(t reflect.Type, index []int, taken map[string]bool) []gqlField {
+	var out []gqlField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if sf.Anonymous && tag == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				out = append(out, b.fields(ft, fieldIndex, taken)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = sf.Name
+		}
+		name := camelCase(tag)
+		if !gqlName.MatchString(name) || strings.HasPrefix(name, "__") || taken[name] {
+			continue
+		}
+		typ := b.typeOf(sf.Type)
+		if typ == nil {
+			continue
+		}
+		taken[name] = true
+		out = append(out, gqlField{name: name, index: fieldIndex, typ: typ})
+	}
+	return out
+}
+
+// camelCase turns risk_score into riskScore.
+func camelCase(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' })
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// execGraphQL runs query against a schema whose one root field, p,
+// returns v.
+func execGraphQL(t *testing.T, v any, query string) *graphql.Response {
+	t.Helper()
+	sdl, resolver, err := newGQLBuilder().schema([]gqlRootField{{
+		name: "p", args: "x: Int", argsType: reflect.TypeFor[struct{ X *int32 }](), result: reflect.TypeOf(v),
+		resolve: func(ctx context.Context, args any) (any, error) { return v, nil },
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := graphql.ParseSchema(sdl, resolver, graphql.UseFieldResolvers(), graphql.MaxDepth(graphqlMaxDepth))
+	if err != nil {
+		t.Fatalf("%v\n%s", err, sdl)
+	}
+	return schema.Exec(context.Background(), query, "", nil)
+}
+
+func TestGraphQLTypes(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	p := &validator.WalletProfile{
+		Address:     "0xabc",
+		RiskScore:   42,
+		TxCount:     7,
+		ScreenedAt:  &now,
+		RiskReasons: []validator.RiskReason{{RuleID: "dusting", Offset: 5}, {RuleID: "velocity", Offset: 10}},
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  string
+		err   string
+	}{
+		{"camelCase JSON names", `{ p { riskScore txCount } }`, `{"p":{"riskScore":42,"txCount":7}}`, ""},
+		{"aliases keep query order", `{ p { z: address a: riskScore } }`, `{"p":{"z":"0xabc","a":42}}`, ""},
+		{"lists of objects", `{ p { riskReasons { ruleId } } }`, `{"p":{"riskReasons":[{"ruleId":"dusting"},{"ruleId":"velocity"}]}}`, ""},
+		{"marshalers are JSON", `{ p { screenedAt } }`, `{"p":{"screenedAt":"2026-01-02T00:00:00Z"}}`, ""},
+		{"nil is null", `{ p { concentration { top1Percent } alerts { trigger } } }`, `{"p":{"concentration":null,"alerts":null}}`, ""},
+		{"typename", `{ p { __typename } }`, `{"p":{"__typename":"WalletProfile"}}`, ""},
+		{"fragments", `{ p { ...F } } fragment F on WalletProfile { address }`, `{"p":{"address":"0xabc"}}`, ""},
+		{"directives", `{ p { address @skip(if: true) riskScore } }`, `{"p":{"riskScore":42}}`, ""},
+		{"unknown field", `{ p { nope } }`, "", `Cannot query field "nope" on type "WalletProfile"`},
+		{"snake_case", `{ p { risk_score } }`, "", `Cannot query field "risk_score"`},
+		{"selection on a leaf", `{ p { riskScore { x } } }`, "", "must not have a selection"},
+		{"object without selection", `{ p { riskReasons } }`, "", "must have a selection of subfields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execGraphQL(t, p, tt.query)
+			if tt.err != "" {
+				if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.err) {
+					t.Fatalf("got errors %v, want %q", resp.Errors, tt.err)
+				}
+				return
+			}
+			if len(resp.Errors) > 0 {
+				t.Fatal(resp.Errors)
+			}
+			if string(resp.Data) != tt.want {
+				t.Errorf("got %s, want %s", resp.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLScalars(t *testing.T) {
+	type inner struct {
+		N int `json:"n"`
+	}
+	type embedded struct {
+		Flat string `json:"flat"`
+	}
+	type value struct {
+		embedded
+		Big     int64          `json:"big"`
+		Small   uint8          `json:"small"`
+		Hours   [3]int         `json:"hours"`
+		Map     map[string]int `json:"by_chain"`
+		Any     any            `json:"any"`
+		Ptr     *float64       `json:"ptr"`
+		Inner   inner          `json:"inner"`
+		Skipped string         `json:"-"`
+		Unnamed string
+		private string
+	}
+	v := &value{embedded: embedded{"x"}, Big: 1 << 40, Small: 200, Hours: [3]int{1, 2, 3}, Map: map[string]int{"evm": 2}, Inner: inner{N: 5}, Unnamed: "u", private: "p"}
+	resp := execGraphQL(t, v, `{ p { flat big small hours byChain any ptr inner { n } Unnamed } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatal(resp.Errors)
+	}
+	want := `{"p":{"flat":"x","big":1099511627776,"small":200,"hours":[1,2,3],"byChain":{"evm":2},"any":null,"ptr":null,"inner":{"n":5},"Unnamed":"u"}}`
+	if string(resp.Data) != want {
+		t.Errorf("got %s\nwant %s", resp.Data, want)
+	}
+	for _, field := range []string{"skipped", "Skipped", "private"} {
+		if resp := execGraphQL(t, v, "{ p { "+field+" } }"); len(resp.Errors) == 0 {
+			t.Errorf("%s is in the schema", field)
+		}
+	}
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	s := &profileServer{maxBatch: 3}
+	introspection := `{ __schema { types { name fields { name type { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } } } } }`
+	tests := []struct {
+		name, query, err string
+	}{
+		{"introspection", introspection, ""},
+		{"too deep", "{ __schema { types { fields { type " + strings.Repeat("{ ofType ", graphqlMaxDepth) + "{ name }" + strings.Repeat(" }", graphqlMaxDepth+4), "exceeds max depth"},
+		{"too long", "{ __typename " + strings.Repeat(" ", graphqlMaxLength) + "}", "exceeds the maximum allowed query length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(graphqlRequest{Query: tt.query})
+			status, resp := postGraphQL(t, s, string(body))
+			if tt.err == "" {
+				if status != http.StatusOK || resp["errors"] != nil {
+					t.Fatalf("got %d %v", status, resp)
+				}
+				return
+			}
+			out, _ := json.Marshal(resp)
+			if status != http.StatusBadRequest || !strings.Contains(string(out), tt.err) {
+				t.Errorf("got %d %s, want 400 with %q", status, out, tt.err)
+			}
+		})
+	}
+}
+
+// postGraphQL sends a query to the handler and decodes the response.
+func postGraphQL(t *testing.T, s *profileServer, body string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.graphqlHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(body)))
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%d %s: %v", rec.Code, rec.Body, err)
+	}
+	return rec.Code, resp
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	var mu sync.Mutex
+	var checked []string
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		checked = append(checked, r.URL.Query().Get("address"))
+		mu.Unlock()
+		w.Write([]byte(`{"sanctioned":true,"currency":"ETH","source":"OFAC"}`))
+	}))
+	defer engine.Close()
+	validator.SetWatchlistEngine(engine.URL, "")
+	t.Cleanup(func() { validator.SetWatchlistEngine("", "") })
+	s := &profileServer{maxBatch: 3}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "aliases and variables",
+			body:   `{"query": "query ($a: String!) { hit: watchlistHit(address: $a) { sanctioned source } t: __typename }", "variables": {"a": "0x1&address=0x2#x"}}`,
+			status: http.StatusOK,
+			want:   `{"data":{"hit":{"sanctioned":true,"source":"OFAC"},"t":"Query"}}`,
+		},
+		{
+			name:   "field errors keep the other fields",
+			body:   `{"query": "{ a: watchlistHit(address: \"\") { sanctioned } b: entity(name: \"x\", threshold: 2) { match } c: watchlistHit(address: \"0x3\") { sanctioned } }"}`,
+			status: http.StatusOK,
+			want: `{"data":{"a":null,"b":null,"c":{"sanctioned":true}},"errors":[` +
+				`{"message":"Argument address of watchlistHit must not be empty","path":["a"]},` +
+				`{"message":"Invalid threshold (0-1]","path":["b"]}]}`,
+		},
+		{
+			name:   "unknown field",
+			body:   `{"query": "{ watchlistHit(address: \"0x4\") { nope } }"}`,
+			status: http.StatusBadRequest,
+			want:   `{"errors":[{"message":"Cannot query field \"nope\" on type \"EngineResponse\".","locations":[{"line":1,"column":34}]}]}`,
+		},
+		{
+			name:   "syntax error",
+			body:   `{"query": "{ watchlistHit("}`,
+			status: http.StatusBadRequest,
+			want:   `{"errors":[{"message":"syntax error: unexpected \"\", expecting Ident","locations":[{"line":1,"column":16}]}]}`,
+		},
+		{
+			name:   "missing query",
+			body:   `{"query": " "}`,
+			status: http.StatusBadRequest,
+			want:   `{"errors":[{"message":"Missing query"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := postGraphQL(t, s, tt.body)
+			got, _ := json.Marshal(resp)
+			var want map[string]any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.status || !reflect.DeepEqual(resp, want) {
+				t.Errorf("got %d %s\nwant %d %s", status, got, tt.status, tt.want)
+			}
+		})
+	}
+
+	mu.Lock()
+	if want := []string{"0x1&address=0x2#x", "0x3"}; !reflect.DeepEqual(checked, want) {
+		t.Errorf("engine was asked for %q, want %q", checked, want)
+	}
+	mu.Unlock()
+
+	// Every lookup counts against SERVE_MAX_BATCH; __typename doesn't
+	status, resp := postGraphQL(t, s, `{"query": "{ a: watchlistHit(address: \"1\") { sanctioned } b: watchlistHit(address: \"2\") { sanctioned } c: watchlistHit(address: \"3\") { sanctioned } d: watchlistHit(address: \"4\") { sanctioned } t: __typename }"}`)
+	errs, _ := resp["errors"].([]any)
+	if status != http.StatusOK || len(errs) != 1 || !strings.Contains(errs[0].(map[string]any)["message"].(string), "Too many root fields (max 3)") {
+		t.Errorf("got %d %v, want one field over the limit", status, resp)
+	}
+}
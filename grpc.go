@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	profilerv1 "github.com/piyushdaiya/crypto-profiler/api/proto/profiler/v1"
+	"github.com/piyushdaiya/crypto-profiler/internal/httpapi"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ---------------------------------------------------------
+// gRPC (ProfileService, next to the REST API in serve mode)
+// ---------------------------------------------------------
+
+// profileService serves api/proto/profiler/v1 with the REST API's
+// profileServer: the same strategies, worker pool, limits and event
+// stream, so a profile is the same whichever transport asked for it.
+type profileService struct {
+	profilerv1.UnimplementedProfileServiceServer
+	s *profileServer
+}
+
+// Profile screens one address, as POST /v1/profile. An address no chain
+// accepts is still an answer, with is_valid false.
+func (g *profileService) Profile(ctx context.Context, req *profilerv1.ProfileRequest) (*profilerv1.WalletProfile, error) {
+	address := strings.TrimSpace(req.GetAddress())
+	if address == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing address")
+	}
+	chain, err := g.s.requestChain(req.GetChain())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid chain: "+err.Error())
+	}
+	p := g.s.screen(ctx, address, req.GetProbe(), chain, "")
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return profileMessage(p)
+}
+
+// BatchProfile screens up to SERVE_MAX_BATCH addresses with the worker
+// pool, as POST /v1/profile/batch, and sends each profile in request
+// order as soon as those before it are done. A client that goes away
+// stops the addresses not yet started.
+func (g *profileService) BatchProfile(req *profilerv1.BatchProfileRequest, stream grpc.ServerStreamingServer[profilerv1.WalletProfile]) error {
+	var addresses []string
+	for _, a := range req.GetAddresses() {
+		if a = strings.TrimSpace(a); a != "" {
+			addresses = append(addresses, a)
+		}
+	}
+	if len(addresses) == 0 {
+		return status.Error(codes.InvalidArgument, "Missing addresses")
+	}
+	if len(addresses) > g.s.maxBatch {
+		return status.Errorf(codes.InvalidArgument, "Too many addresses: %d (max %d)", len(addresses), g.s.maxBatch)
+	}
+	chain, err := g.s.requestChain(req.GetChain())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid chain: "+err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	screen := func(ctx context.Context, address string) *validator.WalletProfile {
+		return g.s.screen(ctx, address, req.GetProbe(), chain, "")
+	}
+	defer primeWatchlist(ctx, addresses, chain)()
+	var sendErr error
+	screenPool(ctx, addresses, g.s.workers, chain, screen, func(p *validator.WalletProfile) {
+		if sendErr != nil {
+			return
+		}
+		msg, err := profileMessage(p)
+		if err == nil {
+			err = stream.Send(msg)
+		}
+		if err != nil {
+			sendErr = err
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := stream.Context().Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// profileMessage converts a profile to its ProfileService message. The
+// complete profile, as the REST API returns it, is in profile_json.
+func profileMessage(p *validator.WalletProfile) (*profilerv1.WalletProfile, error) {
+	var raw bytes.Buffer
+	enc := json.NewEncoder(&raw)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(p); err != nil {
+		return nil, status.Errorf(codes.Internal, "Encoding profile: %v", err)
+	}
+
+	msg := &profilerv1.WalletProfile{
+		Address:           p.Address,
+		ResolvedFrom:      p.ResolvedFrom,
+		Network:           p.Network,
+		IsValid:           p.IsValid,
+		ValidationDetails: p.ValidationDetails,
+		IsActive:          p.IsActive,
+		Balance:           p.Balance,
+		BalanceUsd:        p.BalanceUSD,
+		TxCount:           int64(p.TxCount),
+		AccountType:       p.AccountType,
+		RiskScore:         p.RiskScore,
+		RiskGrade:         p.RiskGrade,
+		RiskTier:          int32(p.RiskTier),
+		RiskBreakdown: &profilerv1.RiskCategory{
+			FraudRisk:      p.RiskBreakdown.Fraud,
+			ReputationRisk: p.RiskBreakdown.Reputation,
+			LendingRisk:    p.RiskBreakdown.Lending,
+		},
+		RiskSchema:  p.RiskSchema,
+		RiskPolicy:  p.RiskPolicy,
+		ProfileJson: bytes.TrimSuffix(raw.Bytes(), []byte("\n")),
+	}
+	if p.FirstSeen != nil {
+		unix := p.FirstSeen.Unix()
+		msg.FirstSeenUnix = &unix
+	}
+	if p.LastSeen != nil {
+		unix := p.LastSeen.Unix()
+		msg.LastSeenUnix = &unix
+	}
+	for _, r := range p.RiskReasons {
+		reason := &profilerv1.RiskReason{RuleId: r.RuleID, Category: r.Category, Severity: r.Severity, Description: r.Description, Offset: r.Offset}
+		if e := r.Evidence; e != nil {
+			reason.Evidence = &profilerv1.Evidence{TxHashes: e.TxHashes, Addresses: e.Addresses}
+			for _, t := range e.Timestamps {
+				reason.Evidence.TimestampsUnix = append(reason.Evidence.TimestampsUnix, t.Unix())
+			}
+		}
+		msg.RiskReasons = append(msg.RiskReasons, reason)
+	}
+	for _, a := range p.Alerts {
+		msg.Alerts = append(msg.Alerts, &profilerv1.Alert{Trigger: a.Trigger, Severity: a.Severity, Message: a.Message, RecommendedAction: a.Action, RuleIds: a.RuleIDs})
+	}
+	if d := p.Decision; d != nil {
+		msg.Decision = &profilerv1.Decision{Action: d.Action, Rule: d.Rule, Reason: d.Reason}
+	}
+	return msg, nil
+}
+
+// newGRPCServer returns a server with ProfileService and the standard
+// health service, behind the same API keys as the REST API (the health
+// service stays open, like /health) and logging every call.
+func newGRPCServer(s *profileServer, keys []string) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			start := time.Now()
+			if err := grpcAuth(ctx, keys, info.FullMethod); err != nil {
+				logRPC(ctx, info.FullMethod, err, start)
+				return nil, err
+			}
+			resp, err := handler(ctx, req)
+			logRPC(ctx, info.FullMethod, err, start)
+			return resp, err
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			err := grpcAuth(ss.Context(), keys, info.FullMethod)
+			if err == nil {
+				err = handler(srv, ss)
+			}
+			logRPC(ss.Context(), info.FullMethod, err, start)
+			return err
+		}),
+	)
+	profilerv1.RegisterProfileServiceServer(srv, &profileService{s: s})
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	return srv
+}
+
+// grpcAuth checks a call's "authorization: Bearer <key>" or "x-api-key"
+// metadata against keys; no keys means no authentication.
+func grpcAuth(ctx context.Context, keys []string, method string) error {
+	if len(keys) == 0 || strings.HasPrefix(method, "/grpc.health.v1.Health/") {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	if !httpapi.ValidKey(keys, httpapi.RequestKey(first("authorization"), first("x-api-key"))) {
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	return nil
+}
+
+// logRPC logs a finished call like the HTTP request log; health checks
+// at debug level.
+func logRPC(ctx context.Context, method string, err error, start time.Time) {
+	level := slog.LevelInfo
+	if strings.HasPrefix(method, "/grpc.health.v1.Health/") {
+		level = slog.LevelDebug
+	}
+	slog.Log(ctx, level, "📡 [RPC]", "method", method, "code", status.Code(err).String(), "took", time.Since(start))
+}
+
+// serveGRPC listens on port and serves srv until it is stopped; a
+// failure to listen or serve calls stop, shutting the whole server down.
+func serveGRPC(srv *grpc.Server, port string, stop func()) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("gRPC listen: %w", err)
+	}
+	go func() {
+		slog.Info("✅ [SERVE] gRPC Listening", "port", port)
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("❌ [SERVE] gRPC Server Error", "err", err)
+			stop()
+		}
+	}()
+	return nil
+}
+
+// stopGRPC lets in-flight calls finish until ctx is done, then cuts the
+// rest off.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+		<-stopped
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	profilerv1 "github.com/piyushdaiya/crypto-profiler/api/proto/profiler/v1"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialProfileService serves s over an in-memory listener and returns a
+// client for it.
+func dialProfileService(t *testing.T, s *profileServer, keys []string) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer(s, keys)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newTestProfileServer(t *testing.T) *profileServer {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &profileServer{workers: 2, timeout: 5 * time.Second, maxBatch: 3, stream: newStreamHub(ctx)}
+}
+
+func TestGRPCProfile(t *testing.T) {
+	client := profilerv1.NewProfileServiceClient(dialProfileService(t, newTestProfileServer(t), nil))
+	ctx := context.Background()
+
+	// No chain is registered, so every address is invalid, offline
+	p, err := client.Profile(ctx, &profilerv1.ProfileRequest{Address: "  not-an-address "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Address != "not-an-address" || p.IsValid || p.Network != "UNKNOWN" || p.ValidationDetails == "" {
+		t.Errorf("got %v", p)
+	}
+	var full validator.WalletProfile
+	if err := json.Unmarshal(p.ProfileJson, &full); err != nil || full.Address != p.Address {
+		t.Errorf("profile_json %s: %v", p.ProfileJson, err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		req  *profilerv1.ProfileRequest
+		msg  string
+	}{
+		{"missing address", &profilerv1.ProfileRequest{Address: " "}, "Missing address"},
+		{"unknown chain", &profilerv1.ProfileRequest{Address: "x", Chain: "nochain"}, "Invalid chain: "},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Profile(ctx, tt.req)
+			if st := status.Convert(err); st.Code() != codes.InvalidArgument || len(st.Message()) < len(tt.msg) || st.Message()[:len(tt.msg)] != tt.msg {
+				t.Errorf("got %v, want InvalidArgument %q", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestGRPCBatchProfile(t *testing.T) {
+	client := profilerv1.NewProfileServiceClient(dialProfileService(t, newTestProfileServer(t), nil))
+	ctx := context.Background()
+
+	stream, err := client.BatchProfile(ctx, &profilerv1.BatchProfileRequest{Addresses: []string{"a", " ", "b", "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p.Address)
+	}
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "a" {
+		t.Errorf("streamed %q, want [a b a] in request order", got)
+	}
+
+	for _, tt := range []struct {
+		name string
+		req  *profilerv1.BatchProfileRequest
+	}{
+		{"empty", &profilerv1.BatchProfileRequest{Addresses: []string{" "}}},
+		{"over SERVE_MAX_BATCH", &profilerv1.BatchProfileRequest{Addresses: []string{"a", "b", "c", "d"}}},
+		{"unknown chain", &profilerv1.BatchProfileRequest{Addresses: []string{"a"}, Chain: "nochain"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.BatchProfile(ctx, tt.req)
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("got %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestGRPCAuth(t *testing.T) {
+	conn := dialProfileService(t, newTestProfileServer(t), []string{"k1", "k2"})
+	client := profilerv1.NewProfileServiceClient(conn)
+	req := &profilerv1.ProfileRequest{Address: "x"}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{"no key", nil, codes.Unauthenticated},
+		{"wrong key", metadata.Pairs("authorization", "Bearer nope"), codes.Unauthenticated},
+		{"bearer", metadata.Pairs("authorization", "Bearer k2"), codes.OK},
+		{"x-api-key", metadata.Pairs("x-api-key", "k1"), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			if _, err := client.Profile(ctx, req); status.Code(err) != tt.code {
+				t.Errorf("Profile: got %v, want %s", err, tt.code)
+			}
+			stream, err := client.BatchProfile(ctx, &profilerv1.BatchProfileRequest{Addresses: []string{"x"}})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != tt.code {
+				t.Errorf("BatchProfile: got %v, want %s", err, tt.code)
+			}
+		})
+	}
+
+	// Health checks need no key, like /health
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("health: %v, %v", resp, err)
+	}
+}
+
+func TestProfileMessage(t *testing.T) {
+	first := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	usd := 12.5
+	p := &validator.WalletProfile{
+		Address:       "0xabc",
+		Network:       "Ethereum Mainnet",
+		IsValid:       true,
+		TxCount:       7,
+		FirstSeen:     &first,
+		BalanceUSD:    &usd,
+		RiskScore:     55,
+		RiskTier:      3,
+		RiskBreakdown: validator.RiskCategory{Fraud: 40, Reputation: 10, Lending: 5},
+		RiskReasons: []validator.RiskReason{{
+			RuleID: "mixer_interaction", Category: "FRAUD", Severity: "HIGH", Offset: 25,
+			Evidence: &validator.Evidence{TxHashes: []string{"0x1"}, Timestamps: []time.Time{first}},
+		}},
+		Alerts:   []validator.Alert{{Trigger: "MIXER", Severity: "HIGH", Action: "Review", RuleIDs: []string{"mixer_interaction"}}},
+		Decision: &validator.Decision{Action: "REVIEW", Rule: "decisions.review_score", Reason: "score"},
+	}
+	msg, err := profileMessage(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.GetFirstSeenUnix() != first.Unix() || msg.LastSeenUnix != nil || msg.GetBalanceUsd() != 12.5 || msg.TxCount != 7 || msg.RiskTier != 3 {
+		t.Errorf("scalars: %v", msg)
+	}
+	if msg.RiskBreakdown.FraudRisk != 40 || msg.RiskBreakdown.LendingRisk != 5 {
+		t.Errorf("breakdown: %v", msg.RiskBreakdown)
+	}
+	r := msg.RiskReasons[0]
+	if r.RuleId != "mixer_interaction" || r.Evidence.TxHashes[0] != "0x1" || r.Evidence.TimestampsUnix[0] != first.Unix() {
+		t.Errorf("reason: %v", r)
+	}
+	if a := msg.Alerts[0]; a.RecommendedAction != "Review" || a.RuleIds[0] != "mixer_interaction" {
+		t.Errorf("alert: %v", a)
+	}
+	if msg.Decision.GetAction() != "REVIEW" {
+		t.Errorf("decision: %v", msg.Decision)
+	}
+
+	if msg, _ := profileMessage(&validator.WalletProfile{Address: "x"}); msg.Decision != nil || msg.BalanceUsd != nil || msg.FirstSeenUnix != nil {
+		t.Errorf("unset fields aren't nil: %v", msg)
+	}
+}
@@ -0,0 +1,378 @@
+// Package config holds the settings of the validator and the Watchlist
+// Engine in one typed Config. Load fills it from crypto-profiler.yaml and
+// the environment on top of the defaults the caller sets, so the
+// precedence is: flags, then the environment (.env included), then the
+// file, then the built-in defaults. Nothing is written back to the
+// environment; main passes the Config down.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is read when neither --config nor CONFIG_FILE names a file.
+const DefaultPath = "crypto-profiler.yaml"
+
+// Config is every setting of both binaries. Each section's key tag is its
+// name in the file; each field's key tag is its name in the section and
+// its env tag the env var that overrides it. Empty strings and zero
+// numbers are the defaults unless the caller sets others before Load.
+type Config struct {
+	// API keys
+	APIKeys struct {
+		Etherscan   string `key:"etherscan" env:"ETHERSCAN_API_KEY"`
+		CoinStats   string `key:"coinstats" env:"COINSTATS_API_KEY"`
+		CoinGecko   string `key:"coingecko" env:"COINGECKO_API_KEY"`
+		Blockchair  string `key:"blockchair" env:"BLOCKCHAIR_API_KEY"`
+		NearBlocks  string `key:"nearblocks" env:"NEARBLOCKS_API_KEY"`
+		Glacier     string `key:"glacier" env:"GLACIER_API_KEY"`
+		Unstoppable string `key:"unstoppable" env:"UNSTOPPABLE_API_KEY"`
+	} `key:"keys"`
+
+	// Provider endpoints (empty = public defaults)
+	Providers struct {
+		EVMRPC      string `key:"evm_rpc" env:"EVM_RPC_URL"`
+		SolanaRPC   string `key:"solana_rpc" env:"SOLANA_RPC_URL"`
+		BTCEsplora  string `key:"btc_esplora" env:"BTC_ESPLORA_URL"`
+		NearRPC     string `key:"near_rpc" env:"NEAR_RPC_URL"`
+		LNGraph     string `key:"ln_graph" env:"LN_GRAPH_URL"`
+		SNSResolver string `key:"sns_resolver" env:"SNS_RESOLVER_URL"`
+		PriceOracle string `key:"price_oracle" env:"PRICE_ORACLE"`
+		RateLimits  string `key:"rate_limits" env:"RATE_LIMITS"`
+	} `key:"providers"`
+
+	// Watchlist (engine URL, or the database for embedded mode)
+	Watchlist struct {
+		EngineURL string `key:"engine_url" env:"WATCHLIST_ENGINE_URL"`
+		DBPath    string `key:"db_path" env:"WATCHLIST_DB_PATH"`
+	} `key:"watchlist"`
+
+	// Risk rules and labels
+	Rules struct {
+		File          string  `key:"file" env:"RISK_RULES_FILE"`
+		Policy        string  `key:"policy" env:"RISK_POLICY"`
+		NameThreshold float64 `key:"name_threshold" env:"NAME_MATCH_THRESHOLD"`
+	} `key:"rules"`
+	Labels struct {
+		Providers     string `key:"providers" env:"LABEL_PROVIDERS"`
+		ExchangesFile string `key:"exchanges_file" env:"LABELS_FILE"`
+		AddressesFile string `key:"addresses_file" env:"ADDRESS_LABELS_FILE"`
+	} `key:"labels"`
+
+	// Timeouts, retries and circuit breakers
+	Timeouts struct {
+		HTTP  time.Duration `key:"http" env:"HTTP_TIMEOUT"`
+		Batch time.Duration `key:"batch" env:"BATCH_TIMEOUT"`
+	} `key:"timeouts"`
+	Retry struct {
+		Attempts  int           `key:"attempts" env:"RETRY_ATTEMPTS"`
+		BaseDelay time.Duration `key:"base_delay" env:"RETRY_BASE_DELAY"`
+		MaxDelay  time.Duration `key:"max_delay" env:"RETRY_MAX_DELAY"`
+	} `key:"retry"`
+	Breaker struct {
+		Threshold int           `key:"threshold" env:"BREAKER_THRESHOLD"`
+		Cooldown  time.Duration `key:"cooldown" env:"BREAKER_COOLDOWN"`
+	} `key:"breaker"`
+
+	// Cache and score history
+	Cache struct {
+		Backend     string        `key:"backend" env:"CACHE_BACKEND"`
+		RedisURL    string        `key:"redis_url" env:"REDIS_URL"`
+		BalanceTTL  time.Duration `key:"balance_ttl" env:"CACHE_BALANCE_TTL"`
+		HistoryTTL  time.Duration `key:"history_ttl" env:"CACHE_HISTORY_TTL"`
+		PriceTTL    time.Duration `key:"price_ttl" env:"CACHE_PRICE_TTL"`
+		HistoryFile string        `key:"history_file" env:"HISTORY_FILE"`
+		ProfileFile string        `key:"profile_file" env:"PROFILE_CACHE_FILE"`
+		ProfileTTL  time.Duration `key:"profile_ttl" env:"PROFILE_CACHE_TTL"`
+	} `key:"cache"`
+
+	// Chain enablement and per-chain options
+	Chains struct {
+		Enabled  string `key:"enabled" env:"CHAINS"`
+		EVM      string `key:"evm" env:"EVM_CHAINS"`
+		Testnet  bool   `key:"testnet" env:"TESTNET"`
+		ProbeAll bool   `key:"probe_all" env:"PROBE_ALL"`
+		Offline  bool   `key:"offline" env:"OFFLINE"`
+	} `key:"chains"`
+	EVM struct {
+		MaxTxs       int  `key:"max_txs" env:"EVM_MAX_TXS"`
+		SkipTokens   bool `key:"skip_tokens" env:"EVM_SKIP_TOKENS"`
+		NFTs         bool `key:"nfts" env:"EVM_NFTS"`
+		Approvals    bool `key:"approvals" env:"EVM_APPROVALS"`
+		Deposits     bool `key:"deposits" env:"EVM_DEPOSITS"`
+		ScamTokens   bool `key:"scam_tokens" env:"EVM_SCAM_TOKENS"`
+		WashTrading  bool `key:"wash_trading" env:"EVM_WASH_TRADING"`
+		Sybil        bool `key:"sybil" env:"EVM_SYBIL"`
+		ExposureHops int  `key:"exposure_hops" env:"EXPOSURE_HOPS"`
+		PeelHops     int  `key:"peel_hops" env:"PEEL_HOPS"`
+	} `key:"evm"`
+	Bitcoin struct {
+		GapLimit      int  `key:"gap_limit" env:"BTC_GAP_LIMIT"`
+		PreferEsplora bool `key:"prefer_esplora" env:"BTC_PREFER_ESPLORA"`
+	} `key:"bitcoin"`
+
+	// Output, batch, watch, monitor and serve modes
+	Output struct {
+		Format        string  `key:"format" env:"OUTPUT_FORMAT"`
+		Template      string  `key:"template" env:"OUTPUT_TEMPLATE"`
+		WebhookURL    string  `key:"webhook_url" env:"WEBHOOK_URL"`
+		WebhookSecret string  `key:"webhook_secret" env:"WEBHOOK_SECRET"`
+		FailAbove     float64 `key:"fail_above" env:"FAIL_ABOVE"`
+	} `key:"output"`
+	Batch struct {
+		Workers          int           `key:"workers" env:"BATCH_WORKERS"`
+		Checkpoint       string        `key:"checkpoint" env:"BATCH_CHECKPOINT"`
+		ProgressInterval time.Duration `key:"progress_interval" env:"BATCH_PROGRESS_INTERVAL"`
+	} `key:"batch"`
+	Watch struct {
+		Interval time.Duration `key:"interval" env:"WATCH_INTERVAL"`
+	} `key:"watch"`
+	Monitor struct {
+		File       string        `key:"file" env:"MONITOR_FILE"`
+		Interval   time.Duration `key:"interval" env:"MONITOR_INTERVAL"`
+		Threshold  float64       `key:"threshold" env:"MONITOR_THRESHOLD"`
+		WebhookURL string        `key:"webhook_url" env:"MONITOR_WEBHOOK_URL"`
+	} `key:"monitor"`
+	Serve struct {
+		Port     string        `key:"port" env:"SERVE_PORT"`
+		APIKeys  string        `key:"api_keys" env:"SERVE_API_KEYS"`
+		MaxJob   int           `key:"max_job" env:"SERVE_MAX_JOB"`
+		JobTTL   time.Duration `key:"job_ttl" env:"SERVE_JOB_TTL"`
+		Engine   bool          `key:"engine" env:"SERVE_ENGINE"`
+		MaxBatch int           `key:"max_batch" env:"SERVE_MAX_BATCH"`
+		GRPCPort string        `key:"grpc_port" env:"SERVE_GRPC_PORT"`
+	} `key:"serve"`
+	Queue struct {
+		URL             string        `key:"url" env:"QUEUE_URL"`
+		Stream          string        `key:"stream" env:"QUEUE_STREAM"`
+		Consumer        string        `key:"consumer" env:"QUEUE_CONSUMER"`
+		Subject         string        `key:"subject" env:"QUEUE_SUBJECT"`
+		ProfilesSubject string        `key:"profiles_subject" env:"QUEUE_PROFILES_SUBJECT"`
+		AlertsSubject   string        `key:"alerts_subject" env:"QUEUE_ALERTS_SUBJECT"`
+		DeadSubject     string        `key:"dead_subject" env:"QUEUE_DEAD_SUBJECT"`
+		MaxDeliver      int           `key:"max_deliver" env:"QUEUE_MAX_DELIVER"`
+		RetryDelay      time.Duration `key:"retry_delay" env:"QUEUE_RETRY_DELAY"`
+	} `key:"queue"`
+	Kafka struct {
+		Brokers       string        `key:"brokers" env:"KAFKA_BROKERS"`
+		TLS           bool          `key:"tls" env:"KAFKA_TLS"`
+		Group         string        `key:"group" env:"KAFKA_GROUP"`
+		Topic         string        `key:"topic" env:"KAFKA_TOPIC"`
+		ProfilesTopic string        `key:"profiles_topic" env:"KAFKA_PROFILES_TOPIC"`
+		AlertsTopic   string        `key:"alerts_topic" env:"KAFKA_ALERTS_TOPIC"`
+		DeadTopic     string        `key:"dead_topic" env:"KAFKA_DEAD_TOPIC"`
+		StartOffset   string        `key:"start_offset" env:"KAFKA_START_OFFSET"`
+		MaxAttempts   int           `key:"max_attempts" env:"KAFKA_MAX_ATTEMPTS"`
+		RetryDelay    time.Duration `key:"retry_delay" env:"KAFKA_RETRY_DELAY"`
+	} `key:"kafka"`
+
+	// Logging (both binaries)
+	Logging struct {
+		Level  string `key:"level" env:"LOG_LEVEL"`
+		Format string `key:"format" env:"LOG_FORMAT"`
+	} `key:"logging"`
+
+	// Watchlist Engine (also serve --engine)
+	Engine struct {
+		DBPath       string        `key:"db_path" env:"DB_PATH"`
+		Port         string        `key:"port" env:"PORT"`
+		SyncInterval time.Duration `key:"sync_interval" env:"SYNC_INTERVAL"`
+		OFACURL      string        `key:"ofac_url" env:"OFAC_URL"`
+		HTTPTimeout  time.Duration `key:"http_timeout" env:"SYNC_HTTP_TIMEOUT"`
+		Proxy        string        `key:"proxy" env:"SYNC_PROXY_URL"`
+	} `key:"engine"`
+}
+
+// Keys maps each "section.key" of the file to the env var that overrides it.
+var Keys = func() map[string]string {
+	keys := map[string]string{}
+	for _, f := range settings(&Config{}) {
+		keys[f.key] = f.env
+	}
+	return keys
+}()
+
+// setting is one field of a Config.
+type setting struct {
+	key   string // "section.key"
+	env   string
+	value reflect.Value
+}
+
+// settings lists the fields of cfg in declaration order.
+func settings(cfg *Config) []setting {
+	var out []setting
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := range sections.NumField() {
+		section := sections.Type().Field(i).Tag.Get("key")
+		fields := sections.Field(i)
+		for j := range fields.NumField() {
+			tag := fields.Type().Field(j).Tag
+			out = append(out, setting{key: section + "." + tag.Get("key"), env: tag.Get("env"), value: fields.Field(j)})
+		}
+	}
+	return out
+}
+
+// Path finds the config file: --config (or -config) in args, else
+// CONFIG_FILE. required reports whether it was named explicitly; if not,
+// a missing DefaultPath is fine.
+func Path(args []string) (path string, required bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		return value, true
+	}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path, true
+	}
+	return DefaultPath, false
+}
+
+// Load reads the file at path into cfg, then overrides each setting whose
+// env var is set and not empty (Docker Compose passes unset variables
+// through as empty). Settings in neither keep the value cfg already has.
+// It returns whether a file was read. Unknown keys and values that don't
+// parse as the setting's type are errors naming the key or env var, so
+// typos don't silently fall back to defaults.
+func Load(cfg *Config, path string, required bool) (bool, error) {
+	var values map[string]string
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && !required:
+	case err != nil:
+		return false, err
+	default:
+		if values, err = Parse(data); err != nil {
+			return false, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var problems []string
+	for _, s := range settings(cfg) {
+		value, from := values[s.key], path+": "+s.key
+		if v := os.Getenv(s.env); v != "" {
+			value, from = v, s.env
+		}
+		if value == "" {
+			continue
+		}
+		if err := set(s.value, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", from, err))
+		}
+	}
+	if len(problems) > 0 {
+		return false, errors.New(strings.Join(problems, "; "))
+	}
+	return values != nil, nil
+}
+
+// set parses s into v, a string, bool, int, float64 or time.Duration field.
+func set(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (true or false)", s)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	default:
+		panic("config: unsupported setting type " + v.Type().String())
+	}
+	return nil
+}
+
+// Parse reads a config file into "section.key" values. Values are kept as
+// the text an env var would hold, so "yes" stays a (bad) boolean and
+// "70.5" a number either way.
+func Parse(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return map[string]string{}, nil // Empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected sections of settings", root.Line)
+	}
+
+	out := map[string]string{}
+	var problems []string
+	seen := map[string]bool{} // Decoding into nodes doesn't catch duplicates
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		section, fields := root.Content[i].Value, root.Content[i+1]
+		if seen[section] {
+			return nil, fmt.Errorf("line %d: duplicate key %q", root.Content[i].Line, section)
+		}
+		seen[section] = true
+		if fields.Tag == "!!null" {
+			continue // An empty section
+		}
+		if fields.Kind != yaml.MappingNode {
+			problems = append(problems, fmt.Sprintf("%s: expected a section of settings", section))
+			continue
+		}
+		for j := 0; j+1 < len(fields.Content); j += 2 {
+			key, value := section+"."+fields.Content[j].Value, fields.Content[j+1]
+			if seen[key] {
+				return nil, fmt.Errorf("line %d: duplicate key %q", fields.Content[j].Line, key)
+			}
+			seen[key] = true
+			switch {
+			case Keys[key] == "":
+				problems = append(problems, fmt.Sprintf("%s: unknown setting", key))
+			case value.Kind != yaml.ScalarNode || value.Tag == "!!null":
+				problems = append(problems, fmt.Sprintf("%s: expected a value", key))
+			default:
+				out[key] = value.Value
+			}
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return out, nil
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "crypto-profiler.yaml")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+keys:
+  etherscan: file-key
+retry:
+  attempts: 5
+  base_delay: 250ms
+chains:
+  testnet: true
+output:
+  fail_above: "70.5"
+serve:
+  port: "9000"
+`)
+	t.Setenv("ETHERSCAN_API_KEY", "env-key")
+	t.Setenv("SERVE_PORT", "") // Unset in Compose: the file still applies
+	t.Setenv("BATCH_TIMEOUT", "2m")
+
+	cfg := &Config{}
+	cfg.Retry.MaxDelay = 8 * time.Second
+	cfg.Output.FailAbove = -1
+	loaded, err := Load(cfg, path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded {
+		t.Error("the file wasn't reported as loaded")
+	}
+
+	for _, c := range []struct {
+		name      string
+		got, want any
+	}{
+		{"env over file", cfg.APIKeys.Etherscan, "env-key"},
+		{"int from file", cfg.Retry.Attempts, 5},
+		{"duration from file", cfg.Retry.BaseDelay, 250 * time.Millisecond},
+		{"default kept", cfg.Retry.MaxDelay, 8 * time.Second},
+		{"bool from file", cfg.Chains.Testnet, true},
+		{"quoted float", cfg.Output.FailAbove, 70.5},
+		{"empty env", cfg.Serve.Port, "9000"},
+		{"env only", cfg.Timeouts.Batch, 2 * time.Minute},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if v, ok := os.LookupEnv("RETRY_ATTEMPTS"); ok {
+		t.Errorf("Load set RETRY_ATTEMPTS=%q in the environment", v)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+	t.Setenv("WATCH_INTERVAL", "30s")
+
+	cfg := &Config{}
+	loaded, err := Load(cfg, path, false)
+	if err != nil || loaded {
+		t.Fatalf("got %v, %v for an optional missing file", loaded, err)
+	}
+	if cfg.Watch.Interval != 30*time.Second {
+		t.Errorf("env ignored without a file: %v", cfg.Watch.Interval)
+	}
+	if _, err := Load(&Config{}, path, true); err == nil {
+		t.Error("a missing --config file isn't an error")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, src string
+		env       map[string]string
+		err       []string
+	}{
+		{
+			name: "unknown key",
+			src:  "retry:\n  attempt: 3\n",
+			err:  []string{"retry.attempt: unknown setting"},
+		},
+		{
+			name: "bad values name the key",
+			src:  "retry:\n  attempts: three\nchains:\n  testnet: yes\n",
+			err:  []string{`retry.attempts: invalid integer "three"`, `chains.testnet: invalid boolean "yes"`},
+		},
+		{
+			name: "bad env names the variable",
+			src:  "retry:\n  base_delay: 1s\n",
+			env:  map[string]string{"RETRY_BASE_DELAY": "soon", "MONITOR_THRESHOLD": "high"},
+			err:  []string{`RETRY_BASE_DELAY: invalid duration "soon"`, `MONITOR_THRESHOLD: invalid number "high"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(&Config{}, writeConfig(t, tt.src), true)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			for _, want := range tt.err {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+		err  string
+	}{
+		{
+			name: "scalars are kept as text",
+			src:  "# header\nretry:\n  attempts: 5 # trailing\n  base_delay: 250ms\nchains:\n  testnet: yes\noutput:\n  fail_above: \"70.5\"\n",
+			want: map[string]string{"retry.attempts": "5", "retry.base_delay": "250ms", "chains.testnet": "yes", "output.fail_above": "70.5"},
+		},
+		{
+			name: "hash inside quotes",
+			src:  "keys:\n  etherscan: 'abc #def'\n  coingecko: abc#def\n",
+			want: map[string]string{"keys.etherscan": "abc #def", "keys.coingecko": "abc#def"},
+		},
+		{
+			name: "empty file and section",
+			src:  "---\nretry:\n",
+			want: map[string]string{},
+		},
+		{name: "setting at the top level", src: "etherscan: abc\n", err: "etherscan: expected a section of settings"},
+		{name: "list value", src: "chains:\n  enabled: [ethereum, base]\n", err: "chains.enabled: expected a value"},
+		{name: "duplicate key", src: "retry:\n  attempts: 1\n  attempts: 2\n", err: `line 3: duplicate key "retry.attempts"`},
+		{name: "tab indentation", src: "retry:\n\tattempts: 1\n", err: "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.src))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExample(t *testing.T) {
+	data, err := os.ReadFile("../../crypto-profiler.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	envs := map[string]string{}
+	for key, env := range Keys {
+		section, name, ok := strings.Cut(key, ".")
+		if !ok || section == "" || name == "" || env == "" {
+			t.Errorf("incomplete setting %q -> %q", key, env)
+		}
+		if other, dup := envs[env]; dup {
+			t.Errorf("%s and %s both read %s", key, other, env)
+		}
+		envs[env] = key
+	}
+	if Keys["engine.http_timeout"] != "SYNC_HTTP_TIMEOUT" || Keys["keys.unstoppable"] != "UNSTOPPABLE_API_KEY" {
+		t.Error("keys don't map to their env vars")
+	}
+}
@@ -0,0 +1,242 @@
+// Package httpapi holds the middleware the HTTP servers share: request
+// logging, API-key authentication and Prometheus metrics. The Watchlist
+// Engine and the validator's serve mode wrap their routes with the same
+// chain, so one deployment logs, authenticates and counts every endpoint
+// alike.
+package httpapi
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statusRecorder remembers the status code a handler wrote.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush passes through, for handlers that stream.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func record(w http.ResponseWriter) *statusRecorder {
+	if rec, ok := w.(*statusRecorder); ok {
+		return rec
+	}
+	return &statusRecorder{ResponseWriter: w}
+}
+
+// Handler wraps mux in the shared chain: logging, then metrics (if
+// any), then authentication with keys (if any).
+func Handler(mux *http.ServeMux, keys []string, metrics *Metrics) http.Handler {
+	h := Auth(keys, mux)
+	if metrics != nil {
+		h = metrics.Wrap(h)
+	}
+	return Logging(h)
+}
+
+// ---------------------------------------------------------
+// LOGGING
+// ---------------------------------------------------------
+
+// Logging logs every request once it is answered. Health checks, which
+// arrive every few seconds, are logged at debug level.
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := record(w)
+		next.ServeHTTP(rec, r)
+		level := slog.LevelInfo
+		if r.URL.Path == "/health" {
+			level = slog.LevelDebug
+		}
+		slog.Log(r.Context(), level, "📡 [REQ]", "method", r.Method, "path", r.URL.Path, "status", max(rec.status, http.StatusOK), "took", time.Since(start))
+	})
+}
+
+// ---------------------------------------------------------
+// AUTHENTICATION (static API keys)
+// ---------------------------------------------------------
+
+// Auth lets a request through only with one of keys, sent as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>"; anything else is a
+// 401. GET /health stays open for container health checks. No keys means
+// no authentication.
+func Auth(keys []string, next http.Handler) http.Handler {
+	if len(keys) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" || ValidKey(keys, RequestKey(r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="crypto-profiler"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// ParseKeys splits a comma-separated key list, dropping blanks.
+func ParseKeys(s string) []string {
+	var keys []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// RequestKey picks the key a request sent: the Bearer token of its
+// Authorization header, else its X-API-Key header. gRPC calls send the
+// same two as metadata.
+func RequestKey(authorization, apiKey string) string {
+	if authorization != "" {
+		if scheme, token, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return apiKey
+}
+
+// ValidKey compares against every key in constant time, so the timing
+// tells nothing about which key came close.
+func ValidKey(keys []string, got string) bool {
+	if got == "" {
+		return false
+	}
+	ok := 0
+	for _, k := range keys {
+		ok |= subtle.ConstantTimeCompare([]byte(k), []byte(got))
+	}
+	return ok == 1
+}
+
+// ---------------------------------------------------------
+// METRICS (Prometheus text format, no client library)
+// ---------------------------------------------------------
+
+// Metrics counts requests and their latency by route and status.
+// Routes are the ServeMux patterns, so path parameters and unknown paths
+// don't grow the label set.
+type Metrics struct {
+	prefix string
+
+	mu     sync.Mutex
+	series map[metricKey]*metricValue
+	start  time.Time
+}
+
+type metricKey struct {
+	route, method string
+	status        int
+}
+
+type metricValue struct {
+	count   int64
+	seconds float64
+}
+
+// NewMetrics returns empty metrics named prefix_http_... (e.g.
+// "profiler").
+func NewMetrics(prefix string) *Metrics {
+	return &Metrics{prefix: prefix, series: map[metricKey]*metricValue{}, start: time.Now()}
+}
+
+// Wrap counts the requests answered by next, which should be the mux.
+func (m *Metrics) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := record(w)
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern // Set by the ServeMux that routed r
+		if route == "" {
+			route = "unmatched"
+		}
+		key := metricKey{route: route, method: r.Method, status: max(rec.status, http.StatusOK)}
+		m.mu.Lock()
+		v := m.series[key]
+		if v == nil {
+			v = &metricValue{}
+			m.series[key] = v
+		}
+		v.count++
+		v.seconds += time.Since(start).Seconds()
+		m.mu.Unlock()
+	})
+}
+
+// ServeHTTP answers GET /metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	keys := make([]metricKey, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	values := make(map[metricKey]metricValue, len(keys))
+	for _, k := range keys {
+		values[k] = *m.series[k]
+	}
+	m.mu.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+
+	var sb strings.Builder
+	name := m.prefix + "_http_requests_total"
+	fmt.Fprintf(&sb, "# HELP %s HTTP requests answered, by route, method and status.\n# TYPE %s counter\n", name, name)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s{route=%q,method=%q,status=\"%d\"} %d\n", name, k.route, k.method, k.status, values[k].count)
+	}
+	name = m.prefix + "_http_request_duration_seconds_sum"
+	fmt.Fprintf(&sb, "# HELP %s Total time spent answering, by route, method and status.\n# TYPE %s counter\n", name, name)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s{route=%q,method=%q,status=\"%d\"} %g\n", name, k.route, k.method, k.status, values[k].seconds)
+	}
+	name = m.prefix + "_uptime_seconds"
+	fmt.Fprintf(&sb, "# HELP %s Seconds since the server started.\n# TYPE %s gauge\n%s %g\n", name, name, name, time.Since(m.start).Seconds())
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,82 @@
+// Package logging sets up the process-wide slog logger shared by the
+// validator and the Watchlist Engine. Records always go to stderr, so
+// stdout carries nothing but results.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LevelTrace is below Debug: every outbound HTTP request (--debug).
+const LevelTrace = slog.LevelDebug - 4
+
+// Config is how much is logged and how.
+type Config struct {
+	Level  slog.Level
+	Format string // text (default) or json
+	Source bool   // Add the file:line of each call
+}
+
+// ParseLevel reads LOG_LEVEL-style names: trace, debug, info, warn, error.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown level %q (trace, debug, info, warn or error)", s)
+}
+
+// ParseConfig reads the LOG_LEVEL and LOG_FORMAT settings.
+func ParseConfig(level, format string) (Config, error) {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+	return Config{Level: l, Format: format}, nil
+}
+
+// Setup makes a logger for cfg writing to w the default, for slog and for
+// anything still using the standard log package.
+func Setup(cfg Config, w io.Writer) error {
+	opts := &slog.HandlerOptions{
+		Level:     cfg.Level,
+		AddSource: cfg.Source,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 && a.Value.Any() == LevelTrace {
+				a.Value = slog.StringValue("TRACE")
+			}
+			return a
+		},
+	}
+	var h slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT: %q (text or json)", cfg.Format)
+	}
+	slog.SetDefault(slog.New(h))
+	log.SetFlags(0) // The handler adds the time
+	return nil
+}
+
+// Fatal logs msg at Error and exits with status 1.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
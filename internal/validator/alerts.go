@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// ALERTS (routable outcomes of a profile, apart from the reasons)
+// ---------------------------------------------------------
+
+// Alert triggers
+const (
+	AlertSanctions           = "SANCTIONS_HIT"
+	AlertHighScore           = "HIGH_RISK_SCORE"
+	AlertMixerExposure       = "MIXER_EXPOSURE"
+	AlertThreatCounterparty  = "THREAT_COUNTERPARTY"
+	AlertScreeningIncomplete = "SCREENING_INCOMPLETE"
+)
+
+// AlertRule is when one alert fires and how it is routed. What the
+// threshold measures depends on the alert; alerts without one ignore it.
+type AlertRule struct {
+	Threshold float64 `json:"threshold,omitempty"`
+	Severity  string  `json:"severity"`
+	Action    string  `json:"action"`
+	Disabled  bool    `json:"disabled,omitempty"`
+}
+
+// AlertRules are the alert conditions of the policy.
+type AlertRules struct {
+	Sanctions           AlertRule `json:"sanctions"`            // OFAC hit on the address, a derived address or a Safe owner
+	HighScore           AlertRule `json:"high_score"`           // Threshold: min risk score
+	MixerExposure       AlertRule `json:"mixer_exposure"`       // Mixer contact; Threshold: min % of funds from indirect exposure
+	ThreatCounterparty  AlertRule `json:"threat_counterparty"`  // Contact with a drainer, scam or sanctioned label
+	ScreeningIncomplete AlertRule `json:"screening_incomplete"` // Watchlist engine unavailable
+}
+
+// DefaultAlertRules returns the built-in alert conditions.
+func DefaultAlertRules() AlertRules {
+	return AlertRules{
+		Sanctions:           AlertRule{Severity: "CRITICAL", Action: "Block the transaction and file a sanctions report"},
+		HighScore:           AlertRule{Threshold: 60, Severity: "HIGH", Action: "Escalate for enhanced due diligence"},
+		MixerExposure:       AlertRule{Threshold: 10, Severity: "HIGH", Action: "Request the source of funds before accepting them"},
+		ThreatCounterparty:  AlertRule{Severity: "MEDIUM", Action: "Review the flagged counterparties"},
+		ScreeningIncomplete: AlertRule{Severity: "MEDIUM", Action: "Re-screen once the watchlist engine is back"},
+	}
+}
+
+// alertSeverities rank the severities, most urgent first.
+var alertSeverities = map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "LOW": 3}
+
+// byID returns every alert rule by its rules-file key.
+func (a AlertRules) byID() map[string]AlertRule {
+	return map[string]AlertRule{
+		"sanctions": a.Sanctions, "high_score": a.HighScore, "mixer_exposure": a.MixerExposure,
+		"threat_counterparty": a.ThreatCounterparty, "screening_incomplete": a.ScreeningIncomplete,
+	}
+}
+
+// validate lists the problems of the alert rules.
+func (a AlertRules) validate() []string {
+	var problems []string
+	for name, rule := range a.byID() {
+		if rule.Disabled {
+			continue
+		}
+		if _, ok := alertSeverities[rule.Severity]; !ok {
+			problems = append(problems, fmt.Sprintf("alerts.%s.severity must be LOW, MEDIUM, HIGH or CRITICAL", name))
+		}
+		if strings.TrimSpace(rule.Action) == "" {
+			problems = append(problems, fmt.Sprintf("alerts.%s.action must not be empty", name))
+		}
+		if rule.Threshold < 0 {
+			problems = append(problems, fmt.Sprintf("alerts.%s.threshold must not be negative", name))
+		}
+	}
+	if !a.HighScore.Disabled && (a.HighScore.Threshold <= 0 || a.HighScore.Threshold > 100) {
+		problems = append(problems, "alerts.high_score.threshold must be within (0, 100]")
+	}
+	return problems
+}
+
+// buildAlerts evaluates the alert rules against a scored profile, most
+// severe first.
+func buildAlerts(a AlertRules, profile *WalletProfile) []Alert {
+	ids := map[string][]RiskReason{}
+	for _, r := range profile.RiskReasons {
+		ids[r.RuleID] = append(ids[r.RuleID], r)
+	}
+	var alerts []Alert
+	add := func(rule AlertRule, trigger, message string, ruleIDs ...string) {
+		if !rule.Disabled {
+			alerts = append(alerts, Alert{Trigger: trigger, Severity: rule.Severity, Message: message, Action: rule.Action, RuleIDs: ruleIDs})
+		}
+	}
+
+	var sanctioned []string
+	for _, id := range []string{"sanctions", "sanctioned_safe_owner"} {
+		if len(ids[id]) > 0 {
+			sanctioned = append(sanctioned, id)
+		}
+	}
+	if len(sanctioned) > 0 {
+		add(a.Sanctions, AlertSanctions, ids[sanctioned[0]][0].Description, sanctioned...)
+	}
+	if profile.RiskScore >= a.HighScore.Threshold {
+		add(a.HighScore, AlertHighScore, fmt.Sprintf("Risk Score %.1f (%s), Threshold %.0f", profile.RiskScore, profile.RiskGrade, a.HighScore.Threshold))
+	}
+
+	// Mixers: direct contact, or enough of the funds a few hops from flagged addresses
+	var mixer []string
+	var ruleIDs []string
+	if len(ids["mixer_interaction"]) > 0 {
+		mixer = append(mixer, ids["mixer_interaction"][0].Description)
+		ruleIDs = append(ruleIDs, "mixer_interaction")
+	}
+	var worst HopExposure
+	for _, e := range profile.Exposure {
+		if e.Percent > worst.Percent {
+			worst = e
+		}
+	}
+	if worst.Percent > 0 && worst.Percent >= a.MixerExposure.Threshold {
+		mixer = append(mixer, fmt.Sprintf("%.1f%% of Funds %s from Flagged Addresses", worst.Percent, plural(worst.Hop, "Hop", "Hops")))
+		if len(ids["indirect_exposure"]) > 0 {
+			ruleIDs = append(ruleIDs, "indirect_exposure")
+		}
+	}
+	if len(mixer) > 0 {
+		add(a.MixerExposure, AlertMixerExposure, strings.Join(mixer, "; "), ruleIDs...)
+	}
+
+	if threats := ids["threat_counterparty"]; len(threats) > 0 {
+		add(a.ThreatCounterparty, AlertThreatCounterparty, threats[0].Description, "threat_counterparty")
+	}
+	if len(ids["watchlist_unavailable"]) > 0 {
+		add(a.ScreeningIncomplete, AlertScreeningIncomplete, "Sanctions Check Skipped: Watchlist Engine Unavailable", "watchlist_unavailable")
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alertSeverities[alerts[i].Severity] < alertSeverities[alerts[j].Severity]
+	})
+	return alerts
+}
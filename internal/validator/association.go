@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// SANCTIONED BY ASSOCIATION (co-listed addresses of an SDN entity)
+// ---------------------------------------------------------
+
+// sanctionedEntity builds the entity of a watchlist hit and finds the
+// co-listed addresses among the wallet's counterparties.
+func sanctionedEntity(resp *EngineResponse, address string, txs []Transaction) *SanctionedEntity {
+	e := &SanctionedEntity{ID: resp.EntityID, Name: resp.EntityName, CoListed: []string{}}
+	coListed := map[string]string{}
+	for _, r := range resp.CoListed {
+		e.CoListed = append(e.CoListed, r.Address)
+		coListed[strings.ToLower(r.Address)] = r.Address
+	}
+
+	seen := map[string]bool{}
+	for _, tx := range txs {
+		other := strings.ToLower(tx.From)
+		if strings.EqualFold(tx.From, address) {
+			other = strings.ToLower(tx.To)
+		}
+		if listed, ok := coListed[other]; ok && !seen[other] {
+			seen[other] = true
+			e.Transacted = append(e.Transacted, listed)
+		}
+	}
+	return e
+}
+
+// entityDesc is the reason text: "Lazarus Group (12 Co-listed Addresses,
+// 2 Transacted With)".
+func entityDesc(e *SanctionedEntity) string {
+	name := e.Name
+	if name == "" {
+		name = "SDN Entity " + e.ID
+	}
+	desc := fmt.Sprintf("%s (%s", name, plural(len(e.CoListed), "Co-listed Address", "Co-listed Addresses"))
+	if len(e.Transacted) > 0 {
+		desc += fmt.Sprintf(", %d Transacted With", len(e.Transacted))
+	}
+	return desc + ")"
+}
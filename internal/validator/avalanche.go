@@ -0,0 +1,177 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AvalancheStrategy covers the non-EVM X-Chain (assets) and P-Chain (staking)
+// addresses, e.g. "X-avax1...", "P-avax1..." or a bare "avax1...".
+// C-Chain 0x addresses are handled by EVMStrategy.
+type AvalancheStrategy struct {
+	Testnet bool // Fuji testnet ("fuji1..." addresses)
+}
+
+func (a *AvalancheStrategy) Name() string {
+	return "AVALANCHE"
+}
+
+// splitAvalancheAddress returns the chain aliases to query and the bare
+// bech32 address. A bare address is checked on both X and P chains.
+// wantHRP is "avax" on mainnet and "fuji" on testnet.
+func splitAvalancheAddress(address, wantHRP string) ([]string, string, bool) {
+	chains := []string{"x-chain", "p-chain"}
+	bare := address
+	if len(address) > 2 && address[1] == '-' {
+		switch strings.ToUpper(address[:1]) {
+		case "X":
+			chains = []string{"x-chain"}
+		case "P":
+			chains = []string{"p-chain"}
+		default:
+			return nil, "", false
+		}
+		bare = address[2:]
+	}
+
+	hrp, data, enc, err := bech32Decode(bare, 90)
+	if err != nil || enc != encodingBech32 || hrp != wantHRP {
+		return nil, "", false
+	}
+	payload, err := convertBits(data, 5, 8, false)
+	if err != nil || len(payload) != 20 {
+		return nil, "", false
+	}
+	return chains, strings.ToLower(bare), true
+}
+
+func (a *AvalancheStrategy) network() (hrp, glacierNetwork string) {
+	if a.Testnet {
+		return "fuji", "fuji"
+	}
+	return "avax", "mainnet"
+}
+
+func (a *AvalancheStrategy) IsValidSyntax(address string) bool {
+	hrp, _ := a.network()
+	_, _, ok := splitAvalancheAddress(strings.TrimSpace(address), hrp)
+	return ok
+}
+
+type glacierAsset struct {
+	Symbol string `json:"symbol"`
+	Amount string `json:"amount"`
+}
+
+func (a *AvalancheStrategy) FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error) {
+	cleanAddr := strings.TrimSpace(address)
+	profile := &WalletProfile{
+		Address: cleanAddr,
+		Network: "AVALANCHE",
+		IsValid: true,
+		Testnet: a.Testnet,
+	}
+
+	hrp, glacierNetwork := a.network()
+	chains, bare, ok := splitAvalancheAddress(cleanAddr, hrp)
+	if !ok {
+		profile.IsValid = false
+		profile.ValidationDetails = "Invalid Avalanche Address"
+		return profile, nil
+	}
+
+	client := httpClient(15 * time.Second)
+	baseURL := fmt.Sprintf("https://glacier-api.avax.network/v1/networks/%s/blockchains", glacierNetwork)
+
+	get := func(url string, target interface{}) error {
+		return doJSON(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			if apiKey != "" {
+				req.Header.Set("x-glacier-api-key", apiKey)
+			}
+			return req, nil
+		}, target)
+	}
+
+	total := new(big.Int) // nAVAX (9 decimals)
+	var details []string
+	fetchFailed := false
+
+	for _, chain := range chains {
+		label := strings.ToUpper(chain[:1]) + "-Chain"
+
+		// 1. Balances (X: locked/unlocked, P: staked/unstaked buckets)
+		var balResp struct {
+			Balances map[string]json.RawMessage `json:"balances"`
+		}
+		if err := get(fmt.Sprintf("%s/%s/balances?addresses=%s", baseURL, chain, bare), &balResp); err != nil {
+			details = append(details, fmt.Sprintf("%s Glacier Error: %v", label, err))
+			fetchFailed = true
+			continue
+		}
+
+		chainTotal := new(big.Int)
+		for _, raw := range balResp.Balances {
+			var assets []glacierAsset
+			if err := json.Unmarshal(raw, &assets); err != nil {
+				continue
+			}
+			for _, asset := range assets {
+				if asset.Symbol != "AVAX" {
+					continue
+				}
+				if amt, ok := new(big.Int).SetString(asset.Amount, 10); ok {
+					chainTotal.Add(chainTotal, amt)
+				}
+			}
+		}
+		total.Add(total, chainTotal)
+
+		// 2. Transactions (newest first; capped at one page)
+		var txResp struct {
+			Transactions []struct {
+				Timestamp int64 `json:"timestamp"`
+			} `json:"transactions"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := get(fmt.Sprintf("%s/%s/transactions?addresses=%s&pageSize=100&sortOrder=desc", baseURL, chain, bare), &txResp); err != nil {
+			details = append(details, fmt.Sprintf("%s History Unavailable", label))
+			fetchFailed = true
+			continue
+		}
+
+		txs := txResp.Transactions
+		profile.TxCount += len(txs)
+		if len(txs) > 0 {
+			last := time.Unix(txs[0].Timestamp, 0)
+			first := time.Unix(txs[len(txs)-1].Timestamp, 0)
+			profile.LastSeen = latestTime(profile.LastSeen, &last)
+			profile.FirstSeen = earliestTime(profile.FirstSeen, &first)
+		}
+
+		summary := fmt.Sprintf("%s: %s, %d Tx", label, FormatUnits(chainTotal, 9, "AVAX"), len(txs))
+		if txResp.NextPageToken != "" {
+			summary += "+"
+		}
+		details = append(details, summary)
+	}
+
+	profile.Balance = FormatUnits(total, 9, "AVAX")
+	setRawBalance(profile, total, 9, "AVAX")
+	profile.IsActive = total.Sign() > 0 || profile.TxCount > 0
+
+	if !profile.IsActive && !fetchFailed {
+		profile.ValidationDetails = "Inactive Account (No Tx History)"
+		return profile, nil
+	}
+	profile.ValidationDetails = strings.Join(details, " | ")
+	return profile, nil
+}
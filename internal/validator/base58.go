@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// ---------------------------------------------------------
+// BASE58 / BASE58CHECK (Bitcoin alphabet)
+// ---------------------------------------------------------
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Index = func() [256]int {
+	var idx [256]int
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		idx[base58Alphabet[i]] = i
+	}
+	return idx
+}()
+
+// base58Decode decodes a base58 string, preserving leading zero bytes.
+func base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("base58: empty string")
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		v := base58Index[s[i]]
+		if v < 0 {
+			return nil, errors.New("base58: invalid character")
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// base58Encode is the inverse of base58Decode.
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < len(b) && b[i] == 0; i++ {
+		out = append(out, '1')
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// base58CheckEncode appends the 4-byte double-SHA256 checksum and encodes.
+func base58CheckEncode(payload []byte) string {
+	return base58Encode(append(append([]byte{}, payload...), doubleSHA256(payload)[:4]...))
+}
+
+// base58CheckDecode verifies the trailing 4-byte double-SHA256 checksum and
+// returns the payload (version bytes included, checksum stripped).
+func base58CheckDecode(s string) ([]byte, error) {
+	raw, err := base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < 5 {
+		return nil, errors.New("base58check: too short")
+	}
+
+	payload, checksum := raw[:len(raw)-4], raw[len(raw)-4:]
+	if !bytes.Equal(doubleSHA256(payload)[:4], checksum) {
+		return nil, errors.New("base58check: invalid checksum")
+	}
+	return payload, nil
+}
+
+func doubleSHA256(b []byte) []byte {
+	first := sha256.Sum256(b)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+// decodeLegacyAddress validates a Bitcoin-family legacy address
+// (1-byte version + 20-byte hash160, Base58Check) and returns its version.
+func decodeLegacyAddress(address string) (byte, bool) {
+	payload, err := base58CheckDecode(address)
+	if err != nil || len(payload) != 21 {
+		return 0, false
+	}
+	return payload[0], true
+}
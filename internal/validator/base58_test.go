@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Encode/decode vectors from Bitcoin Core's base58_encode_decode.json.
+func TestBase58Vectors(t *testing.T) {
+	tests := []struct {
+		hex, b58 string
+	}{
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+		{"572e4794", "3EFU7m"},
+		{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+		{"10c8511e", "Rt5zm"},
+		{"00000000000000000000", "1111111111"},
+		{"000111d38e5fc9071ffcd20b4a763cc9ae4f252bb4e48fd66a835e252ada93ff480d6dd43dc62a641155a5", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.b58, func(t *testing.T) {
+			raw, _ := hex.DecodeString(tt.hex)
+			if got := base58Encode(raw); got != tt.b58 {
+				t.Errorf("base58Encode = %s", got)
+			}
+			got, err := base58Decode(tt.b58)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.hex {
+				t.Errorf("base58Decode = %x", got)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "0OIl", "3SEo3LWLoPntC0"} {
+		if _, err := base58Decode(s); err == nil {
+			t.Errorf("base58Decode(%q) succeeded", s)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	// The genesis block's coinbase address: version 0 + its hash160
+	payload, err := base58CheckDecode("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(payload); got != "0062e907b15cbf27d5425399ebf6f0fb50ebb88f18" {
+		t.Errorf("payload %s", got)
+	}
+	if got := base58CheckEncode(payload); got != "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa" {
+		t.Errorf("base58CheckEncode = %s", got)
+	}
+
+	for _, s := range []string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", // Last character changed
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN",  // Truncated
+		"1111",                               // Shorter than a checksum
+	} {
+		if _, err := base58CheckDecode(s); err == nil {
+			t.Errorf("base58CheckDecode(%q) succeeded", s)
+		}
+	}
+}
+
+func TestBitcoinAddressType(t *testing.T) {
+	tests := []struct {
+		address string
+		testnet bool
+		want    string
+	}{
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false, "P2PKH"},
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", false, "P2PKH"},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", false, "P2SH"},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false, "P2WPKH"},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", false, "P2WSH"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", false, "P2TR"},
+		{"BC1SW50QGDZ25J", false, "WITNESS_V16"},
+		{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true, "P2PKH"},
+		{"2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", true, "P2SH"},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", true, "P2WPKH"},
+
+		// Valid checksums on the wrong network
+		{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", false, ""},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true, ""},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", false, ""},
+		// Broken checksums
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false, ""},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			b := &BitcoinStrategy{Testnet: tt.testnet}
+			if got := b.addressType(tt.address); got != tt.want {
+				t.Errorf("addressType = %q, want %q", got, tt.want)
+			}
+			if got := b.IsValidSyntax(tt.address); got != (tt.want != "") {
+				t.Errorf("IsValidSyntax = %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// BECH32 / BECH32M (BIP-173 / BIP-350)
+// ---------------------------------------------------------
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+type bech32Encoding int
+
+const (
+	encodingBech32  bech32Encoding = 1          // BIP-173 checksum constant
+	encodingBech32m bech32Encoding = 0x2bc830a3 // BIP-350 checksum constant
+)
+
+var bech32Gen = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Decode validates the checksum and returns the lower-cased HRP,
+// the 5-bit data part (checksum stripped) and which checksum variant matched.
+// maxLen is 90 for BIP-173 addresses; pass a larger value for formats
+// like Lightning invoices that exceed it.
+func bech32Decode(s string, maxLen int) (string, []byte, bech32Encoding, error) {
+	if len(s) > maxLen {
+		return "", nil, 0, errors.New("bech32: too long")
+	}
+	lower, upper := strings.ToLower(s), strings.ToUpper(s)
+	if s != lower && s != upper {
+		return "", nil, 0, errors.New("bech32: mixed case")
+	}
+	s = lower
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, errors.New("bech32: invalid separator position")
+	}
+
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, errors.New("bech32: invalid hrp character")
+		}
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		idx := strings.IndexByte(bech32Charset, s[i])
+		if idx < 0 {
+			return "", nil, 0, errors.New("bech32: invalid data character")
+		}
+		data = append(data, byte(idx))
+	}
+
+	enc := bech32Encoding(bech32Polymod(append(bech32HRPExpand(hrp), data...)))
+	if enc != encodingBech32 && enc != encodingBech32m {
+		return "", nil, 0, errors.New("bech32: invalid checksum")
+	}
+
+	return hrp, data[:len(data)-6], enc, nil
+}
+
+// bech32Encode builds the string for an HRP and 5-bit data (checksum added).
+func bech32Encode(hrp string, data []byte, enc bech32Encoding) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ uint32(enc)
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// convertBits regroups a byte slice between bit widths (e.g. 5 -> 8).
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<toBits - 1
+	var out []byte
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, errors.New("convertbits: value out of range")
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, errors.New("convertbits: invalid padding")
+	}
+	return out, nil
+}
+
+// decodeSegwitAddress validates a BIP-173/BIP-350 Segwit address for the
+// given HRP ("bc" mainnet, "tb" testnet) and returns the witness version
+// and program. v0 must use bech32 with a 20/32-byte program; v1+ (Taproot
+// and future versions) must use bech32m.
+func decodeSegwitAddress(address, wantHRP string) (int, []byte, error) {
+	hrp, data, enc, err := bech32Decode(address, 90)
+	if err != nil {
+		return 0, nil, err
+	}
+	if hrp != wantHRP {
+		return 0, nil, errors.New("segwit: wrong hrp")
+	}
+	if len(data) < 1 {
+		return 0, nil, errors.New("segwit: empty data")
+	}
+
+	version := int(data[0])
+	if version > 16 {
+		return 0, nil, errors.New("segwit: invalid witness version")
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, errors.New("segwit: invalid program length")
+	}
+
+	if version == 0 {
+		if enc != encodingBech32 {
+			return 0, nil, errors.New("segwit: v0 requires bech32")
+		}
+		if len(program) != 20 && len(program) != 32 {
+			return 0, nil, errors.New("segwit: invalid v0 program length")
+		}
+	} else if enc != encodingBech32m {
+		return 0, nil, errors.New("segwit: v1+ requires bech32m")
+	}
+
+	return version, program, nil
+}
+
+// encodeSegwitAddress is the inverse of decodeSegwitAddress.
+func encodeSegwitAddress(hrp string, version int, program []byte) (string, error) {
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	enc := encodingBech32
+	if version > 0 {
+		enc = encodingBech32m
+	}
+	return bech32Encode(hrp, append([]byte{byte(version)}, data...), enc), nil
+}
@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from BIP-173 and BIP-350.
+
+func TestBech32DecodeVectors(t *testing.T) {
+	valid := []struct {
+		s   string
+		enc bech32Encoding
+	}{
+		{"A12UEL5L", encodingBech32},
+		{"a12uel5l", encodingBech32},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", encodingBech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", encodingBech32},
+		{"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j", encodingBech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", encodingBech32},
+		{"?1ezyfcl", encodingBech32},
+		{"A1LQFN3A", encodingBech32m},
+		{"a1lqfn3a", encodingBech32m},
+		{"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", encodingBech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", encodingBech32m},
+		{"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8", encodingBech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", encodingBech32m},
+		{"?1v759aa", encodingBech32m},
+	}
+	for _, tt := range valid {
+		t.Run(tt.s, func(t *testing.T) {
+			_, _, enc, err := bech32Decode(tt.s, 90)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enc != tt.enc {
+				t.Errorf("encoding %#x, want %#x", enc, tt.enc)
+			}
+		})
+	}
+
+	invalid := []string{
+		// BIP-173
+		"\x201nwldj5", // HRP character out of range
+		"\x7f1axkwrx", // HRP character out of range
+		"\x801eym55h", // HRP character out of range
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", // Too long
+		"pzry9x0s0muk",  // No separator
+		"1pzry9x0s0muk", // Empty HRP
+		"x1b4n0q5v",     // Invalid data character
+		"li1dgmt3",      // Checksum too short
+		"de1lg7wt\xff",  // Invalid character in checksum
+		"A1G7SGD8",      // Checksum calculated with uppercase HRP
+		"10a06t8",       // Empty HRP
+		"1qzzfhee",      // Empty HRP
+		// BIP-350
+		"\x201xj0phk",
+		"\x7f1g6xzxy",
+		"\x801vctc34",
+		"an84characterslonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11d6pts4",
+		"qyrz8wqd2c9m",
+		"1qyrz8wqd2c9m",
+		"y1b0jsk6g",
+		"lt1igcx5c0",
+		"in1muywd",
+		"mm1crxm3i",
+		"au1s5cgom",
+		"M1VUXWEZ",
+		"16plkw9",
+		"1p2gdwpf",
+	}
+	for _, s := range invalid {
+		if _, _, _, err := bech32Decode(s, 90); err == nil {
+			t.Errorf("bech32Decode(%q) succeeded", s)
+		}
+	}
+}
+
+// segwitScript is the scriptPubKey for a witness version and program.
+func segwitScript(version int, program []byte) string {
+	op := byte(0)
+	if version > 0 {
+		op = byte(0x50 + version)
+	}
+	return hex.EncodeToString(append([]byte{op, byte(len(program))}, program...))
+}
+
+func TestSegwitAddressVectors(t *testing.T) {
+	valid := []struct {
+		address, hrp, script string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "bc", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "tb", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", "bc", "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", "bc", "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "bc", "5210751e76e8199196d454941c45d1b3a323"},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "tb", "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "tb", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "bc", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+	for _, tt := range valid {
+		t.Run(tt.address, func(t *testing.T) {
+			version, program, err := decodeSegwitAddress(tt.address, tt.hrp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := segwitScript(version, program); got != tt.script {
+				t.Errorf("script %s, want %s", got, tt.script)
+			}
+		})
+	}
+
+	invalid := []string{
+		"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", // Invalid HRP
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", // v1 with a bech32 checksum
+		"tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", // v2 with a bech32 checksum
+		"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", // v16 with a bech32 checksum
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",                     // v0 with a bech32m checksum
+		"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", // v0 with a bech32m checksum
+		"bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", // Invalid data character
+		"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", // Witness version 17
+		"bc1pw5dgrnzv", // 1-byte program
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", // 41-byte program
+		"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P",                                         // 16-byte v0 program
+		"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq",               // Mixed case
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf",             // More than 4 bits of padding
+		"tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j",               // Non-zero padding
+		"bc1gmk9yu", // Empty data
+	}
+	for _, address := range invalid {
+		for _, hrp := range []string{"bc", "tb"} {
+			if _, _, err := decodeSegwitAddress(address, hrp); err == nil {
+				t.Errorf("decodeSegwitAddress(%q, %q) succeeded", address, hrp)
+			}
+		}
+	}
+}
+
+func TestSegwitAddressRoundTrip(t *testing.T) {
+	for _, address := range []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+	} {
+		hrp := address[:2]
+		version, program, err := decodeSegwitAddress(address, hrp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := encodeSegwitAddress(hrp, version, program); err != nil || got != address {
+			t.Errorf("encodeSegwitAddress = %q, %v; want %q", got, err, address)
+		}
+	}
+}
@@ -3,26 +3,81 @@ package validator
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 )
 
-type BitcoinStrategy struct{}
+type BitcoinStrategy struct {
+	Testnet bool // testnet3: m/n (P2PKH), 2 (P2SH), tb1 (Segwit) via Blockstream Esplora
+
+	// Esplora-compatible indexer (Blockstream.info, self-hosted mempool.space).
+	// Empty means Blockstream.info for the configured network.
+	EsploraURL string
+	// Query Esplora first and fall back to blockchain.info, instead of the reverse
+	PreferEsplora bool
+	// Extended keys: unused addresses in a row before a branch is done (0 = 20)
+	GapLimit int
+	// Remainder hops followed for peel chains (0 = off, max 20)
+	PeelHops int
+}
+
+// Default public Esplora endpoints
+const (
+	blockstreamMainnetURL = "https://blockstream.info/api"
+	blockstreamTestnetURL = "https://blockstream.info/testnet/api"
+)
 
 func (b *BitcoinStrategy) Name() string {
 	return "BITCOIN"
 }
 
-func (b *BitcoinStrategy) IsValidSyntax(address string) bool {
-	cleanAddr := strings.TrimSpace(address)
-	// Regex covers Legacy (1...), Script (3...), Segwit (bc1q...), Taproot (bc1p...)
-	legacy := regexp.MustCompile(`^[1][a-km-zA-HJ-NP-Z1-9]{25,34}$`)
-	script := regexp.MustCompile(`^[3][a-km-zA-HJ-NP-Z1-9]{25,34}$`)
-	bech32 := regexp.MustCompile(`(?i)^bc1[a-z0-9]{25,87}$`)
+// addressType decodes the address (Base58Check or bech32/bech32m) and
+// returns its script type, or "" if it is not a valid address for the
+// configured network.
+func (b *BitcoinStrategy) addressType(address string) string {
+	segwitHRP, p2pkh, p2sh := "bc", byte(0x00), byte(0x05)
+	if b.Testnet {
+		segwitHRP, p2pkh, p2sh = "tb", 0x6f, 0xc4
+	}
+
+	// Segwit (bc1q...) / Taproot (bc1p...): full bech32/bech32m checksum check
+	if strings.HasPrefix(strings.ToLower(address), segwitHRP+"1") {
+		version, program, err := decodeSegwitAddress(address, segwitHRP)
+		switch {
+		case err != nil:
+			return ""
+		case version == 0 && len(program) == 20:
+			return "P2WPKH"
+		case version == 0:
+			return "P2WSH"
+		case version == 1 && len(program) == 32:
+			return "P2TR"
+		default:
+			return fmt.Sprintf("WITNESS_V%d", version)
+		}
+	}
 
-	return legacy.MatchString(cleanAddr) || script.MatchString(cleanAddr) || bech32.MatchString(cleanAddr)
+	// Legacy (1... / m, n...) and Script (3... / 2...): Base58Check + version byte
+	switch version, ok := decodeLegacyAddress(address); {
+	case !ok:
+		return ""
+	case version == p2pkh:
+		return "P2PKH"
+	case version == p2sh:
+		return "P2SH"
+	}
+	return ""
+}
+
+func (b *BitcoinStrategy) IsValidSyntax(address string) bool {
+	address = strings.TrimSpace(address)
+	if isExtendedPubKeySyntax(address) {
+		key, err := parseExtendedPubKey(address)
+		return err == nil && key.Version.Testnet == b.Testnet
+	}
+	return b.addressType(address) != ""
 }
 
 func (b *BitcoinStrategy) FetchState(ctx context.Context, address string, _ string) (*WalletProfile, error) {
@@ -30,61 +85,259 @@ func (b *BitcoinStrategy) FetchState(ctx context.Context, address string, _ stri
 	// We ignore the configParam (API Key) here.
 	
 	cleanAddr := strings.TrimSpace(address)
+
+	// xpub/ypub/zpub: profile the whole wallet behind the key
+	if isExtendedPubKeySyntax(cleanAddr) {
+		key, err := parseExtendedPubKey(cleanAddr)
+		if err != nil {
+			return nil, err
+		}
+		profile := &WalletProfile{Address: cleanAddr, Network: "BITCOIN", IsValid: true, Testnet: b.Testnet}
+		b.fetchExtendedKey(ctx, key, profile)
+		return profile, nil
+	}
+
+	// Bech32 is case-insensitive but indexers and the watchlist store lowercase
+	if lower := strings.ToLower(cleanAddr); strings.HasPrefix(lower, "bc1") || strings.HasPrefix(lower, "tb1") {
+		cleanAddr = lower
+	}
 	profile := &WalletProfile{
-		Address: cleanAddr,
-		Network: "BITCOIN",
-		IsValid: true,
+		Address:     cleanAddr,
+		Network:     "BITCOIN",
+		IsValid:     true,
+		Testnet:     b.Testnet,
+		AddressType: b.addressType(cleanAddr),
+	}
+
+	client := httpClient(10 * time.Second)
+
+	// 1. Balance & History (first provider that answers wins)
+	providers := b.providers(client)
+	if err := loadBalance(ctx, providers, cleanAddr, 8, 8, "BTC", profile); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("Indexer Error: %v", err)
+		return profile, nil
+	}
+	if _, err := loadHistory(ctx, providers, cleanAddr, profile); err != nil {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("History Unavailable: %v", err))
+	}
+
+	// 2. UTXO set (dust, fragmentation, consolidation); empty without a balance
+	if profile.BalanceRaw != nil && profile.BalanceRaw.Sign() > 0 {
+		if err := loadUTXOs(ctx, providers, cleanAddr, profile); err != nil {
+			profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("UTXOs Unavailable: %v", err))
+		}
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	url := fmt.Sprintf("https://blockchain.info/rawaddr/%s", cleanAddr)
+	// 3. Peel chain (opt-in): follow large change outputs hop by hop
+	if b.PeelHops > 0 && profile.TxCount > 0 {
+		loadPeelChain(ctx, btcPeelStep(providers), cleanAddr, b.PeelHops, formatSats, profile)
+	}
+
+	return profile, nil
+}
 
-	var respObj struct {
-		FinalBalance int64 `json:"final_balance"` // Satoshis
-		NTx          int   `json:"n_tx"`          // Transaction Count
-		Txs          []struct {
-			Time int64 `json:"time"` // Unix Timestamp
-		} `json:"txs"`
+// providers returns the indexers in failover order. Blockchain.com has no
+// testnet API; Blockstream's Esplora does.
+func (b *BitcoinStrategy) providers(client *http.Client) ProviderChain {
+	esploraURL := b.EsploraURL
+	if esploraURL == "" {
+		esploraURL = blockstreamMainnetURL
+		if b.Testnet {
+			esploraURL = blockstreamTestnetURL
+		}
 	}
 
-	// 1. Fetch Data
+	esplora := &EsploraProvider{Client: client, BaseURL: esploraURL}
+	blockchainInfo := &BlockchainInfoProvider{Client: client}
+	switch {
+	case b.Testnet:
+		return cachedChain("bitcoin:testnet", ProviderChain{esplora})
+	case b.PreferEsplora:
+		return cachedChain("bitcoin", ProviderChain{esplora, blockchainInfo})
+	}
+	return cachedChain("bitcoin", ProviderChain{blockchainInfo, esplora})
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Blockchain.com (rawaddr)
+// ---------------------------------------------------------
+
+// BlockchainInfoProvider reads the Blockchain.com rawaddr API. It needs no
+// key but rate limits aggressively (429).
+type BlockchainInfoProvider struct {
+	Client *http.Client
+
+	cache map[string]*blockchainInfoAddr // One rawaddr call serves balance + history
+}
+
+type blockchainInfoAddr struct {
+	FinalBalance int64 `json:"final_balance"` // Satoshis
+	NTx          int   `json:"n_tx"`          // Transaction Count
+	Txs          []struct {
+		Hash   string `json:"hash"`
+		Time   int64  `json:"time"` // Unix Timestamp
+		Inputs []struct {
+			PrevOut struct {
+				Addr string `json:"addr"`
+			} `json:"prev_out"`
+		} `json:"inputs"`
+		Out []struct {
+			Addr  string `json:"addr"`
+			Value int64  `json:"value"`
+		} `json:"out"`
+	} `json:"txs"`
+}
+
+func (p *BlockchainInfoProvider) Name() string {
+	return "Blockchain.com"
+}
+
+func (p *BlockchainInfoProvider) rawaddr(ctx context.Context, address string) (*blockchainInfoAddr, error) {
+	if cached, ok := p.cache[address]; ok {
+		return cached, nil
+	}
+	var respObj blockchainInfoAddr
 	// Note: Blockchain.com returns 429 if rate limited (limit is strict for free tier).
-	if err := getJSON(ctx, client, url, &respObj); err != nil {
-		profile.ValidationDetails = fmt.Sprintf("Blockchain.com Error: %v", err)
-		return profile, nil
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("https://blockchain.info/rawaddr/%s", address), &respObj); err != nil {
+		return nil, err
+	}
+	if p.cache == nil {
+		p.cache = map[string]*blockchainInfoAddr{}
+	}
+	p.cache[address] = &respObj
+	return &respObj, nil
+}
+
+func (p *BlockchainInfoProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	resp, err := p.rawaddr(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(resp.FinalBalance), nil
+}
+
+func (p *BlockchainInfoProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	resp, err := p.rawaddr(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// rawaddr returns the newest 50 txs; reverse to oldest first
+	hist := &TxHistory{Total: resp.NTx, Complete: len(resp.Txs) >= resp.NTx}
+	for i := len(resp.Txs) - 1; i >= 0; i-- {
+		hist.Txs = append(hist.Txs, Transaction{TimeStamp: resp.Txs[i].Time, Hash: resp.Txs[i].Hash})
 	}
+	return hist, nil
+}
 
-	// 2. Parse Balance (Satoshis -> BTC)
-	profile.Balance = fmt.Sprintf("%.8f BTC", float64(respObj.FinalBalance)/1e8)
-	profile.TxCount = respObj.NTx
-
-	// 3. Determine Status and Dates
-	if respObj.NTx > 0 {
-		profile.IsActive = true
-		profile.ValidationDetails = "Active Account (History Found)"
-
-		// API returns transactions sorted by time (newest first usually), 
-		// but we scan to be safe or just take first/last if confident.
-		// Blockchain.com rawaddr default sort is newest first.
-		
-		if len(respObj.Txs) > 0 {
-			// Last Seen = Time of the first tx in the list (Newest)
-			lastTime := time.Unix(respObj.Txs[0].Time, 0)
-			profile.LastSeen = &lastTime
-
-			// First Seen = Time of the last tx in the list (Oldest)
-			// Note: rawaddr has a limit (default 50). If n_tx > 50, this is the "First Seen *recently*".
-			// To get absolute first seen for huge wallets, you'd need to paginate. 
-			// For this implementation, we take the oldest returned in the batch.
-			firstTime := time.Unix(respObj.Txs[len(respObj.Txs)-1].Time, 0)
-			profile.FirstSeen = &firstTime
-			
-			profile.ValidationDetails += fmt.Sprintf(" | Last Active: %s", lastTime.Format("2006-01-02"))
+// GetFirstSeen pages straight to the oldest tx (offset n_tx-1).
+func (p *BlockchainInfoProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	resp, err := p.rawaddr(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if resp.NTx == 0 {
+		return nil, nil
+	}
+
+	var oldest blockchainInfoAddr
+	url := fmt.Sprintf("https://blockchain.info/rawaddr/%s?limit=1&offset=%d", address, resp.NTx-1)
+	if err := getJSON(ctx, p.Client, url, &oldest); err != nil {
+		return nil, err
+	}
+	if len(oldest.Txs) == 0 {
+		return nil, ErrNotSupported
+	}
+	t := time.Unix(oldest.Txs[0].Time, 0)
+	return &t, nil
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Esplora (Blockstream.info, mempool.space)
+// ---------------------------------------------------------
+
+// EsploraProvider reads an Esplora-compatible indexer.
+type EsploraProvider struct {
+	Client  *http.Client
+	BaseURL string
+
+	cache map[string]*esploraAddr
+}
+
+type esploraStats struct {
+	FundedTxoSum int64 `json:"funded_txo_sum"`
+	SpentTxoSum  int64 `json:"spent_txo_sum"`
+	TxCount      int   `json:"tx_count"`
+}
+
+type esploraAddr struct {
+	ChainStats   esploraStats `json:"chain_stats"`
+	MempoolStats esploraStats `json:"mempool_stats"`
+}
+
+func (p *EsploraProvider) Name() string {
+	return "Esplora"
+}
+
+func (p *EsploraProvider) address(ctx context.Context, address string) (*esploraAddr, error) {
+	if cached, ok := p.cache[address]; ok {
+		return cached, nil
+	}
+	var addrResp esploraAddr
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("%s/address/%s", strings.TrimRight(p.BaseURL, "/"), address), &addrResp); err != nil {
+		return nil, err
+	}
+	if p.cache == nil {
+		p.cache = map[string]*esploraAddr{}
+	}
+	p.cache[address] = &addrResp
+	return &addrResp, nil
+}
+
+// GetBalance returns the confirmed balance.
+func (p *EsploraProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	resp, err := p.address(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(resp.ChainStats.FundedTxoSum - resp.ChainStats.SpentTxoSum), nil
+}
+
+// GetTransactions returns the newest page (25 txs); mempool txs have no
+// block_time and are counted but not timed.
+func (p *EsploraProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	resp, err := p.address(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	hist := &TxHistory{Total: resp.ChainStats.TxCount + resp.MempoolStats.TxCount}
+	if hist.Total == 0 {
+		hist.Complete = true
+		return hist, nil
+	}
+
+	var txs []struct {
+		Txid   string `json:"txid"`
+		Status struct {
+			BlockTime int64 `json:"block_time"`
+		} `json:"status"`
+	}
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("%s/address/%s/txs", strings.TrimRight(p.BaseURL, "/"), address), &txs); err != nil {
+		// Counts are still useful without dates
+		hist.Warnings = append(hist.Warnings, "Tx Dates Unavailable")
+		return hist, nil
+	}
+
+	for i := len(txs) - 1; i >= 0; i-- {
+		if txs[i].Status.BlockTime == 0 {
+			continue
 		}
-	} else {
-		profile.IsActive = false
-		profile.ValidationDetails = "Inactive Account (Zero Transactions)"
+		hist.Txs = append(hist.Txs, Transaction{TimeStamp: txs[i].Status.BlockTime, Hash: txs[i].Txid})
 	}
+	hist.Complete = len(txs) >= hist.Total
+	return hist, nil
+}
 
-	return profile, nil
-}
\ No newline at end of file
+func (p *EsploraProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	return nil, ErrNotSupported
+}
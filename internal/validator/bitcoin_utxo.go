@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// UTXO ANALYSIS (dust, fragmentation, consolidation)
+// ---------------------------------------------------------
+
+const (
+	// Outputs at or below this are dust: they cost about as much to spend
+	// as they are worth, and dusting attacks send exactly such amounts
+	dustThresholdSats = 1000
+	// This many spendable UTXOs means the address is collecting, not spending
+	fragmentedUTXOs = 50
+	// Dust outputs that together look like a dusting attack, not change
+	dustedUTXOs = 3
+	// Dust outputs listed in the profile (the count covers all of them)
+	maxListedDust = 20
+)
+
+// UTXOProvider is implemented by Bitcoin providers that can list the
+// current unspent outputs of an address.
+type UTXOProvider interface {
+	GetUTXOs(ctx context.Context, address string) ([]UTXO, error)
+}
+
+// GetUTXOs returns the UTXO set from the first provider that can list it.
+func (pc ProviderChain) GetUTXOs(ctx context.Context, address string) ([]UTXO, string, []string, error) {
+	return failover(ctx, pc, func(p DataProvider) ([]UTXO, error) {
+		lister, ok := p.(UTXOProvider)
+		if !ok {
+			return nil, ErrNotSupported
+		}
+		return lister.GetUTXOs(ctx, address)
+	})
+}
+
+// loadUTXOs summarizes the address's UTXO set into the profile.
+func loadUTXOs(ctx context.Context, pc ProviderChain, address string, profile *WalletProfile) error {
+	utxos, servedBy, failures, err := pc.GetUTXOs(ctx, address)
+	if err != nil {
+		return err
+	}
+	noteFallback(profile, servedBy, failures)
+
+	profile.UTXOs = summarizeUTXOs(utxos, profile.TxCount)
+	profile.ValidationDetails = appendDetail(profile.ValidationDetails, utxoDetail(profile.UTXOs))
+	return nil
+}
+
+// summarizeUTXOs counts dust and classifies how the address holds its coins.
+// txCount is the address's lifetime tx count (0 if unknown).
+func summarizeUTXOs(utxos []UTXO, txCount int) *UTXOSummary {
+	summary := &UTXOSummary{Count: len(utxos)}
+	total, dust := new(big.Int), new(big.Int)
+	var largest int64
+	for _, u := range utxos {
+		total.Add(total, big.NewInt(u.Value))
+		if u.Value <= dustThresholdSats {
+			summary.DustCount++
+			dust.Add(dust, big.NewInt(u.Value))
+			if len(summary.DustOutputs) < maxListedDust {
+				summary.DustOutputs = append(summary.DustOutputs, u)
+			}
+		}
+		if !u.Confirmed {
+			summary.Unconfirmed++
+		}
+		largest = max(largest, u.Value)
+	}
+	summary.Total = formatSats(total)
+	if summary.DustCount > 0 {
+		summary.DustValue = formatSats(dust)
+	}
+	if len(utxos) > 0 {
+		summary.Largest = formatSats(big.NewInt(largest))
+	}
+
+	switch spendable := summary.Count - summary.DustCount; {
+	case summary.Count == 0:
+		summary.Pattern = "EMPTY"
+	case summary.DustCount >= dustedUTXOs:
+		summary.Pattern = "DUSTED" // Tiny unsolicited outputs waiting to be merged
+	case spendable >= fragmentedUTXOs:
+		summary.Pattern = "FRAGMENTED" // Deposit/merchant style: many receipts, no sweeps
+	case spendable <= 2 && txCount > 2*summary.Count+2:
+		summary.Pattern = "CONSOLIDATED" // Far more history than outputs left: swept together
+	default:
+		summary.Pattern = "NORMAL"
+	}
+	return summary
+}
+
+// utxoDetail is the human-readable ValidationDetails fragment.
+func utxoDetail(s *UTXOSummary) string {
+	if s == nil || s.Count == 0 {
+		return ""
+	}
+	detail := fmt.Sprintf("%d UTXOs", s.Count)
+	if s.DustCount > 0 {
+		detail += fmt.Sprintf(" (%d Dust)", s.DustCount)
+	}
+	switch s.Pattern {
+	case "DUSTED":
+		detail += " - Possible Dusting Attack"
+	case "FRAGMENTED":
+		detail += " - Fragmented (Unconsolidated)"
+	case "CONSOLIDATED":
+		detail += " - Consolidated"
+	}
+	return detail
+}
+
+// ---------------------------------------------------------
+// PROVIDER SUPPORT
+// ---------------------------------------------------------
+
+// GetUTXOs reads Blockchain.com's unspent endpoint (up to 1000 outputs).
+func (p *BlockchainInfoProvider) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
+	var resp struct {
+		UnspentOutputs []struct {
+			TxHash        string `json:"tx_hash_big_endian"`
+			TxOutputN     int    `json:"tx_output_n"`
+			Value         int64  `json:"value"`
+			Confirmations int    `json:"confirmations"`
+		} `json:"unspent_outputs"`
+	}
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("https://blockchain.info/unspent?active=%s&limit=1000", address), &resp); err != nil {
+		return nil, err
+	}
+	utxos := make([]UTXO, 0, len(resp.UnspentOutputs))
+	for _, u := range resp.UnspentOutputs {
+		utxos = append(utxos, UTXO{Txid: u.TxHash, Vout: u.TxOutputN, Value: u.Value, Confirmed: u.Confirmations > 0})
+	}
+	return utxos, nil
+}
+
+// GetUTXOs reads Esplora's address/utxo endpoint. Esplora refuses
+// addresses with very large UTXO sets, and the chain fails over.
+func (p *EsploraProvider) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
+	var resp []struct {
+		Txid   string `json:"txid"`
+		Vout   int    `json:"vout"`
+		Value  int64  `json:"value"`
+		Status struct {
+			Confirmed bool `json:"confirmed"`
+		} `json:"status"`
+	}
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("%s/address/%s/utxo", strings.TrimRight(p.BaseURL, "/"), address), &resp); err != nil {
+		return nil, err
+	}
+	utxos := make([]UTXO, 0, len(resp))
+	for _, u := range resp {
+		utxos = append(utxos, UTXO{Txid: u.Txid, Vout: u.Vout, Value: u.Value, Confirmed: u.Status.Confirmed})
+	}
+	return utxos, nil
+}
+
+// GetUTXOs caches UTXO sets like balances (they change together).
+func (p *cachedProvider) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
+	lister, ok := p.DataProvider.(UTXOProvider)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	c, ttl, _ := currentCache()
+	if c == nil || ttl <= 0 {
+		return lister.GetUTXOs(ctx, address)
+	}
+	key := p.key("utxos", address)
+	if raw, ok := c.Get(key); ok {
+		var utxos []UTXO
+		if json.Unmarshal(raw, &utxos) == nil {
+			return utxos, nil
+		}
+	}
+	utxos, err := lister.GetUTXOs(ctx, address)
+	if err == nil {
+		if raw, err := json.Marshal(utxos); err == nil {
+			c.Set(key, raw, ttl)
+		}
+	}
+	return utxos, err
+}
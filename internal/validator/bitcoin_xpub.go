@@ -0,0 +1,225 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// EXTENDED KEY PROFILING (xpub / ypub / zpub)
+// ---------------------------------------------------------
+
+const (
+	// BIP-44 default: stop a branch after this many unused addresses in a row
+	DefaultGapLimit = 20
+	// Hard cap per branch so a huge wallet can't run forever
+	maxDerivedPerBranch = 500
+	// Parallel per-address lookups when no batch API is available
+	xpubWorkers = 4
+)
+
+// addressActivity is what the indexers know about one derived address.
+type addressActivity struct {
+	Balance *big.Int
+	TxCount int
+	Txs     []Transaction
+	Err     error
+}
+
+// fetchExtendedKey derives receive (0/i) and change (1/i) addresses until
+// GapLimit unused addresses in a row, and aggregates their activity. Every
+// derived address is handed to the investigator for watchlist screening.
+func (b *BitcoinStrategy) fetchExtendedKey(ctx context.Context, key *extendedPubKey, profile *WalletProfile) {
+	gapLimit := b.GapLimit
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+	profile.AddressType = fmt.Sprintf("%s (%s)", strings.ToUpper(key.Version.Prefix), key.Version.ScriptType)
+
+	client := httpClient(10 * time.Second)
+	total := new(big.Int)
+	var txs []Transaction
+	var failures []string
+	derived := 0
+
+	for branch := uint32(0); branch <= 1; branch++ {
+		branchKey, err := key.child(branch)
+		if err != nil {
+			profile.ValidationDetails = fmt.Sprintf("Derivation Failed: %v", err)
+			return
+		}
+
+		gap := 0
+		for start := uint32(0); gap < gapLimit && start < maxDerivedPerBranch; start += uint32(gapLimit) {
+			// 1. Derive the next window of addresses
+			var addrs, paths []string
+			for i := start; i < start+uint32(gapLimit); i++ {
+				child, err := branchKey.child(i)
+				if err != nil {
+					continue // Astronomically rare; BIP-32 says skip the index
+				}
+				addr, err := child.address()
+				if err != nil {
+					continue
+				}
+				addrs = append(addrs, addr)
+				paths = append(paths, fmt.Sprintf("%d/%d", branch, i))
+			}
+			profile.ScreenAddresses = append(profile.ScreenAddresses, addrs...)
+			derived += len(addrs)
+
+			// 2. Look them up (one batch call if the indexer supports it)
+			activity := b.scanAddresses(ctx, client, addrs)
+
+			// 3. Aggregate, stopping at the gap limit
+			for i, addr := range addrs {
+				act := activity[addr]
+				if act == nil || act.Err != nil {
+					if act != nil {
+						failures = append(failures, fmt.Sprintf("%s: %v", paths[i], act.Err))
+					}
+					gap++ // Unknown counts as unused so a dead indexer can't loop forever
+					continue
+				}
+				if act.TxCount == 0 {
+					gap++
+					if gap >= gapLimit {
+						break
+					}
+					continue
+				}
+				gap = 0
+				total.Add(total, act.Balance)
+				profile.TxCount += act.TxCount
+				txs = append(txs, act.Txs...)
+				profile.DerivedAddresses = append(profile.DerivedAddresses, DerivedAddress{
+					Path:    paths[i],
+					Address: addr,
+					Balance: formatSats(act.Balance),
+					TxCount: act.TxCount,
+				})
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	profile.Balance = formatSats(total)
+	setRawBalance(profile, total, 8, "BTC")
+	profile.IsActive = total.Sign() > 0 || profile.TxCount > 0
+
+	// Oldest first; a tx touching two own addresses appears twice (dates only)
+	sort.Slice(txs, func(i, j int) bool { return txs[i].TimeStamp < txs[j].TimeStamp })
+	if len(txs) > 0 {
+		first, last := time.Unix(txs[0].TimeStamp, 0), time.Unix(txs[len(txs)-1].TimeStamp, 0)
+		profile.FirstSeen, profile.LastSeen = &first, &last
+	}
+
+	details := fmt.Sprintf("Extended Key: %d Used / %d Derived Addresses (Gap Limit %d)", len(profile.DerivedAddresses), derived, gapLimit)
+	if profile.FirstSeen != nil {
+		details = appendDetail(details, fmt.Sprintf("Active | First Seen: %s | Last Seen: %s", profile.FirstSeen.Format("2006-01-02"), profile.LastSeen.Format("2006-01-02")))
+	} else if !profile.IsActive {
+		details = appendDetail(details, "Inactive Wallet (No Tx History)")
+	}
+	if len(failures) > 0 {
+		details = appendDetail(details, fmt.Sprintf("%d Lookups Failed (%s)", len(failures), failures[0]))
+	}
+	profile.ValidationDetails = details
+}
+
+// scanAddresses returns the activity of each address. Blockchain.com's
+// multiaddr answers a whole window in one call; otherwise (testnet,
+// PreferEsplora, or multiaddr failing) each address goes through the
+// normal provider chain, a few at a time.
+func (b *BitcoinStrategy) scanAddresses(ctx context.Context, client *http.Client, addrs []string) map[string]*addressActivity {
+	if !b.Testnet && !b.PreferEsplora {
+		if activity, err := blockchainInfoMultiAddr(ctx, client, addrs); err == nil {
+			return activity
+		}
+	}
+
+	var mu sync.Mutex
+	activity := map[string]*addressActivity{}
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < xpubWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			providers := b.providers(client) // Provider caches are per goroutine
+			for addr := range jobs {
+				act := &addressActivity{}
+				if bal, _, _, err := providers.GetBalance(ctx, addr); err != nil {
+					act.Err = err
+				} else if hist, _, _, err := providers.GetTransactions(ctx, addr); err != nil {
+					act.Err = err
+				} else {
+					act.Balance, act.TxCount, act.Txs = bal, hist.Total, hist.Txs
+				}
+				mu.Lock()
+				activity[addr] = act
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, addr := range addrs {
+		jobs <- addr
+	}
+	close(jobs)
+	wg.Wait()
+	return activity
+}
+
+// blockchainInfoMultiAddr looks up many addresses in one call. Txs are the
+// newest 100 across the whole set, so they only serve for dates.
+func blockchainInfoMultiAddr(ctx context.Context, client *http.Client, addrs []string) (map[string]*addressActivity, error) {
+	var resp struct {
+		Addresses []struct {
+			Address      string `json:"address"`
+			FinalBalance int64  `json:"final_balance"`
+			NTx          int    `json:"n_tx"`
+		} `json:"addresses"`
+		Txs []struct {
+			Hash string `json:"hash"`
+			Time int64  `json:"time"`
+		} `json:"txs"`
+	}
+	url := fmt.Sprintf("https://blockchain.info/multiaddr?active=%s&n=100", strings.Join(addrs, "|"))
+	if err := getJSON(ctx, client, url, &resp); err != nil {
+		return nil, err
+	}
+
+	activity := map[string]*addressActivity{}
+	for _, a := range resp.Addresses {
+		activity[a.Address] = &addressActivity{Balance: big.NewInt(a.FinalBalance), TxCount: a.NTx}
+	}
+	for _, addr := range addrs {
+		if activity[addr] == nil {
+			return nil, fmt.Errorf("multiaddr did not return %s", addr)
+		}
+	}
+
+	// Dates only: credit the wallet-wide txs to the first used address
+	for _, addr := range addrs {
+		if act := activity[addr]; act != nil && act.TxCount > 0 {
+			for i := len(resp.Txs) - 1; i >= 0; i-- {
+				act.Txs = append(act.Txs, Transaction{TimeStamp: resp.Txs[i].Time, Hash: resp.Txs[i].Hash})
+			}
+			break
+		}
+	}
+	return activity, nil
+}
+
+// formatSats renders satoshis as BTC with 8 decimals.
+func formatSats(sats *big.Int) string {
+	f := new(big.Float).Quo(new(big.Float).SetInt(sats), big.NewFloat(1e8))
+	return fmt.Sprintf("%.8f BTC", f)
+}
@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// AUTOMATED TRADERS (MEV bots, DEX market makers)
+// ---------------------------------------------------------
+
+// Bot classes
+const (
+	BotClassMEV         = "MEV_BOT"      // Sandwiches, bundles, priority gas bidding
+	BotClassMarketMaker = "MARKET_MAKER" // High-frequency trading through DEXs
+)
+
+const (
+	// Blocks with a buy and a sell around someone else's tx
+	mevMinSandwiches = 2
+	// Blocks with back-to-back txs from the bot (searcher bundles)
+	mevMinBundles = 3
+	// Txs at the top of their block; Etherscan has no priority fee, but
+	// paying for priority (or a Flashbots bundle) is what gets a tx there
+	mevMinTopOfBlock = 5
+	// Txs priced at this multiple of the bot's median gas price (gas auctions)
+	mevGasSpikeMultiple = 3
+	mevMinGasSpikes     = 5
+	// Share of outgoing txs into DEX routers and pools that makes a
+	// high-velocity wallet a market maker
+	marketMakerDeFiShare = 0.8
+	// Fewest txs worth classifying
+	botMinTxs = 20
+)
+
+// DEX routers and aggregators (Ethereum mainnet), labelled DEFI
+var knownDEXRouters = map[string]string{
+	"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap V2 Router",
+	"0xe592427a0aece92de3edee1f18e0157c05861564": "Uniswap V3 Router",
+	"0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45": "Uniswap V3 Router 2",
+	"0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad": "Uniswap Universal Router",
+	"0xd9e1ce17f2641f24ae83637ab66a2cca9c378b9f": "SushiSwap Router",
+	"0x1111111254eeb25477b68fb85ed929f73a960582": "1inch Router V5",
+	"0x111111125421ca6dc452d289314280a0f8842a65": "1inch Router V6",
+	"0xdef1c0ded9bec7f1a1670819833240f027b25eff": "0x Exchange Proxy",
+	"0x9008d19f58aabd9ed0d60971565aa8510560ab41": "CoW Protocol Settlement",
+	"0xba12222222228d8ba445958a75a0704d566bf2c8": "Balancer Vault",
+}
+
+// classifyBot looks at the txs the address initiated (for a contract,
+// the calls it received from its operator). MEV signals need block
+// positions, so only Etherscan histories qualify. defi holds the
+// counterparties labelled DEFI; fast is whether the wallet exceeded a
+// velocity window. Returns nil for anything else.
+func classifyBot(address string, txs []Transaction, isContract bool, defi map[string]bool, fast bool) *BotInfo {
+	address = strings.ToLower(address)
+	var ops []Transaction
+	for _, tx := range txs {
+		if tx.Internal || tx.Block == 0 {
+			continue
+		}
+		if (!isContract && strings.EqualFold(tx.From, address)) || (isContract && strings.EqualFold(tx.To, address)) {
+			ops = append(ops, tx)
+		}
+	}
+	if len(ops) < botMinTxs {
+		return nil
+	}
+
+	b := &BotInfo{}
+
+	// 1. Same-block patterns. Multi-network histories are merged, so a
+	// block is its number and timestamp.
+	type blockKey struct {
+		number, time int64
+	}
+	blocks := map[blockKey][]Transaction{}
+	for _, tx := range ops {
+		k := blockKey{tx.Block, tx.TimeStamp}
+		blocks[k] = append(blocks[k], tx)
+		if tx.TxIndex == 0 {
+			b.TopOfBlock++
+		}
+	}
+	keys := make([]blockKey, 0, len(blocks))
+	for k := range blocks {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].time < keys[j].time })
+	for _, k := range keys {
+		group := blocks[k]
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].TxIndex < group[j].TxIndex })
+		sandwich, bundle := false, false
+		for i := 0; i < len(group) && !sandwich; i++ {
+			for j := i + 1; j < len(group); j++ {
+				gap := group[j].TxIndex - group[i].TxIndex
+				switch {
+				case gap > 1 && strings.EqualFold(group[i].To, group[j].To):
+					// Front-run and back-run with the victim in between
+					sandwich = true
+					b.EvidenceTxs = append(b.EvidenceTxs, group[i].Hash, group[j].Hash)
+				case gap == 1:
+					bundle = true
+				}
+			}
+		}
+		if sandwich {
+			b.Sandwiches++
+		} else if bundle {
+			b.Bundles++
+		}
+	}
+
+	// 2. Gas auctions: bids far above what the bot usually pays
+	var prices []*big.Int
+	for _, tx := range ops {
+		if p, ok := new(big.Int).SetString(tx.GasPrice, 10); ok && p.Sign() > 0 {
+			prices = append(prices, p)
+		}
+	}
+	if len(prices) >= botMinTxs {
+		sorted := append([]*big.Int(nil), prices...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+		spike := new(big.Int).Mul(sorted[len(sorted)/2], big.NewInt(mevGasSpikeMultiple))
+		for _, p := range prices {
+			if p.Cmp(spike) >= 0 {
+				b.GasSpikes++
+			}
+		}
+	}
+
+	if b.Sandwiches >= mevMinSandwiches {
+		b.Signals = append(b.Signals, plural(b.Sandwiches, "Sandwich", "Sandwiches"))
+	}
+	if b.Bundles >= mevMinBundles {
+		b.Signals = append(b.Signals, plural(b.Bundles, "Bundled Block", "Bundled Blocks"))
+	}
+	if b.TopOfBlock >= mevMinTopOfBlock {
+		b.Signals = append(b.Signals, plural(b.TopOfBlock, "Top-of-Block Tx", "Top-of-Block Txs"))
+	}
+	if b.GasSpikes >= mevMinGasSpikes {
+		b.Signals = append(b.Signals, plural(b.GasSpikes, "Priority Gas Bid", "Priority Gas Bids"))
+	}
+	if len(b.Signals) > 0 {
+		b.Class = BotClassMEV
+		return b
+	}
+
+	// 3. Market makers: fast wallets trading almost only through DEXs
+	if !fast || isContract {
+		return nil
+	}
+	intoDeFi := 0
+	for _, tx := range ops {
+		if defi[strings.ToLower(tx.To)] {
+			intoDeFi++
+		}
+	}
+	share := float64(intoDeFi) / float64(len(ops))
+	if share < marketMakerDeFiShare {
+		return nil
+	}
+	b.Class = BotClassMarketMaker
+	b.DeFiPercent = math.Round(share*1000) / 10
+	b.Signals = append(b.Signals, fmt.Sprintf("%.1f%% of %s into DEXs", b.DeFiPercent, plural(len(ops), "Tx", "Txs")))
+	return b
+}
+
+// defiCounterparties collects the counterparties labelled DEFI.
+func defiCounterparties(labels []CounterpartyLabels) map[string]bool {
+	out := map[string]bool{}
+	for _, cp := range labels {
+		for _, l := range cp.Labels {
+			if l.Category == CategoryDeFi {
+				out[cp.Address] = true
+			}
+		}
+	}
+	return out
+}
+
+// botDesc is the reason text.
+func botDesc(b *BotInfo) string {
+	class := "MEV Bot"
+	if b.Class == BotClassMarketMaker {
+		class = "Market Maker"
+	}
+	return fmt.Sprintf("Automated Trader: %s (%s)", class, strings.Join(b.Signals, ", "))
+}
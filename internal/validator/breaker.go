@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// CIRCUIT BREAKERS (one per data provider)
+// ---------------------------------------------------------
+
+// ErrCircuitOpen is returned instead of calling a provider whose breaker
+// tripped; the chain fails over (or degrades to syntax + sanctions) at once.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Breaker states
+const (
+	BreakerClosed   = "CLOSED"    // Calls flow normally
+	BreakerOpen     = "OPEN"      // Calls are skipped until the cooldown ends
+	BreakerHalfOpen = "HALF_OPEN" // One trial call decides whether to close again
+)
+
+// CircuitBreaker trips after Threshold consecutive failures and skips the
+// provider for Cooldown. The first call after that is a trial.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+// Default breaker settings, overridable with SetBreakerConfig
+var (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+var (
+	breakersMu       sync.Mutex
+	breakers         = map[string]*CircuitBreaker{}
+	breakerThreshold = DefaultBreakerThreshold
+	breakerCooldown  = DefaultBreakerCooldown
+)
+
+// SetBreakerConfig sets the threshold and cooldown for every provider's
+// breaker. A threshold below 1 disables the breakers.
+func SetBreakerConfig(threshold int, cooldown time.Duration) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breakerThreshold, breakerCooldown = threshold, cooldown
+	breakers = map[string]*CircuitBreaker{}
+}
+
+// providerBreaker returns the shared breaker for a provider name.
+func providerBreaker(name string) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[name]
+	if !ok {
+		b = &CircuitBreaker{Threshold: breakerThreshold, Cooldown: breakerCooldown}
+		breakers[name] = b
+	}
+	return b
+}
+
+// Allow reports whether a call may go through. After the cooldown, the
+// breaker moves to half-open and lets a trial call through.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Threshold < 1 || b.state != BreakerOpen {
+		return nil
+	}
+	if wait := b.Cooldown - time.Since(b.openedAt); wait > 0 {
+		return fmt.Errorf("%w (retry in %s)", ErrCircuitOpen, max(wait.Round(time.Second), time.Second))
+	}
+	b.state = BreakerHalfOpen
+	return nil
+}
+
+// Record feeds a call's outcome to the breaker and reports whether this
+// call tripped it. Caller cancellations and unsupported queries don't count.
+func (b *CircuitBreaker) Record(err error) (tripped bool) {
+	if errors.Is(err, ErrNotSupported) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Threshold < 1 {
+		return false
+	}
+	if err == nil {
+		b.state, b.failures = BreakerClosed, 0
+		return false
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state != BreakerOpen && b.failures >= b.Threshold) {
+		b.state, b.openedAt = BreakerOpen, time.Now()
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether calls are currently being skipped.
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Threshold >= 1 && b.state == BreakerOpen && time.Since(b.openedAt) < b.Cooldown
+}
+
+// State returns CLOSED, OPEN or HALF_OPEN.
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == "" {
+		return BreakerClosed
+	}
+	return b.state
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// CROSS-CHAIN BRIDGES
+// ---------------------------------------------------------
+
+// A bridge deposit this soon after receiving funds is pass-through
+const rapidBridgeWindow = 24 * 60 * 60 // Seconds
+
+// Known bridge contracts (Ethereum mainnet)
+var knownBridges = map[string]string{
+	"0x3ee18b2214aff97000d974cf647e7c347e8fa585": "Wormhole",
+	"0x98f3c9e6e3face36baad05fe09d375ef1464288b": "Wormhole",
+	"0x8731d54e9d02c286767d56ac03e8037c07e01e98": "Stargate",
+	"0x150f94b44927f078737562f0fcf3c95c01cc2376": "Stargate",
+	"0x5c7bcd6e7de5423a257d81b442095a1a6ced35c5": "Across",
+	"0xe4b679400f0f267212d5d812b95f58c83243ee71": "RenBridge",
+}
+
+// bridgeUsage sums the address's bridge activity. decimals and symbol
+// render the deposited volume.
+func bridgeUsage(address string, txs []Transaction, decimals int, symbol string) []BridgeUsage {
+	byBridge := map[string]*BridgeUsage{}
+	volumes := map[string]*big.Int{}
+	lastFunded := int64(-1) // Time of the latest incoming non-bridge tx
+
+	sorted := append([]Transaction(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].TimeStamp < sorted[j].TimeStamp })
+	for _, tx := range sorted {
+		sent := strings.EqualFold(tx.From, address)
+		party := strings.ToLower(tx.From)
+		if sent {
+			party = strings.ToLower(tx.To)
+		}
+		bridge, isBridge := knownBridges[party]
+		if !isBridge {
+			if !sent && tx.From != "" {
+				lastFunded = tx.TimeStamp
+			}
+			continue
+		}
+
+		usage := byBridge[bridge]
+		if usage == nil {
+			usage = &BridgeUsage{Bridge: bridge}
+			byBridge[bridge] = usage
+			volumes[bridge] = new(big.Int)
+		}
+		if !sent {
+			usage.Releases++
+			continue
+		}
+		usage.Deposits++
+		if v, ok := new(big.Int).SetString(tx.Value, 10); ok {
+			volumes[bridge].Add(volumes[bridge], v)
+		}
+		if lastFunded >= 0 && tx.TimeStamp-lastFunded <= rapidBridgeWindow {
+			usage.Rapid++
+		}
+	}
+
+	var usages []BridgeUsage
+	for bridge, u := range byBridge {
+		f := new(big.Float).Quo(new(big.Float).SetInt(volumes[bridge]), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+		u.Volume = strings.TrimSpace(fmt.Sprintf("%.4f %s", f, symbol))
+		usages = append(usages, *u)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Bridge < usages[j].Bridge })
+	return usages
+}
+
+// bridgeDetail is the human-readable ValidationDetails fragment.
+func bridgeDetail(usages []BridgeUsage) string {
+	var parts []string
+	for _, u := range usages {
+		if u.Deposits > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%s)", u.Bridge, u.Volume))
+		} else {
+			parts = append(parts, u.Bridge)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Bridges: " + strings.Join(parts, ", ")
+}
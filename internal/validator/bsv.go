@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// BSVPrefix marks an address as Bitcoin SV. BSV uses the same legacy
+// address format as BTC, so a bare 1.../3... address is routed to the
+// Bitcoin strategy; "bsv:1..." selects this one.
+const BSVPrefix = "bsv:"
+
+type BSVStrategy struct{}
+
+func (b *BSVStrategy) Name() string {
+	return "BITCOIN_SV"
+}
+
+func (b *BSVStrategy) IsValidSyntax(address string) bool {
+	cleanAddr := strings.TrimSpace(address)
+	if !strings.HasPrefix(strings.ToLower(cleanAddr), BSVPrefix) {
+		return false
+	}
+	// Legacy only: BSV never adopted Segwit (no bc1 addresses)
+	version, ok := decodeLegacyAddress(cleanAddr[len(BSVPrefix):])
+	return ok && (version == 0x00 || version == 0x05)
+}
+
+func (b *BSVStrategy) FetchState(ctx context.Context, address string, _ string) (*WalletProfile, error) {
+	// WhatsOnChain's free tier does not require an API key.
+	cleanAddr := strings.TrimSpace(address)
+	if strings.HasPrefix(strings.ToLower(cleanAddr), BSVPrefix) {
+		cleanAddr = cleanAddr[len(BSVPrefix):]
+	}
+
+	profile := &WalletProfile{
+		Address:     cleanAddr,
+		Network:     "BITCOIN_SV",
+		IsValid:     true,
+		AddressType: "P2PKH",
+	}
+	if version, _ := decodeLegacyAddress(cleanAddr); version == 0x05 {
+		profile.AddressType = "P2SH"
+	}
+
+	client := httpClient(10 * time.Second)
+	baseURL := "https://api.whatsonchain.com/v1/bsv/main"
+
+	// 1. Balance (Satoshis -> BSV)
+	var balResp struct {
+		Confirmed   int64 `json:"confirmed"`
+		Unconfirmed int64 `json:"unconfirmed"`
+	}
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/address/%s/balance", baseURL, cleanAddr), &balResp); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("WhatsOnChain Error: %v", err)
+		return profile, nil
+	}
+	profile.Balance = fmt.Sprintf("%.8f BSV", float64(balResp.Confirmed+balResp.Unconfirmed)/1e8)
+	setRawBalance(profile, big.NewInt(balResp.Confirmed+balResp.Unconfirmed), 8, "BSV")
+
+	// 2. History (tx hash + block height; height <= 0 means unconfirmed)
+	var history []struct {
+		TxHash string `json:"tx_hash"`
+		Height int64  `json:"height"`
+	}
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/address/%s/history", baseURL, cleanAddr), &history); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("History Fetch Failed: %v", err)
+		return profile, nil
+	}
+
+	profile.TxCount = len(history)
+	if profile.TxCount == 0 {
+		profile.IsActive = balResp.Confirmed+balResp.Unconfirmed > 0
+		profile.ValidationDetails = "Inactive Account (Zero Transactions)"
+		return profile, nil
+	}
+	profile.IsActive = true
+	profile.ValidationDetails = "Active Account (History Found)"
+
+	// 3. First / Last Seen from the lowest and highest confirmed block
+	var minHeight, maxHeight int64
+	for _, h := range history {
+		if h.Height <= 0 {
+			continue
+		}
+		if minHeight == 0 || h.Height < minHeight {
+			minHeight = h.Height
+		}
+		if h.Height > maxHeight {
+			maxHeight = h.Height
+		}
+	}
+
+	blockTime := func(height int64) *time.Time {
+		var block struct {
+			Time int64 `json:"time"`
+		}
+		if err := getJSON(ctx, client, fmt.Sprintf("%s/block/height/%d", baseURL, height), &block); err != nil || block.Time == 0 {
+			return nil
+		}
+		t := time.Unix(block.Time, 0)
+		return &t
+	}
+
+	if minHeight > 0 {
+		profile.FirstSeen = blockTime(minHeight)
+		profile.LastSeen = profile.FirstSeen
+		if maxHeight != minHeight {
+			profile.LastSeen = blockTime(maxHeight)
+		}
+	}
+	if profile.LastSeen != nil {
+		profile.ValidationDetails += fmt.Sprintf(" | Last Active: %s", profile.LastSeen.Format("2006-01-02"))
+	}
+
+	return profile, nil
+}
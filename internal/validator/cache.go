@@ -0,0 +1,288 @@
+package validator
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+)
+
+// ---------------------------------------------------------
+// RESPONSE CACHE (balances + tx histories, per chain/address)
+// ---------------------------------------------------------
+
+// Cache stores provider responses. Implementations must be safe for
+// concurrent use; a miss or a backend error both return ok=false.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// Default TTLs, overridable with SetCache
+const (
+	DefaultBalanceTTL = 1 * time.Minute
+	DefaultHistoryTTL = 10 * time.Minute
+)
+
+var (
+	cacheMu       sync.Mutex
+	responseCache Cache = NewMemoryCache()
+	balanceTTL          = DefaultBalanceTTL
+	historyTTL          = DefaultHistoryTTL
+)
+
+// SetCache replaces the response cache and its TTLs. A nil cache (or a
+// zero TTL) disables caching for that kind of response.
+func SetCache(c Cache, balance, history time.Duration) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	responseCache, balanceTTL, historyTTL = c, balance, history
+}
+
+func currentCache() (Cache, time.Duration, time.Duration) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return responseCache, balanceTTL, historyTTL
+}
+
+// cachedChain wraps each provider so its balances and histories are cached
+// under chain + provider + address. chain must tell networks apart
+// (e.g. "evm:1", "bitcoin:testnet").
+func cachedChain(chain string, pc ProviderChain) ProviderChain {
+	if c, _, _ := currentCache(); c == nil {
+		return pc
+	}
+	wrapped := make(ProviderChain, len(pc))
+	for i, p := range pc {
+		wrapped[i] = &cachedProvider{DataProvider: p, chain: chain}
+	}
+	return wrapped
+}
+
+// cachedProvider serves GetBalance/GetTransactions from the cache when it
+// can. GetFirstSeen is rare and passes through.
+type cachedProvider struct {
+	DataProvider
+	chain string
+}
+
+func (p *cachedProvider) key(kind, address string) string {
+	return fmt.Sprintf("profiler:%s:%s:%s:%s", kind, p.chain, p.Name(), address)
+}
+
+func (p *cachedProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	c, ttl, _ := currentCache()
+	if c == nil || ttl <= 0 {
+		return p.DataProvider.GetBalance(ctx, address)
+	}
+	key := p.key("balance", address)
+	if raw, ok := c.Get(key); ok {
+		if bal, ok := new(big.Int).SetString(string(raw), 10); ok {
+			slog.Debug("💾 [CACHE] Hit", "key", key)
+			return bal, nil
+		}
+	}
+	bal, err := p.DataProvider.GetBalance(ctx, address)
+	if err == nil {
+		c.Set(key, []byte(bal.String()), ttl)
+	}
+	return bal, err
+}
+
+func (p *cachedProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	c, _, ttl := currentCache()
+	if c == nil || ttl <= 0 {
+		return p.DataProvider.GetTransactions(ctx, address)
+	}
+	key := p.key("history", address)
+	if raw, ok := c.Get(key); ok {
+		var hist TxHistory
+		if json.Unmarshal(raw, &hist) == nil {
+			slog.Debug("💾 [CACHE] Hit", "key", key)
+			return &hist, nil
+		}
+	}
+	hist, err := p.DataProvider.GetTransactions(ctx, address)
+	if err == nil {
+		if raw, err := json.Marshal(hist); err == nil {
+			c.Set(key, raw, ttl)
+		}
+	}
+	return hist, err
+}
+
+// ---------------------------------------------------------
+// BACKEND: In-process
+// ---------------------------------------------------------
+
+// MemoryCache is a TTL map, shared by every lookup in the process.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty in-process cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]memoryEntry{}}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{value: value, expires: time.Now().Add(ttl)}
+}
+
+// ---------------------------------------------------------
+// BACKEND: Redis (shared across runs and replicas)
+// ---------------------------------------------------------
+
+// RedisCache keeps entries in Redis through go-redis's pooled client.
+// Failures degrade to cache misses.
+type RedisCache struct {
+	client  *redis.Client
+	Timeout time.Duration // Per call, dial included
+}
+
+// NewRedisCache parses redis://[[user]:password@]host[:port][/db] (or
+// rediss:// for TLS). The connection is made on first use.
+func NewRedisCache(rawURL string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	timeout := 2 * time.Second
+	opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = timeout, timeout, timeout
+	return &RedisCache{client: redis.NewClient(opts), Timeout: timeout}, nil
+}
+
+func (r *RedisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Debug("⚠️ Redis GET failed", "key", key, "err", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (r *RedisCache) Set(key string, value []byte, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		slog.Debug("⚠️ Redis SET failed", "key", key, "err", err)
+	}
+}
+
+// Close releases the connection pool.
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
+
+// ---------------------------------------------------------
+// BACKEND: Local SQLite file (shared across CLI runs on one machine)
+// ---------------------------------------------------------
+
+// FileCache keeps entries in a local SQLite database, one row per key with
+// its expiry, so they outlive the process (the CLI's profile cache) and
+// concurrent runs share them. Expired rows are deleted whenever an entry
+// is written; database errors degrade to cache misses.
+type FileCache struct {
+	Path string
+	db   *sql.DB
+}
+
+const fileCacheSchema = `CREATE TABLE IF NOT EXISTS cache_entries (
+	key        TEXT PRIMARY KEY,
+	value      BLOB NOT NULL,
+	expires_at INTEGER NOT NULL -- Unix milliseconds
+);
+CREATE INDEX IF NOT EXISTS cache_entries_expires_at ON cache_entries(expires_at);`
+
+// NewFileCache opens (or creates) the cache database at path.
+func NewFileCache(path string) (*FileCache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	// WAL and a busy timeout let several CLI runs use the cache at once
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(fileCacheSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &FileCache{Path: path, db: db}, nil
+}
+
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	var value []byte
+	err := f.db.QueryRow("SELECT value FROM cache_entries WHERE key = ? AND expires_at > ?", key, time.Now().UnixMilli()).Scan(&value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Debug("⚠️ Cache read failed", "path", f.Path, "key", key, "err", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	now := time.Now()
+	tx, err := f.db.Begin()
+	if err != nil {
+		slog.Warn("⚠️ Cache write failed", "path", f.Path, "err", err)
+		return
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM cache_entries WHERE expires_at <= ?", now.UnixMilli()); err != nil {
+		slog.Warn("⚠️ Cache write failed", "path", f.Path, "err", err)
+		return
+	}
+	_, err = tx.Exec(`INSERT INTO cache_entries(key, value, expires_at) VALUES(?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`,
+		key, value, now.Add(ttl).UnixMilli())
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		slog.Warn("⚠️ Cache write failed", "path", f.Path, "err", err)
+	}
+}
+
+// Close releases the database handle.
+func (f *FileCache) Close() error {
+	return f.db.Close()
+}
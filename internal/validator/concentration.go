@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"math"
+	"sort"
+)
+
+// ---------------------------------------------------------
+// COUNTERPARTY CONCENTRATION
+// ---------------------------------------------------------
+
+// Fewer loaded txs than this say nothing about concentration
+const minConcentrationTxs = 5
+
+// counterpartyConcentration summarizes txs (dust already excluded), or
+// returns nil if there are too few to judge.
+func counterpartyConcentration(address string, txs []Transaction) *ConcentrationInfo {
+	if len(txs) < minConcentrationTxs {
+		return nil
+	}
+	weights := counterpartyWeights(address, txs)
+	if len(weights) == 0 {
+		return nil
+	}
+	parties := make([]string, 0, len(weights))
+	for party := range weights {
+		parties = append(parties, party)
+	}
+	sort.Slice(parties, func(i, j int) bool {
+		if weights[parties[i]] != weights[parties[j]] {
+			return weights[parties[i]] > weights[parties[j]]
+		}
+		return parties[i] < parties[j]
+	})
+
+	info := &ConcentrationInfo{Counterparties: len(parties), TopCounterparty: parties[0]}
+	var top5 float64
+	for _, party := range parties[:min(5, len(parties))] {
+		top5 += weights[party]
+	}
+	info.Top1Percent = math.Round(weights[parties[0]]*10000) / 100
+	info.Top5Percent = math.Round(top5*10000) / 100
+	return info
+}
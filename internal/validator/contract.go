@@ -1,50 +1,66 @@
 package validator
 
-import (
-	"context"
-	"time"
-)
+import "github.com/piyushdaiya/crypto-profiler/pkg/profile"
 
-type WalletProfile struct {
-	Address           string     `json:"address"`
-	Network           string     `json:"network"`
-	IsValid           bool       `json:"is_valid"`
-	ValidationDetails string     `json:"validation_details"`
-	IsActive          bool       `json:"is_active"`
-	Balance           string     `json:"balance"`
-	TxCount           int        `json:"tx_count"`
-	FirstSeen         *time.Time `json:"first_seen,omitempty"`
-	LastSeen          *time.Time `json:"last_seen,omitempty"`
+// The profile and the types around it are defined in pkg/profile, so code
+// outside this module can build and read them; the engine uses them under
+// the same names.
+type (
+	ChainStrategy = profile.ChainStrategy
+	Transaction   = profile.Transaction
 
-	// --- NEW: Advanced Risk Scoring ---
-	RiskScore     float64      `json:"risk_score"`     // Combined Score (0-100)
-	RiskGrade     string       `json:"risk_grade"`     // EXCELLENT, NEUTRAL, FAILING, etc.
-	RiskBreakdown RiskCategory `json:"risk_breakdown"` // Fraud, Reputation, Lending
-	RiskReasons   []RiskReason `json:"risk_reasons"`   // Explainable offsets
-}
+	WalletProfile      = profile.WalletProfile
+	CachedProfile      = profile.CachedProfile
+	DerivedAddress     = profile.DerivedAddress
+	UTXOSummary        = profile.UTXOSummary
+	UTXO               = profile.UTXO
+	ChainActivity      = profile.ChainActivity
+	LightningInfo      = profile.LightningInfo
+	LightningInvoice   = profile.LightningInvoice
+	SafeInfo           = profile.SafeInfo
+	SafeOwner          = profile.SafeOwner
+	TokenBalance       = profile.TokenBalance
+	StablecoinActivity = profile.StablecoinActivity
+	NFTCollection      = profile.NFTCollection
+	TokenApproval      = profile.TokenApproval
+	RiskCategory       = profile.RiskCategory
+	RiskReason         = profile.RiskReason
+	Evidence           = profile.Evidence
 
-type RiskCategory struct {
-	Fraud      float64 `json:"fraud_risk"`
-	Reputation float64 `json:"reputation_risk"`
-	Lending    float64 `json:"lending_risk"`
-}
+	Alert              = profile.Alert
+	Decision           = profile.Decision
+	SanctionedEntity   = profile.SanctionedEntity
+	RiskTrend          = profile.RiskTrend
+	ContractRisk       = profile.ContractRisk
+	PoisoningInfo      = profile.PoisoningInfo
+	Lookalike          = profile.Lookalike
+	ConcentrationInfo  = profile.ConcentrationInfo
+	PeelChainInfo      = profile.PeelChainInfo
+	CounterpartyLabels = profile.CounterpartyLabels
+	ExchangeLink       = profile.ExchangeLink
+	BridgeUsage        = profile.BridgeUsage
+	VelocityWindow     = profile.VelocityWindow
+	TemporalProfile    = profile.TemporalProfile
+	IntervalStats      = profile.IntervalStats
+	BotInfo            = profile.BotInfo
+	FlashLoanInfo      = profile.FlashLoanInfo
+	SybilInfo          = profile.SybilInfo
+	WashTradingInfo    = profile.WashTradingInfo
+	HopExposure        = profile.HopExposure
+	ProbeMatch         = profile.ProbeMatch
 
-type RiskReason struct {
-	Category    string  `json:"category"` // "FRAUD", "REPUTATION"
-	Description string  `json:"description"`
-	Offset      float64 `json:"offset"`    // e.g. +15.5 or -5.0
-}
+	HistoryStore     = profile.HistoryStore
+	ScoreRecord      = profile.ScoreRecord
+	MonitorStore     = profile.MonitorStore
+	MonitoredAddress = profile.MonitoredAddress
+	MonitorAlert     = profile.MonitorAlert
+	Labels           = profile.Labels
+	Label            = profile.Label
 
-type Transaction struct {
-	TimeStamp int64  `json:"timeStamp"`
-	From      string `json:"from"`
-	To        string `json:"to"`
-	Value     string `json:"value"`
-	Hash      string `json:"hash"`
-}
+	WatchlistClient      = profile.WatchlistClient
+	BatchWatchlistClient = profile.BatchWatchlistClient
+	EngineResponse       = profile.EngineResponse
+)
 
-type ChainStrategy interface {
-	Name() string
-	IsValidSyntax(address string) bool
-	FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error)
-}
\ No newline at end of file
+// RiskSchemaVersion versions the risk fields of the profile.
+const RiskSchemaVersion = profile.RiskSchemaVersion
@@ -0,0 +1,230 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CosmosChain describes one Cosmos SDK chain, keyed by its bech32 prefix.
+type CosmosChain struct {
+	Prefix   string // bech32 HRP, e.g. "osmo"
+	Name     string // chain registry name, e.g. "osmosis"
+	LCD      string // REST (LCD) base URL
+	Denom    string // native staking denom, e.g. "uosmo"
+	Symbol   string
+	Decimals int
+}
+
+// DefaultCosmosChains covers the most common Cosmos SDK chains via the
+// public cosmos.directory REST proxy. Override an endpoint with
+// COSMOS_LCD_<PREFIX> (e.g. COSMOS_LCD_OSMO=https://lcd.internal).
+var DefaultCosmosChains = []CosmosChain{
+	{Prefix: "cosmos", Name: "cosmoshub", LCD: "https://rest.cosmos.directory/cosmoshub", Denom: "uatom", Symbol: "ATOM", Decimals: 6},
+	{Prefix: "osmo", Name: "osmosis", LCD: "https://rest.cosmos.directory/osmosis", Denom: "uosmo", Symbol: "OSMO", Decimals: 6},
+	{Prefix: "celestia", Name: "celestia", LCD: "https://rest.cosmos.directory/celestia", Denom: "utia", Symbol: "TIA", Decimals: 6},
+	{Prefix: "juno", Name: "juno", LCD: "https://rest.cosmos.directory/juno", Denom: "ujuno", Symbol: "JUNO", Decimals: 6},
+	{Prefix: "akash", Name: "akash", LCD: "https://rest.cosmos.directory/akash", Denom: "uakt", Symbol: "AKT", Decimals: 6},
+	{Prefix: "stars", Name: "stargaze", LCD: "https://rest.cosmos.directory/stargaze", Denom: "ustars", Symbol: "STARS", Decimals: 6},
+	{Prefix: "inj", Name: "injective", LCD: "https://rest.cosmos.directory/injective", Denom: "inj", Symbol: "INJ", Decimals: 18},
+	{Prefix: "dydx", Name: "dydx", LCD: "https://rest.cosmos.directory/dydx", Denom: "adydx", Symbol: "DYDX", Decimals: 18},
+}
+
+type CosmosStrategy struct {
+	Chains []CosmosChain // nil = DefaultCosmosChains
+}
+
+func (c *CosmosStrategy) Name() string {
+	return "COSMOS"
+}
+
+func (c *CosmosStrategy) chains() []CosmosChain {
+	if c.Chains != nil {
+		return c.Chains
+	}
+	return DefaultCosmosChains
+}
+
+// chainFor decodes the bech32 address and returns the matching chain.
+func (c *CosmosStrategy) chainFor(address string) (CosmosChain, bool) {
+	hrp, data, enc, err := bech32Decode(strings.TrimSpace(address), 90)
+	if err != nil || enc != encodingBech32 {
+		return CosmosChain{}, false
+	}
+
+	// Account (20 bytes) or module/contract (32 bytes) addresses
+	payload, err := convertBits(data, 5, 8, false)
+	if err != nil || (len(payload) != 20 && len(payload) != 32) {
+		return CosmosChain{}, false
+	}
+
+	for _, chain := range c.chains() {
+		if chain.Prefix == hrp {
+			if override := os.Getenv("COSMOS_LCD_" + strings.ToUpper(chain.Prefix)); override != "" {
+				chain.LCD = override
+			}
+			return chain, true
+		}
+	}
+	return CosmosChain{}, false
+}
+
+func (c *CosmosStrategy) IsValidSyntax(address string) bool {
+	_, ok := c.chainFor(address)
+	return ok
+}
+
+func (c *CosmosStrategy) FetchState(ctx context.Context, address string, _ string) (*WalletProfile, error) {
+	cleanAddr := strings.ToLower(strings.TrimSpace(address))
+	profile := &WalletProfile{
+		Address: cleanAddr,
+		Network: "COSMOS",
+		IsValid: true,
+	}
+
+	chain, ok := c.chainFor(cleanAddr)
+	if !ok {
+		profile.IsValid = false
+		profile.ValidationDetails = "Unsupported Cosmos prefix"
+		return profile, nil
+	}
+
+	client := httpClient(15 * time.Second)
+	baseURL := strings.TrimRight(chain.LCD, "/")
+	label := fmt.Sprintf("Chain: %s", chain.Name)
+
+	// 1. Balance
+	var balResp struct {
+		Balances []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balances"`
+	}
+	balURL := fmt.Sprintf("%s/cosmos/bank/v1beta1/balances/%s?pagination.limit=200", baseURL, cleanAddr)
+	if err := getJSON(ctx, client, balURL, &balResp); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("%s | LCD Error: %v", label, err)
+		return profile, nil
+	}
+
+	amount := new(big.Int)
+	for _, b := range balResp.Balances {
+		if b.Denom == chain.Denom {
+			amount.SetString(b.Amount, 10)
+		}
+	}
+	profile.Balance = FormatUnits(amount, chain.Decimals, chain.Symbol)
+	setRawBalance(profile, amount, chain.Decimals, chain.Symbol)
+	if amount.Sign() > 0 || len(balResp.Balances) > 0 {
+		profile.IsActive = true
+	}
+
+	// 2. Account Sequence (number of signed txs)
+	var acctResp struct {
+		Account json.RawMessage `json:"account"`
+	}
+	acctURL := fmt.Sprintf("%s/cosmos/auth/v1beta1/accounts/%s", baseURL, cleanAddr)
+	if err := getJSON(ctx, client, acctURL, &acctResp); err == nil {
+		profile.TxCount = cosmosSequence(acctResp.Account)
+		if profile.TxCount > 0 {
+			profile.IsActive = true
+		}
+	}
+
+	// 3. First / Last Seen (requires tx indexing on the node; best effort)
+	first, firstErr := c.txTime(ctx, client, baseURL, cleanAddr, "ORDER_BY_ASC")
+	last, _ := c.txTime(ctx, client, baseURL, cleanAddr, "ORDER_BY_DESC")
+	if first != nil {
+		profile.FirstSeen = first
+		profile.IsActive = true
+	}
+	if last != nil {
+		profile.LastSeen = last
+	}
+
+	switch {
+	case profile.LastSeen != nil:
+		profile.ValidationDetails = fmt.Sprintf("%s | Active | Last Seen: %s", label, profile.LastSeen.Format("2006-01-02"))
+	case profile.IsActive:
+		profile.ValidationDetails = fmt.Sprintf("%s | Active", label)
+		if firstErr != nil {
+			profile.ValidationDetails += " | Tx History Unavailable"
+		}
+	default:
+		profile.ValidationDetails = fmt.Sprintf("%s | Inactive Account (No Tx History)", label)
+	}
+
+	return profile, nil
+}
+
+// txTime returns the timestamp of the oldest/newest tx sent by address.
+func (c *CosmosStrategy) txTime(ctx context.Context, client *http.Client, baseURL, address, order string) (*time.Time, error) {
+	event := fmt.Sprintf("message.sender='%s'", address)
+	params := url.Values{}
+	params.Set("query", event)  // SDK v0.50+
+	params.Set("events", event) // older SDKs
+	params.Set("order_by", order)
+	params.Set("pagination.limit", "1")
+	params.Set("limit", "1")
+	params.Set("page", "1")
+
+	var txResp struct {
+		TxResponses []struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"tx_responses"`
+	}
+	if err := getJSON(ctx, client, baseURL+"/cosmos/tx/v1beta1/txs?"+params.Encode(), &txResp); err != nil {
+		return nil, err
+	}
+	if len(txResp.TxResponses) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, txResp.TxResponses[0].Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// cosmosSequence digs the sequence out of the various account types
+// (BaseAccount, vesting accounts, module accounts).
+func cosmosSequence(raw json.RawMessage) int {
+	var acct struct {
+		Sequence    string `json:"sequence"`
+		BaseAccount *struct {
+			Sequence string `json:"sequence"`
+		} `json:"base_account"`
+		BaseVestingAccount *struct {
+			BaseAccount struct {
+				Sequence string `json:"sequence"`
+			} `json:"base_account"`
+		} `json:"base_vesting_account"`
+	}
+	if err := json.Unmarshal(raw, &acct); err != nil {
+		return 0
+	}
+
+	seq := acct.Sequence
+	if acct.BaseAccount != nil {
+		seq = acct.BaseAccount.Sequence
+	}
+	if acct.BaseVestingAccount != nil {
+		seq = acct.BaseVestingAccount.BaseAccount.Sequence
+	}
+	n, _ := strconv.Atoi(seq)
+	return n
+}
+
+// FormatUnits renders an integer base-unit amount (e.g. uatom) with the
+// given number of decimals: 1234567, 6, "ATOM" -> "1.234567 ATOM".
+func FormatUnits(amount *big.Int, decimals int, symbol string) string {
+	f := new(big.Float).SetInt(amount)
+	f.Quo(f, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	return fmt.Sprintf("%.*f %s", decimals, f, symbol)
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Counterparty is an address a profile points at, and why.
+type Counterparty struct {
+	Address string `json:"address"`
+	Why     string `json:"why"`
+}
+
+// Counterparties collects the addresses a profile points at, each once:
+// labeled counterparties, exchange links, the top counterparty, the
+// sanctioned entity's other addresses and the risk reasons' evidence
+// (highest offset first). The address itself is left out.
+func Counterparties(p *WalletProfile) []Counterparty {
+	var out []Counterparty
+	seen := map[string]bool{historyAddress(p.Address): true}
+	add := func(address, why string) {
+		if address == "" || seen[historyAddress(address)] {
+			return
+		}
+		seen[historyAddress(address)] = true
+		out = append(out, Counterparty{address, why})
+	}
+
+	for _, c := range p.CounterpartyLabels {
+		var labels []string
+		for _, l := range c.Labels {
+			labels = append(labels, fmt.Sprintf("%s (%s)", l.Entity, l.Category))
+		}
+		add(c.Address, fmt.Sprintf("%s, %d txs", strings.Join(labels, ", "), c.Txs))
+	}
+	for _, e := range p.ExchangeLinks {
+		add(e.Address, fmt.Sprintf("%s %s, %d sent / %d received", e.Entity, strings.ToLower(strings.ReplaceAll(e.Via, "_", " ")), e.Sent, e.Received))
+	}
+	if c := p.Concentration; c != nil {
+		add(c.TopCounterparty, fmt.Sprintf("Top counterparty, %.0f%% of volume", c.Top1Percent))
+	}
+	if e := p.SanctionedEntity; e != nil {
+		for _, a := range e.Transacted {
+			add(a, "Co-listed with "+e.Name+", transacted")
+		}
+		for _, a := range e.CoListed {
+			add(a, "Co-listed with "+e.Name)
+		}
+	}
+	reasons := append([]RiskReason(nil), p.RiskReasons...)
+	sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].Offset > reasons[j].Offset })
+	for _, r := range reasons {
+		if r.Evidence != nil {
+			for _, a := range r.Evidence.Addresses {
+				add(a, "Evidence for "+r.RuleID)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ---------------------------------------------------------
+// DECISIONS (the policy's final action on a scored profile)
+// ---------------------------------------------------------
+
+// Decision actions
+const (
+	DecisionApprove = "APPROVE"
+	DecisionReview  = "REVIEW"
+	DecisionReject  = "REJECT"
+)
+
+var decisionActions = []string{DecisionApprove, DecisionReview, DecisionReject}
+
+// DecisionRules map a scored profile to an action. They are checked in
+// order: sanctions (always REJECT), reject_score, an incomplete screening,
+// alerts at or above review_alerts, review_score; anything else is
+// APPROVE.
+type DecisionRules struct {
+	RejectScore  float64 `json:"reject_score"`  // Min risk score to reject
+	ReviewScore  float64 `json:"review_score"`  // Min risk score to review
+	ReviewAlerts string  `json:"review_alerts"` // Alerts this severe or worse mean review; "" ignores alerts
+	Incomplete   string  `json:"incomplete"`    // Action when the screening was incomplete
+}
+
+// DefaultDecisionRules returns the built-in decisions: review from the
+// WARNING grade up, reject from FAILING.
+func DefaultDecisionRules() DecisionRules {
+	return DecisionRules{RejectScore: 60, ReviewScore: 35, ReviewAlerts: "HIGH", Incomplete: DecisionReview}
+}
+
+// validate lists the problems of the decision rules.
+func (d DecisionRules) validate() []string {
+	var problems []string
+	if !(0 < d.ReviewScore && d.ReviewScore <= d.RejectScore && d.RejectScore <= 100) {
+		problems = append(problems, "decisions must satisfy 0 < review_score <= reject_score <= 100")
+	}
+	if _, ok := alertSeverities[d.ReviewAlerts]; !ok && d.ReviewAlerts != "" {
+		problems = append(problems, "decisions.review_alerts must be LOW, MEDIUM, HIGH, CRITICAL or empty")
+	}
+	if !slices.Contains(decisionActions, d.Incomplete) {
+		problems = append(problems, "decisions.incomplete must be APPROVE, REVIEW or REJECT")
+	}
+	return problems
+}
+
+// Decide maps a scored profile to its decision under rules. Invalid
+// profiles get none.
+func Decide(rules RiskRules, p *WalletProfile) *Decision {
+	if p == nil || !p.IsValid {
+		return nil
+	}
+	d := rules.Decisions
+	ids := map[string]bool{}
+	for _, r := range p.RiskReasons {
+		ids[r.RuleID] = true
+	}
+
+	switch {
+	case ids["sanctions"] || ids["sanctioned_safe_owner"]:
+		return &Decision{Action: DecisionReject, Rule: "sanctions", Reason: "Sanctioned Address"}
+	case p.RiskScore >= d.RejectScore:
+		return &Decision{Action: DecisionReject, Rule: "decisions.reject_score", Reason: fmt.Sprintf("Risk Score %.1f, Reject Threshold %g", p.RiskScore, d.RejectScore)}
+	}
+
+	incomplete := ""
+	switch errs := ProviderErrors(p); {
+	case p.Partial:
+		incomplete = "Offline: Syntax and Sanctions Checked Only"
+	case ids["watchlist_unavailable"]:
+		incomplete = "Sanctions Check Skipped: Watchlist Engine Unavailable"
+	case len(errs) > 0:
+		incomplete = "Provider Errors: " + errs[0]
+	}
+	if incomplete != "" {
+		return &Decision{Action: d.Incomplete, Rule: "decisions.incomplete", Reason: incomplete}
+	}
+
+	if d.ReviewAlerts != "" {
+		for _, a := range p.Alerts { // Most severe first
+			if alertSeverities[a.Severity] <= alertSeverities[d.ReviewAlerts] {
+				return &Decision{Action: DecisionReview, Rule: "decisions.review_alerts", Reason: fmt.Sprintf("%s Alert %s: %s", a.Severity, a.Trigger, a.Message)}
+			}
+		}
+	}
+	if p.RiskScore >= d.ReviewScore {
+		return &Decision{Action: DecisionReview, Rule: "decisions.review_score", Reason: fmt.Sprintf("Risk Score %.1f, Review Threshold %g", p.RiskScore, d.ReviewScore)}
+	}
+	return &Decision{Action: DecisionApprove, Rule: "decisions.review_score", Reason: fmt.Sprintf("Risk Score %.1f, Below Review Threshold %g", p.RiskScore, d.ReviewScore)}
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// DORMANCY REACTIVATION (old wallets suddenly moving funds)
+// ---------------------------------------------------------
+
+const (
+	// Activity this soon after the gap counts as the reactivation
+	reactivationWindow = 30 * 24 * time.Hour
+	daysPerMonth       = 30.44
+)
+
+// reactivation is the longest quiet period in a history and what moved
+// right after it.
+type reactivation struct {
+	Months      float64
+	At          time.Time // First tx after the gap
+	Moved       *big.Int  // Outgoing value in the window (base units); nil if unknown
+	HasOutgoing bool      // An outgoing tx was seen (false if direction is unknown)
+}
+
+// findReactivation returns the longest gap of at least minMonths between
+// consecutive txs, or nil.
+func findReactivation(address string, txs []Transaction, minMonths float64) *reactivation {
+	if len(txs) < 2 || minMonths <= 0 {
+		return nil
+	}
+	sorted := append([]Transaction(nil), txs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].TimeStamp < sorted[j].TimeStamp })
+
+	best := -1
+	var bestGap int64
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].TimeStamp == 0 {
+			continue // Untimed (mempool) txs
+		}
+		if gap := sorted[i].TimeStamp - sorted[i-1].TimeStamp; gap > bestGap {
+			best, bestGap = i, gap
+		}
+	}
+	months := float64(bestGap) / 86400 / daysPerMonth
+	if best < 0 || months < minMonths {
+		return nil
+	}
+
+	r := &reactivation{Months: months, At: time.Unix(sorted[best].TimeStamp, 0).UTC()}
+	end := sorted[best].TimeStamp + int64(reactivationWindow.Seconds())
+	for _, tx := range sorted[best:] {
+		if tx.TimeStamp > end {
+			break
+		}
+		if tx.From == "" || !strings.EqualFold(tx.From, address) {
+			continue
+		}
+		r.HasOutgoing = true
+		if v, ok := new(big.Int).SetString(tx.Value, 10); ok {
+			if r.Moved == nil {
+				r.Moved = new(big.Int)
+			}
+			r.Moved.Add(r.Moved, v)
+		}
+	}
+	return r
+}
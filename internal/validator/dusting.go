@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// DUSTING ATTACKS (tiny unsolicited transfers)
+// ---------------------------------------------------------
+
+// DefaultDustThresholds are the largest incoming transfers (in whole units
+// of the native asset) still treated as dust, per symbol.
+var DefaultDustThresholds = map[string]float64{
+	"BTC":  0.00001, // 1,000 sats, as for UTXOs
+	"ETH":  0.00001,
+	"BNB":  0.00005,
+	"POL":  0.01,
+	"AVAX": 0.001,
+}
+
+// dustFilter returns a test for incoming dust transfers to the profiled
+// address, or nil if no threshold applies to its asset. Multi-network
+// profiles without a common symbol use the strictest chain's threshold.
+func dustFilter(rules RiskRules, profile *WalletProfile) func(Transaction) bool {
+	symbols := []string{profile.Symbol}
+	if profile.Symbol == "" {
+		symbols = nil
+		for _, c := range profile.Chains {
+			symbols = append(symbols, c.Symbol)
+		}
+	}
+	limit := math.Inf(1)
+	for _, symbol := range symbols {
+		if t, ok := rules.DustThresholds[symbol]; ok {
+			limit = math.Min(limit, t)
+		}
+	}
+	if math.IsInf(limit, 1) {
+		return nil
+	}
+	decimals := profile.Decimals
+	if decimals == 0 {
+		decimals = 18 // Multi-network EVM profiles carry no decimals
+	}
+	maxDust, _ := new(big.Float).Mul(big.NewFloat(limit), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))).Int(nil)
+
+	return func(tx Transaction) bool {
+		if tx.Internal || strings.EqualFold(tx.From, profile.Address) || !strings.EqualFold(tx.To, profile.Address) {
+			return false
+		}
+		v, ok := new(big.Int).SetString(tx.Value, 10)
+		return ok && v.Sign() > 0 && v.Cmp(maxDust) <= 0
+	}
+}
@@ -0,0 +1,61 @@
+package validator
+
+import "math/big"
+
+// ---------------------------------------------------------
+// ED25519 CURVE CHECK (Solana wallet vs PDA)
+// ---------------------------------------------------------
+
+var (
+	ed25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	// d = -121665 / 121666 mod p
+	ed25519D = func() *big.Int {
+		num := new(big.Int).Sub(ed25519P, big.NewInt(121665))
+		den := new(big.Int).ModInverse(big.NewInt(121666), ed25519P)
+		return num.Mul(num, den).Mod(num, ed25519P)
+	}()
+	ed25519Legendre = new(big.Int).Rsh(new(big.Int).Sub(ed25519P, big.NewInt(1)), 1)
+)
+
+// isOnEd25519Curve reports whether a 32-byte compressed Edwards point
+// decompresses to a valid curve point. Solana wallets are ed25519 public
+// keys (on-curve); Program Derived Addresses are deliberately off-curve.
+// Mirrors curve25519-dalek's CompressedEdwardsY::decompress.
+func isOnEd25519Curve(key []byte) bool {
+	if len(key) != 32 {
+		return false
+	}
+
+	// y is little-endian with the top bit holding the sign of x
+	le := make([]byte, 32)
+	for i := range key {
+		le[31-i] = key[i]
+	}
+	le[0] &= 0x7f
+	y := new(big.Int).SetBytes(le)
+	y.Mod(y, ed25519P)
+
+	// x^2 = (y^2 - 1) / (d*y^2 + 1)
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, ed25519P)
+
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	u.Mod(u, ed25519P)
+
+	v := new(big.Int).Mul(ed25519D, y2)
+	v.Add(v, big.NewInt(1))
+	v.Mod(v, ed25519P)
+
+	vInv := new(big.Int).ModInverse(v, ed25519P)
+	if vInv == nil {
+		return false
+	}
+	x2 := u.Mul(u, vInv)
+	x2.Mod(x2, ed25519P)
+
+	if x2.Sign() == 0 {
+		return true
+	}
+	// Euler's criterion: x2 must be a quadratic residue for x to exist
+	return new(big.Int).Exp(x2, ed25519Legendre, ed25519P).Cmp(big.NewInt(1)) == 0
+}
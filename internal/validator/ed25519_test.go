@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestEd25519OnCurve(t *testing.T) {
+	// Public keys from RFC 8032 section 7.1
+	for _, pub := range []string{
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"278117fc144c72340f67d0f2316e8386ceffbf2b2428c9c51fef7c597f1d426e",
+	} {
+		key, _ := hex.DecodeString(pub)
+		if !isOnEd25519Curve(key) {
+			t.Errorf("RFC 8032 key %s is off-curve", pub)
+		}
+	}
+
+	// Any key crypto/ed25519 generates
+	for i := 0; i < 64; i++ {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0], seed[31] = byte(i), byte(255-i)
+		pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+		if !isOnEd25519Curve(pub) {
+			t.Errorf("generated key %x is off-curve", []byte(pub))
+		}
+	}
+
+	// Small y, with and without the sign bit, against a square root found
+	// the RFC 8032 way rather than by Euler's criterion
+	for y := 0; y < 64; y++ {
+		key := make([]byte, 32)
+		key[0] = byte(y)
+		want := hasEd25519X(big.NewInt(int64(y)))
+		if got := isOnEd25519Curve(key); got != want {
+			t.Errorf("y=%d: got %v, want %v", y, got, want)
+		}
+		key[31] |= 0x80
+		if got := isOnEd25519Curve(key); got != want {
+			t.Errorf("y=%d with sign bit: got %v, want %v", y, got, want)
+		}
+	}
+
+	for _, key := range [][]byte{nil, make([]byte, 31), make([]byte, 33)} {
+		if isOnEd25519Curve(key) {
+			t.Errorf("%d-byte key is on-curve", len(key))
+		}
+	}
+}
+
+// hasEd25519X recovers x for y as RFC 8032 section 5.1.3 does: a
+// candidate root of u/v, times sqrt(-1) if needed, then squared to check.
+func hasEd25519X(y *big.Int) bool {
+	p := ed25519P
+	y2 := new(big.Int).Mul(y, y)
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	u.Mod(u, p)
+	v := new(big.Int).Mul(ed25519D, y2)
+	v.Add(v, big.NewInt(1)).Mod(v, p)
+	x2 := new(big.Int).Mul(u, new(big.Int).ModInverse(v, p))
+	x2.Mod(x2, p)
+
+	exp := new(big.Int).Add(p, big.NewInt(3))
+	x := new(big.Int).Exp(x2, exp.Rsh(exp, 3), p)
+	if new(big.Int).Exp(x, big.NewInt(2), p).Cmp(x2) != 0 {
+		exp := new(big.Int).Sub(p, big.NewInt(1))
+		sqrtM1 := new(big.Int).Exp(big.NewInt(2), exp.Rsh(exp, 2), p)
+		x.Mul(x, sqrtM1).Mod(x, p)
+	}
+	return new(big.Int).Exp(x, big.NewInt(2), p).Cmp(x2) == 0
+}
+
+func TestSolanaAddressType(t *testing.T) {
+	tests := []struct {
+		address, want string
+	}{
+		{"11111111111111111111111111111111", "WALLET"},             // y = 0: on-curve
+		{"8opHzTAnfzRpPEx21XtnrVTX28YQuCpAjcn1PczScKh", "PDA"},     // y = 2: off-curve
+		{"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", "WALLET"}, // A real wallet
+		{"1111111111111111111111111111111", ""},                    // 31 bytes
+		{"0OIl", ""},
+	}
+	for _, tt := range tests {
+		if got := solanaAddressType(tt.address); got != tt.want {
+			t.Errorf("solanaAddressType(%s) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
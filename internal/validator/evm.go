@@ -3,16 +3,122 @@ package validator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-type EVMStrategy struct{}
+// evmHexRegex matches a 0x-prefixed 20-byte hex address, in any case.
+var evmHexRegex = regexp.MustCompile(`^0x[a-fA-F0-9]{40}$`)
+
+// EVMChain is one network reachable through the Etherscan v2 multichain API.
+type EVMChain struct {
+	ID     string // Etherscan v2 chainid
+	Name   string
+	Symbol string // Native gas token
+}
+
+// DefaultEVMChains is the set profiled when EVM_CHAINS=all.
+var DefaultEVMChains = []EVMChain{
+	{ID: "1", Name: "Ethereum", Symbol: "ETH"},
+	{ID: "137", Name: "Polygon", Symbol: "POL"},
+	{ID: "56", Name: "BSC", Symbol: "BNB"},
+	{ID: "42161", Name: "Arbitrum", Symbol: "ETH"},
+	{ID: "10", Name: "Optimism", Symbol: "ETH"},
+	{ID: "8453", Name: "Base", Symbol: "ETH"},
+	{ID: "43114", Name: "Avalanche-C", Symbol: "AVAX"},
+}
+
+// TestnetEVMChains are the testnet counterparts used in testnet mode.
+var TestnetEVMChains = []EVMChain{
+	{ID: "11155111", Name: "Sepolia", Symbol: "ETH"},
+	{ID: "80002", Name: "Amoy", Symbol: "POL"},
+	{ID: "97", Name: "BSC-Testnet", Symbol: "tBNB"},
+	{ID: "421614", Name: "Arbitrum-Sepolia", Symbol: "ETH"},
+	{ID: "11155420", Name: "Optimism-Sepolia", Symbol: "ETH"},
+	{ID: "84532", Name: "Base-Sepolia", Symbol: "ETH"},
+	{ID: "43113", Name: "Fuji", Symbol: "AVAX"},
+}
+
+// ParseEVMChains turns a comma-separated list of chain IDs or names
+// ("1,polygon,base") into chains. "all" selects DefaultEVMChains (or
+// TestnetEVMChains when testnet is set) and an empty spec returns nil
+// (Ethereum mainnet / Sepolia only).
+func ParseEVMChains(spec string, testnet bool) ([]EVMChain, error) {
+	known := DefaultEVMChains
+	if testnet {
+		known = TestnetEVMChains
+	}
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(spec, "all") {
+		return known, nil
+	}
+
+	var chains []EVMChain
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		found := false
+		for _, c := range known {
+			if c.ID == part || strings.EqualFold(c.Name, part) {
+				chains = append(chains, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			// Any other Etherscan v2 chainid is accepted as-is
+			if _, err := strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("unknown EVM chain %q", part)
+			}
+			chains = append(chains, EVMChain{ID: part, Name: "Chain " + part, Symbol: "NATIVE"})
+		}
+	}
+	return chains, nil
+}
+
+type EVMStrategy struct {
+	// Chains to profile. Empty means Ethereum mainnet only; more than one
+	// chain switches to multi-network mode with per-chain activity.
+	Chains []EVMChain
+
+	Testnet bool // Default to Sepolia instead of Ethereum mainnet
+
+	SkipTokens     bool // Native balance only (saves up to 21 API calls per chain)
+	FetchNFTs      bool // Also summarize ERC-721/1155 holdings (2 API calls per chain)
+	FetchApprovals bool // Also list live token approvals (2 + up to 20 API calls per chain)
+	FetchDeposits  bool // Also attribute exchange deposit addresses (up to 5 API calls per chain)
+	CheckTokens    bool // Also check held tokens for honeypot levers (up to 40 API calls per chain)
+
+	FetchWashTrading bool // Also look for wash-trading clusters (up to 12 API calls per chain)
+	FetchSybil       bool // Also check the funder and its other wallets (up to 6 API calls per chain)
+
+	MaxTxs int // History cap per chain and list; 0 means DefaultMaxEVMTxs
+
+	// Remainder hops followed for peel chains (0 = off, max 20)
+	PeelHops int
+
+	// Counterparty hops walked for indirect exposure (0 = off, max 3).
+	// Each hop fetches up to 10 more histories per address.
+	ExposureHops int
+
+	// JSON-RPC endpoint used when no Etherscan key is configured
+	// (core.Config.EvmRPC). Empty means a public Ethereum/Sepolia node.
+	RPCURL string
+}
 
 func (e *EVMStrategy) Name() string {
 	return "EVM (Etherscan)"
@@ -20,154 +126,596 @@ func (e *EVMStrategy) Name() string {
 
 func (e *EVMStrategy) IsValidSyntax(address string) bool {
 	cleanAddr := strings.TrimSpace(address)
-	regex := regexp.MustCompile(`^0x[a-fA-F0-9]{40}$`)
-	return regex.MatchString(cleanAddr)
+	return evmHexRegex.MatchString(cleanAddr) && validEIP55(cleanAddr)
 }
 
 func (e *EVMStrategy) FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error) {
 	cleanAddr := strings.TrimSpace(address)
-	
+
+	client := httpClient(15 * time.Second)
+
+	// Keyless mode can only reach the one network behind the RPC URL
+	if len(e.Chains) > 1 && apiKey != "" {
+		return e.fetchMultiChain(ctx, client, cleanAddr, apiKey), nil
+	}
+
+	chain := DefaultEVMChains[0]
+	if e.Testnet {
+		chain = TestnetEVMChains[0]
+	}
+	if len(e.Chains) == 1 {
+		chain = e.Chains[0]
+	}
+	profile, investigationTxs := e.fetchChain(ctx, client, chain, cleanAddr, apiKey)
+	if investigationTxs == nil {
+		return profile, nil
+	}
+
+	// ---------------------------------------------------------
+	// CALL 3: THE INVESTIGATOR
+	// ---------------------------------------------------------
+	// UPDATED: Now calls Investigate with only 2 arguments.
+	// The HTTP client inside Investigate handles the engine connection.
+	PriceProfile(ctx, profile) // USD value for the holdings heuristics
+	Investigate(profile, investigationTxs)
+
+	return profile, nil
+}
+
+// fetchMultiChain profiles the address on every configured chain and
+// aggregates the results. The investigator sees the union of all txs.
+func (e *EVMStrategy) fetchMultiChain(ctx context.Context, client *http.Client, cleanAddr, apiKey string) *WalletProfile {
 	profile := &WalletProfile{
 		Address: cleanAddr,
 		Network: "EVM",
 		IsValid: true,
+		Testnet: e.Testnet,
 	}
 
-	if apiKey == "" {
+	var allTxs []Transaction
+	var balances, activeOn []string
+	failed := 0
+
+	for _, chain := range e.Chains {
+		chainProfile, txs := e.fetchChain(ctx, client, chain, cleanAddr, apiKey)
+		allTxs = append(allTxs, txs...)
+		for _, source := range chainProfile.DataSources {
+			if !slices.Contains(profile.DataSources, source) {
+				profile.DataSources = append(profile.DataSources, source)
+			}
+		}
+
+		profile.Chains = append(profile.Chains, ChainActivity{
+			ChainID:     chain.ID,
+			Network:     chain.Name,
+			AccountType: chainProfile.AccountType,
+			Safe:        chainProfile.Safe,
+			IsActive:    chainProfile.IsActive,
+			Balance:     chainProfile.Balance,
+			BalanceRaw:  chainProfile.BalanceRaw,
+			Decimals:    chainProfile.Decimals,
+			Symbol:      chainProfile.Symbol,
+			TxCount:     chainProfile.TxCount,
+			FirstSeen:   chainProfile.FirstSeen,
+			LastSeen:    chainProfile.LastSeen,
+			Details:     chainProfile.ValidationDetails,
+		})
+
+		if chainProfile.IsActive {
+			activeOn = append(activeOn, chain.Name)
+			profile.IsActive = true
+		}
+		if chainProfile.Balance == "" {
+			failed++ // Balance call failed; details hold the error
+		} else if !strings.HasPrefix(chainProfile.Balance, "0.0000 ") {
+			balances = append(balances, fmt.Sprintf("%s [%s]", chainProfile.Balance, chain.Name))
+		}
+		// A contract on any chain outranks EOA elsewhere
+		if chainProfile.AccountType != "" && (profile.AccountType == "" || profile.AccountType == "EOA") {
+			profile.AccountType = chainProfile.AccountType
+			profile.ContractName = chainProfile.ContractName
+			profile.ContractVerified = chainProfile.ContractVerified
+			profile.ContractRisk = chainProfile.ContractRisk
+		}
+		if profile.Safe == nil {
+			profile.Safe = chainProfile.Safe // Owners may differ per chain; all are screened
+		}
+		profile.TokenHoldings = append(profile.TokenHoldings, chainProfile.TokenHoldings...)
+		profile.Stablecoins = mergeStablecoins(profile.Stablecoins, chainProfile.Stablecoins)
+		profile.NFTHoldings = append(profile.NFTHoldings, chainProfile.NFTHoldings...)
+		profile.Approvals = append(profile.Approvals, chainProfile.Approvals...)
+		profile.ExchangeLinks = append(profile.ExchangeLinks, chainProfile.ExchangeLinks...)
+		profile.Bridges = append(profile.Bridges, chainProfile.Bridges...)
+		if profile.PeelChain == nil || (chainProfile.PeelChain != nil && chainProfile.PeelChain.Hops > profile.PeelChain.Hops) {
+			profile.PeelChain = chainProfile.PeelChain // Longest chain on any network
+		}
+		profile.Exposure = append(profile.Exposure, chainProfile.Exposure...)
+		if s := chainProfile.Sybil; s != nil && (profile.Sybil == nil || len(s.Signals) > len(profile.Sybil.Signals)) {
+			profile.Sybil = s // Strongest pattern on any network
+		}
+		if w := chainProfile.WashTrading; w != nil && (profile.WashTrading == nil || w.Transfers > profile.WashTrading.Transfers) {
+			profile.WashTrading = w // Busiest cluster on any network
+		}
+		profile.TxCount += chainProfile.TxCount
+		profile.InternalTxCount += chainProfile.InternalTxCount
+		profile.FirstSeen = earliestTime(profile.FirstSeen, chainProfile.FirstSeen)
+		profile.LastSeen = latestTime(profile.LastSeen, chainProfile.LastSeen)
+	}
+
+	profile.Balance = strings.Join(balances, ", ")
+	if profile.Balance == "" {
+		profile.Balance = "0.0000 " + e.Chains[0].Symbol
+	}
+	profile.BalanceRaw, profile.Decimals, profile.Symbol = sumChainBalances(profile.Chains)
+
+	switch {
+	case failed == len(e.Chains):
+		profile.ValidationDetails = "All Chain Lookups Failed (see chains)"
+	case len(activeOn) == 0:
+		profile.ValidationDetails = fmt.Sprintf("Inactive on %d/%d Chains", len(e.Chains)-failed, len(e.Chains))
+	default:
+		profile.ValidationDetails = fmt.Sprintf("Active on %d/%d Chains (%s)", len(activeOn), len(e.Chains), strings.Join(activeOn, ", "))
+		if profile.FirstSeen != nil {
+			profile.ValidationDetails += fmt.Sprintf(" | First Seen: %s", profile.FirstSeen.Format("2006-01-02"))
+		}
+	}
+	profile.StablecoinTotal = stablecoinTotal(profile.TokenHoldings)
+	for _, detail := range []string{tokenDetail(profile.TokenHoldings), stablecoinDetail(profile.Stablecoins), nftDetail(profile.NFTHoldings), approvalDetail(profile.Approvals), exchangeDetail(profile.ExchangeLinks), bridgeDetail(profile.Bridges), exposureDetail(profile.Exposure), sybilDetail(profile.Sybil), washDetail(profile.WashTrading)} {
+		if detail != "" {
+			profile.ValidationDetails += " | " + detail
+		}
+	}
+	if failed > 0 && failed < len(e.Chains) {
+		profile.ValidationDetails += fmt.Sprintf(" | %d Chain Lookups Failed", failed)
+	}
+
+	// Sorted so the velocity/age heuristics see one coherent timeline
+	sort.Slice(allTxs, func(i, j int) bool { return allTxs[i].TimeStamp < allTxs[j].TimeStamp })
+	PriceProfile(ctx, profile)
+	Investigate(profile, allTxs)
+
+	return profile
+}
+
+// fetchChain loads balance and tx history from one chain via the provider
+// chain (Etherscan, then JSON-RPC). The returned txs are nil if history
+// could not be loaded.
+func (e *EVMStrategy) fetchChain(ctx context.Context, client *http.Client, chain EVMChain, cleanAddr, apiKey string) (*WalletProfile, []Transaction) {
+	profile := &WalletProfile{
+		Address: cleanAddr,
+		Network: "EVM",
+		IsValid: true,
+		Testnet: e.Testnet,
+	}
+
+	// Appended last: the history step below rewrites ValidationDetails
+	var extraDetails []string
+	defer func() {
+		for _, detail := range append([]string{accountTypeDetail(profile), contractRiskDetail(profile.ContractRisk), safeDetail(profile.Safe), tokenDetail(profile.TokenHoldings), stablecoinDetail(profile.Stablecoins), nftDetail(profile.NFTHoldings), approvalDetail(profile.Approvals)}, extraDetails...) {
+			profile.ValidationDetails = appendDetail(profile.ValidationDetails, detail)
+		}
+	}()
+
+	providers, rpc := e.providers(client, chain, apiKey)
+	if len(providers) == 0 {
 		profile.ValidationDetails = "Offline: No Etherscan API Key provided"
 		return profile, nil
 	}
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	baseURL := "https://api.etherscan.io/v2/api"
-	chainID := "1" 
+	if apiKey == "" {
+		extraDetails = append(extraDetails, "RPC Mode (No Etherscan Key)")
+	}
 
 	// ---------------------------------------------------------
 	// CALL 1: Get Balance
 	// ---------------------------------------------------------
-	balURL := fmt.Sprintf("%s?chainid=%s&module=account&action=balance&address=%s&tag=latest&apikey=%s", baseURL, chainID, cleanAddr, apiKey)
-	
-	var balResp struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-		Result  string `json:"result"`
-	}
-	
-	if err := getJSON(ctx, client, balURL, &balResp); err != nil {
-		profile.ValidationDetails = fmt.Sprintf("Network Error (Balance): %v", err)
+	if err := loadBalance(ctx, providers, cleanAddr, 18, 4, chain.Symbol, profile); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("Balance Lookup Failed: %v", err)
 		return profile, nil
 	}
 
-	if balResp.Status == "0" && balResp.Message != "OK" {
-		profile.ValidationDetails = fmt.Sprintf("Etherscan API Error: %s", balResp.Result)
-		return profile, nil
+	// Etherscan-only lookups are skipped while its breaker is open
+	etherscanUp := apiKey != "" && !providerBreaker("Etherscan").IsOpen()
+	if apiKey != "" && !etherscanUp {
+		extraDetails = append(extraDetails, "Degraded: Etherscan Circuit Open (Tokens/Contract Skipped)")
 	}
 
-	wei := new(big.Float)
-	wei.SetString(balResp.Result)
-	ethValue := new(big.Float).Quo(wei, big.NewFloat(1e18))
-	profile.Balance = fmt.Sprintf("%.4f ETH", ethValue)
-	
-	if balResp.Result != "0" {
-		profile.IsActive = true
+	// ---------------------------------------------------------
+	// CALL 1b: Contract vs EOA (best effort)
+	// ---------------------------------------------------------
+	if etherscanUp {
+		_ = fetchAccountType(ctx, client, etherscanV2URL, chain.ID, cleanAddr, apiKey, profile)
+	} else if rpc != nil {
+		if code, err := rpc.GetCode(ctx, cleanAddr); err == nil {
+			if profile.AccountType = classifyCode(code); profile.AccountType == "CONTRACT" {
+				profile.ContractRisk = newContractRisk(code, nil)
+			}
+		}
 	}
 
 	// ---------------------------------------------------------
-	// CALL 2: Get Transaction History
+	// CALL 1c: Gnosis Safe owners, else contract owner and
+	// implementation privileges (contracts only, best effort)
 	// ---------------------------------------------------------
-	txURL := fmt.Sprintf("%s?chainid=%s&module=account&action=txlist&address=%s&startblock=0&endblock=99999999&sort=asc&apikey=%s", baseURL, chainID, cleanAddr, apiKey)
+	if profile.AccountType == "CONTRACT" || profile.AccountType == "SMART_ACCOUNT" {
+		var call ethCaller
+		if etherscanUp {
+			call = etherscanCaller(client, etherscanV2URL, chain.ID, apiKey)
+		} else if rpc != nil {
+			call = rpc.Call
+		}
+		if call != nil {
+			if profile.Safe = fetchSafe(ctx, call, cleanAddr, 0); profile.Safe != nil {
+				profile.AccountType = "SMART_ACCOUNT"
+				profile.ContractRisk = nil // A wallet, not an application
+			}
+		}
+		if cr := profile.ContractRisk; cr != nil {
+			// A proxy's own ABI hides the privileges of the code behind it
+			if etherscanUp && cr.Implementation != "" {
+				if impl, err := fetchContractSource(ctx, client, etherscanV2URL, chain.ID, cr.Implementation, apiKey); err == nil {
+					addPrivileges(cr, impl.ABI)
+				}
+			}
+			if call != nil {
+				loadContractOwner(ctx, call, cleanAddr, cr)
+			}
+		}
+	}
 
-	var txResp struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-		Result  json.RawMessage `json:"result"`
+	// Token, NFT and approval lookups need Etherscan's indexes
+	if etherscanUp {
+		e.fetchHoldings(ctx, client, chain, cleanAddr, apiKey, profile, &extraDetails)
 	}
 
-	if err := getJSON(ctx, client, txURL, &txResp); err != nil {
-		profile.ValidationDetails += fmt.Sprintf(" | History Fetch Failed: %v", err)
+	// ---------------------------------------------------------
+	// CALL 2: Get Transaction History (regular + internal)
+	// ---------------------------------------------------------
+	investigationTxs, err := loadHistory(ctx, providers, cleanAddr, profile)
+	if err != nil {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("History Fetch Failed: %v", err))
+		return profile, nil
+	}
+	if len(investigationTxs) == 0 {
 		return profile, nil
 	}
 
-	if txResp.Status == "0" {
-		if txResp.Message == "No transactions found" {
-			if !profile.IsActive {
-				profile.ValidationDetails = "Inactive Account (No Tx History)"
-			}
-			return profile, nil
-		} else {
-			var errorMsg string
-			_ = json.Unmarshal(txResp.Result, &errorMsg)
-			profile.ValidationDetails += fmt.Sprintf(" | API Error: %s - %s", txResp.Message, errorMsg)
-			return profile, nil
-		}
+	// ---------------------------------------------------------
+	// CALL 3: Exchange Attribution (deposit addresses opt-in)
+	// ---------------------------------------------------------
+	profile.ExchangeLinks = exchangeLinks(cleanAddr, investigationTxs)
+	if e.FetchDeposits {
+		profile.ExchangeLinks = append(profile.ExchangeLinks, attributeDeposits(ctx, providers, cleanAddr, investigationTxs)...)
 	}
+	extraDetails = append(extraDetails, exchangeDetail(profile.ExchangeLinks))
+	profile.Bridges = bridgeUsage(cleanAddr, investigationTxs, 18, chain.Symbol)
+	extraDetails = append(extraDetails, bridgeDetail(profile.Bridges))
 
 	// ---------------------------------------------------------
-	// PREPARE FOR INVESTIGATOR
+	// CALL 4: Peel Chain (opt-in, one history per hop)
 	// ---------------------------------------------------------
-	var rawTxs []struct {
-		TimeStamp string `json:"timeStamp"`
-		From      string `json:"from"`
-		To        string `json:"to"`
-		Value     string `json:"value"`
-		Hash      string `json:"hash"`
+	if e.PeelHops > 0 {
+		loadPeelChain(ctx, evmPeelStep(providers, cleanAddr, investigationTxs), cleanAddr, e.PeelHops, func(wei *big.Int) string {
+			return fmt.Sprintf("%.4f %s", new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)), chain.Symbol)
+		}, profile)
 	}
-	
-	if err := json.Unmarshal(txResp.Result, &rawTxs); err != nil {
-		profile.ValidationDetails += " | Error parsing tx list"
-		return profile, nil
+
+	// ---------------------------------------------------------
+	// CALL 5: Indirect Exposure (opt-in, best effort)
+	// ---------------------------------------------------------
+	if e.ExposureHops > 0 {
+		network := ""
+		if len(e.Chains) > 1 {
+			network = chain.Name
+		}
+		traceExposure(ctx, providers, cleanAddr, investigationTxs, e.ExposureHops, network, profile)
+		extraDetails = append(extraDetails, exposureDetail(profile.Exposure))
 	}
 
-	var investigationTxs []Transaction
-	for _, t := range rawTxs {
-		ts, _ := strconv.ParseInt(t.TimeStamp, 10, 64)
-		investigationTxs = append(investigationTxs, Transaction{
-			TimeStamp: ts,
-			From:      t.From,
-			To:        t.To,
-			Value:     t.Value,
-			Hash:      t.Hash,
-		})
+	// ---------------------------------------------------------
+	// CALL 6: Sybil Signals (funder and sibling lookups opt-in)
+	// ---------------------------------------------------------
+	profile.Sybil = detectSybil(ctx, providers, cleanAddr, investigationTxs, e.FetchSybil)
+	extraDetails = append(extraDetails, sybilDetail(profile.Sybil))
+
+	// ---------------------------------------------------------
+	// CALL 7: Wash Trading (opt-in, token/NFT transfers of the
+	// main counterparties)
+	// ---------------------------------------------------------
+	if e.FetchWashTrading && etherscanUp {
+		loadWashTrading(ctx, client, etherscanV2URL, chain, cleanAddr, apiKey, profile)
+		extraDetails = append(extraDetails, washDetail(profile.WashTrading))
 	}
 
-	if len(investigationTxs) > 0 {
-		profile.IsActive = true
-		profile.TxCount = len(investigationTxs)
+	return profile, investigationTxs
+}
 
-		firstTime := time.Unix(investigationTxs[0].TimeStamp, 0)
-		profile.FirstSeen = &firstTime
+// providers returns the chain's data sources in failover order: Etherscan
+// (with a key), then a JSON-RPC node. The RPC fallback is only used when it
+// can be trusted to point at this chain: an explicit RPCURL in
+// single-chain mode, or the public Ethereum/Sepolia node for that chain.
+func (e *EVMStrategy) providers(client *http.Client, chain EVMChain, apiKey string) (ProviderChain, *EVMRPCProvider) {
+	var pc ProviderChain
+	if apiKey != "" {
+		maxTxs := e.MaxTxs
+		if maxTxs <= 0 {
+			maxTxs = DefaultMaxEVMTxs
+		}
+		pc = append(pc, &EtherscanProvider{Client: client, BaseURL: etherscanV2URL, Chain: chain, APIKey: apiKey, MaxTxs: maxTxs})
+	}
 
-		lastTime := time.Unix(investigationTxs[len(investigationTxs)-1].TimeStamp, 0)
-		profile.LastSeen = &lastTime
+	rpcURL := ""
+	switch {
+	case e.RPCURL != "" && len(e.Chains) <= 1:
+		rpcURL = e.RPCURL
+	case chain.ID == DefaultEVMChains[0].ID:
+		rpcURL = publicEthereumRPC
+	case chain.ID == TestnetEVMChains[0].ID:
+		rpcURL = publicSepoliaRPC
+	}
+	if rpcURL == "" {
+		return cachedChain("evm:"+chain.ID, pc), nil
+	}
+	rpc := &EVMRPCProvider{Client: client, URL: rpcURL}
+	return cachedChain("evm:"+chain.ID, append(pc, rpc)), rpc
+}
 
-		profile.ValidationDetails = fmt.Sprintf("Active | First Seen: %s", firstTime.Format("2006-01-02"))
+// fetchHoldings runs the optional Etherscan-only lookups: ERC-20 tokens,
+// NFTs and approvals. Failures are best effort and noted in extraDetails.
+func (e *EVMStrategy) fetchHoldings(ctx context.Context, client *http.Client, chain EVMChain, cleanAddr, apiKey string, profile *WalletProfile, extraDetails *[]string) {
+	// ---------------------------------------------------------
+	// CALL 1d: ERC-20 Holdings (best effort)
+	// ---------------------------------------------------------
+	if !e.SkipTokens {
+		holdings, stables, err := fetchTokenHoldings(ctx, client, etherscanV2URL, chain, cleanAddr, apiKey)
+		if err != nil {
+			*extraDetails = append(*extraDetails, "Token Lookup Failed")
+		}
+		profile.TokenHoldings = holdings
+		profile.StablecoinTotal = stablecoinTotal(holdings)
+		profile.Stablecoins = stables
+		if e.CheckTokens {
+			flagScamTokens(ctx, client, etherscanV2URL, chain, holdings, apiKey)
+		}
+		if len(holdings) > 0 {
+			profile.IsActive = true // "0.0000 ETH" wallets can still hold millions in USDT
+		}
 	}
 
 	// ---------------------------------------------------------
-	// CALL 3: THE INVESTIGATOR
+	// CALL 1e: NFT Holdings (opt-in, best effort)
 	// ---------------------------------------------------------
-	// UPDATED: Now calls Investigate with only 2 arguments.
-	// The HTTP client inside Investigate handles the engine connection.
-	Investigate(profile, investigationTxs)
+	if e.FetchNFTs {
+		collections, err := fetchNFTHoldings(ctx, client, etherscanV2URL, chain, cleanAddr, apiKey)
+		if err != nil {
+			*extraDetails = append(*extraDetails, "NFT Lookup Failed")
+		}
+		profile.NFTHoldings = collections
+		if len(collections) > 0 {
+			profile.IsActive = true
+		}
+	}
 
-	return profile, nil
+	// ---------------------------------------------------------
+	// CALL 1f: Token Approvals (opt-in, best effort)
+	// ---------------------------------------------------------
+	if e.FetchApprovals {
+		approvals, err := fetchApprovals(ctx, client, etherscanV2URL, chain, cleanAddr, apiKey)
+		if err != nil {
+			*extraDetails = append(*extraDetails, "Approval Lookup Failed")
+		}
+		profile.Approvals = approvals
+	}
 }
 
-// getJSON Helper
-func getJSON(ctx context.Context, client *http.Client, url string, target interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// ---------------------------------------------------------
+// PROVIDER: Etherscan v2 (multichain)
+// ---------------------------------------------------------
+
+const etherscanV2URL = "https://api.etherscan.io/v2/api"
+
+// Etherscan returns at most 10,000 records per query (page x offset)
+const etherscanMaxResults = 10000
+
+// DefaultMaxEVMTxs caps how many records fetchTxList pages through.
+const DefaultMaxEVMTxs = 50000
+
+// EtherscanProvider reads one chain through the Etherscan v2 API.
+type EtherscanProvider struct {
+	Client  *http.Client
+	BaseURL string
+	Chain   EVMChain
+	APIKey  string
+	MaxTxs  int // History cap per list (txlist, txlistinternal)
+}
+
+func (p *EtherscanProvider) Name() string {
+	return "Etherscan"
+}
+
+func (p *EtherscanProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	balURL := fmt.Sprintf("%s?chainid=%s&module=account&action=balance&address=%s&tag=latest&apikey=%s", p.BaseURL, p.Chain.ID, address, p.APIKey)
+
+	var balResp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Result  string `json:"result"`
+	}
+
+	if err := getJSON(ctx, p.Client, balURL, &balResp); err != nil {
+		return nil, err
+	}
+
+	if balResp.Status == "0" && balResp.Message != "OK" {
+		return nil, fmt.Errorf("API Error: %s", balResp.Result)
+	}
+
+	wei, ok := new(big.Int).SetString(balResp.Result, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %q", balResp.Result)
+	}
+	return wei, nil
+}
+
+// GetTransactions loads regular and internal transactions. Mixer
+// deposits/withdrawals often only show up as internal calls.
+func (p *EtherscanProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	txs, truncated, err := fetchTxList(ctx, p.Client, p.BaseURL, p.Chain.ID, "txlist", address, p.APIKey, p.MaxTxs)
+	if err != nil && len(txs) == 0 {
+		return nil, err
+	}
+
+	hist := &TxHistory{Total: len(txs), Complete: !truncated && err == nil, Truncated: truncated}
 	if err != nil {
-		return err
+		// A later page failed; keep what was loaded
+		hist.Warnings = append(hist.Warnings, "History Incomplete: "+err.Error())
 	}
-	resp, err := client.Do(req)
+
+	internalTxs, _, err := fetchTxList(ctx, p.Client, p.BaseURL, p.Chain.ID, "txlistinternal", address, p.APIKey, p.MaxTxs)
 	if err != nil {
-		return err
+		hist.Warnings = append(hist.Warnings, "Internal Tx Fetch Failed")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	hist.Txs = append(txs, internalTxs...)
+	sort.SliceStable(hist.Txs, func(i, j int) bool { return hist.Txs[i].TimeStamp < hist.Txs[j].TimeStamp })
+	return hist, nil
+}
+
+// GetFirstSeen reads the single oldest transaction.
+func (p *EtherscanProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	url := fmt.Sprintf("%s?chainid=%s&module=account&action=txlist&address=%s&startblock=0&endblock=99999999&page=1&offset=1&sort=asc&apikey=%s", p.BaseURL, p.Chain.ID, address, p.APIKey)
+
+	var resp struct {
+		Status string `json:"status"`
+		Result []struct {
+			TimeStamp string `json:"timeStamp"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, p.Client, url, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "1" || len(resp.Result) == 0 {
+		return nil, nil
+	}
+	ts, _ := strconv.ParseInt(resp.Result[0].TimeStamp, 10, 64)
+	t := time.Unix(ts, 0)
+	return &t, nil
+}
+
+// fetchTxList loads an Etherscan account history ("txlist" or
+// "txlistinternal") oldest first. Histories over 10k records are paged in
+// startblock windows up to maxTxs; truncated reports whether the cap was hit.
+// Failed internal calls are skipped.
+func fetchTxList(ctx context.Context, client *http.Client, baseURL, chainID, action, cleanAddr, apiKey string, maxTxs int) (txs []Transaction, truncated bool, err error) {
+	seen := map[string]bool{}
+	startBlock := int64(0)
+
+	for {
+		txURL := fmt.Sprintf("%s?chainid=%s&module=account&action=%s&address=%s&startblock=%d&endblock=99999999&page=1&offset=%d&sort=asc&apikey=%s", baseURL, chainID, action, cleanAddr, startBlock, etherscanMaxResults, apiKey)
+
+		var txResp struct {
+			Status  string          `json:"status"`
+			Message string          `json:"message"`
+			Result  json.RawMessage `json:"result"`
+		}
+
+		if err := getJSON(ctx, client, txURL, &txResp); err != nil {
+			return txs, false, fmt.Errorf("History Fetch Failed: %v", err)
+		}
+
+		if txResp.Status == "0" {
+			if txResp.Message == "No transactions found" {
+				return txs, false, nil
+			}
+			var errorMsg string
+			_ = json.Unmarshal(txResp.Result, &errorMsg)
+			return txs, false, fmt.Errorf("API Error: %s - %s", txResp.Message, errorMsg)
+		}
+
+		var rawTxs []struct {
+			BlockNumber      string `json:"blockNumber"`
+			TransactionIndex string `json:"transactionIndex"`
+			GasPrice         string `json:"gasPrice"`
+			TimeStamp        string `json:"timeStamp"`
+			From             string `json:"from"`
+			To               string `json:"to"`
+			Value            string `json:"value"`
+			Hash             string `json:"hash"`
+			ContractAddress  string `json:"contractAddress"` // Contract creations only
+			IsError          string `json:"isError"`
+			TraceID          string `json:"traceId"` // Internal txs share the parent hash
+		}
+
+		if err := json.Unmarshal(txResp.Result, &rawTxs); err != nil {
+			return txs, false, errors.New("Error parsing tx list")
+		}
+
+		lastBlock := startBlock
+		for _, t := range rawTxs {
+			lastBlock, _ = strconv.ParseInt(t.BlockNumber, 10, 64)
+
+			// The next window restarts at lastBlock, so skip what we already have
+			key := t.Hash + "/" + t.TraceID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if action == "txlistinternal" && t.IsError == "1" {
+				continue
+			}
+			if len(txs) >= maxTxs {
+				return txs, true, nil
+			}
+			ts, _ := strconv.ParseInt(t.TimeStamp, 10, 64)
+			tx := Transaction{
+				TimeStamp: ts,
+				From:      t.From,
+				To:        t.To,
+				Value:     t.Value,
+				Hash:      t.Hash,
+				Internal:  action == "txlistinternal",
+
+				Block:           lastBlock,
+				ContractAddress: strings.ToLower(t.ContractAddress),
+			}
+			if !tx.Internal {
+				tx.TxIndex, _ = strconv.Atoi(t.TransactionIndex)
+				tx.GasPrice = t.GasPrice
+			}
+			txs = append(txs, tx)
+		}
+
+		// A short page is the end; a full page that ends where it started
+		// is one block with >10k records, which cannot be windowed further
+		if len(rawTxs) < etherscanMaxResults || lastBlock == startBlock {
+			return txs, len(rawTxs) >= etherscanMaxResults, nil
+		}
+		startBlock = lastBlock
 	}
+}
 
-	return json.NewDecoder(resp.Body).Decode(target)
-}
\ No newline at end of file
+// getJSON Helper (retried per the shared RetryPolicy)
+func getJSON(ctx context.Context, client *http.Client, url string, target interface{}) error {
+	return doJSON(ctx, client, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	}, target)
+}
+
+// sumChainBalances totals the per-chain balances when every chain answered
+// in the same native token (e.g. ETH on mainnet and its L2s). Mixed tokens
+// have no meaningful sum, so the raw fields stay empty.
+func sumChainBalances(chains []ChainActivity) (*big.Int, int, string) {
+	total := new(big.Int)
+	symbol, decimals := "", 0
+	for _, c := range chains {
+		if c.BalanceRaw == nil || (symbol != "" && c.Symbol != symbol) {
+			return nil, 0, ""
+		}
+		symbol, decimals = c.Symbol, c.Decimals
+		total.Add(total, c.BalanceRaw)
+	}
+	if symbol == "" {
+		return nil, 0, ""
+	}
+	return total, decimals, symbol
+}
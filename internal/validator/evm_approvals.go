@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// TOKEN APPROVAL EXPOSURE
+// ---------------------------------------------------------
+
+const (
+	// Approval(address indexed owner, address indexed spender, uint256 value)
+	// ERC-721 emits the same signature with tokenId as a 4th (indexed) topic.
+	topicApproval = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+	// ApprovalForAll(address indexed owner, address indexed operator, bool approved)
+	topicApprovalForAll = "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"
+
+	// Each unlimited spender costs up to 2 calls (getCode + getsourcecode)
+	maxSpenderLookups = 10
+)
+
+// Allowances at or above 2^255 are "infinite" (wallets send 2^256-1)
+var unlimitedAllowance = new(big.Int).Lsh(big.NewInt(1), 255)
+
+// fetchApprovals replays the owner's Approval/ApprovalForAll logs to get the
+// approvals that are still live, then classifies each unlimited spender.
+// ERC-721 single-token approvals are skipped: they expire on transfer.
+func fetchApprovals(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, cleanAddr, apiKey string) ([]TokenApproval, error) {
+	ownerTopic := "0x000000000000000000000000" + strings.ToLower(strings.TrimPrefix(cleanAddr, "0x"))
+
+	type logEntry struct {
+		Address string   `json:"address"`
+		Topics  []string `json:"topics"`
+		Data    string   `json:"data"`
+	}
+
+	live := map[string]TokenApproval{} // token/spender -> latest state
+	var order []string
+
+	for _, topic0 := range []string{topicApproval, topicApprovalForAll} {
+		url := fmt.Sprintf("%s?chainid=%s&module=logs&action=getLogs&fromBlock=0&toBlock=latest&topic0=%s&topic0_1_opr=and&topic1=%s&page=1&offset=1000&apikey=%s", baseURL, chain.ID, topic0, ownerTopic, apiKey)
+
+		var resp struct {
+			Status  string     `json:"status"`
+			Message string     `json:"message"`
+			Result  []logEntry `json:"result"`
+		}
+		if err := getJSON(ctx, client, url, &resp); err != nil {
+			return nil, err
+		}
+		if resp.Status == "0" && resp.Message != "No records found" {
+			return nil, fmt.Errorf("getLogs: %s", resp.Message)
+		}
+
+		for _, l := range resp.Result {
+			if len(l.Topics) != 3 {
+				continue // ERC-721 single-token Approval (4 topics)
+			}
+			token := strings.ToLower(l.Address)
+			spender := "0x" + strings.ToLower(l.Topics[2][len(l.Topics[2])-40:])
+			value, ok := new(big.Int).SetString(strings.TrimPrefix(l.Data, "0x"), 16)
+			if !ok {
+				value = new(big.Int)
+			}
+
+			a := TokenApproval{ChainID: chain.ID, Token: token, Spender: spender, Standard: "ERC-20"}
+			if topic0 == topicApprovalForAll {
+				a.Standard = "NFT (ApprovalForAll)"
+				a.Unlimited = value.Sign() != 0
+				a.Amount = "ALL"
+			} else {
+				a.Unlimited = value.Cmp(unlimitedAllowance) >= 0
+				a.Amount = value.String()
+				if a.Unlimited {
+					a.Amount = "UNLIMITED"
+				}
+			}
+
+			key := token + "/" + spender
+			if _, seen := live[key]; !seen {
+				order = append(order, key)
+			}
+			if value.Sign() == 0 {
+				delete(live, key) // Revoked
+				continue
+			}
+			live[key] = a
+		}
+	}
+
+	// Classify unlimited spenders (known threat, EOA, unverified contract)
+	var approvals []TokenApproval
+	spenders := map[string]*WalletProfile{}
+	for _, key := range order {
+		a, ok := live[key]
+		if !ok {
+			continue
+		}
+		if a.Unlimited {
+			if label, threat := addressThreat(a.Spender, true); threat {
+				a.SpenderLabel = label.Entity
+			}
+			sp, looked := spenders[a.Spender]
+			if !looked && len(spenders) < maxSpenderLookups {
+				sp = &WalletProfile{}
+				if err := fetchAccountType(ctx, client, baseURL, chain.ID, a.Spender, apiKey, sp); err != nil {
+					sp = nil
+				}
+				spenders[a.Spender] = sp
+			}
+			if sp != nil {
+				a.SpenderType = sp.AccountType
+				a.SpenderVerified = sp.ContractVerified
+				if a.SpenderLabel == "" {
+					a.SpenderLabel = sp.ContractName
+				}
+			}
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
+
+// riskyApproval scores an unlimited approval whose spender is a known
+// threat (e.g. a drainer), a plain EOA (typical of permit phishing) or an
+// unverified contract. It returns the risk description and FRAUD offset.
+func riskyApproval(a TokenApproval, rules RiskRules) (string, float64, bool) {
+	if !a.Unlimited {
+		return "", 0, false
+	}
+	if label, threat := addressThreat(a.Spender, true); threat {
+		return fmt.Sprintf("Unlimited Approval to %s", label.Entity), rules.ThreatApproval.Offset, true
+	}
+	switch {
+	case a.SpenderType == "EOA":
+		return "Unlimited Approval to an EOA (Phishing Pattern)", rules.RiskyApproval.Offset, true
+	case a.SpenderVerified != nil && !*a.SpenderVerified:
+		return "Unlimited Approval to Unverified Contract", rules.RiskyApproval.Offset, true
+	}
+	return "", 0, false
+}
+
+// approvalDetail is the human-readable ValidationDetails fragment.
+func approvalDetail(approvals []TokenApproval) string {
+	if len(approvals) == 0 {
+		return ""
+	}
+	unlimited := 0
+	for _, a := range approvals {
+		if a.Unlimited {
+			unlimited++
+		}
+	}
+	return fmt.Sprintf("%d Live Approvals (%d Unlimited)", len(approvals), unlimited)
+}
@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// ACCOUNT TYPE (EOA vs CONTRACT vs SMART_ACCOUNT)
+// ---------------------------------------------------------
+
+// EIP-7702: an EOA that delegates to contract code carries 0xef0100 || address
+const eip7702DelegationPrefix = "0xef0100"
+
+// Verified contract names of common smart-contract wallets
+var smartAccountContracts = []string{
+	"GnosisSafeProxy", "SafeProxy", "SimpleAccount", "LightAccount",
+	"Kernel", "CoinbaseSmartWallet", "BiconomySmartAccount",
+}
+
+// fetchAccountType classifies the address via eth_getCode and, for contracts,
+// looks up Etherscan source verification. It sets AccountType,
+// ContractName, ContractVerified and (plain contracts) ContractRisk on the
+// profile.
+func fetchAccountType(ctx context.Context, client *http.Client, baseURL, chainID, cleanAddr, apiKey string, profile *WalletProfile) error {
+	codeURL := fmt.Sprintf("%s?chainid=%s&module=proxy&action=eth_getCode&address=%s&tag=latest&apikey=%s", baseURL, chainID, cleanAddr, apiKey)
+
+	var codeResp struct {
+		Result string    `json:"result"`
+		Error  *rpcError `json:"error"`
+	}
+	if err := getJSON(ctx, client, codeURL, &codeResp); err != nil {
+		return err
+	}
+	if codeResp.Error != nil {
+		return codeResp.Error
+	}
+
+	// Etherscan returns plain-text errors (e.g. rate limits) in "result"
+	if !strings.HasPrefix(codeResp.Result, "0x") {
+		return fmt.Errorf("unexpected eth_getCode result: %s", codeResp.Result)
+	}
+	profile.AccountType = classifyCode(codeResp.Result)
+	if profile.AccountType != "CONTRACT" {
+		return nil
+	}
+
+	src, err := fetchContractSource(ctx, client, baseURL, chainID, cleanAddr, apiKey)
+	if err != nil {
+		// Type is known; verification status stays unknown
+		profile.ContractRisk = newContractRisk(codeResp.Result, nil)
+		return nil
+	}
+	verified := src.Verified
+	profile.ContractVerified = &verified
+	profile.ContractName = src.Name
+
+	for _, name := range smartAccountContracts {
+		if strings.EqualFold(src.Name, name) {
+			profile.AccountType = "SMART_ACCOUNT"
+			return nil
+		}
+	}
+	profile.ContractRisk = newContractRisk(codeResp.Result, src)
+	return nil
+}
+
+// contractSource is what Etherscan's getsourcecode reports for a contract.
+type contractSource struct {
+	Name           string
+	Verified       bool
+	ABI            string // JSON; empty if unverified
+	Proxy          bool   // Etherscan detected a proxy
+	Implementation string
+}
+
+func fetchContractSource(ctx context.Context, client *http.Client, baseURL, chainID, address, apiKey string) (*contractSource, error) {
+	srcURL := fmt.Sprintf("%s?chainid=%s&module=contract&action=getsourcecode&address=%s&apikey=%s", baseURL, chainID, address, apiKey)
+	var srcResp struct {
+		Status string `json:"status"`
+		Result []struct {
+			SourceCode     string `json:"SourceCode"`
+			ABI            string `json:"ABI"`
+			ContractName   string `json:"ContractName"`
+			Proxy          string `json:"Proxy"`
+			Implementation string `json:"Implementation"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, client, srcURL, &srcResp); err != nil {
+		return nil, err
+	}
+	if srcResp.Status != "1" || len(srcResp.Result) == 0 {
+		return nil, fmt.Errorf("getsourcecode: no result")
+	}
+
+	r := srcResp.Result[0]
+	src := &contractSource{
+		Name:           r.ContractName,
+		Verified:       r.SourceCode != "",
+		Proxy:          r.Proxy == "1",
+		Implementation: strings.ToLower(r.Implementation),
+	}
+	if src.Verified {
+		src.ABI = r.ABI
+	}
+	return src, nil
+}
+
+// classifyCode maps eth_getCode output to an account type.
+func classifyCode(code string) string {
+	code = strings.ToLower(code)
+	switch {
+	case code == "0x" || code == "":
+		return "EOA"
+	case strings.HasPrefix(code, eip7702DelegationPrefix):
+		return "SMART_ACCOUNT"
+	}
+	return "CONTRACT"
+}
+
+// accountTypeDetail is the human-readable ValidationDetails fragment.
+func accountTypeDetail(profile *WalletProfile) string {
+	switch profile.AccountType {
+	case "CONTRACT", "SMART_ACCOUNT":
+		label := "Contract"
+		if profile.AccountType == "SMART_ACCOUNT" {
+			label = "Smart Account"
+		}
+		if profile.ContractName != "" {
+			label += fmt.Sprintf(" (%s)", profile.ContractName)
+		}
+		if profile.ContractVerified != nil {
+			if *profile.ContractVerified {
+				label += " - Verified"
+			} else {
+				label += " - Unverified"
+			}
+		}
+		return label
+	}
+	return ""
+}
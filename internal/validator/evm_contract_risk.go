@@ -0,0 +1,173 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// CONTRACT RISK (upgradability, owner privileges, rug-pull levers)
+// ---------------------------------------------------------
+
+const (
+	selectorOwner = "0x8da5cb5b" // owner() returns (address)
+
+	// EIP-1167 minimal proxy: a fixed delegatecall to the address that follows
+	minimalProxyPrefix = "0x363d3d373d3d3d363d73"
+)
+
+// ABI functions that let a privileged account act on holders' funds
+var privilegedFunctions = map[string]string{
+	"mint":             "MINT",
+	"mintTo":           "MINT",
+	"issue":            "MINT",
+	"pause":            "PAUSE",
+	"unpause":          "PAUSE",
+	"upgradeTo":        "UPGRADE",
+	"upgradeToAndCall": "UPGRADE",
+
+	// Honeypot levers: a token the owner can stop holders from selling
+	"blacklist":         "BLACKLIST",
+	"addBlacklist":      "BLACKLIST",
+	"addBlackList":      "BLACKLIST",
+	"addToBlacklist":    "BLACKLIST",
+	"setBlacklist":      "BLACKLIST",
+	"setBots":           "BLACKLIST",
+	"addBots":           "BLACKLIST",
+	"setFee":            "TAX",
+	"setFees":           "TAX",
+	"setTax":            "TAX",
+	"setTaxFee":         "TAX",
+	"setSellFee":        "TAX",
+	"setSellTax":        "TAX",
+	"updateFees":        "TAX",
+	"setTrading":        "TRADING",
+	"setTradingEnabled": "TRADING",
+	"toggleTrading":     "TRADING",
+	"setMaxTxAmount":    "LIMIT",
+	"setMaxTxPercent":   "LIMIT",
+	"setMaxWalletSize":  "LIMIT",
+}
+
+// ERC-20 functions a token ABI must have
+var erc20Functions = []string{"totalSupply", "balanceOf", "transfer"}
+
+// newContractRisk reads the proxy pattern from the runtime code and the
+// privileges from the verified ABI. src may be nil (unverified or RPC mode).
+func newContractRisk(code string, src *contractSource) *ContractRisk {
+	cr := &ContractRisk{}
+	code = strings.ToLower(code)
+	switch {
+	case strings.HasPrefix(code, minimalProxyPrefix) && len(code) >= len(minimalProxyPrefix)+40:
+		// The implementation is baked into the code: a clone cannot be upgraded
+		cr.Proxy = "EIP-1167"
+		cr.Implementation = "0x" + code[len(minimalProxyPrefix):len(minimalProxyPrefix)+40]
+	case src != nil && src.Proxy:
+		cr.Proxy = "PROXY"
+		cr.Implementation = src.Implementation
+		cr.Upgradeable = true
+	}
+	if src != nil {
+		addPrivileges(cr, src.ABI)
+	}
+	return cr
+}
+
+// addPrivileges records the privileged functions found in a JSON ABI, and
+// whether it is an ERC-20 token.
+func addPrivileges(cr *ContractRisk, abi string) {
+	var entries []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if json.Unmarshal([]byte(abi), &entries) != nil {
+		return
+	}
+	functions := map[string]bool{}
+	for _, e := range entries {
+		functions[e.Name] = functions[e.Name] || e.Type == "function"
+	}
+	token := true
+	for _, name := range erc20Functions {
+		token = token && functions[name]
+	}
+	cr.Token = cr.Token || token
+
+	for _, e := range entries {
+		privilege, ok := privilegedFunctions[e.Name]
+		if e.Type != "function" || !ok || hasPrivilege(cr, privilege) {
+			continue
+		}
+		cr.Privileges = append(cr.Privileges, privilege)
+		if privilege == "UPGRADE" && cr.Proxy != "EIP-1167" {
+			cr.Upgradeable = true // UUPS: the upgrade lives in the implementation
+		}
+	}
+	sort.Strings(cr.Privileges)
+}
+
+func hasPrivilege(cr *ContractRisk, privilege string) bool {
+	for _, p := range cr.Privileges {
+		if p == privilege {
+			return true
+		}
+	}
+	return false
+}
+
+// loadContractOwner calls owner() and classifies the result. Contracts
+// without an owner() function (role-based access) keep an empty owner.
+func loadContractOwner(ctx context.Context, call ethCaller, address string, cr *ContractRisk) {
+	result, err := call(ctx, address, selectorOwner)
+	if err != nil {
+		return
+	}
+	words := abiWords(result)
+	if len(words) != 1 || words[0].BitLen() > 160 {
+		return // No owner(), or not an address
+	}
+	if words[0].Sign() == 0 {
+		cr.OwnerType = "RENOUNCED"
+		return
+	}
+	cr.Owner = fmt.Sprintf("0x%040x", words[0])
+	cr.OwnerType = "ACCOUNT"
+	if fetchSafe(ctx, call, cr.Owner, maxSafeDepth) != nil {
+		cr.OwnerType = "SAFE" // Privileges need several signers
+	}
+}
+
+// contractRiskDetail is the human-readable ValidationDetails fragment.
+func contractRiskDetail(cr *ContractRisk) string {
+	if cr == nil {
+		return ""
+	}
+	var parts []string
+	if cr.Upgradeable {
+		parts = append(parts, "Upgradeable")
+	} else if cr.Proxy != "" {
+		parts = append(parts, cr.Proxy+" Clone")
+	}
+	if hasPrivilege(cr, "MINT") {
+		parts = append(parts, "Mintable")
+	}
+	if hasPrivilege(cr, "PAUSE") {
+		parts = append(parts, "Pausable")
+	}
+	if levers := honeypotLevers(cr); len(levers) > 0 {
+		parts = append(parts, "Honeypot Levers: "+strings.Join(levers, "/"))
+	}
+	switch cr.OwnerType {
+	case "RENOUNCED":
+		parts = append(parts, "Ownership Renounced")
+	case "SAFE":
+		parts = append(parts, "Safe-Owned")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Contract Risk: " + strings.Join(parts, ", ")
+}
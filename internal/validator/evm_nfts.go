@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// NFT HOLDINGS (ERC-721 / ERC-1155)
+// ---------------------------------------------------------
+
+type nftTransfer struct {
+	ContractAddress string `json:"contractAddress"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	TokenID         string `json:"tokenID"`
+	TokenName       string `json:"tokenName"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenValue      string `json:"tokenValue"` // ERC-1155 only
+}
+
+// fetchNFTHoldings reconstructs current NFT holdings from the ERC-721 and
+// ERC-1155 transfer history (Etherscan has no free "NFTs owned" endpoint)
+// and summarizes them per collection, largest first.
+func fetchNFTHoldings(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, cleanAddr, apiKey string) ([]NFTCollection, error) {
+	owner := strings.ToLower(cleanAddr)
+	var collections []NFTCollection
+
+	for _, std := range []struct{ action, standard string }{
+		{"tokennfttx", "ERC-721"},
+		{"token1155tx", "ERC-1155"},
+	} {
+		url := fmt.Sprintf("%s?chainid=%s&module=account&action=%s&address=%s&page=1&offset=10000&sort=asc&apikey=%s", baseURL, chain.ID, std.action, cleanAddr, apiKey)
+
+		var resp struct {
+			Status  string        `json:"status"`
+			Message string        `json:"message"`
+			Result  []nftTransfer `json:"result"`
+		}
+		if err := getJSON(ctx, client, url, &resp); err != nil {
+			return collections, err
+		}
+		if resp.Status == "0" && resp.Message != "No transactions found" {
+			return collections, fmt.Errorf("%s: %s", std.action, resp.Message)
+		}
+
+		// Net units held per contract+tokenID (ERC-721 transfers are 1 unit)
+		held := map[string]*big.Int{}
+		meta := map[string]nftTransfer{}
+		for _, t := range resp.Result {
+			contract := strings.ToLower(t.ContractAddress)
+			key := contract + "/" + t.TokenID
+			qty := big.NewInt(1)
+			if std.standard == "ERC-1155" {
+				if v, ok := new(big.Int).SetString(t.TokenValue, 10); ok {
+					qty = v
+				}
+			}
+			if held[key] == nil {
+				held[key] = new(big.Int)
+			}
+			if strings.EqualFold(t.To, owner) {
+				held[key].Add(held[key], qty)
+			}
+			if strings.EqualFold(t.From, owner) {
+				held[key].Sub(held[key], qty)
+			}
+			meta[contract] = t
+		}
+
+		counts := map[string]int{}
+		for key, qty := range held {
+			if qty.Sign() > 0 {
+				counts[key[:strings.IndexByte(key, '/')]]++
+			}
+		}
+		for contract, n := range counts {
+			collections = append(collections, NFTCollection{
+				ChainID:  chain.ID,
+				Contract: contract,
+				Name:     meta[contract].TokenName,
+				Symbol:   meta[contract].TokenSymbol,
+				Standard: std.standard,
+				Count:    n,
+			})
+		}
+	}
+
+	sort.Slice(collections, func(i, j int) bool {
+		if collections[i].Count != collections[j].Count {
+			return collections[i].Count > collections[j].Count
+		}
+		return collections[i].Contract < collections[j].Contract
+	})
+	return collections, nil
+}
+
+// nftDetail is the human-readable ValidationDetails fragment.
+func nftDetail(collections []NFTCollection) string {
+	if len(collections) == 0 {
+		return ""
+	}
+	total := 0
+	for _, c := range collections {
+		total += c.Count
+	}
+	return fmt.Sprintf("Holds %d NFTs in %d Collections", total, len(collections))
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// PROVIDER: Raw JSON-RPC node (keyless mode / Etherscan fallback)
+// ---------------------------------------------------------
+
+// Public nodes used when no RPC URL is configured
+const (
+	publicEthereumRPC = "https://ethereum-rpc.publicnode.com"
+	publicSepoliaRPC  = "https://ethereum-sepolia-rpc.publicnode.com"
+)
+
+// EVMRPCProvider reads balance, nonce and code straight from a node.
+// There is no history index, so it reports the nonce (sent txs) as the
+// tx count and cannot answer first-seen queries.
+type EVMRPCProvider struct {
+	Client *http.Client
+	URL    string
+}
+
+func (p *EVMRPCProvider) Name() string {
+	return "RPC"
+}
+
+func (p *EVMRPCProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return p.hexCall(ctx, "eth_getBalance", address)
+}
+
+// GetTransactions returns the nonce as the tx count, without txs.
+func (p *EVMRPCProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	nonce, err := p.hexCall(ctx, "eth_getTransactionCount", address)
+	if err != nil {
+		return nil, err
+	}
+	return &TxHistory{Total: int(nonce.Int64())}, nil
+}
+
+func (p *EVMRPCProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	return nil, ErrNotSupported
+}
+
+// GetCode returns the deployed bytecode ("0x" for EOAs).
+func (p *EVMRPCProvider) GetCode(ctx context.Context, address string) (string, error) {
+	var code string
+	err := jsonRPC(ctx, p.Client, p.URL, "eth_getCode", []string{address, "latest"}, &code)
+	return code, err
+}
+
+// hexCall performs an (address, "latest") call returning a hex quantity.
+func (p *EVMRPCProvider) hexCall(ctx context.Context, method, address string) (*big.Int, error) {
+	var hex string
+	if err := jsonRPC(ctx, p.Client, p.URL, method, []string{address, "latest"}, &hex); err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(hex, "0x"), 16)
+	if !ok {
+		return new(big.Int), nil // "0x" (zero)
+	}
+	return n, nil
+}
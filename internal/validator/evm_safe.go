@@ -0,0 +1,166 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// GNOSIS SAFE (multisig owners + threshold)
+// ---------------------------------------------------------
+
+const (
+	selectorGetThreshold = "0xe75235b8" // getThreshold() returns (uint256)
+	selectorGetOwners    = "0xa0e67e2b" // getOwners() returns (address[])
+
+	// Owners that are themselves Safes are expanded this many levels deep
+	maxSafeDepth = 2
+	// Each owner costs one eth_call to check whether it is a nested Safe
+	maxSafeOwners = 20
+)
+
+// ethCaller performs a read-only eth_call and returns the hex result.
+type ethCaller func(ctx context.Context, to, data string) (string, error)
+
+// etherscanCaller runs eth_call through the Etherscan proxy module.
+func etherscanCaller(client *http.Client, baseURL, chainID, apiKey string) ethCaller {
+	return func(ctx context.Context, to, data string) (string, error) {
+		url := fmt.Sprintf("%s?chainid=%s&module=proxy&action=eth_call&to=%s&data=%s&tag=latest&apikey=%s", baseURL, chainID, to, data, apiKey)
+		var resp struct {
+			Result string    `json:"result"`
+			Error  *rpcError `json:"error"`
+		}
+		if err := getJSON(ctx, client, url, &resp); err != nil {
+			return "", err
+		}
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		if !strings.HasPrefix(resp.Result, "0x") {
+			return "", fmt.Errorf("unexpected eth_call result: %s", resp.Result)
+		}
+		return resp.Result, nil
+	}
+}
+
+// Call runs a read-only eth_call against the node.
+func (p *EVMRPCProvider) Call(ctx context.Context, to, data string) (string, error) {
+	var result string
+	err := jsonRPC(ctx, p.Client, p.URL, "eth_call", []interface{}{map[string]string{"to": to, "data": data}, "latest"}, &result)
+	return result, err
+}
+
+// fetchSafe returns the Safe's threshold and owners, or nil if the address
+// does not answer getThreshold/getOwners like a Safe. Owners that are Safes
+// themselves are expanded up to maxSafeDepth.
+func fetchSafe(ctx context.Context, call ethCaller, address string, depth int) *SafeInfo {
+	thresholdHex, err := call(ctx, address, selectorGetThreshold)
+	if err != nil {
+		return nil
+	}
+	words := abiWords(thresholdHex)
+	if len(words) != 1 || words[0].Sign() == 0 || !words[0].IsInt64() {
+		return nil // EOAs and other contracts return "0x" or revert
+	}
+
+	ownersHex, err := call(ctx, address, selectorGetOwners)
+	if err != nil {
+		return nil
+	}
+	owners, ok := abiDecodeAddressArray(ownersHex)
+	if !ok || len(owners) == 0 {
+		return nil
+	}
+
+	safe := &SafeInfo{Threshold: int(words[0].Int64()), OwnerCount: len(owners)}
+	for i, owner := range owners {
+		o := SafeOwner{Address: owner}
+		if depth < maxSafeDepth && i < maxSafeOwners {
+			o.Safe = fetchSafe(ctx, call, owner, depth+1)
+		}
+		safe.Owners = append(safe.Owners, o)
+	}
+	return safe
+}
+
+// abiWords splits ABI-encoded return data into 32-byte words.
+func abiWords(hexData string) []*big.Int {
+	data := strings.TrimPrefix(hexData, "0x")
+	var words []*big.Int
+	for len(data) >= 64 {
+		w, ok := new(big.Int).SetString(data[:64], 16)
+		if !ok {
+			return nil
+		}
+		words = append(words, w)
+		data = data[64:]
+	}
+	return words
+}
+
+// abiDecodeAddressArray decodes a lone dynamic address[] return value.
+func abiDecodeAddressArray(hexData string) ([]string, bool) {
+	words := abiWords(hexData)
+	if len(words) < 2 || !words[0].IsInt64() || words[0].Int64()%32 != 0 {
+		return nil, false
+	}
+	lenIdx := int(words[0].Int64() / 32)
+	if lenIdx >= len(words) || !words[lenIdx].IsInt64() {
+		return nil, false
+	}
+	n := int(words[lenIdx].Int64())
+	if lenIdx+1+n > len(words) {
+		return nil, false
+	}
+
+	addrs := make([]string, 0, n)
+	for _, w := range words[lenIdx+1 : lenIdx+1+n] {
+		if w.BitLen() > 160 {
+			return nil, false
+		}
+		addrs = append(addrs, fmt.Sprintf("0x%040x", w))
+	}
+	return addrs, true
+}
+
+// safeOwners flattens the owner tree (nested Safes included), deduplicated.
+func safeOwners(safe *SafeInfo) []string {
+	var out []string
+	seen := map[string]bool{}
+	var walk func(*SafeInfo)
+	walk = func(s *SafeInfo) {
+		if s == nil {
+			return
+		}
+		for _, o := range s.Owners {
+			if !seen[o.Address] {
+				seen[o.Address] = true
+				out = append(out, o.Address)
+			}
+			walk(o.Safe)
+		}
+	}
+	walk(safe)
+	return out
+}
+
+// safeDetail is the human-readable ValidationDetails fragment.
+func safeDetail(safe *SafeInfo) string {
+	if safe == nil {
+		return ""
+	}
+	nested := 0
+	for _, o := range safe.Owners {
+		if o.Safe != nil {
+			nested++
+		}
+	}
+	detail := fmt.Sprintf("Gnosis Safe (%d of %d Owners)", safe.Threshold, safe.OwnerCount)
+	if nested > 0 {
+		detail += fmt.Sprintf(" - %d Nested Safes", nested)
+	}
+	return detail
+}
@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"context"
+	"net/http"
+)
+
+// ---------------------------------------------------------
+// HONEYPOT / SCAM TOKENS (tokens holders cannot sell)
+// ---------------------------------------------------------
+
+// Each token checked costs up to 4 calls (source, implementation source,
+// owner, Safe check)
+const maxScamTokenChecks = 10
+
+// Honeypot levers, as named in the reasons
+var honeypotPrivileges = []struct{ Privilege, Label string }{
+	{"BLACKLIST", "Blacklist"},
+	{"TAX", "Adjustable Tax"},
+	{"TRADING", "Trading Switch"},
+	{"LIMIT", "Adjustable Limits"},
+}
+
+// honeypotLevers lists the levers a token's owner still holds to block or
+// confiscate sells: blacklisting holders, raising the transfer tax (to
+// 100%), switching trading off, or shrinking the max transaction.
+func honeypotLevers(cr *ContractRisk) []string {
+	if cr == nil || !cr.Token || cr.OwnerType == "RENOUNCED" {
+		return nil
+	}
+	var levers []string
+	for _, h := range honeypotPrivileges {
+		if hasPrivilege(cr, h.Privilege) {
+			levers = append(levers, h.Label)
+		}
+	}
+	return levers
+}
+
+// flagScamTokens checks the verified ABI of the largest non-stablecoin
+// holdings and records their honeypot levers. Tokens without verified
+// source are left unchecked.
+func flagScamTokens(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, holdings []TokenBalance, apiKey string) {
+	call := etherscanCaller(client, baseURL, chain.ID, apiKey)
+	checked := 0
+	for i := range holdings {
+		t := &holdings[i]
+		if t.Stablecoin {
+			continue
+		}
+		if checked >= maxScamTokenChecks || ctx.Err() != nil {
+			return
+		}
+		checked++
+
+		src, err := fetchContractSource(ctx, client, baseURL, chain.ID, t.Contract, apiKey)
+		if err != nil || !src.Verified {
+			continue
+		}
+		cr := newContractRisk("", src)
+		if src.Proxy && src.Implementation != "" {
+			if impl, err := fetchContractSource(ctx, client, baseURL, chain.ID, src.Implementation, apiKey); err == nil {
+				addPrivileges(cr, impl.ABI)
+			}
+		}
+		if len(honeypotLevers(cr)) == 0 {
+			continue
+		}
+		loadContractOwner(ctx, call, t.Contract, cr) // Renounced levers are harmless
+		t.HoneypotLevers = honeypotLevers(cr)
+	}
+}
@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// ERC-20 TOKEN HOLDINGS
+// ---------------------------------------------------------
+
+// Each holding costs one tokenbalance call; keep free-tier keys usable
+const maxTokenLookups = 20
+
+// Stablecoin transfers newer than this count as recent flow volume
+const stablecoinFlowWindow = 30 * 24 * time.Hour
+
+// Known stablecoin contracts per chainid (lower-case). Matched by address,
+// never by symbol: fake "USDT" tokens are a common scam vector.
+var stablecoinContracts = map[string]map[string]string{
+	"1": {
+		"0xdac17f958d2ee523a2206206994597c13d831ec7": "USDT",
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": "USDC",
+		"0x6b175474e89094c44da98b954eedeac495271d0f": "DAI",
+		"0x6c3ea9036406852006290770bedfcaba0e23a0e8": "PYUSD",
+	},
+	"137": {
+		"0xc2132d05d31c914a87c6611c10748aeb04b58e8f": "USDT",
+		"0x3c499c542cef5e3811e1192ce70d8cc03d5c3359": "USDC",
+	},
+	"56": {
+		"0x55d398326f99059ff775485246999027b3197955": "USDT",
+		"0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d": "USDC",
+	},
+	"42161": {
+		"0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9": "USDT",
+		"0xaf88d065e77c8cc2239327c5edb3a432268e5831": "USDC",
+	},
+	"10": {
+		"0x94b008aa00579c1307b0ef2c499ad98a8ce58e58": "USDT",
+		"0x0b2c639c533813f4aa9d7837caf62653d097ff85": "USDC",
+	},
+	"8453": {
+		"0x833589fcd6edb6e08f4c7c32d4f71b54bda02913": "USDC",
+	},
+	"43114": {
+		"0x9702230a8ea53601f5cd2dc00fdbc13d4df4a8c7": "USDT",
+		"0xb97ef9ef8734c71904d8002f8b6bc66dd9c48a6e": "USDC",
+	},
+}
+
+// fetchTokenHoldings discovers the ERC-20 tokens an address has touched
+// (tokentx) and reads the current balance of each (tokenbalance).
+// Zero balances are dropped; stablecoins are looked up first. The same
+// transfers give the recent stablecoin flows, per symbol.
+func fetchTokenHoldings(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, cleanAddr, apiKey string) ([]TokenBalance, []StablecoinActivity, error) {
+	// 1. Discover tokens from transfer history (most recent first)
+	txURL := fmt.Sprintf("%s?chainid=%s&module=account&action=tokentx&address=%s&page=1&offset=1000&sort=desc&apikey=%s", baseURL, chain.ID, cleanAddr, apiKey)
+
+	var txResp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Result  []struct {
+			ContractAddress string `json:"contractAddress"`
+			TokenName       string `json:"tokenName"`
+			TokenSymbol     string `json:"tokenSymbol"`
+			TokenDecimal    string `json:"tokenDecimal"`
+			From            string `json:"from"`
+			To              string `json:"to"`
+			Value           string `json:"value"`
+			TimeStamp       string `json:"timeStamp"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, client, txURL, &txResp); err != nil {
+		// Etherscan errors put a string in "result", which fails to decode here
+		return nil, nil, err
+	}
+	if txResp.Status == "0" && txResp.Message != "No transactions found" {
+		return nil, nil, fmt.Errorf("tokentx: %s", txResp.Message)
+	}
+
+	stables := stablecoinContracts[chain.ID]
+	seen := map[string]bool{}
+	var candidates []TokenBalance
+	flows := map[string]*StablecoinActivity{}
+	var flowOrder []string
+	since := time.Now().Add(-stablecoinFlowWindow).Unix()
+	for _, t := range txResp.Result {
+		contract := strings.ToLower(t.ContractAddress)
+
+		// Recent stablecoin volume (transfers are newest first)
+		if symbol, ok := stables[contract]; ok {
+			ts, _ := strconv.ParseInt(t.TimeStamp, 10, 64)
+			raw, okValue := new(big.Int).SetString(t.Value, 10)
+			if ts >= since && okValue {
+				decimals, _ := strconv.Atoi(t.TokenDecimal)
+				if flows[symbol] == nil {
+					flows[symbol] = &StablecoinActivity{Symbol: symbol}
+					flowOrder = append(flowOrder, symbol)
+				}
+				f := flows[symbol]
+				amount := tokenAmount(raw, decimals)
+				if strings.EqualFold(t.To, cleanAddr) {
+					f.InflowUSD += amount
+				}
+				if strings.EqualFold(t.From, cleanAddr) {
+					f.OutflowUSD += amount
+				}
+				f.Transfers++
+			}
+		}
+
+		if seen[contract] {
+			continue
+		}
+		seen[contract] = true
+
+		decimals, _ := strconv.Atoi(t.TokenDecimal)
+		tb := TokenBalance{
+			ChainID:  chain.ID,
+			Contract: contract,
+			Symbol:   t.TokenSymbol,
+			Name:     t.TokenName,
+			Decimals: decimals,
+		}
+		if symbol, ok := stables[contract]; ok {
+			tb.Symbol = symbol
+			tb.Stablecoin = true
+		}
+		candidates = append(candidates, tb)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Stablecoin && !candidates[j].Stablecoin })
+	if len(candidates) > maxTokenLookups {
+		candidates = candidates[:maxTokenLookups]
+	}
+
+	// 2. Current balance of each token
+	var holdings []TokenBalance
+	for _, tb := range candidates {
+		balURL := fmt.Sprintf("%s?chainid=%s&module=account&action=tokenbalance&contractaddress=%s&address=%s&tag=latest&apikey=%s", baseURL, chain.ID, tb.Contract, cleanAddr, apiKey)
+
+		var balResp struct {
+			Status string `json:"status"`
+			Result string `json:"result"`
+		}
+		if err := getJSON(ctx, client, balURL, &balResp); err != nil {
+			if ctx.Err() != nil {
+				return holdings, stablecoinBreakdown(holdings, flows, flowOrder), err
+			}
+			continue
+		}
+		raw, ok := new(big.Int).SetString(balResp.Result, 10)
+		if balResp.Status != "1" || !ok || raw.Sign() == 0 {
+			continue
+		}
+
+		tb.Balance = FormatUnits(raw, tb.Decimals, tb.Symbol)
+		tb.Amount = tokenAmount(raw, tb.Decimals)
+		holdings = append(holdings, tb)
+	}
+	return holdings, stablecoinBreakdown(holdings, flows, flowOrder), nil
+}
+
+// tokenAmount converts raw token units to a float amount.
+func tokenAmount(raw *big.Int, decimals int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))).Float64()
+	return f
+}
+
+// stablecoinBreakdown joins stablecoin balances and recent flows per symbol.
+func stablecoinBreakdown(holdings []TokenBalance, flows map[string]*StablecoinActivity, order []string) []StablecoinActivity {
+	var out []StablecoinActivity
+	for _, tb := range holdings {
+		if !tb.Stablecoin {
+			continue
+		}
+		if flows[tb.Symbol] == nil {
+			flows[tb.Symbol] = &StablecoinActivity{Symbol: tb.Symbol}
+			order = append(order, tb.Symbol)
+		}
+		flows[tb.Symbol].BalanceUSD += tb.Amount
+	}
+	for _, symbol := range order {
+		out = append(out, *flows[symbol])
+	}
+	return out
+}
+
+// mergeStablecoins adds b's per-symbol figures into a (multi-chain totals).
+func mergeStablecoins(a, b []StablecoinActivity) []StablecoinActivity {
+	for _, s := range b {
+		merged := false
+		for i := range a {
+			if a[i].Symbol == s.Symbol {
+				a[i].BalanceUSD += s.BalanceUSD
+				a[i].InflowUSD += s.InflowUSD
+				a[i].OutflowUSD += s.OutflowUSD
+				a[i].Transfers += s.Transfers
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			a = append(a, s)
+		}
+	}
+	return a
+}
+
+// stablecoinDetail is the human-readable ValidationDetails fragment for
+// recent flows (balances are already in tokenDetail).
+func stablecoinDetail(stables []StablecoinActivity) string {
+	var in, out float64
+	for _, s := range stables {
+		in += s.InflowUSD
+		out += s.OutflowUSD
+	}
+	if in == 0 && out == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Stablecoin Flows (%dd): $%.2f In / $%.2f Out", int(stablecoinFlowWindow.Hours()/24), in, out)
+}
+
+// stablecoinTotal sums stablecoin holdings (all pegged 1:1 to USD).
+func stablecoinTotal(holdings []TokenBalance) float64 {
+	total := 0.0
+	for _, tb := range holdings {
+		if tb.Stablecoin {
+			total += tb.Amount
+		}
+	}
+	return total
+}
+
+// tokenDetail is the human-readable ValidationDetails fragment.
+func tokenDetail(holdings []TokenBalance) string {
+	if len(holdings) == 0 {
+		return ""
+	}
+	detail := fmt.Sprintf("Holds %d Tokens", len(holdings))
+	if total := stablecoinTotal(holdings); total > 0 {
+		detail += fmt.Sprintf(" ($%.2f Stablecoins)", total)
+	}
+	return detail
+}
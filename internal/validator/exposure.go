@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// INDIRECT EXPOSURE (multi-hop taint)
+// ---------------------------------------------------------
+
+const (
+	// Deeper walks multiply API calls for little signal
+	MaxExposureHops = 3
+	// Counterparties followed per address, by value moved
+	exposureFanout = 10
+	// Histories fetched per trace, across all hops
+	maxExposureLookups = 50
+)
+
+// exposureTracer walks counterparties depth-first through one chain's
+// providers. Histories and screening results are memoized per trace.
+type exposureTracer struct {
+	ctx       context.Context
+	pc        ProviderChain
+	histories map[string][]Transaction
+	flags     map[string]string // address -> label ("" = clean)
+	lookups   int
+	screen    bool // Watchlist reachable
+}
+
+// traceExposure fills profile.Exposure with the per-hop share of the
+// address's funds that reaches a flagged address within hops.
+func traceExposure(ctx context.Context, pc ProviderChain, address string, txs []Transaction, hops int, network string, profile *WalletProfile) {
+	hops = min(hops, MaxExposureHops)
+	t := &exposureTracer{
+		ctx:       ctx,
+		pc:        pc,
+		histories: map[string][]Transaction{strings.ToLower(address): txs},
+		flags:     map[string]string{},
+		screen:    true,
+	}
+	shares, labels := t.exposure(strings.ToLower(address), hops, map[string]bool{})
+
+	for hop, share := range shares {
+		if share <= 0 {
+			continue
+		}
+		profile.Exposure = append(profile.Exposure, HopExposure{
+			Hop:     hop + 1,
+			Network: network,
+			Percent: math.Round(share*10000) / 100,
+			Flagged: sortedKeys(labels[hop]),
+		})
+	}
+	if t.lookups >= maxExposureLookups {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("Exposure Trace Capped at %d Lookups", maxExposureLookups))
+	}
+}
+
+// exposure returns, per hop, the fraction of address's funds that first
+// reaches a flagged address at that hop, and the flagged labels per hop.
+// Funds are split between counterparties by value moved (by tx count if
+// no values are known) and taint propagates proportionally.
+func (t *exposureTracer) exposure(address string, depth int, path map[string]bool) ([]float64, []map[string]bool) {
+	shares := make([]float64, depth)
+	labels := make([]map[string]bool, depth)
+	for i := range labels {
+		labels[i] = map[string]bool{}
+	}
+
+	txs, ok := t.history(address)
+	if !ok {
+		return shares, labels
+	}
+	weights := counterpartyWeights(address, txs)
+	path[address] = true
+	defer delete(path, address)
+
+	// Follow the heaviest counterparties first; the rest still count
+	// towards hop 1 but are not expanded
+	parties := make([]string, 0, len(weights))
+	for party := range weights {
+		parties = append(parties, party)
+	}
+	sort.Slice(parties, func(i, j int) bool {
+		if weights[parties[i]] != weights[parties[j]] {
+			return weights[parties[i]] > weights[parties[j]]
+		}
+		return parties[i] < parties[j]
+	})
+
+	for i, party := range parties {
+		w := weights[party]
+		if label := t.flag(party); label != "" {
+			shares[0] += w
+			labels[0][label] = true
+			continue
+		}
+		if depth == 1 || i >= exposureFanout || path[party] || t.ctx.Err() != nil {
+			continue
+		}
+		sub, subLabels := t.exposure(party, depth-1, path)
+		for hop, share := range sub {
+			shares[hop+1] += w * share
+			for label := range subLabels[hop] {
+				labels[hop+1][label] = true
+			}
+		}
+	}
+	return shares, labels
+}
+
+// history returns the address's txs, fetching them once per trace.
+func (t *exposureTracer) history(address string) ([]Transaction, bool) {
+	if txs, ok := t.histories[address]; ok {
+		return txs, true
+	}
+	if t.lookups >= maxExposureLookups {
+		return nil, false
+	}
+	t.lookups++
+	hist, _, _, err := t.pc.GetTransactions(t.ctx, address)
+	if err != nil {
+		t.histories[address] = nil
+		return nil, false
+	}
+	t.histories[address] = hist.Txs
+	return hist.Txs, true
+}
+
+// flag returns why an address is flagged (known threat or sanctioned),
+// or "" if it is clean.
+func (t *exposureTracer) flag(address string) string {
+	if label, ok := t.flags[address]; ok {
+		return label
+	}
+	label := ""
+	if l, threat := addressThreat(address, false); threat {
+		label = l.Entity
+	}
+	if label == "" && t.screen {
+		resp, err := CheckWatchlist(address)
+		switch {
+		case err != nil:
+			t.screen = false // Engine down: known threats only from here on
+		case resp.Sanctioned:
+			label = fmt.Sprintf("%s Sanctioned", resp.Source)
+		}
+	}
+	t.flags[address] = label
+	return label
+}
+
+// counterpartyWeights splits an address's activity between its
+// counterparties. Weights sum to 1.
+func counterpartyWeights(address string, txs []Transaction) map[string]float64 {
+	byValue := map[string]float64{}
+	byCount := map[string]float64{}
+	var totalValue, totalCount float64
+	for _, tx := range txs {
+		party := strings.ToLower(tx.From)
+		if strings.EqualFold(tx.From, address) {
+			party = strings.ToLower(tx.To)
+		}
+		if party == "" || party == address {
+			continue
+		}
+		byCount[party]++
+		totalCount++
+		if v, ok := new(big.Float).SetString(tx.Value); ok {
+			f, _ := v.Float64()
+			byValue[party] += f
+			totalValue += f
+		}
+	}
+
+	weights, total := byValue, totalValue
+	if totalValue <= 0 {
+		weights, total = byCount, totalCount
+	}
+	for party := range weights {
+		weights[party] /= total
+	}
+	return weights
+}
+
+// exposureDetail is the human-readable ValidationDetails fragment.
+func exposureDetail(exposure []HopExposure) string {
+	var parts []string
+	for _, e := range exposure {
+		parts = append(parts, fmt.Sprintf("%.1f%% at Hop %d", e.Percent, e.Hop))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Indirect Exposure: " + strings.Join(parts, ", ")
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
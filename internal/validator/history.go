@@ -0,0 +1,199 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// RISK SCORE HISTORY (previous score and trend, per address)
+// ---------------------------------------------------------
+
+// Score changes smaller than this are reported as UNCHANGED
+const trendTolerance = 0.5
+
+var (
+	historyMu    sync.Mutex
+	scoreHistory HistoryStore // nil = no history (the default)
+)
+
+// SetHistory replaces the score history store; nil disables it.
+func SetHistory(h HistoryStore) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	scoreHistory = h
+}
+
+func currentHistory() HistoryStore {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return scoreHistory
+}
+
+// LastRecord returns the most recent stored run of the address on the
+// network; nil if there is none or no history store is set.
+func LastRecord(address, network string) (*ScoreRecord, error) {
+	h := currentHistory()
+	if h == nil {
+		return nil, nil
+	}
+	return h.Last(historyAddress(address), network)
+}
+
+// RecordHistory is what Analyze does with a finished profile when a
+// history store is set: set profile.RiskTrend from the previous record,
+// then store this run. It is for profiles analyzed with the store off.
+func RecordHistory(profile *WalletProfile) { recordHistory(profile) }
+
+// recordHistory sets profile.RiskTrend from the previous record, then
+// stores this run. Store errors are logged, never fatal to the profile.
+func recordHistory(profile *WalletProfile) {
+	h := currentHistory()
+	if h == nil || profile == nil || !profile.IsValid {
+		return
+	}
+	address := historyAddress(profile.Address)
+
+	prev, err := h.Last(address, profile.Network)
+	if err != nil {
+		slog.Warn("⚠️ [HISTORY] Read failed", "err", err)
+	} else if prev != nil {
+		delta := profile.RiskScore - prev.RiskScore
+		direction := "UNCHANGED"
+		if delta >= trendTolerance {
+			direction = "RISING"
+		} else if delta <= -trendTolerance {
+			direction = "FALLING"
+		}
+		profile.RiskTrend = &RiskTrend{
+			PreviousScore: prev.RiskScore,
+			PreviousGrade: prev.RiskGrade,
+			PreviousAt:    prev.Timestamp,
+			Delta:         delta,
+			Direction:     direction,
+		}
+	}
+
+	counterparties := []string{} // Empty, not nil: recorded, just none
+	for _, c := range Counterparties(profile) {
+		counterparties = append(counterparties, c.Address)
+	}
+	err = h.Save(ScoreRecord{
+		Address:        address,
+		Network:        profile.Network,
+		Timestamp:      time.Now().UTC(),
+		RiskScore:      profile.RiskScore,
+		RiskGrade:      profile.RiskGrade,
+		RiskBreakdown:  profile.RiskBreakdown,
+		RiskReasons:    profile.RiskReasons,
+		Balance:        profile.Balance,
+		BalanceRaw:     profile.BalanceRaw,
+		Decimals:       profile.Decimals,
+		Symbol:         profile.Symbol,
+		BalanceUSD:     profile.BalanceUSD,
+		TxCount:        profile.TxCount,
+		Counterparties: counterparties,
+	})
+	if err != nil {
+		slog.Warn("⚠️ [HISTORY] Write failed", "err", err)
+	}
+}
+
+// historyAddress lowercases hex addresses, whose case is only a checksum.
+func historyAddress(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// ---------------------------------------------------------
+// BACKEND: JSON Lines file (one record per line, append-only)
+// ---------------------------------------------------------
+
+// FileHistory appends records to a local JSON Lines file.
+type FileHistory struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileHistory opens (creating if needed) the history file at path.
+func NewFileHistory(path string) (*FileHistory, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return &FileHistory{Path: path}, nil
+}
+
+func (h *FileHistory) Last(address, network string) (*ScoreRecord, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.Open(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// A malformed line (a torn write from a crash, a hand edit) is skipped,
+	// so the records after it still count
+	var last *ScoreRecord
+	skipped := 0
+	r := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var rec ScoreRecord
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				skipped++
+				slog.Debug("⚠️ [HISTORY] Skipping malformed line", "path", h.Path, "line", lineNo, "err", jsonErr)
+			} else if rec.Address == address && rec.Network == network {
+				if last == nil || !rec.Timestamp.Before(last.Timestamp) {
+					last = &rec
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return last, err
+		}
+	}
+	if skipped > 0 {
+		slog.Warn("⚠️ [HISTORY] Skipped malformed lines", "path", h.Path, "lines", skipped)
+	}
+	return last, nil
+}
+
+func (h *FileHistory) Save(rec ScoreRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.OpenFile(h.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
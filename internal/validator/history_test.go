@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileHistoryLastSkipsMalformedLines(t *testing.T) {
+	const address = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	record := func(score float64, at time.Time) string {
+		line, err := json.Marshal(ScoreRecord{Address: address, Network: "Ethereum Mainnet", Timestamp: at, RiskScore: score})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(line) + "\n"
+	}
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	src := record(10, t0) +
+		`{"address": "` + address + `", "network": "Ethereum Mai` + "\n" + // Torn by a crash
+		"\n" +
+		"not json\n" +
+		record(30, t0.Add(2*time.Hour)) +
+		record(20, t0.Add(time.Hour)) + // Older, written late
+		record(40, t0.Add(3*time.Hour))[:20] // Torn final line, no newline
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := NewFileHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := h.Last(address, "Ethereum Mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last == nil || last.RiskScore != 30 {
+		t.Fatalf("got %+v, want the record scored 30", last)
+	}
+	if other, err := h.Last(address, "Polygon"); err != nil || other != nil {
+		t.Errorf("other network: got %+v, %v", other, err)
+	}
+
+	// Saving after a torn line still leaves a readable file
+	if err := os.WriteFile(path, []byte(src+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Save(ScoreRecord{Address: address, Network: "Ethereum Mainnet", Timestamp: t0.Add(4 * time.Hour), RiskScore: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := h.Last(address, "Ethereum Mainnet"); err != nil || last == nil || last.RiskScore != 50 {
+		t.Errorf("after Save: got %+v, %v", last, err)
+	}
+}
+
+func TestFileHistoryLastMissingFile(t *testing.T) {
+	h, err := NewFileHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, err := h.Last("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "Bitcoin Mainnet"); err != nil || last != nil {
+		t.Errorf("got %+v, %v", last, err)
+	}
+}
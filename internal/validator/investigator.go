@@ -1,75 +1,403 @@
 package validator
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
-)
 
-// Response from the Watchlist Engine Service
-type EngineResponse struct {
-	Sanctioned bool   `json:"sanctioned"`
-	Currency   string `json:"currency"`
-	Source     string `json:"source"`
-}
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
+)
 
 // ---------------------------------------------------------
 // CLIENT: Check Watchlist (HTTP)
 // ---------------------------------------------------------
 
-func CheckWatchlist(address string) (*EngineResponse, error) {
-	// Get Engine URL from Env (defaults to local for dev, or docker service name)
-	engineURL := os.Getenv("WATCHLIST_ENGINE_URL")
+// Embedded (in-process) watchlist, used instead of the HTTP engine
+// when a database path is set or a store is injected via UseWatchlist.
+var (
+	embeddedMu    sync.Mutex
+	embeddedStore *watchlist.Store
+	embeddedPath  string
+	watchlistURL  = defaultEngineURL
+)
+
+// defaultEngineURL is the Watchlist Engine of a local dev setup.
+const defaultEngineURL = "http://localhost:8080"
+
+// SetWatchlistEngine points CheckWatchlist and CheckName at the Watchlist
+// Engine at engineURL (empty = http://localhost:8080), or, with a dbPath,
+// at that database opened in-process instead.
+func SetWatchlistEngine(engineURL, dbPath string) {
+	embeddedMu.Lock()
+	defer embeddedMu.Unlock()
 	if engineURL == "" {
-		engineURL = "http://localhost:8080"
+		engineURL = defaultEngineURL
 	}
+	watchlistURL, embeddedPath = engineURL, dbPath
+}
 
-	// Short timeout - we don't want validation to hang if engine is down
-	client := &http.Client{Timeout: 2 * time.Second}
-	url := fmt.Sprintf("%s/check?address=%s", engineURL, address)
+// WatchlistEngine returns what SetWatchlistEngine set.
+func WatchlistEngine() (engineURL, dbPath string) {
+	embeddedMu.Lock()
+	defer embeddedMu.Unlock()
+	return watchlistURL, embeddedPath
+}
 
-	resp, err := client.Get(url)
+// UseWatchlist makes CheckWatchlist query the given store directly
+// instead of calling the remote Watchlist Engine. Pass nil to revert.
+func UseWatchlist(store *watchlist.Store) {
+	embeddedMu.Lock()
+	defer embeddedMu.Unlock()
+	embeddedStore = store
+}
+
+func localWatchlist() (*watchlist.Store, error) {
+	embeddedMu.Lock()
+	defer embeddedMu.Unlock()
+
+	if embeddedStore != nil {
+		return embeddedStore, nil
+	}
+
+	if embeddedPath == "" {
+		return nil, nil
+	}
+
+	store, err := watchlist.Open(embeddedPath)
 	if err != nil {
-		return nil, fmt.Errorf("connection refused")
+		return nil, err
 	}
-	defer resp.Body.Close()
+	embeddedStore = store
+	return store, nil
+}
+
+// watchlistHTTP is shared by every engine check, so screening many
+// addresses reuses its connections.
+var watchlistHTTP = &http.Client{Timeout: 2 * time.Second}
+
+// EngineWatchlist is the default WatchlistClient: the embedded store
+// (UseWatchlist, or SetWatchlistEngine's database), else the remote
+// Watchlist Engine (SetWatchlistEngine's URL).
+type EngineWatchlist struct{}
+
+func (EngineWatchlist) Check(ctx context.Context, address string) (*EngineResponse, error) {
+	return checkWatchlist(ctx, address)
+}
+
+// CheckWatchlist screens an address with the default watchlist client.
+func CheckWatchlist(address string) (*EngineResponse, error) {
+	return checkWatchlist(context.Background(), address)
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("server error %d", resp.StatusCode)
+func checkWatchlist(ctx context.Context, address string) (*EngineResponse, error) {
+	if res := primedCheck(address); res != nil {
+		return res, nil
 	}
 
+	// Small deployments can skip the engine entirely and read the DB in-process
+	store, err := localWatchlist()
+	if err != nil {
+		return nil, fmt.Errorf("local watchlist: %w", err)
+	}
+	if store != nil {
+		res, err := store.Check(address)
+		if err != nil {
+			return nil, err
+		}
+		resp := &EngineResponse{Sanctioned: res.Sanctioned, Currency: res.Currency, Source: res.Source, EntityID: res.EntityID, EntityName: res.EntityName, CoListed: res.CoListed}
+		if version, err := store.ListVersion(ctx); err == nil && version != (watchlist.ListVersion{}) {
+			resp.ListVersion = &version
+		}
+		return resp, nil
+	}
+
+	// Engine URL from SetWatchlistEngine (local for dev, or docker service name)
+	engineURL, _ := WatchlistEngine()
+
+	// Short timeout - we don't want validation to hang if engine is down.
+	// Transient failures are retried, but the whole check is bounded.
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	reqURL := engineURL + "/check?" + url.Values{"address": {address}}.Encode()
+
 	var result EngineResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	err = doJSON(ctx, watchlistHTTP, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	}, &result)
+	if err != nil {
+		return nil, engineError(err)
 	}
 	return &result, nil
 }
 
+// engineError shortens a failed engine call for the profile's details.
+func engineError(err error) error {
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return fmt.Errorf("server error %d", httpErr.StatusCode)
+	case isRetryable(err):
+		return fmt.Errorf("connection refused")
+	}
+	return err
+}
+
+func (EngineWatchlist) BatchCheck(ctx context.Context, addresses []string) (map[string]*EngineResponse, error) {
+	return BatchCheckWatchlist(ctx, addresses)
+}
+
+// BatchCheckWatchlist screens many addresses with the default watchlist
+// client: one query against the embedded store, or POST /check to the
+// engine per watchlist.MaxBatchCheck addresses. Every address gets an
+// entry; hits carry the currency and source but not the SDN entity.
+func BatchCheckWatchlist(ctx context.Context, addresses []string) (map[string]*EngineResponse, error) {
+	out := make(map[string]*EngineResponse, len(addresses))
+	if len(addresses) == 0 {
+		return out, nil
+	}
+
+	store, err := localWatchlist()
+	if err != nil {
+		return nil, fmt.Errorf("local watchlist: %w", err)
+	}
+	if store != nil {
+		results, err := store.BatchCheck(addresses)
+		if err != nil {
+			return nil, err
+		}
+		var version *watchlist.ListVersion
+		if v, err := store.ListVersion(ctx); err == nil && v != (watchlist.ListVersion{}) {
+			version = &v
+		}
+		for address, res := range results {
+			out[address] = &EngineResponse{Sanctioned: res.Sanctioned, Currency: res.Currency, Source: res.Source, ListVersion: version}
+		}
+		return out, nil
+	}
+
+	engineURL, _ := WatchlistEngine()
+	for start := 0; start < len(addresses); start += watchlist.MaxBatchCheck {
+		body, err := json.Marshal(watchlist.BatchCheckRequest{Addresses: addresses[start:min(start+watchlist.MaxBatchCheck, len(addresses))]})
+		if err != nil {
+			return nil, err
+		}
+		var result watchlist.BatchCheckResponse
+		chunkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = doJSON(chunkCtx, watchlistHTTP, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(chunkCtx, "POST", engineURL+"/check", bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			return req, err
+		}, &result)
+		cancel()
+		if err != nil {
+			return nil, engineError(err)
+		}
+		for _, res := range result.Results {
+			out[res.Address] = &EngineResponse{Sanctioned: res.Sanctioned, Currency: res.Currency, Source: res.Source, ListVersion: result.ListVersion}
+		}
+	}
+	for _, address := range addresses {
+		if out[address] == nil {
+			return nil, fmt.Errorf("engine returned no result for %s", address)
+		}
+	}
+	return out, nil
+}
+
+// Clean results screened ahead of a batch (PrimeWatchlist), by address.
+// Batches running at once share entries; refs counts their holders.
+var (
+	primedMu sync.Mutex
+	primed   = map[string]*primedResult{}
+)
+
+type primedResult struct {
+	resp *EngineResponse
+	refs int
+}
+
+// PrimeWatchlist screens a batch's addresses with BatchCheckWatchlist up
+// front, so the default client answers the clean ones without a round trip
+// each. Hits aren't kept: checking them fetches their SDN entity. Call
+// release once the batch is done.
+func PrimeWatchlist(ctx context.Context, addresses []string) (release func(), err error) {
+	results, err := BatchCheckWatchlist(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	var clean []string
+	primedMu.Lock()
+	for address, res := range results {
+		if res.Sanctioned {
+			continue
+		}
+		clean = append(clean, address)
+		if p := primed[address]; p != nil {
+			p.refs++
+		} else {
+			primed[address] = &primedResult{resp: res, refs: 1}
+		}
+	}
+	primedMu.Unlock()
+	return func() {
+		primedMu.Lock()
+		defer primedMu.Unlock()
+		for _, address := range clean {
+			if p := primed[address]; p != nil {
+				if p.refs--; p.refs == 0 {
+					delete(primed, address)
+				}
+			}
+		}
+		clean = nil // A second call is a no-op
+	}, nil
+}
+
+// primedCheck returns a copy of address's primed result, or nil.
+func primedCheck(address string) *EngineResponse {
+	primedMu.Lock()
+	defer primedMu.Unlock()
+	p := primed[address]
+	if p == nil {
+		return nil
+	}
+	copied := *p.resp
+	return &copied
+}
+
 // ---------------------------------------------------------
 // CORE: Investigator Logic
 // ---------------------------------------------------------
 
-// Known heuristic threats (fallback/supplementary to OFAC)
+// OfflineGrade is the grade of a Partial profile that isn't sanctioned:
+// with no chain data there is nothing else to grade.
+const OfflineGrade = "UNSCORED (Offline)"
+
+// Known heuristic threats (fallback/supplementary to OFAC), served as
+// MIXER labels by BuiltinLabels
 var knownThreats = map[string]string{
 	"0xd90e2f925da726b50c4ed8d0fb90ad053324f31b": "Tornado Cash Router",
 }
 
-// Investigate analyzes risk using both Heuristics and the Remote Watchlist Engine
+// Investigator scores profiles with injected dependencies: the watchlist
+// client, the label providers, the rules and the clock. Safe for
+// concurrent use.
+type Investigator struct {
+	watchlist WatchlistClient
+	labels    []Labels
+	rules     RiskRules
+	custom    []*CustomRule
+	now       func() time.Time
+}
+
+// InvestigatorOption customizes an Investigator.
+type InvestigatorOption func(*Investigator)
+
+// WithWatchlist sets the sanctions watchlist client.
+func WithWatchlist(client WatchlistClient) InvestigatorOption {
+	return func(inv *Investigator) { inv.watchlist = client }
+}
+
+// WithLabelProviders sets the counterparty label providers, asked in order.
+// No providers means no counterparty labels.
+func WithLabelProviders(providers ...Labels) InvestigatorOption {
+	return func(inv *Investigator) { inv.labels = append([]Labels{}, providers...) }
+}
+
+// WithRules sets the policy (validated by NewInvestigator).
+func WithRules(rules RiskRules) InvestigatorOption {
+	return func(inv *Investigator) { inv.rules = rules }
+}
+
+// WithClock sets what "now" is for ages, idle times and decay.
+func WithClock(now func() time.Time) InvestigatorOption {
+	return func(inv *Investigator) { inv.now = now }
+}
+
+// NewInvestigator returns an Investigator. Dependencies not given default
+// to the process-wide ones: EngineWatchlist, the label providers of
+// SetLabelProviders, the rules of SetRiskRules and the system clock.
+func NewInvestigator(opts ...InvestigatorOption) (*Investigator, error) {
+	inv := &Investigator{
+		watchlist: EngineWatchlist{},
+		labels:    currentLabelProviders(),
+		rules:     currentRiskRules(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(inv)
+	}
+	if inv.watchlist == nil || inv.now == nil {
+		return nil, errors.New("investigator: watchlist client and clock must not be nil")
+	}
+	if err := inv.rules.Validate(); err != nil {
+		return nil, err
+	}
+	custom, err := compileCustomRules(inv.rules.Custom)
+	if err != nil {
+		return nil, err
+	}
+	inv.custom = custom
+	return inv, nil
+}
+
+// Rules returns the investigator's policy.
+func (inv *Investigator) Rules() RiskRules { return inv.rules }
+
+// Investigate scores a profile with the process-wide dependencies (see
+// NewInvestigator).
 func Investigate(profile *WalletProfile, txs []Transaction) {
+	inv := &Investigator{
+		watchlist: EngineWatchlist{},
+		labels:    currentLabelProviders(),
+		rules:     currentRiskRules(), // Thresholds and offsets (RISK_RULES_FILE)
+		custom:    currentCustomRules(),
+		now:       time.Now,
+	}
+	inv.Investigate(context.Background(), profile, txs)
+}
+
+// Investigate analyzes risk using both Heuristics and the Watchlist
+func (inv *Investigator) Investigate(ctx context.Context, profile *WalletProfile, txs []Transaction) {
+	rules := inv.rules
+	now := inv.now()
 	var fraudScore, repScore, lendScore float64
 	var reasons []RiskReason
 
-	// Helper to track risk
-	addRisk := func(category, desc string, offset float64) {
-		reasons = append(reasons, RiskReason{
+	screenedAt := now.UTC()
+	profile.ScreenedAt = &screenedAt
+	profile.RiskSchema = RiskSchemaVersion
+	profile.RiskPolicy = rules.Policy
+	disabled := rules.disabledRules()
+
+	// Helper to track risk (ruleID is the rules-file key; disabled rules
+	// don't score)
+	addRisk := func(ruleID, category, desc string, offset float64, evidence ...Evidence) {
+		if disabled[ruleID] {
+			return
+		}
+		reason := RiskReason{
+			RuleID:      ruleID,
 			Category:    category,
+			Severity:    severity(offset),
 			Description: desc,
 			Offset:      offset,
-		})
+		}
+		if len(evidence) > 0 {
+			reason.Evidence = cappedEvidence(evidence[0])
+		}
+		reasons = append(reasons, reason)
 		switch category {
 		case "FRAUD":
 			fraudScore += offset
@@ -83,43 +411,247 @@ func Investigate(profile *WalletProfile, txs []Transaction) {
 	// ---------------------------------------------------------
 	// 1. CALL REMOTE WATCHLIST ENGINE
 	// ---------------------------------------------------------
-	engineResp, err := CheckWatchlist(profile.Address)
+	// Extended keys also screen every address derived from them
+	engineResp, err := inv.watchlist.Check(ctx, profile.Address)
+	hitAddress := ""
+	if err == nil && !engineResp.Sanctioned && len(profile.ScreenAddresses) > 0 {
+		var hit *EngineResponse
+		if hitAddress, hit, err = inv.screenDerived(ctx, profile.ScreenAddresses); hit != nil {
+			engineResp = hit
+		}
+	}
+	watchlistUp := err == nil // Skip further lookups (Safe owners) if it is down
+	if watchlistUp {
+		profile.SanctionsList = engineResp.ListVersion
+	}
 	
 	if err != nil {
 		// FAIL OPEN: If engine is down, warn but don't crash
-		addRisk("SYSTEM", "⚠️ Watchlist Engine Unavailable - Sanctions Check Skipped", 0.0)
+		addRisk("watchlist_unavailable", "SYSTEM", "⚠️ Watchlist Engine Unavailable - Sanctions Check Skipped", 0.0)
 		profile.ValidationDetails += " | [Warning: Sanctions DB Offline]"
 	} else if engineResp.Sanctioned {
 		// CRITICAL HIT
-		addRisk("FRAUD", fmt.Sprintf("CRITICAL: %s Sanctioned Address (%s)", engineResp.Source, engineResp.Currency), 100.0)
-		addRisk("REPUTATION", "Government Blacklisted Entity", 100.0)
-		addRisk("LENDING", "Prohibited: Federal Sanctions", 100.0)
+		desc := fmt.Sprintf("CRITICAL: %s Sanctioned Address (%s)", engineResp.Source, engineResp.Currency)
+		if hitAddress != "" {
+			desc += fmt.Sprintf(" - Derived Address %s", hitAddress)
+		}
+		hit := Evidence{Addresses: []string{profile.Address}}
+		if hitAddress != "" {
+			hit.Addresses = []string{hitAddress}
+		}
+
+		// Sanctioned by association: the entity's other addresses, and which
+		// of them the wallet dealt with
+		if engineResp.EntityID != "" {
+			profile.SanctionedEntity = sanctionedEntity(engineResp, profile.Address, txs)
+			desc += " - " + entityDesc(profile.SanctionedEntity)
+			hit.Addresses = append(hit.Addresses, profile.SanctionedEntity.Transacted...)
+		}
+		addRisk("sanctions", "FRAUD", desc, 100.0, hit)
+		addRisk("sanctions", "REPUTATION", "Government Blacklisted Entity", 100.0, hit)
+		addRisk("sanctions", "LENDING", "Prohibited: Federal Sanctions", 100.0, hit)
 		
 		// Force Max Score Immediately
 		profile.RiskScore = 100.0
-		profile.RiskGrade = "CRITICAL (Sanctioned)"
-		profile.RiskBreakdown = RiskCategory{100, 100, 100}
+		profile.RiskGrade = rules.Grades.Labels.Sanctioned
+		if rules.Grades.Tiers {
+			profile.RiskTier = 5
+		}
+		profile.RiskBreakdown = RiskCategory{Fraud: 100, Reputation: 100, Lending: 100}
 		profile.RiskReasons = reasons
+		profile.Alerts = buildAlerts(rules.Alerts, profile)
+		profile.Decision = Decide(rules, profile)
 		return // Stop processing
 	}
 
+	// Offline profiles have no chain data for the heuristics to score
+	if profile.Partial {
+		profile.RiskGrade = OfflineGrade
+		profile.RiskReasons = append([]RiskReason{}, reasons...)
+		profile.Decision = Decide(rules, profile)
+		return
+	}
+
 	// ---------------------------------------------------------
 	// 2. HEURISTICS (Age, Velocity, Mixers)
 	// ---------------------------------------------------------
 
+	// Plain contracts are scored on what their owner can do, not on the
+	// wallet heuristics (age, holdings, dust, counterparties, flows)
+	isContract := profile.AccountType == "CONTRACT"
+	if cr := profile.ContractRisk; cr != nil {
+		cr.Indicators = nil
+		var ownerEvidence Evidence
+		if cr.Owner != "" {
+			ownerEvidence.Addresses = []string{cr.Owner}
+		}
+		addIndicator := func(ruleID, desc string, offset float64) {
+			cr.Indicators = append(cr.Indicators, desc)
+			addRisk(ruleID, "FRAUD", desc, offset, ownerEvidence)
+		}
+		// Privileges are only a lever while someone holds them
+		held := cr.OwnerType != "RENOUNCED"
+		owner := ""
+		switch cr.OwnerType {
+		case "SAFE":
+			owner = fmt.Sprintf(" (Safe Owner %s)", cr.Owner)
+		case "ACCOUNT":
+			owner = fmt.Sprintf(" (Owner %s)", cr.Owner)
+		}
+		if held && cr.Upgradeable {
+			addIndicator("upgradeable_contract", "Upgradeable Contract: Code Can Be Replaced"+owner, rules.UpgradeableContract.Offset)
+		}
+		if held && hasPrivilege(cr, "MINT") {
+			addIndicator("mint_authority", "Mint Authority: Supply Can Be Inflated"+owner, rules.MintAuthority.Offset)
+		}
+		if held && hasPrivilege(cr, "PAUSE") {
+			addIndicator("pausable_contract", "Pausable Contract: Transfers Can Be Frozen"+owner, rules.PausableContract.Offset)
+		}
+		if levers := honeypotLevers(cr); len(levers) > 0 && float64(len(levers)) >= rules.HoneypotToken.Threshold {
+			addIndicator("honeypot_token", fmt.Sprintf("Honeypot Token: Owner Can Block Sells (%s)", strings.Join(levers, ", ")), rules.HoneypotToken.Offset)
+		}
+		if profile.FirstSeen != nil && now.Sub(*profile.FirstSeen).Hours() < 24*rules.YoungContract.Threshold {
+			addIndicator("young_contract", fmt.Sprintf("Recently Deployed Contract (<%s)", formatDays(rules.YoungContract.Threshold)), rules.YoungContract.Offset)
+		}
+	}
+
 	// Age Check
-	if profile.FirstSeen != nil {
-		hoursOld := time.Since(*profile.FirstSeen).Hours()
-		if hoursOld > 24*365 {
-			addRisk("REPUTATION", "Established History (>1 Year)", -10.0)
-		} else if hoursOld < 24 {
-			addRisk("FRAUD", "Freshly Created Wallet (<24h)", 35.0)
+	if profile.FirstSeen != nil && !isContract {
+		hoursOld := now.Sub(*profile.FirstSeen).Hours()
+		if hoursOld > 24*rules.EstablishedHistory.Threshold {
+			addRisk("established_history", "REPUTATION", fmt.Sprintf("Established History (>%s)", formatDays(rules.EstablishedHistory.Threshold)), rules.EstablishedHistory.Offset, Evidence{Timestamps: []time.Time{*profile.FirstSeen}})
+		} else if hoursOld < rules.FreshWallet.Threshold {
+			addRisk("fresh_wallet", "FRAUD", fmt.Sprintf("Freshly Created Wallet (<%gh)", rules.FreshWallet.Threshold), rules.FreshWallet.Offset, Evidence{Timestamps: []time.Time{*profile.FirstSeen}})
+		}
+	}
+
+	// Holdings Check (native balance + stablecoins, once priced in USD)
+	if profile.BalanceUSD != nil && !isContract {
+		if holdings := *profile.BalanceUSD + profile.StablecoinTotal; holdings >= rules.SubstantialHoldings.Threshold {
+			addRisk("substantial_holdings", "LENDING", fmt.Sprintf("Substantial Holdings ($%.0f)", holdings), rules.SubstantialHoldings.Offset)
+		}
+	}
+
+	// Scam Token Holdings (a wallet full of honeypots is a victim of, or
+	// party to, token scams)
+	var scamTokens, scamContracts []string
+	for _, t := range profile.TokenHoldings {
+		if len(t.HoneypotLevers) > 0 && float64(len(t.HoneypotLevers)) >= rules.HoneypotToken.Threshold {
+			scamTokens = append(scamTokens, t.Symbol)
+			scamContracts = append(scamContracts, t.Contract)
 		}
 	}
+	if n := len(profile.TokenHoldings); len(scamTokens) > 0 && float64(len(scamTokens))*100/float64(n) >= rules.ScamTokenHoldings.Threshold {
+		addRisk("scam_token_holdings", "REPUTATION", fmt.Sprintf("Holdings Dominated by Scam Tokens: %d of %d Tokens (%s)", len(scamTokens), n, strings.Join(scamTokens, ", ")), rules.ScamTokenHoldings.Offset, Evidence{Addresses: scamContracts})
+	}
 
-	// Interactions Check
-	directThreat := false
+	// Contract Check (a DEX router is not a personal wallet)
+	if (isContract || profile.AccountType == "SMART_ACCOUNT") && profile.ContractVerified != nil {
+		if *profile.ContractVerified {
+			addRisk("verified_contract", "REPUTATION", "Verified Contract Source", rules.VerifiedContract.Offset)
+		} else {
+			addRisk("unverified_contract", "FRAUD", "Unverified Contract Code", rules.UnverifiedContract.Offset)
+		}
+	}
+
+	// Safe Owner Screening (owners of nested Safes included)
+	safes := []*SafeInfo{profile.Safe}
+	for _, c := range profile.Chains {
+		safes = append(safes, c.Safe)
+	}
+	screenedOwners := map[string]bool{}
+	for _, safe := range safes {
+		for _, owner := range safeOwners(safe) {
+			if screenedOwners[owner] {
+				continue
+			}
+			screenedOwners[owner] = true
+			if label, threat := threatLabel(lookupLabelsIn(inv.labels, owner, true)); threat && label.Category != CategorySanctioned {
+				addRisk("safe_owner_threat", "FRAUD", fmt.Sprintf("Safe Owner is %s (%s)", label.Entity, owner), rules.SafeOwnerThreat.Offset, Evidence{Addresses: []string{owner}})
+			}
+			if !watchlistUp {
+				continue
+			}
+			if resp, err := inv.watchlist.Check(ctx, owner); err == nil && resp.Sanctioned {
+				signer := Evidence{Addresses: []string{owner}}
+				addRisk("sanctioned_safe_owner", "FRAUD", fmt.Sprintf("Sanctioned Safe Owner %s (%s)", owner, resp.Source), 100.0, signer)
+				addRisk("sanctioned_safe_owner", "LENDING", "Prohibited: Controlled by a Sanctioned Signer", 100.0, signer)
+			}
+		}
+	}
+
+	// Dusting Check (tiny unsolicited transfers from many senders; the
+	// victim didn't choose these counterparties)
+	isDust := dustFilter(rules, profile)
+	dustSenders := map[string]bool{}
+	dustTxs := 0
+	var dustHashes []string
 	for _, tx := range txs {
+		if isDust != nil && isDust(tx) {
+			dustSenders[strings.ToLower(tx.From)] = true
+			dustTxs++
+			dustHashes = append(dustHashes, tx.Hash)
+		}
+	}
+	if float64(len(dustSenders)) >= rules.Dusting.Threshold && !isContract {
+		addRisk("dusting", "REPUTATION", fmt.Sprintf("Dusting Attack: %d Dust Transfers from %d Senders", dustTxs, len(dustSenders)), rules.Dusting.Offset, Evidence{TxHashes: dustHashes, Addresses: sortedKeys(dustSenders)})
+	} else if u := profile.UTXOs; u != nil && u.Pattern == "DUSTED" {
+		addRisk("dusting", "REPUTATION", fmt.Sprintf("Dusting Attack: %d Dust Outputs", u.DustCount), rules.Dusting.Offset)
+	}
+
+	// Address Poisoning (lookalikes of real counterparties seeding the
+	// history). Their txs are left out below, so a poisoner's scam label
+	// doesn't count against its victim.
+	if !isContract {
+		profile.AddressPoisoning = addressPoisoning(profile.Address, txs, isDust)
+	}
+	poisoners := map[string]bool{}
+	if p := profile.AddressPoisoning; p != nil {
+		for _, l := range p.Lookalikes {
+			poisoners[l.Address] = true
+		}
+		if float64(len(p.Lookalikes)) >= rules.AddressPoisoning.Threshold {
+			addRisk("address_poisoning", "REPUTATION", poisoningDesc(p), rules.AddressPoisoning.Offset, Evidence{Addresses: sortedKeys(poisoners)})
+		}
+	}
+
+	// Counterparty Concentration (a wallet living off one counterparty has
+	// thin, easily broken relationships)
+	var counted []Transaction
+	for _, tx := range txs {
+		if (isDust == nil || !isDust(tx)) && !poisoners[strings.ToLower(tx.From)] && !poisoners[strings.ToLower(tx.To)] {
+			counted = append(counted, tx)
+		}
+	}
+	if !isContract {
+		profile.Concentration = counterpartyConcentration(strings.ToLower(profile.Address), counted)
+	}
+	if c := profile.Concentration; c != nil && c.Top1Percent >= rules.Concentration.Threshold {
+		addRisk("concentration", "LENDING", fmt.Sprintf("Concentrated Counterparties: %.1f%% of Volume with %s (Top 5: %.1f%%)", c.Top1Percent, c.TopCounterparty, c.Top5Percent), rules.Concentration.Offset, Evidence{Addresses: []string{c.TopCounterparty}})
+	}
+
+	// Counterparty Labels (every label provider; remote ones are only asked
+	// about the largest counterparties)
+	profile.CounterpartyLabels = labelCounterparties(inv.labels, profile.Address, counted)
+	threats := map[string]Label{}
+	for _, cp := range profile.CounterpartyLabels {
+		if l, ok := threatLabel(cp.Labels); ok {
+			threats[cp.Address] = l
+		}
+	}
+
+	// Interactions Check (incoming dust excluded: anyone can send it). Mixers
+	// score mixer_interaction, other threats (drainers, scams, sanctioned
+	// counterparties) threat_counterparty. The offsets decay with the age of
+	// the latest interaction.
+	type interaction struct {
+		desc     string
+		last     int64
+		evidence Evidence
+		parties  map[string]bool
+	}
+	interactions := map[string]*interaction{}
+	for _, tx := range counted {
 		otherParty := ""
 		if strings.EqualFold(tx.From, profile.Address) {
 			otherParty = strings.ToLower(tx.To)
@@ -127,22 +659,245 @@ func Investigate(profile *WalletProfile, txs []Transaction) {
 			otherParty = strings.ToLower(tx.From)
 		}
 
-		if label, isThreat := knownThreats[otherParty]; isThreat {
-			if !directThreat {
-				addRisk("FRAUD", fmt.Sprintf("Direct Interaction with %s", label), 55.0)
-				directThreat = true
+		label, threat := threats[otherParty]
+		if !threat {
+			continue
+		}
+		ruleID, entity := "threat_counterparty", fmt.Sprintf("%s (%s)", label.Entity, titleWord(label.Category))
+		if label.Category == CategoryMixer {
+			ruleID, entity = "mixer_interaction", label.Entity
+		}
+		in := interactions[ruleID]
+		if in == nil {
+			in = &interaction{desc: fmt.Sprintf("Direct Interaction with %s", entity), parties: map[string]bool{}}
+			if tx.Internal {
+				in.desc = fmt.Sprintf("Interaction with %s (via Internal Call)", entity)
 			}
+			interactions[ruleID] = in
+		}
+		in.last = max(in.last, tx.TimeStamp)
+		in.evidence.TxHashes = append(in.evidence.TxHashes, tx.Hash)
+		in.parties[otherParty] = true
+	}
+	for _, ruleID := range []string{"mixer_interaction", "threat_counterparty"} {
+		in := interactions[ruleID]
+		if in == nil {
+			continue
+		}
+		rule := rules.MixerInteraction
+		if ruleID == "threat_counterparty" {
+			rule = rules.ThreatCounterparty
 		}
+		in.evidence.Addresses = sortedKeys(in.parties)
+		if in.last > 0 {
+			in.evidence.Timestamps = []time.Time{time.Unix(in.last, 0).UTC()}
+		}
+		addRisk(ruleID, "FRAUD", decayed(rules.Decay, in.desc, in.last, now), math.Round(rule.Offset*rules.Decay.factor(time.Unix(in.last, 0), now)*100)/100, in.evidence)
 	}
 
-	// Velocity Check
-	if profile.TxCount > 0 && profile.FirstSeen != nil {
-		hoursActive := time.Since(*profile.FirstSeen).Hours()
-		if hoursActive < 1 { hoursActive = 1 }
-		
-		txPerHour := float64(profile.TxCount) / hoursActive
-		if txPerHour > 20.0 {
-			addRisk("FRAUD", "High Velocity Behavior (Potential Bot)", 25.0)
+	// Dormancy Reactivation (long-idle wallet suddenly moving value: hack
+	// proceeds, compromised keys). Histories without directions or amounts
+	// (Bitcoin, Solana) are flagged on the dormancy alone.
+	if r := findReactivation(profile.Address, txs, rules.Reactivation.Threshold); r != nil && !isContract {
+		directional := false
+		for _, tx := range txs {
+			directional = directional || tx.From != ""
+		}
+		desc := fmt.Sprintf("Dormant Wallet Reactivated (%.0f Months Idle, %s)", r.Months, r.At.Format("2006-01-02"))
+		offset := math.Round(rules.Reactivation.Offset*rules.Decay.factor(r.At, now)*100) / 100
+		at := Evidence{Timestamps: []time.Time{r.At}}
+		switch {
+		case !directional:
+			addRisk("reactivation", "FRAUD", desc, offset, at)
+		case r.HasOutgoing && r.Moved != nil:
+			decimals := profile.Decimals
+			if decimals == 0 {
+				decimals = 18
+			}
+			moved, _ := new(big.Float).Quo(new(big.Float).SetInt(r.Moved), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))).Float64()
+			if profile.PriceUSD == nil {
+				addRisk("reactivation", "FRAUD", fmt.Sprintf("%s - %.4f %s Moved", desc, moved, profile.Symbol), offset, at)
+			} else if usd := moved * *profile.PriceUSD; usd >= rules.Reactivation.MinUSD {
+				addRisk("reactivation", "FRAUD", fmt.Sprintf("%s - $%.0f Moved", desc, usd), offset, at)
+			}
+		}
+	}
+
+	// Rapid Bridging (funds received, then bridged away within a day)
+	rapid := 0
+	var rapidBridges []string
+	for _, b := range profile.Bridges {
+		if b.Rapid > 0 {
+			rapid += b.Rapid
+			rapidBridges = append(rapidBridges, b.Bridge)
+		}
+	}
+	if rapid > 0 && !isContract && float64(rapid) >= rules.RapidBridging.Threshold {
+		addRisk("rapid_bridging", "FRAUD", fmt.Sprintf("Rapid Bridging: %d Bridge Deposits within 24h of Receiving Funds (%s)", rapid, strings.Join(rapidBridges, ", ")), rules.RapidBridging.Offset)
+	}
+
+	// Wash Trading (tokens or NFTs cycling within a closed cluster, inflating
+	// volume and prices)
+	if w := profile.WashTrading; w != nil && float64(w.Transfers) >= rules.WashTrading.Threshold {
+		others := make([]string, 0, len(w.Members))
+		for _, m := range w.Members {
+			if !strings.EqualFold(m, profile.Address) {
+				others = append(others, m)
+			}
+		}
+		addRisk("wash_trading", "REPUTATION", fmt.Sprintf("Suspected Wash Trading: %d Transfers Cycling with %s (%.0f%% External Inflow)", w.Transfers, strings.Join(others, ", "), w.ExternalInflow), rules.WashTrading.Offset, Evidence{Addresses: others})
+	}
+
+	// Sybil Farming (one operator running many wallets to game airdrops)
+	if s := profile.Sybil; s != nil && float64(len(s.Signals)) >= rules.SybilFarming.Threshold {
+		var wallets []string
+		if s.Funder != "" {
+			wallets = append(wallets, s.Funder)
+		}
+		addRisk("sybil_farming", "FRAUD", "Sybil Farming Pattern: "+strings.Join(s.Signals, ", "), rules.SybilFarming.Offset, Evidence{Addresses: append(wallets, s.Siblings...)})
+	}
+
+	// Exploit Preparation (flash loans next to a freshly deployed contract or
+	// large funds passing through in one block: the shape of a DeFi attack)
+	decimals := profile.Decimals
+	if decimals == 0 {
+		decimals = 18
+	}
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	var minFlow *big.Int
+	if profile.PriceUSD != nil && *profile.PriceUSD > 0 {
+		minFlow, _ = new(big.Float).Mul(big.NewFloat(rules.ExploitPreparation.MinUSD / *profile.PriceUSD), unit).Int(nil)
+	}
+	profile.FlashLoans = flashLoanActivity(profile.Address, txs, isContract, profile.FirstSeen, rules.ExploitPreparation.Threshold, minFlow)
+	if f := profile.FlashLoans; f != nil {
+		if f.SameBlockFlow != nil {
+			amount, _ := new(big.Float).Quo(new(big.Float).SetInt(f.SameBlockFlow), unit).Float64()
+			f.Signals = append(f.Signals, fmt.Sprintf("$%.0f In and Out in One Block", amount * *profile.PriceUSD))
+		}
+		if len(f.Signals) > 1 {
+			addRisk("exploit_preparation", "FRAUD", "Possible Exploit Preparation: "+strings.Join(f.Signals, ", "), rules.ExploitPreparation.Offset, Evidence{TxHashes: f.EvidenceTxs, Addresses: f.NewContracts})
+		}
+	}
+
+	// Peel Chain (remainders passed on hop after hop, small amounts split off)
+	if pc := profile.PeelChain; pc != nil && !isContract && float64(pc.Hops) >= rules.PeelChain.Threshold {
+		addRisk("peel_chain", "FRAUD", fmt.Sprintf("Peel Chain Detected (%d Hops, %s Peeled)", pc.Hops, pc.Peeled), rules.PeelChain.Offset, Evidence{Addresses: pc.Path})
+	}
+
+	// Exchange Links (one reason per exchange; a deposit address is the
+	// strongest sign the owner has an account there)
+	var exchanges []string
+	bestLink := map[string]ExchangeLink{}
+	for _, l := range profile.ExchangeLinks {
+		if isContract {
+			break // Exchanges use contracts too; links say nothing about an owner
+		}
+		best, seen := bestLink[l.Entity]
+		if !seen {
+			exchanges = append(exchanges, l.Entity)
+		}
+		if !seen || exchangeLinkRank(l) > exchangeLinkRank(best) {
+			bestLink[l.Entity] = l
+		}
+	}
+	for _, entity := range exchanges {
+		l := bestLink[entity]
+		var desc string
+		switch {
+		case l.Via == "DEPOSIT_ADDRESS":
+			desc = fmt.Sprintf("Funds Sent to %s Deposit Address (%s)", entity, l.Address)
+		case l.Sent > 0:
+			desc = fmt.Sprintf("Funds Sent to %s Hot Wallet", entity)
+		default:
+			desc = fmt.Sprintf("Funds Received from %s", entity)
+		}
+		if l.Regulated {
+			addRisk("regulated_exchange", "REPUTATION", desc+" (Likely KYC)", rules.RegulatedExchange.Offset, Evidence{Addresses: []string{l.Address}})
+		} else {
+			addRisk("unregulated_exchange", "REPUTATION", desc+" (No-KYC Exchange)", rules.UnregulatedExchange.Offset, Evidence{Addresses: []string{l.Address}})
+		}
+	}
+
+	// Jurisdiction Risk (exchanges in FATF-listed countries), one reason per
+	// country, weighted by list: the full offset for HIGH, half for MONITORED
+	for _, e := range jurisdictionExposure(profile.ExchangeLinks, rules) {
+		if isContract {
+			break
+		}
+		addRisk("jurisdiction_risk", "REPUTATION", jurisdictionDesc(e), rules.JurisdictionRisk.Offset*jurisdictionWeights[e.Level], Evidence{Addresses: e.Addresses})
+	}
+
+	// Indirect Exposure (funds within N hops of flagged addresses, EVM only).
+	// The offset scales with the share of funds and fades with distance.
+	for _, e := range profile.Exposure {
+		if e.Percent < rules.IndirectExposure.Threshold {
+			continue
+		}
+		hops := "1 Hop"
+		if e.Hop > 1 {
+			hops = fmt.Sprintf("%d Hops", e.Hop)
+		}
+		desc := fmt.Sprintf("Indirect Exposure: %.1f%% of Funds %s from %s", e.Percent, hops, strings.Join(e.Flagged, ", "))
+		if e.Network != "" {
+			desc += fmt.Sprintf(" [%s]", e.Network)
+		}
+		offset := rules.IndirectExposure.Offset * e.Percent / 100 / float64(e.Hop)
+		addRisk("indirect_exposure", "FRAUD", desc, math.Round(offset*100)/100)
+	}
+
+	// Approval Exposure (live unlimited approvals, EVM only)
+	approvalCounts := map[string]int{}
+	approvalOffsets := map[string]float64{}
+	approvalRules := map[string]string{}
+	approvalSpenders := map[string][]string{}
+	var approvalOrder []string
+	for _, a := range profile.Approvals {
+		if desc, offset, risky := riskyApproval(a, rules); risky {
+			if approvalCounts[desc] == 0 {
+				approvalOrder = append(approvalOrder, desc)
+				approvalOffsets[desc] = offset
+				approvalRules[desc] = "risky_approval"
+				if _, threat := threatLabel(lookupLabelsIn(inv.labels, a.Spender, true)); threat {
+					approvalRules[desc] = "threat_approval"
+				}
+			}
+			approvalCounts[desc]++
+			approvalSpenders[desc] = append(approvalSpenders[desc], a.Spender)
+		}
+	}
+	for _, desc := range approvalOrder {
+		label := desc
+		if n := approvalCounts[desc]; n > 1 {
+			label = fmt.Sprintf("%s (x%d)", desc, n)
+		}
+		addRisk(approvalRules[desc], "FRAUD", label, approvalOffsets[desc], Evidence{Addresses: approvalSpenders[desc]})
+	}
+
+	// Busiest rolling windows (1h/24h/7d by default): a lifetime average
+	// hides a recent burst
+	profile.Velocity = velocityWindows(txs, rules.VelocityWindows)
+	profile.Temporal = temporalProfile(txs)
+	burst, fast := worstBurst(profile.Velocity)
+
+	// Automated Traders (MEV bots and DEX market makers are busy by design;
+	// they get a behavioral class instead of the velocity penalty)
+	profile.Bot = classifyBot(profile.Address, txs, isContract, defiCounterparties(profile.CounterpartyLabels), fast)
+	if b := profile.Bot; b != nil {
+		addRisk("automated_trader", "REPUTATION", botDesc(b), rules.AutomatedTrader.Offset, Evidence{TxHashes: b.EvidenceTxs})
+	}
+
+	// Velocity Check (skipped for contracts: routers and pools are busy by design)
+	if !isContract && profile.Bot == nil && fast {
+		addRisk("velocity", "FRAUD", burstDesc(burst), rules.Velocity.Offset, Evidence{Timestamps: []time.Time{burst.Start}})
+	}
+
+	// Custom Rules (defined in RISK_RULES_FILE)
+	if len(inv.custom) > 0 {
+		vars := ruleVariables(profile, txs, isDust, now)
+		for _, rule := range inv.custom {
+			if rule.Matches(vars) {
+				addRisk("custom."+rule.Name, rule.Category, rule.Reason, rule.Offset)
+			}
 		}
 	}
 
@@ -155,31 +910,131 @@ func Investigate(profile *WalletProfile, txs []Transaction) {
 	repScore = clamp(repScore, 0, 100)
 	lendScore = clamp(lendScore, 0, 100)
 
-	combinedRisk := (fraudScore * 0.5) + (repScore * 0.3) + (lendScore * 0.2)
+	combinedRisk := (fraudScore * rules.Weights.Fraud) + (repScore * rules.Weights.Reputation) + (lendScore * rules.Weights.Lending)
 	
-	grade := "UNKNOWN"
-	if combinedRisk < 10 {
-		grade = "EXCELLENT (Safe)"
-	} else if combinedRisk < 35 {
-		grade = "LOW (Neutral)"
-	} else if combinedRisk < 60 {
-		grade = "WARNING (Elevated)"
-	} else {
-		grade = "FAILING (High Risk)"
-	}
+	grade, tier := rules.Grades.grade(combinedRisk)
 
 	profile.RiskScore = math.Round(combinedRisk*100) / 100
 	profile.RiskGrade = grade
+	if rules.Grades.Tiers {
+		profile.RiskTier = tier
+	}
 	profile.RiskBreakdown = RiskCategory{
 		Fraud:      math.Round(fraudScore*100) / 100,
 		Reputation: math.Round(repScore*100) / 100,
 		Lending:    math.Round(lendScore*100) / 100,
 	}
 	profile.RiskReasons = reasons
+	profile.Alerts = buildAlerts(rules.Alerts, profile)
+	profile.Decision = Decide(rules, profile)
+}
+
+// screenDerived screens an extended key's derived addresses, in one call
+// if the watchlist client can batch. It returns the first sanctioned address
+// and its full result (SDN entity included), or a nil result if none is.
+func (inv *Investigator) screenDerived(ctx context.Context, addresses []string) (string, *EngineResponse, error) {
+	batch, ok := inv.watchlist.(BatchWatchlistClient)
+	if !ok {
+		for _, addr := range addresses {
+			resp, err := inv.watchlist.Check(ctx, addr)
+			if err != nil || resp.Sanctioned {
+				return addr, resp, err
+			}
+		}
+		return "", nil, nil
+	}
+
+	results, err := batch.BatchCheck(ctx, addresses)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, addr := range addresses {
+		if res := results[addr]; res != nil && res.Sanctioned {
+			resp, err := inv.watchlist.Check(ctx, addr)
+			return addr, resp, err
+		}
+	}
+	return "", nil, nil
 }
 
 func clamp(val, min, max float64) float64 {
 	if val < min { return min }
 	if val > max { return max }
 	return val
-}
\ No newline at end of file
+}
+
+// formatDays renders an age threshold the way the reasons always read
+// ("1 Year" for the default 365 days).
+func formatDays(days float64) string {
+	if days == 365 {
+		return "1 Year"
+	}
+	if math.Mod(days, 365) == 0 {
+		return fmt.Sprintf("%g Years", days/365)
+	}
+	return fmt.Sprintf("%g Days", days)
+}
+
+// plural formats a count for reason text: "1 Tx", "3 Txs".
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// maxEvidence caps each Evidence list (dust and mixer txs can be many)
+const maxEvidence = 20
+
+// cappedEvidence drops empty evidence and trims long lists.
+func cappedEvidence(e Evidence) *Evidence {
+	hashes := e.TxHashes[:0:0]
+	for _, h := range e.TxHashes {
+		if h != "" { // Histories without hashes
+			hashes = append(hashes, h)
+		}
+	}
+	e.TxHashes = hashes[:min(len(hashes), maxEvidence)]
+	e.Addresses = e.Addresses[:min(len(e.Addresses), maxEvidence)]
+	e.Timestamps = e.Timestamps[:min(len(e.Timestamps), maxEvidence)]
+	if len(e.TxHashes)+len(e.Addresses)+len(e.Timestamps) == 0 {
+		return nil
+	}
+	return &e
+}
+
+// severity buckets an offset for case-management systems. Offsets that
+// lower risk are INFO.
+func severity(offset float64) string {
+	switch {
+	case offset >= 100:
+		return "CRITICAL"
+	case offset >= 40:
+		return "HIGH"
+	case offset >= 20:
+		return "MEDIUM"
+	case offset > 0:
+		return "LOW"
+	}
+	return "INFO"
+}
+
+// decayed notes the event's date on a reason whose offset has decayed.
+func decayed(d RiskDecay, desc string, at int64, now time.Time) string {
+	if d.factor(time.Unix(at, 0), now) < 1 {
+		return fmt.Sprintf("%s (Last: %s)", desc, time.Unix(at, 0).UTC().Format("2006-01-02"))
+	}
+	return desc
+}
+
+// exchangeLinkRank orders the evidence for an exchange account: a deposit
+// address, then sending to a hot wallet, then only receiving from one.
+func exchangeLinkRank(l ExchangeLink) int {
+	switch {
+	case l.Via == "DEPOSIT_ADDRESS":
+		return 2
+	case l.Sent > 0:
+		return 1
+	}
+	return 0
+}
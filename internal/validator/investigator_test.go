@@ -0,0 +1,214 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
+)
+
+// cleanWatchlist reports every address as unlisted.
+type cleanWatchlist struct{}
+
+func (cleanWatchlist) Check(ctx context.Context, address string) (*EngineResponse, error) {
+	return &EngineResponse{}, nil
+}
+
+func TestInvestigatorCustomRulesUseInjectedDustThresholds(t *testing.T) {
+	const target = "0x1111111111111111111111111111111111111111"
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rules := DefaultRiskRules()
+	rules.DustThresholds = map[string]float64{"ETH": 1}
+	rules.Custom = map[string]string{"dust_probe": "dust_senders >= 1 => REPUTATION 5 'dusted'"}
+	inv, err := NewInvestigator(
+		WithWatchlist(cleanWatchlist{}),
+		WithLabelProviders(),
+		WithRules(rules),
+		WithClock(func() time.Time { return now }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Six 0.5 ETH transfers: dust only under the injected thresholds
+	var txs []Transaction
+	for i := 0; i < 6; i++ {
+		txs = append(txs, Transaction{
+			TimeStamp: now.Add(-time.Duration(i+1) * time.Hour).Unix(),
+			From:      fmt.Sprintf("0x%040d", i+2),
+			To:        target,
+			Value:     "500000000000000000",
+			Hash:      fmt.Sprintf("0x%064d", i),
+		})
+	}
+	profile := &WalletProfile{Address: target, Network: "Ethereum Mainnet", Symbol: "ETH", Decimals: 18, TxCount: len(txs)}
+	inv.Investigate(context.Background(), profile, txs)
+
+	var fired bool
+	for _, r := range profile.RiskReasons {
+		if r.RuleID == "custom.dust_probe" {
+			fired = true
+		}
+	}
+	if !fired {
+		t.Fatalf("custom rule did not see the injected dust thresholds; reasons: %+v", profile.RiskReasons)
+	}
+}
+
+// batchWatchlist lists the addresses in hits and counts its calls.
+type batchWatchlist struct {
+	hits         map[string]bool
+	checks       []string
+	batches      int
+	batchedAddrs int
+}
+
+func (w *batchWatchlist) Check(ctx context.Context, address string) (*EngineResponse, error) {
+	w.checks = append(w.checks, address)
+	if !w.hits[address] {
+		return &EngineResponse{}, nil
+	}
+	return &EngineResponse{Sanctioned: true, Source: "OFAC", Currency: "XBT", EntityID: "1001", EntityName: "LAZARUS GROUP"}, nil
+}
+
+func (w *batchWatchlist) BatchCheck(ctx context.Context, addresses []string) (map[string]*EngineResponse, error) {
+	w.batches++
+	w.batchedAddrs += len(addresses)
+	out := make(map[string]*EngineResponse, len(addresses))
+	for _, a := range addresses {
+		out[a] = &EngineResponse{Sanctioned: w.hits[a], Source: "OFAC", Currency: "XBT"}
+	}
+	return out, nil
+}
+
+func TestInvestigatorScreensDerivedAddressesInOneBatch(t *testing.T) {
+	derived := make([]string, 40)
+	for i := range derived {
+		derived[i] = fmt.Sprintf("bc1derived%02d", i)
+	}
+	tests := []struct {
+		name       string
+		hits       map[string]bool
+		sanctioned bool
+		checks     []string
+	}{
+		{name: "clean", checks: []string{"xpub-probe"}},
+		{name: "derived hit", hits: map[string]bool{derived[7]: true, derived[30]: true}, sanctioned: true, checks: []string{"xpub-probe", derived[7]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wl := &batchWatchlist{hits: tt.hits}
+			inv, err := NewInvestigator(WithWatchlist(wl), WithLabelProviders())
+			if err != nil {
+				t.Fatal(err)
+			}
+			profile := &WalletProfile{Address: "xpub-probe", Network: "Bitcoin Mainnet", Symbol: "BTC", ScreenAddresses: derived}
+			inv.Investigate(context.Background(), profile, nil)
+
+			if wl.batches != 1 || wl.batchedAddrs != len(derived) {
+				t.Errorf("%d batch calls for %d addresses, want 1 for %d", wl.batches, wl.batchedAddrs, len(derived))
+			}
+			if strings.Join(wl.checks, ",") != strings.Join(tt.checks, ",") {
+				t.Errorf("single checks %v, want %v", wl.checks, tt.checks)
+			}
+			if sanctioned := profile.RiskGrade == DefaultRiskRules().Grades.Labels.Sanctioned; sanctioned != tt.sanctioned {
+				t.Errorf("grade %s, want sanctioned=%v", profile.RiskGrade, tt.sanctioned)
+			}
+			if tt.sanctioned && (profile.SanctionedEntity == nil || profile.SanctionedEntity.ID != "1001") {
+				t.Errorf("entity %+v, want the first hit's entity", profile.SanctionedEntity)
+			}
+		})
+	}
+}
+
+// fakeEngine serves /check, listing addresses that start with 0xbad.
+// It counts the requests of each method.
+func fakeEngine(t *testing.T) (gets, posts *atomic.Int32) {
+	t.Helper()
+	gets, posts = new(atomic.Int32), new(atomic.Int32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+			address := r.URL.Query().Get("address")
+			json.NewEncoder(w).Encode(watchlist.Result{Address: address, Sanctioned: strings.HasPrefix(address, "0xbad"), EntityID: "1001"})
+			return
+		}
+		posts.Add(1)
+		var req watchlist.BatchCheckRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Addresses) > watchlist.MaxBatchCheck {
+			http.Error(w, "bad batch", http.StatusBadRequest)
+			return
+		}
+		resp := watchlist.BatchCheckResponse{ListVersion: &watchlist.ListVersion{LastModified: "v1"}}
+		for _, a := range req.Addresses {
+			resp.Results = append(resp.Results, watchlist.Result{Address: a, Sanctioned: strings.HasPrefix(a, "0xbad")})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	SetWatchlistEngine(srv.URL, "")
+	t.Cleanup(func() { SetWatchlistEngine("", "") })
+	return gets, posts
+}
+
+func TestBatchCheckWatchlist(t *testing.T) {
+	_, posts := fakeEngine(t)
+	addresses := make([]string, watchlist.MaxBatchCheck+5)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040d", i)
+	}
+	addresses[3] = "0xbad"
+
+	results, err := BatchCheckWatchlist(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := posts.Load(); n != 2 {
+		t.Errorf("%d requests, want 2 (one per %d addresses)", n, watchlist.MaxBatchCheck)
+	}
+	if len(results) != len(addresses) {
+		t.Errorf("got %d results, want %d", len(results), len(addresses))
+	}
+	if r := results["0xbad"]; r == nil || !r.Sanctioned || r.ListVersion == nil || r.ListVersion.LastModified != "v1" {
+		t.Errorf("hit: %+v", r)
+	}
+	if r := results[addresses[len(addresses)-1]]; r == nil || r.Sanctioned {
+		t.Errorf("clean address in the second request: %+v", r)
+	}
+}
+
+func TestPrimeWatchlist(t *testing.T) {
+	gets, posts := fakeEngine(t)
+	clean := "0x0000000000000000000000000000000000000001"
+	release, err := PrimeWatchlist(context.Background(), []string{clean, "0xbad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := CheckWatchlist(clean); err != nil || res.Sanctioned {
+		t.Fatalf("primed clean address: %+v, %v", res, err)
+	}
+	if gets.Load() != 0 {
+		t.Error("a primed clean address went to the engine")
+	}
+	// A hit isn't primed: its check fetches the SDN entity
+	if res, err := CheckWatchlist("0xbad"); err != nil || !res.Sanctioned || res.EntityID != "1001" {
+		t.Fatalf("hit: %+v, %v", res, err)
+	}
+	if gets.Load() != 1 || posts.Load() != 1 {
+		t.Errorf("%d GETs and %d POSTs, want 1 of each", gets.Load(), posts.Load())
+	}
+
+	release()
+	if _, err := CheckWatchlist(clean); err != nil || gets.Load() != 2 {
+		t.Errorf("after release: %v, %d GETs", err, gets.Load())
+	}
+}
@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// JURISDICTION RISK (FATF lists, per exchange/VASP country)
+// ---------------------------------------------------------
+
+// FATF list levels, as used in RiskRules.Jurisdictions
+const (
+	JurisdictionHigh      = "HIGH"      // High-risk, subject to a call for action ("black list")
+	JurisdictionMonitored = "MONITORED" // Under increased monitoring ("grey list")
+)
+
+// How much of the jurisdiction_risk offset each level carries
+var jurisdictionWeights = map[string]float64{
+	JurisdictionHigh:      1,
+	JurisdictionMonitored: 0.5,
+}
+
+// DefaultJurisdictions are the FATF lists by ISO 3166-1 alpha-2 code, as
+// published in June 2025. FATF revises them three times a year; override
+// them with "jurisdictions" in the rules file.
+var DefaultJurisdictions = map[string]string{
+	"KP": JurisdictionHigh, "IR": JurisdictionHigh, "MM": JurisdictionHigh,
+
+	"DZ": JurisdictionMonitored, "AO": JurisdictionMonitored, "BO": JurisdictionMonitored,
+	"BG": JurisdictionMonitored, "BF": JurisdictionMonitored, "CM": JurisdictionMonitored,
+	"CI": JurisdictionMonitored, "HR": JurisdictionMonitored, "CD": JurisdictionMonitored,
+	"HT": JurisdictionMonitored, "KE": JurisdictionMonitored, "LA": JurisdictionMonitored,
+	"LB": JurisdictionMonitored, "MC": JurisdictionMonitored, "MZ": JurisdictionMonitored,
+	"NA": JurisdictionMonitored, "NP": JurisdictionMonitored, "NG": JurisdictionMonitored,
+	"ZA": JurisdictionMonitored, "SS": JurisdictionMonitored, "SY": JurisdictionMonitored,
+	"VE": JurisdictionMonitored, "VN": JurisdictionMonitored, "VG": JurisdictionMonitored,
+	"YE": JurisdictionMonitored,
+}
+
+// jurisdictionLevel returns the FATF level of a country code, or "".
+func (r RiskRules) jurisdictionLevel(code string) string {
+	if code == "" {
+		return ""
+	}
+	return strings.ToUpper(r.Jurisdictions[strings.ToUpper(code)])
+}
+
+// vaspExposure is the activity with every exchange in one FATF-listed
+// jurisdiction.
+type vaspExposure struct {
+	Jurisdiction string
+	Level        string
+	Entities     []string
+	Addresses    []string
+	Txs          int
+}
+
+// jurisdictionExposure groups the exchange links by listed jurisdiction,
+// highest level first, and marks each link's level.
+func jurisdictionExposure(links []ExchangeLink, rules RiskRules) []vaspExposure {
+	byCode := map[string]*vaspExposure{}
+	for i := range links {
+		l := &links[i]
+		level := rules.jurisdictionLevel(l.Jurisdiction)
+		l.JurisdictionRisk = level
+		if level == "" {
+			continue
+		}
+		code := strings.ToUpper(l.Jurisdiction)
+		e := byCode[code]
+		if e == nil {
+			e = &vaspExposure{Jurisdiction: code, Level: level}
+			byCode[code] = e
+		}
+		if !slices.Contains(e.Entities, l.Entity) {
+			e.Entities = append(e.Entities, l.Entity)
+		}
+		e.Addresses = append(e.Addresses, l.Address)
+		e.Txs += l.Sent + l.Received
+	}
+
+	out := make([]vaspExposure, 0, len(byCode))
+	for _, e := range byCode {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if wi, wj := jurisdictionWeights[out[i].Level], jurisdictionWeights[out[j].Level]; wi != wj {
+			return wi > wj
+		}
+		return out[i].Jurisdiction < out[j].Jurisdiction
+	})
+	return out
+}
+
+// jurisdictionDesc is the reason text for one listed jurisdiction.
+func jurisdictionDesc(e vaspExposure) string {
+	list := "FATF Call for Action"
+	if e.Level == JurisdictionMonitored {
+		list = "FATF Increased Monitoring"
+	}
+	return fmt.Sprintf("Exposure to High-Risk Jurisdiction VASP: %s (%s, %s) - %s", strings.Join(e.Entities, ", "), e.Jurisdiction, list, plural(e.Txs, "Tx", "Txs"))
+}
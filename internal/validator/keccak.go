@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// KECCAK-256 / EIP-55 (mixed-case EVM address checksums)
+// ---------------------------------------------------------
+
+// Round constants and rotation offsets (x + 5y) of Keccak-f[1600]
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+var keccakRot = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+func keccakF(a *[25]uint64) {
+	for round := 0; round < 24; round++ {
+		// θ
+		var c [5]uint64
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+		// ρ and π
+		var b [25]uint64
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRot[x+5*y])
+			}
+		}
+		// χ and ι
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+		a[0] ^= keccakRC[round]
+	}
+}
+
+// keccak256 is the original Keccak-256 Ethereum uses (0x01 padding, not
+// SHA3-256's 0x06), which the standard library doesn't provide.
+func keccak256(data []byte) [32]byte {
+	const rate = 136
+	msg := make([]byte, (len(data)/rate+1)*rate)
+	copy(msg, data)
+	msg[len(data)] ^= 0x01
+	msg[len(msg)-1] ^= 0x80
+
+	var a [25]uint64
+	for off := 0; off < len(msg); off += rate {
+		for i := 0; i < rate/8; i++ {
+			a[i] ^= binary.LittleEndian.Uint64(msg[off+8*i:])
+		}
+		keccakF(&a)
+	}
+	var out [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[8*i:], a[i])
+	}
+	return out
+}
+
+// validEIP55 reports whether a 0x-prefixed hex address passes its EIP-55
+// checksum. All-lowercase and all-uppercase addresses carry none, so they
+// pass; a mixed-case one with a wrong letter's case is a typo.
+func validEIP55(address string) bool {
+	digits := address[2:]
+	lower := strings.ToLower(digits)
+	if digits == lower || digits == strings.ToUpper(digits) {
+		return true
+	}
+	hash := keccak256([]byte(lower))
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if (nibble >= 8) != (c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,371 @@
+package validator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// ADDRESS LABELS (pluggable providers, counterparty categories)
+// ---------------------------------------------------------
+
+// Label categories. Threat categories make a counterparty a risk.
+const (
+	CategoryMixer      = "MIXER"
+	CategoryDrainer    = "DRAINER"
+	CategoryScam       = "SCAM" // Phishing, rug pulls, exploiters
+	CategorySanctioned = "SANCTIONED"
+	CategoryExchange   = "EXCHANGE"
+	CategoryBridge     = "BRIDGE"
+	CategoryDeFi       = "DEFI"
+	CategoryOther      = "OTHER"
+)
+
+var threatCategories = map[string]bool{
+	CategoryMixer: true, CategoryDrainer: true, CategoryScam: true, CategorySanctioned: true,
+}
+
+// Counterparties (by tx count) looked up with remote providers; the rest
+// only get local labels
+const maxRemoteLabelLookups = 25
+
+// RemoteLabels is implemented by providers that call out over the network.
+// They are only asked about an address's largest counterparties.
+type RemoteLabels interface {
+	Remote() bool
+}
+
+// CachedLabels is implemented by remote providers that can label some
+// addresses without a network call (e.g. those linked to an earlier hit).
+// Counterparties too small for a remote lookup are still asked Cached.
+type CachedLabels interface {
+	Cached(address string) []Label
+}
+
+var (
+	labelProvidersMu sync.Mutex
+	labelProviders   = []Labels{BuiltinLabels{}}
+)
+
+// SetLabelProviders replaces the label providers, asked in order. Include
+// BuiltinLabels{} to keep the built-in threats, bridges and exchanges.
+func SetLabelProviders(providers ...Labels) {
+	labelProvidersMu.Lock()
+	defer labelProvidersMu.Unlock()
+	labelProviders = providers
+}
+
+func currentLabelProviders() []Labels {
+	labelProvidersMu.Lock()
+	defer labelProvidersMu.Unlock()
+	return labelProviders
+}
+
+// lookupLabels asks every provider (local ones, and the caches of remote
+// ones, unless remote is set) and drops duplicates.
+func lookupLabels(address string, remote bool) []Label {
+	return lookupLabelsIn(currentLabelProviders(), address, remote)
+}
+
+// lookupLabelsIn is lookupLabels over the given providers.
+func lookupLabelsIn(providers []Labels, address string, remote bool) []Label {
+	address = strings.ToLower(address)
+	var out []Label
+	seen := map[Label]bool{}
+	for _, p := range providers {
+		lookup := p.Lookup
+		if r, ok := p.(RemoteLabels); ok && r.Remote() && !remote {
+			c, ok := p.(CachedLabels)
+			if !ok {
+				continue
+			}
+			lookup = c.Cached
+		}
+		for _, l := range lookup(address) {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+	}
+	return out
+}
+
+// threatLabel returns the first threat label among labels.
+func threatLabel(labels []Label) (Label, bool) {
+	for _, l := range labels {
+		if threatCategories[l.Category] {
+			return l, true
+		}
+	}
+	return Label{}, false
+}
+
+// addressThreat reports whether any provider labels the address as a threat.
+func addressThreat(address string, remote bool) (Label, bool) {
+	return threatLabel(lookupLabels(address, remote))
+}
+
+// titleWord turns a category into reason text: "SANCTIONED" -> "Sanctioned".
+func titleWord(s string) string {
+	if s == "" {
+		return s
+	}
+	return s[:1] + strings.ToLower(s[1:])
+}
+
+// labelCounterparties labels the address's counterparties; remote
+// providers are only asked about the largest maxRemoteLabelLookups.
+func labelCounterparties(providers []Labels, address string, txs []Transaction) []CounterpartyLabels {
+	counts := map[string]int{}
+	for _, tx := range txs {
+		other := tx.From
+		if strings.EqualFold(tx.From, address) {
+			other = tx.To
+		}
+		if other = strings.ToLower(other); other != "" && other != strings.ToLower(address) {
+			counts[other]++
+		}
+	}
+	parties := make([]string, 0, len(counts))
+	for p := range counts {
+		parties = append(parties, p)
+	}
+	sort.Slice(parties, func(i, j int) bool {
+		if counts[parties[i]] != counts[parties[j]] {
+			return counts[parties[i]] > counts[parties[j]]
+		}
+		return parties[i] < parties[j]
+	})
+
+	var out []CounterpartyLabels
+	for i, p := range parties {
+		if labels := lookupLabelsIn(providers, p, i < maxRemoteLabelLookups); len(labels) > 0 {
+			out = append(out, CounterpartyLabels{Address: p, Txs: counts[p], Labels: labels})
+		}
+	}
+	return out
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Built-in (threats, bridges, DEX routers, lenders, exchange hot wallets)
+// ---------------------------------------------------------
+
+// BuiltinLabels serves the built-in datasets (exchanges include LABELS_FILE).
+type BuiltinLabels struct{}
+
+func (BuiltinLabels) Lookup(address string) []Label {
+	var out []Label
+	if entity, ok := knownThreats[address]; ok {
+		out = append(out, Label{Entity: entity, Category: CategoryMixer, Source: "builtin"})
+	}
+	if entity, ok := knownBridges[address]; ok {
+		out = append(out, Label{Entity: entity, Category: CategoryBridge, Source: "builtin"})
+	}
+	if entity, ok := knownDEXRouters[address]; ok {
+		out = append(out, Label{Entity: entity, Category: CategoryDeFi, Source: "builtin"})
+	} else if entity, ok := knownFlashLoanProviders[address]; ok {
+		out = append(out, Label{Entity: entity, Category: CategoryDeFi, Source: "builtin"})
+	}
+	if l, ok := lookupLabel(address); ok {
+		out = append(out, Label{Entity: l.Entity, Category: CategoryExchange, Source: "builtin"})
+	}
+	return out
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Local CSV (address,entity,category)
+// ---------------------------------------------------------
+
+// CSVLabels serves labels loaded from a CSV file.
+type CSVLabels struct {
+	labels map[string][]Label
+}
+
+// LoadCSVLabels reads "address,entity,category" rows (a header row and
+// # comments are skipped). Unknown categories are kept as written.
+func LoadCSVLabels(path string) (*CSVLabels, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+	out := &CSVLabels{labels: map[string][]Label{}}
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if line == 1 && strings.EqualFold(rec[0], "address") {
+			continue
+		}
+		category := strings.ToUpper(strings.TrimSpace(rec[2]))
+		if category == "" {
+			return nil, fmt.Errorf("%s: line %d: missing category", path, line)
+		}
+		addr := strings.ToLower(strings.TrimSpace(rec[0]))
+		out.labels[addr] = append(out.labels[addr], Label{Entity: rec[1], Category: category, Source: "csv"})
+	}
+}
+
+func (c *CSVLabels) Lookup(address string) []Label {
+	return c.labels[address]
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Watchlist (engine DB or embedded store)
+// ---------------------------------------------------------
+
+// WatchlistLabels labels sanctioned addresses. It stops asking once the
+// engine fails, so a down engine costs one timeout. A hit on an SDN entity
+// also labels the entity's co-listed addresses, so counterparties that are
+// never queried are still caught.
+type WatchlistLabels struct {
+	mu   sync.Mutex
+	down bool
+	memo map[string][]Label
+}
+
+func (w *WatchlistLabels) Remote() bool { return true }
+
+func (w *WatchlistLabels) Cached(address string) []Label {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.memo[address]
+}
+
+func (w *WatchlistLabels) Lookup(address string) []Label {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if labels, ok := w.memo[address]; ok || w.down {
+		return labels
+	}
+	resp, err := CheckWatchlist(address)
+	if err != nil {
+		w.down = true
+		return nil
+	}
+	if w.memo == nil {
+		w.memo = map[string][]Label{}
+	}
+	var labels []Label
+	if resp.Sanctioned {
+		entity := resp.Source + " Sanctioned Address"
+		if resp.EntityName != "" {
+			entity = resp.EntityName
+		}
+		labels = []Label{{Entity: entity, Category: CategorySanctioned, Source: "watchlist"}}
+		for _, r := range resp.CoListed {
+			if addr := strings.ToLower(r.Address); w.memo[addr] == nil {
+				w.memo[addr] = []Label{{Entity: entity + " (Co-listed)", Category: CategorySanctioned, Source: "watchlist"}}
+			}
+		}
+	}
+	w.memo[address] = labels
+	return labels
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Etherscan name tags (Ethereum mainnet, API plans with tags)
+// ---------------------------------------------------------
+
+// EtherscanLabels looks up Etherscan's public name tags. Plans without
+// the name tag API return an error once and the provider turns itself off.
+type EtherscanLabels struct {
+	APIKey string
+	Client *http.Client // nil = 5s timeout
+
+	mu   sync.Mutex
+	off  bool
+	memo map[string][]Label
+}
+
+func (e *EtherscanLabels) Remote() bool { return true }
+
+func (e *EtherscanLabels) Lookup(address string) []Label {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if labels, ok := e.memo[address]; ok || e.off || e.APIKey == "" {
+		return labels
+	}
+	client := e.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("%s?chainid=1&module=nametag&action=getaddresstag&address=%s&apikey=%s", etherscanV2URL, address, e.APIKey)
+	var resp struct {
+		Status string          `json:"status"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := getJSON(ctx, client, url, &resp); err != nil {
+		return nil // Transient; try again for the next address
+	}
+	var tags []struct {
+		Nametag string   `json:"nametag"`
+		Labels  []string `json:"labels"`
+	}
+	if resp.Status != "1" || json.Unmarshal(resp.Result, &tags) != nil {
+		e.off = true // "Invalid API key" or a plan without name tags
+		return nil
+	}
+
+	var labels []Label
+	for _, t := range tags {
+		if t.Nametag == "" && len(t.Labels) == 0 {
+			continue
+		}
+		entity := t.Nametag
+		if entity == "" {
+			entity = t.Labels[0]
+		}
+		labels = append(labels, Label{Entity: entity, Category: etherscanCategory(t.Labels), Source: "etherscan"})
+	}
+	if e.memo == nil {
+		e.memo = map[string][]Label{}
+	}
+	e.memo[address] = labels
+	return labels
+}
+
+// etherscanCategory maps Etherscan label names ("Exchange", "Phish / Hack",
+// "Tornado.Cash") to a category.
+func etherscanCategory(labels []string) string {
+	joined := strings.ToLower(strings.Join(labels, " "))
+	switch {
+	case strings.Contains(joined, "sanction") || strings.Contains(joined, "ofac"):
+		return CategorySanctioned
+	case strings.Contains(joined, "tornado") || strings.Contains(joined, "mixer"):
+		return CategoryMixer
+	case strings.Contains(joined, "drainer"):
+		return CategoryDrainer
+	case strings.Contains(joined, "phish") || strings.Contains(joined, "hack") || strings.Contains(joined, "exploit") || strings.Contains(joined, "scam") || strings.Contains(joined, "heist"):
+		return CategoryScam
+	case strings.Contains(joined, "exchange"):
+		return CategoryExchange
+	case strings.Contains(joined, "bridge"):
+		return CategoryBridge
+	case strings.Contains(joined, "dex") || strings.Contains(joined, "defi") || strings.Contains(joined, "lending"):
+		return CategoryDeFi
+	}
+	return CategoryOther
+}
@@ -0,0 +1,248 @@
+package validator
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ---------------------------------------------------------
+// EXCHANGE ATTRIBUTION (labelled hot wallets, deposit addresses)
+// ---------------------------------------------------------
+
+const (
+	// Recipients checked for the deposit-address pattern, by value sent
+	maxDepositCandidates = 5
+	// Share of a candidate's outgoing txs that must sweep to one exchange
+	depositSweepShare = 0.8
+)
+
+// AddressLabel attributes an address to a known entity.
+type AddressLabel struct {
+	Entity       string `json:"entity"`                 // e.g. "Coinbase"
+	Regulated    bool   `json:"regulated"`              // Licensed, KYC-enforcing exchange
+	Jurisdiction string `json:"jurisdiction,omitempty"` // ISO 3166-1 alpha-2, e.g. "US"
+}
+
+// Built-in exchange hot wallets (Ethereum and EVM L2s share addresses).
+// LABELS_FILE adds to or overrides these. The jurisdiction is left blank
+// for exchanges without a single home regulator.
+var defaultExchangeLabels = map[string]AddressLabel{
+	"0x28c6c06298d514db089934071355e5743bf21d60": {"Binance", true, ""},
+	"0x21a31ee1afc51d94c2efccaa2092ad1028285549": {"Binance", true, ""},
+	"0xdfd5293d8e347dfe59e90efd55b2956a1343963d": {"Binance", true, ""},
+	"0xbe0eb53f46cd790cd13851d5eff43d12404d33e8": {"Binance", true, ""},
+	"0xf977814e90da44bfa03b6295a0616a897441acec": {"Binance", true, ""},
+	"0x71660c4005ba85c37ccec55d0c4493e66fe775d3": {"Coinbase", true, "US"},
+	"0x503828976d22510aad0201ac7ec88293211d23da": {"Coinbase", true, "US"},
+	"0xddfabcdc4d8ffc6d5beaf154f18b778f892a0740": {"Coinbase", true, "US"},
+	"0xa9d1e08c7793af67e9d92fe308d5697fb81d3e43": {"Coinbase", true, "US"},
+	"0x2910543af39aba0cd09dbb2d50200b3e800a63d2": {"Kraken", true, "US"},
+	"0xda9dfa130df4de4673b89022ee50ff26f6ea73cf": {"Kraken", true, "US"},
+	"0xd24400ae8bfebb18ca49be86258a3c749cf46853": {"Gemini", true, "US"},
+	"0x6cc5f688a315f3dc28a7781717a9a798a59fda7b": {"OKX", true, ""},
+	"0x876eabf441b2ee5b5b0554fd502a8e0600950cfa": {"Bitfinex", true, ""},
+}
+
+var (
+	labelsMu sync.Mutex
+	labels   = defaultExchangeLabels
+)
+
+// SetLabels adds labels to the built-in dataset (same address = override).
+func SetLabels(extra map[string]AddressLabel) {
+	merged := make(map[string]AddressLabel, len(defaultExchangeLabels)+len(extra))
+	for addr, l := range defaultExchangeLabels {
+		merged[addr] = l
+	}
+	for addr, l := range extra {
+		merged[strings.ToLower(addr)] = l
+	}
+	labelsMu.Lock()
+	defer labelsMu.Unlock()
+	labels = merged
+}
+
+func lookupLabel(address string) (AddressLabel, bool) {
+	labelsMu.Lock()
+	defer labelsMu.Unlock()
+	l, ok := labels[strings.ToLower(address)]
+	return l, ok
+}
+
+// LoadLabels reads a CSV of "address,entity,regulated[,jurisdiction]" rows
+// (a header row and # comments are skipped).
+func LoadLabels(path string) (map[string]AddressLabel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1 // The jurisdiction column is optional
+	r.TrimLeadingSpace = true
+	out := map[string]AddressLabel{}
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(rec) != 3 && len(rec) != 4 {
+			return nil, fmt.Errorf("%s: line %d: want address,entity,regulated[,jurisdiction]", path, line)
+		}
+		if line == 1 && strings.EqualFold(rec[0], "address") {
+			continue
+		}
+		regulated, err := strconv.ParseBool(rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: regulated must be true or false", path, line)
+		}
+		label := AddressLabel{Entity: rec[1], Regulated: regulated}
+		if len(rec) == 4 {
+			label.Jurisdiction = strings.ToUpper(strings.TrimSpace(rec[3]))
+		}
+		out[strings.ToLower(rec[0])] = label
+	}
+}
+
+// exchangeLinks finds the labelled hot wallets among the counterparties.
+func exchangeLinks(address string, txs []Transaction) []ExchangeLink {
+	byAddr := map[string]*ExchangeLink{}
+	for _, tx := range txs {
+		sent := strings.EqualFold(tx.From, address)
+		party := tx.From
+		if sent {
+			party = tx.To
+		}
+		label, ok := lookupLabel(party)
+		if !ok {
+			continue
+		}
+		party = strings.ToLower(party)
+		link := byAddr[party]
+		if link == nil {
+			link = &ExchangeLink{Entity: label.Entity, Regulated: label.Regulated, Jurisdiction: label.Jurisdiction, Address: party, Via: "HOT_WALLET"}
+			byAddr[party] = link
+		}
+		if sent {
+			link.Sent++
+		} else {
+			link.Received++
+		}
+	}
+	return sortedLinks(byAddr)
+}
+
+// attributeDeposits follows the largest unlabelled recipients: an EOA whose
+// outgoing txs almost all sweep to one exchange's hot wallet is that
+// exchange's deposit address for a customer.
+func attributeDeposits(ctx context.Context, pc ProviderChain, address string, txs []Transaction) []ExchangeLink {
+	sentValue := map[string]float64{}
+	sentCount := map[string]int{}
+	for _, tx := range txs {
+		if !strings.EqualFold(tx.From, address) || tx.To == "" || tx.Internal {
+			continue
+		}
+		to := strings.ToLower(tx.To)
+		if _, labelled := lookupLabel(to); labelled {
+			continue
+		}
+		sentCount[to]++
+		if v, ok := new(big.Float).SetString(tx.Value); ok {
+			f, _ := v.Float64()
+			sentValue[to] += f
+		}
+	}
+	candidates := make([]string, 0, len(sentCount))
+	for to := range sentCount {
+		candidates = append(candidates, to)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if sentValue[candidates[i]] != sentValue[candidates[j]] {
+			return sentValue[candidates[i]] > sentValue[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	byAddr := map[string]*ExchangeLink{}
+	for i, candidate := range candidates {
+		if i >= maxDepositCandidates || ctx.Err() != nil {
+			break
+		}
+		hist, _, _, err := pc.GetTransactions(ctx, candidate)
+		if err != nil {
+			continue
+		}
+		if label, ok := depositSweepTarget(candidate, hist.Txs); ok {
+			byAddr[candidate] = &ExchangeLink{Entity: label.Entity, Regulated: label.Regulated, Jurisdiction: label.Jurisdiction, Address: candidate, Via: "DEPOSIT_ADDRESS", Sent: sentCount[candidate]}
+		}
+	}
+	return sortedLinks(byAddr)
+}
+
+// depositSweepTarget reports the exchange that receives (nearly) all of
+// the candidate's outgoing txs.
+func depositSweepTarget(candidate string, txs []Transaction) (AddressLabel, bool) {
+	sweeps := map[AddressLabel]int{}
+	outgoing := 0
+	for _, tx := range txs {
+		if !strings.EqualFold(tx.From, candidate) || tx.Internal {
+			continue
+		}
+		outgoing++
+		if label, ok := lookupLabel(tx.To); ok {
+			sweeps[label]++
+		}
+	}
+	for label, n := range sweeps {
+		if float64(n)/float64(outgoing) >= depositSweepShare {
+			return label, true
+		}
+	}
+	return AddressLabel{}, false
+}
+
+func sortedLinks(byAddr map[string]*ExchangeLink) []ExchangeLink {
+	links := make([]ExchangeLink, 0, len(byAddr))
+	for _, l := range byAddr {
+		links = append(links, *l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Sent+links[i].Received != links[j].Sent+links[j].Received {
+			return links[i].Sent+links[i].Received > links[j].Sent+links[j].Received
+		}
+		return links[i].Address < links[j].Address
+	})
+	if len(links) == 0 {
+		return nil
+	}
+	return links
+}
+
+// exchangeDetail is the human-readable ValidationDetails fragment.
+func exchangeDetail(links []ExchangeLink) string {
+	var entities []string
+	seen := map[string]bool{}
+	for _, l := range links {
+		if !seen[l.Entity] {
+			seen[l.Entity] = true
+			entities = append(entities, l.Entity)
+		}
+	}
+	if len(entities) == 0 {
+		return ""
+	}
+	return "Exchange Links: " + strings.Join(entities, ", ")
+}
@@ -0,0 +1,303 @@
+package validator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LightningStrategy profiles Lightning Network node public keys and BOLT11
+// invoices. Invoices are profiled as their destination node; the node's
+// public graph data comes from a mempool.space-compatible Lightning API.
+type LightningStrategy struct {
+	Testnet  bool   // lntb invoices, testnet graph
+	GraphURL string // Empty = mempool.space for the configured network
+}
+
+// Default public Lightning graph APIs
+const (
+	mempoolLightningMainnetURL = "https://mempool.space/api/v1/lightning"
+	mempoolLightningTestnetURL = "https://mempool.space/testnet/api/v1/lightning"
+)
+
+func (l *LightningStrategy) Name() string {
+	return "LIGHTNING"
+}
+
+// invoicePrefix is the BOLT11 HRP prefix (before the amount) for the network.
+func (l *LightningStrategy) invoicePrefix() string {
+	if l.Testnet {
+		return "lntb"
+	}
+	return "lnbc"
+}
+
+func (l *LightningStrategy) IsValidSyntax(address string) bool {
+	address = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(address)), "lightning:")
+	if strings.HasPrefix(address, l.invoicePrefix()) {
+		_, err := decodeBolt11(address, l.invoicePrefix())
+		return err == nil
+	}
+	_, err := parseNodePubKey(address)
+	return err == nil
+}
+
+func (l *LightningStrategy) FetchState(ctx context.Context, address string, _ string) (*WalletProfile, error) {
+	input := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(address)), "lightning:")
+	profile := &WalletProfile{
+		Network:   "LIGHTNING",
+		IsValid:   true,
+		Testnet:   l.Testnet,
+		Lightning: &LightningInfo{},
+	}
+
+	// 1. Destination node (invoices carry it, or it is recovered from the signature)
+	var details []string
+	if strings.HasPrefix(input, l.invoicePrefix()) {
+		inv, err := decodeBolt11(input, l.invoicePrefix())
+		if err != nil {
+			return nil, err
+		}
+		profile.AddressType = "BOLT11_INVOICE"
+		profile.Lightning.Invoice = inv
+		profile.Lightning.NodePubKey = inv.Payee
+		if inv.Expired() {
+			details = append(details, "Invoice Expired")
+		}
+		// On-chain fallback addresses belong to the payee: screen them too
+		profile.ScreenAddresses = append(profile.ScreenAddresses, inv.FallbackAddresses...)
+	} else {
+		pubkey, _ := parseNodePubKey(input)
+		profile.AddressType = "NODE_PUBKEY"
+		profile.Lightning.NodePubKey = pubkey
+	}
+	profile.Address = profile.Lightning.NodePubKey
+
+	// 2. Node metadata from the public graph
+	graphURL := l.GraphURL
+	if graphURL == "" {
+		graphURL = mempoolLightningMainnetURL
+		if l.Testnet {
+			graphURL = mempoolLightningTestnetURL
+		}
+	}
+	client := httpClient(10 * time.Second)
+
+	var node struct {
+		Alias              string `json:"alias"`
+		Capacity           int64  `json:"capacity"` // Satoshis
+		ActiveChannelCount int    `json:"active_channel_count"`
+		ClosedChannelCount int    `json:"closed_channel_count"`
+		FirstSeen          int64  `json:"first_seen"`
+		UpdatedAt          int64  `json:"updated_at"`
+	}
+	err := getJSON(ctx, client, fmt.Sprintf("%s/nodes/%s", strings.TrimRight(graphURL, "/"), profile.Address), &node)
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound:
+		// Private (unannounced) nodes are valid but not in the graph
+		details = append([]string{"Node Not in Public Graph (Private or Offline)"}, details...)
+	case err != nil:
+		details = append([]string{fmt.Sprintf("Graph Lookup Failed: %v", err)}, details...)
+	default:
+		profile.Lightning.Alias = node.Alias
+		profile.Lightning.Channels = node.ActiveChannelCount
+		profile.Balance = formatSats(big.NewInt(node.Capacity)) // Public channel capacity
+		setRawBalance(profile, big.NewInt(node.Capacity), 8, "BTC")
+		profile.IsActive = node.ActiveChannelCount > 0
+		if node.FirstSeen > 0 {
+			first := time.Unix(node.FirstSeen, 0)
+			profile.FirstSeen = &first
+		}
+		if node.UpdatedAt > 0 {
+			last := time.Unix(node.UpdatedAt, 0)
+			profile.LastSeen = &last
+		}
+
+		summary := fmt.Sprintf("Node %q | %d Active Channels | Capacity %s", node.Alias, node.ActiveChannelCount, profile.Balance)
+		if node.ActiveChannelCount == 0 {
+			summary = fmt.Sprintf("Node %q | No Active Channels (%d Closed)", node.Alias, node.ClosedChannelCount)
+		}
+		if profile.FirstSeen != nil {
+			summary += fmt.Sprintf(" | First Seen: %s", profile.FirstSeen.Format("2006-01-02"))
+		}
+		details = append([]string{summary}, details...)
+	}
+	profile.ValidationDetails = strings.Join(details, " | ")
+
+	return profile, nil
+}
+
+// parseNodePubKey accepts a 33-byte compressed secp256k1 key in hex,
+// optionally as a node URI (pubkey@host:port).
+func parseNodePubKey(s string) (string, error) {
+	s, _, _ = strings.Cut(s, "@")
+	if len(s) != 66 {
+		return "", errors.New("lightning: node pubkey must be 66 hex chars")
+	}
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	if _, err := secpDecompress(raw); err != nil {
+		return "", err
+	}
+	return strings.ToLower(s), nil
+}
+
+// ---------------------------------------------------------
+// BOLT11 INVOICES
+// ---------------------------------------------------------
+
+// BOLT11 tagged field types (5-bit values of the bech32 characters)
+const (
+	bolt11TagPaymentHash = 1  // p
+	bolt11TagExpiry      = 6  // x
+	bolt11TagFallback    = 9  // f
+	bolt11TagDescription = 13 // d
+	bolt11TagPayee       = 19 // n
+)
+
+// Invoices are far longer than the 90-char BIP-173 limit
+const bolt11MaxLen = 7089
+
+// decodeBolt11 checks the invoice checksum and signature and extracts the
+// fields that matter for screening. prefix is "lnbc" or "lntb".
+func decodeBolt11(invoice, prefix string) (*LightningInvoice, error) {
+	hrp, data, enc, err := bech32Decode(invoice, bolt11MaxLen)
+	if err != nil {
+		return nil, err
+	}
+	if enc != encodingBech32 || !strings.HasPrefix(hrp, prefix) {
+		return nil, errors.New("bolt11: not an invoice for this network")
+	}
+	// 7 words of timestamp + 104 words of signature at minimum
+	if len(data) < 7+104 {
+		return nil, errors.New("bolt11: too short")
+	}
+
+	inv := &LightningInvoice{Expiry: 3600}
+	if inv.AmountBTC, err = parseBolt11Amount(hrp[len(prefix):]); err != nil {
+		return nil, err
+	}
+
+	body, sigWords := data[:len(data)-104], data[len(data)-104:]
+	inv.Timestamp = time.Unix(int64(readBolt11Uint(body[:7])), 0)
+
+	// Tagged fields: type (1 word), length (2 words), data
+	for fields := body[7:]; len(fields) >= 3; {
+		tag, length := fields[0], int(fields[1])<<5|int(fields[2])
+		if len(fields) < 3+length {
+			return nil, errors.New("bolt11: truncated field")
+		}
+		value := fields[3 : 3+length]
+		fields = fields[3+length:]
+
+		switch tag {
+		case bolt11TagPaymentHash:
+			if b, err := convertBits(value, 5, 8, false); err == nil && len(b) == 32 {
+				inv.PaymentHash = hex.EncodeToString(b)
+			}
+		case bolt11TagDescription:
+			if b, err := convertBits(value, 5, 8, false); err == nil {
+				inv.Description = string(b)
+			}
+		case bolt11TagExpiry:
+			inv.Expiry = int64(readBolt11Uint(value))
+		case bolt11TagPayee:
+			if b, err := convertBits(value, 5, 8, false); err == nil && len(b) == 33 {
+				inv.Payee = hex.EncodeToString(b)
+			}
+		case bolt11TagFallback:
+			if addr, ok := bolt11FallbackAddress(value, prefix == "lntb"); ok {
+				inv.FallbackAddresses = append(inv.FallbackAddresses, addr)
+			}
+		}
+	}
+
+	// The signature commits to the HRP and the data before it; the payee's
+	// key is recovered from it unless the invoice names the payee
+	sig, err := convertBits(sigWords, 5, 8, false)
+	if err != nil || len(sig) != 65 {
+		return nil, errors.New("bolt11: invalid signature encoding")
+	}
+	signed, _ := convertBits(body, 5, 8, true)
+	hash := sha256.Sum256(append([]byte(hrp), signed...))
+	pub, err := secpRecoverPubKey(hash[:], sig[:64], sig[64])
+	if err != nil {
+		return nil, fmt.Errorf("bolt11: %w", err)
+	}
+	recovered := hex.EncodeToString(pub.compress())
+	if inv.Payee == "" {
+		inv.Payee = recovered
+	}
+	// With an explicit payee, a high-S or foreign signature recovers a different key
+	inv.SignatureValid = inv.Payee == recovered
+	return inv, nil
+}
+
+// parseBolt11Amount turns the HRP amount ("2500u", "" for any amount) into BTC.
+func parseBolt11Amount(amount string) (float64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+	divisor := 1.0
+	switch amount[len(amount)-1] {
+	case 'm':
+		divisor = 1e3
+	case 'u':
+		divisor = 1e6
+	case 'n':
+		divisor = 1e9
+	case 'p':
+		divisor = 1e12
+	}
+	if divisor != 1 {
+		amount = amount[:len(amount)-1]
+	}
+	n, ok := new(big.Int).SetString(amount, 10)
+	if !ok || n.Sign() <= 0 {
+		return 0, fmt.Errorf("bolt11: invalid amount %q", amount)
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return f / divisor, nil
+}
+
+func readBolt11Uint(words []byte) uint64 {
+	var v uint64
+	for _, w := range words {
+		v = v<<5 | uint64(w)
+	}
+	return v
+}
+
+// bolt11FallbackAddress decodes an "f" field: version 0-16 segwit, 17 P2PKH, 18 P2SH.
+func bolt11FallbackAddress(value []byte, testnet bool) (string, bool) {
+	if len(value) < 1 {
+		return "", false
+	}
+	program, err := convertBits(value[1:], 5, 8, false)
+	if err != nil {
+		return "", false
+	}
+	hrp, p2pkh, p2sh := "bc", byte(0x00), byte(0x05)
+	if testnet {
+		hrp, p2pkh, p2sh = "tb", 0x6f, 0xc4
+	}
+	switch version := int(value[0]); {
+	case version == 17 && len(program) == 20:
+		return base58CheckEncode(append([]byte{p2pkh}, program...)), true
+	case version == 18 && len(program) == 20:
+		return base58CheckEncode(append([]byte{p2sh}, program...)), true
+	case version <= 16:
+		addr, err := encodeSegwitAddress(hrp, version, program)
+		return addr, err == nil
+	}
+	return "", false
+}
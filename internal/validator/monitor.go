@@ -0,0 +1,323 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// MONITORING (periodic re-scoring of saved addresses)
+// ---------------------------------------------------------
+
+// Watchlist status of a monitored address
+const (
+	StatusClear      = "CLEAR"
+	StatusSanctioned = "SANCTIONED"
+	StatusUnknown    = "UNKNOWN" // Watchlist engine unavailable
+)
+
+// Monitor alert triggers
+const (
+	TriggerScoreAbove       = "SCORE_ABOVE_THRESHOLD"
+	TriggerScoreBelow       = "SCORE_BELOW_THRESHOLD"
+	TriggerWatchlistHit     = "WATCHLIST_HIT"
+	TriggerWatchlistCleared = "WATCHLIST_CLEARED"
+)
+
+const (
+	// How often Run re-scores the list, unless set
+	DefaultMonitorInterval = time.Hour
+	// Time allowed per address, unless set
+	defaultMonitorTimeout = 30 * time.Second
+)
+
+// Monitor re-scores every stored address and reports alerts.
+type Monitor struct {
+	Store     MonitorStore
+	Threshold float64 // Score alerts fire when a re-score crosses it; 0 is the rules' FAILING bound
+	Probe     bool    // Query every chain whose syntax matches
+	Timeout   time.Duration
+	Alert     func(MonitorAlert) // Called for every alert; nil only logs
+}
+
+// Run checks all addresses now and then every interval, until ctx ends.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultMonitorInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			slog.Warn("⚠️ [MONITOR] Check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check re-scores every stored address once and returns the alerts raised.
+// An address that fails to analyze keeps its previous state.
+func (m *Monitor) Check(ctx context.Context) ([]MonitorAlert, error) {
+	entries, err := m.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = defaultMonitorTimeout
+	}
+
+	var alerts []MonitorAlert
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return alerts, ctx.Err()
+		}
+		actx, cancel := context.WithTimeout(ctx, timeout)
+		profile, err := m.analyze(actx, entry.Address)
+		cancel()
+		if err != nil || profile == nil || !profile.IsValid {
+			if err == nil {
+				err = errors.New("no valid profile")
+			}
+			slog.Warn("⚠️ [MONITOR] Check failed", "address", entry.Address, "err", err)
+			continue
+		}
+		// Without the watchlist a sanctioned address would look cleared
+		status := WatchlistStatus(profile)
+		if status == StatusUnknown && entry.LastStatus == StatusSanctioned {
+			slog.Warn("⚠️ [MONITOR] Watchlist unavailable, keeping its SANCTIONED state", "address", entry.Address)
+			continue
+		}
+
+		raised := m.compare(entry, profile)
+		for _, a := range raised {
+			slog.Warn("🔔 [MONITOR] Alert", "trigger", a.Trigger, "address", a.Address, "previous_score", a.PreviousScore, "score", a.Score, "status", a.Status)
+			if m.Alert != nil {
+				m.Alert(a)
+			}
+		}
+		alerts = append(alerts, raised...)
+
+		entry.LastChecked = time.Now().UTC()
+		entry.Network = profile.Network
+		entry.LastScore = profile.RiskScore
+		entry.LastGrade = profile.RiskGrade
+		if status != StatusUnknown {
+			entry.LastStatus = status
+		}
+		if err := m.Store.Put(entry); err != nil {
+			slog.Warn("⚠️ [MONITOR] Write failed", "err", err)
+		}
+	}
+	return alerts, nil
+}
+
+// analyze resolves names (they can be re-pointed) and profiles the address.
+func (m *Monitor) analyze(ctx context.Context, address string) (*WalletProfile, error) {
+	resolvedFrom := ""
+	if IsResolvableName(address) {
+		resolved, _, err := ResolveName(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		resolvedFrom, address = address, resolved
+	}
+	profile, err := Analyze(ctx, address, m.Probe)
+	if profile != nil {
+		profile.ResolvedFrom = resolvedFrom
+	}
+	return profile, err
+}
+
+// compare returns the alerts between an entry's last check and a new
+// profile. The first check of an address only alerts on a sanctions hit
+// or a score already over the threshold.
+func (m *Monitor) compare(entry MonitoredAddress, profile *WalletProfile) []MonitorAlert {
+	status := WatchlistStatus(profile)
+	first := entry.LastChecked.IsZero()
+	threshold := m.Threshold
+	if threshold == 0 {
+		threshold = currentRiskRules().Grades.Warning
+	}
+	alert := func(trigger string) MonitorAlert {
+		a := MonitorAlert{
+			Address:       entry.Address,
+			Network:       profile.Network,
+			Note:          entry.Note,
+			Trigger:       trigger,
+			PreviousScore: entry.LastScore,
+			Score:         profile.RiskScore,
+			PreviousGrade: entry.LastGrade,
+			Grade:         profile.RiskGrade,
+			Status:        status,
+			At:            time.Now().UTC(),
+		}
+		if trigger == TriggerScoreAbove || trigger == TriggerScoreBelow {
+			a.Threshold = threshold
+		}
+		return a
+	}
+
+	var alerts []MonitorAlert
+	switch {
+	case status == StatusSanctioned && entry.LastStatus != StatusSanctioned:
+		alerts = append(alerts, alert(TriggerWatchlistHit))
+	case status == StatusClear && entry.LastStatus == StatusSanctioned:
+		alerts = append(alerts, alert(TriggerWatchlistCleared))
+	}
+	above, wasAbove := profile.RiskScore >= threshold, entry.LastScore >= threshold
+	switch {
+	case above && (first || !wasAbove):
+		alerts = append(alerts, alert(TriggerScoreAbove))
+	case !above && wasAbove && !first:
+		alerts = append(alerts, alert(TriggerScoreBelow))
+	}
+	return alerts
+}
+
+// WatchlistStatus reads the sanctions outcome from the risk reasons:
+// StatusClear, StatusSanctioned or StatusUnknown.
+func WatchlistStatus(profile *WalletProfile) string {
+	for _, r := range profile.RiskReasons {
+		switch r.RuleID {
+		case "sanctions":
+			return StatusSanctioned
+		case "watchlist_unavailable":
+			return StatusUnknown
+		}
+	}
+	return StatusClear
+}
+
+// WebhookAlerts returns an Alert func that POSTs each alert as JSON to url.
+// Failures are logged after the usual retries.
+func WebhookAlerts(url string) func(MonitorAlert) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(a MonitorAlert) {
+		body, err := json.Marshal(a)
+		if err != nil {
+			slog.Warn("⚠️ [MONITOR] Webhook", "err", err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err = doJSON(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			return req, err
+		}, nil)
+		if err != nil {
+			slog.Warn("⚠️ [MONITOR] Webhook failed", "err", err)
+		}
+	}
+}
+
+// ---------------------------------------------------------
+// BACKEND: JSON file (the whole list, rewritten on change)
+// ---------------------------------------------------------
+
+// FileMonitorStore keeps the monitored addresses in a local JSON file.
+type FileMonitorStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileMonitorStore opens (creating if needed) the list at path.
+func NewFileMonitorStore(path string) (*FileMonitorStore, error) {
+	s := &FileMonitorStore{Path: path}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return s, s.write(nil)
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := s.read(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileMonitorStore) List() ([]MonitoredAddress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *FileMonitorStore) Put(entry MonitoredAddress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	key := historyAddress(entry.Address)
+	for i, e := range entries {
+		if historyAddress(e.Address) == key {
+			entries[i] = entry
+			return s.write(entries)
+		}
+	}
+	return s.write(append(entries, entry))
+}
+
+func (s *FileMonitorStore) Remove(address string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	key := historyAddress(address)
+	for i, e := range entries {
+		if historyAddress(e.Address) == key {
+			return true, s.write(append(entries[:i], entries[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+func (s *FileMonitorStore) read() ([]MonitoredAddress, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []MonitoredAddress
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically, sorted by address.
+func (s *FileMonitorStore) write(entries []MonitoredAddress) error {
+	if entries == nil {
+		entries = []MonitoredAddress{}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Address < entries[j].Address })
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
+)
+
+// ---------------------------------------------------------
+// CLIENT: Screen a Name (fuzzy SDN name matching)
+// ---------------------------------------------------------
+
+// CheckName screens a person or company name against the SDN names and
+// aliases, in-process when an embedded watchlist is set and otherwise via
+// the engine's /check-name. A threshold of 0 uses the default (0.85).
+func CheckName(ctx context.Context, name string, threshold float64) (*watchlist.NameScreening, error) {
+	if threshold <= 0 {
+		threshold = watchlist.DefaultNameThreshold
+	}
+
+	store, err := localWatchlist()
+	if err != nil {
+		return nil, fmt.Errorf("local watchlist: %w", err)
+	}
+	if store != nil {
+		matches, err := store.ScreenName(ctx, name, threshold, 0)
+		if err != nil {
+			return nil, err
+		}
+		if matches == nil {
+			matches = []watchlist.NameMatch{}
+		}
+		return &watchlist.NameScreening{
+			Query:      name,
+			Normalized: watchlist.NormalizeName(name),
+			Threshold:  threshold,
+			Match:      len(matches) > 0,
+			Count:      len(matches),
+			Matches:    matches,
+		}, nil
+	}
+
+	engineURL, _ := WatchlistEngine()
+
+	// Every SDN name is scored, so allow longer than an address check
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	client := &http.Client{Timeout: 10 * time.Second}
+	query := url.Values{"name": {name}, "threshold": {strconv.FormatFloat(threshold, 'f', -1, 64)}}
+	reqURL := engineURL + "/check-name?" + query.Encode()
+
+	var result watchlist.NameScreening
+	err = doJSON(ctx, client, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	}, &result)
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return nil, fmt.Errorf("server error %d", httpErr.StatusCode)
+	case err != nil && isRetryable(err):
+		return nil, fmt.Errorf("connection refused")
+	case err != nil:
+		return nil, err
+	}
+	return &result, nil
+}
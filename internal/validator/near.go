@@ -0,0 +1,217 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	nearImplicitRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	// Named accounts: 2-64 chars, lowercase alphanumerics separated by - _ .
+	nearNamedRegex = regexp.MustCompile(`^(([a-z\d]+[\-_])*[a-z\d]+\.)*([a-z\d]+[\-_])*[a-z\d]+$`)
+)
+
+// Code hash of an account with no contract deployed
+const nearEmptyCodeHash = "11111111111111111111111111111111"
+
+type NearStrategy struct {
+	Testnet bool   // *.testnet accounts against NEAR testnet RPC / indexer
+	RPCURL  string // Empty = FastNEAR's public RPC
+}
+
+func (n *NearStrategy) Name() string {
+	return "NEAR"
+}
+
+func (n *NearStrategy) IsValidSyntax(address string) bool {
+	cleanAddr := strings.TrimSpace(address)
+	if nearImplicitRegex.MatchString(cleanAddr) {
+		return true
+	}
+	// Only accept named accounts under the .near (or .testnet) registrar
+	// to avoid swallowing arbitrary dotted strings (e.g. domain names).
+	suffix := ".near"
+	if n.Testnet {
+		suffix = ".testnet"
+	}
+	return len(cleanAddr) >= 2 && len(cleanAddr) <= 64 &&
+		strings.HasSuffix(cleanAddr, suffix) &&
+		nearNamedRegex.MatchString(cleanAddr)
+}
+
+func (n *NearStrategy) FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error) {
+	cleanAddr := strings.TrimSpace(address)
+	profile := &WalletProfile{
+		Address: cleanAddr,
+		Network: "NEAR",
+		IsValid: true,
+		Testnet: n.Testnet,
+	}
+
+	rpcURL := n.RPCURL
+	if rpcURL == "" {
+		rpcURL = "https://free.rpc.fastnear.com"
+		if n.Testnet {
+			rpcURL = "https://test.rpc.fastnear.com"
+		}
+	}
+	client := httpClient(15 * time.Second)
+
+	// 1. Account State (balance, contract)
+	var acct struct {
+		Amount   string `json:"amount"` // yoctoNEAR
+		Locked   string `json:"locked"`
+		CodeHash string `json:"code_hash"`
+	}
+	err := jsonRPC(ctx, client, rpcURL, "query", map[string]string{
+		"request_type": "view_account",
+		"finality":     "final",
+		"account_id":   cleanAddr,
+	}, &acct)
+
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) && rpcErr.Cause != nil && rpcErr.Cause.Name == "UNKNOWN_ACCOUNT" {
+		profile.Balance = "0.0000 NEAR"
+		setRawBalance(profile, new(big.Int), 24, "NEAR")
+		if nearImplicitRegex.MatchString(cleanAddr) {
+			profile.ValidationDetails = "Inactive Implicit Account (Never Funded)"
+		} else {
+			// Named accounts must be created on-chain; this one does not exist
+			profile.IsValid = false
+			profile.ValidationDetails = "Account Does Not Exist"
+		}
+		return profile, nil
+	}
+	if err != nil {
+		profile.ValidationDetails = fmt.Sprintf("NEAR RPC Error: %v", err)
+		return profile, nil
+	}
+
+	yocto, _ := new(big.Int).SetString(acct.Amount, 10)
+	if yocto == nil {
+		yocto = new(big.Int)
+	}
+	profile.Balance = formatNear(yocto)
+	setRawBalance(profile, yocto, 24, "NEAR")
+	profile.IsActive = yocto.Sign() > 0
+
+	details := []string{"Implicit Account"}
+	if !nearImplicitRegex.MatchString(cleanAddr) {
+		details[0] = "Named Account"
+	}
+	if acct.CodeHash != "" && acct.CodeHash != nearEmptyCodeHash {
+		details = append(details, "Contract Deployed")
+	}
+
+	// 2. Access Keys (full-access vs function-call keys)
+	var keys struct {
+		Keys []struct {
+			AccessKey struct {
+				Permission json.RawMessage `json:"permission"`
+			} `json:"access_key"`
+		} `json:"keys"`
+	}
+	err = jsonRPC(ctx, client, rpcURL, "query", map[string]string{
+		"request_type": "view_access_key_list",
+		"finality":     "final",
+		"account_id":   cleanAddr,
+	}, &keys)
+	if err == nil {
+		fullAccess := 0
+		for _, k := range keys.Keys {
+			if string(k.AccessKey.Permission) == `"FullAccess"` {
+				fullAccess++
+			}
+		}
+		details = append(details, fmt.Sprintf("Keys: %d (%d Full Access)", len(keys.Keys), fullAccess))
+		if len(keys.Keys) == 0 {
+			details = append(details, "Locked (No Access Keys)")
+		}
+	}
+
+	// 3. Activity (NearBlocks indexer, best effort)
+	indexerURL := "https://api.nearblocks.io"
+	if n.Testnet {
+		indexerURL = "https://api-testnet.nearblocks.io"
+	}
+	if err := fetchNearActivity(ctx, client, indexerURL, cleanAddr, apiKey, profile); err != nil {
+		details = append(details, "Tx History Unavailable")
+	} else if profile.LastSeen != nil {
+		details = append(details, fmt.Sprintf("Last Seen: %s", profile.LastSeen.Format("2006-01-02")))
+	}
+
+	profile.ValidationDetails = strings.Join(details, " | ")
+	return profile, nil
+}
+
+// fetchNearActivity fills TxCount, FirstSeen and LastSeen from NearBlocks.
+func fetchNearActivity(ctx context.Context, client *http.Client, indexerURL, account, apiKey string, profile *WalletProfile) error {
+	baseURL := fmt.Sprintf("%s/v1/account/%s", indexerURL, account)
+
+	get := func(url string, target interface{}) error {
+		return doJSON(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			if apiKey != "" {
+				req.Header.Set("Authorization", "Bearer "+apiKey)
+			}
+			return req, nil
+		}, target)
+	}
+
+	var countResp struct {
+		Txns []struct {
+			Count string `json:"count"`
+		} `json:"txns"`
+	}
+	if err := get(baseURL+"/txns-only/count", &countResp); err != nil {
+		return err
+	}
+	if len(countResp.Txns) > 0 {
+		profile.TxCount, _ = strconv.Atoi(countResp.Txns[0].Count)
+	}
+	if profile.TxCount == 0 {
+		return nil
+	}
+	profile.IsActive = true
+
+	type txList struct {
+		Txns []struct {
+			BlockTimestamp string `json:"block_timestamp"` // nanoseconds
+		} `json:"txns"`
+	}
+	for _, order := range []string{"asc", "desc"} {
+		var list txList
+		if err := get(baseURL+"/txns-only?per_page=1&order="+order, &list); err != nil || len(list.Txns) == 0 {
+			continue
+		}
+		ns, err := strconv.ParseInt(list.Txns[0].BlockTimestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(0, ns).UTC()
+		if order == "asc" {
+			profile.FirstSeen = &t
+		} else {
+			profile.LastSeen = &t
+		}
+	}
+	return nil
+}
+
+// formatNear converts yoctoNEAR (1e24) to a 4-decimal NEAR string.
+func formatNear(yocto *big.Int) string {
+	f := new(big.Float).SetInt(yocto)
+	f.Quo(f, new(big.Float).SetFloat64(1e24))
+	return fmt.Sprintf("%.4f NEAR", f)
+}
@@ -0,0 +1,274 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// PEEL CHAINS (laundering by repeated small splits)
+// ---------------------------------------------------------
+
+const (
+	// Addresses followed along a chain; each costs one history lookup
+	MaxPeelHops = 20
+	// The remainder passed on must be at least this share of a hop's outflow
+	peelRemainderShare = 0.8
+	// More recipients than this is a batch payout, not a peel
+	maxPeelRecipients = 3
+)
+
+// peelStep inspects one address's outgoing funds. It reports where the
+// remainder went and how much was peeled off, if the address peeled.
+type peelStep func(ctx context.Context, address string) (next string, peeled *big.Int, ok bool)
+
+// tracePeelChain follows remainders from address for up to maxHops.
+func tracePeelChain(ctx context.Context, step peelStep, address string, maxHops int) (int, *big.Int, []string) {
+	peeled := new(big.Int)
+	path := []string{address}
+	seen := map[string]bool{strings.ToLower(address): true}
+	current := address
+	hops := 0
+	for hops < min(maxHops, MaxPeelHops) && ctx.Err() == nil {
+		next, amount, ok := step(ctx, current)
+		if !ok || seen[strings.ToLower(next)] {
+			break
+		}
+		seen[strings.ToLower(next)] = true
+		hops++
+		peeled.Add(peeled, amount)
+		path = append(path, next)
+		current = next
+	}
+	return hops, peeled, path
+}
+
+// splitRemainder decides whether outflows (per recipient) look like a
+// peel: a few recipients, one of which receives nearly everything.
+func splitRemainder(outflows map[string]*big.Int) (string, *big.Int, bool) {
+	if len(outflows) < 2 || len(outflows) > maxPeelRecipients {
+		return "", nil, false
+	}
+	recipients := make([]string, 0, len(outflows))
+	total := new(big.Int)
+	for to, v := range outflows {
+		recipients = append(recipients, to)
+		total.Add(total, v)
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		if c := outflows[recipients[i]].Cmp(outflows[recipients[j]]); c != 0 {
+			return c > 0
+		}
+		return recipients[i] < recipients[j]
+	})
+	largest := outflows[recipients[0]]
+	if total.Sign() == 0 {
+		return "", nil, false
+	}
+	share, _ := new(big.Float).Quo(new(big.Float).SetInt(largest), new(big.Float).SetInt(total)).Float64()
+	if share < peelRemainderShare {
+		return "", nil, false
+	}
+	return recipients[0], new(big.Int).Sub(total, largest), true
+}
+
+// evmPeelStep treats an account's outgoing transfers as one split. The
+// first address reuses the history already loaded.
+func evmPeelStep(pc ProviderChain, origin string, originTxs []Transaction) peelStep {
+	return func(ctx context.Context, address string) (string, *big.Int, bool) {
+		txs := originTxs
+		if !strings.EqualFold(address, origin) {
+			hist, _, _, err := pc.GetTransactions(ctx, address)
+			if err != nil {
+				return "", nil, false
+			}
+			txs = hist.Txs
+		}
+		outflows := map[string]*big.Int{}
+		for _, tx := range txs {
+			if !strings.EqualFold(tx.From, address) || tx.Internal || tx.To == "" {
+				continue
+			}
+			v, ok := new(big.Int).SetString(tx.Value, 10)
+			if !ok || v.Sign() == 0 {
+				continue // Contract calls and token transfers move no ETH
+			}
+			to := strings.ToLower(tx.To)
+			if outflows[to] == nil {
+				outflows[to] = new(big.Int)
+			}
+			outflows[to].Add(outflows[to], v)
+		}
+		return splitRemainder(outflows)
+	}
+}
+
+// ---------------------------------------------------------
+// BITCOIN SPENDS (inputs and outputs)
+// ---------------------------------------------------------
+
+// Spend is a transaction spending the address's coins, with its outputs
+// (change back to the address excluded).
+type Spend struct {
+	Txid    string        `json:"txid"`
+	Outputs []SpendOutput `json:"outputs"`
+}
+
+// SpendOutput is one output of a Spend.
+type SpendOutput struct {
+	Address string `json:"address"`
+	Value   int64  `json:"value"` // Satoshis
+}
+
+// SpendProvider is implemented by Bitcoin providers that return the
+// outputs of an address's recent spends.
+type SpendProvider interface {
+	GetSpends(ctx context.Context, address string) ([]Spend, error)
+}
+
+// GetSpends returns the spends from the first provider that can list them.
+func (pc ProviderChain) GetSpends(ctx context.Context, address string) ([]Spend, string, []string, error) {
+	return failover(ctx, pc, func(p DataProvider) ([]Spend, error) {
+		lister, ok := p.(SpendProvider)
+		if !ok {
+			return nil, ErrNotSupported
+		}
+		return lister.GetSpends(ctx, address)
+	})
+}
+
+// btcPeelStep takes the first spend that splits into a small payment and
+// a large remainder to another address.
+func btcPeelStep(pc ProviderChain) peelStep {
+	return func(ctx context.Context, address string) (string, *big.Int, bool) {
+		spends, _, _, err := pc.GetSpends(ctx, address)
+		if err != nil {
+			return "", nil, false
+		}
+		for _, s := range spends {
+			outflows := map[string]*big.Int{}
+			for _, out := range s.Outputs {
+				if out.Address == "" {
+					continue // OP_RETURN and non-standard scripts
+				}
+				if outflows[out.Address] == nil {
+					outflows[out.Address] = new(big.Int)
+				}
+				outflows[out.Address].Add(outflows[out.Address], big.NewInt(out.Value))
+			}
+			if next, peeled, ok := splitRemainder(outflows); ok {
+				return next, peeled, true
+			}
+		}
+		return "", nil, false
+	}
+}
+
+// loadPeelChain traces a peel chain from the address into the profile.
+// format renders the peeled base units.
+func loadPeelChain(ctx context.Context, step peelStep, address string, maxHops int, format func(*big.Int) string, profile *WalletProfile) {
+	hops, peeled, path := tracePeelChain(ctx, step, address, maxHops)
+	if hops < 2 {
+		return // One split is an ordinary payment with change
+	}
+	profile.PeelChain = &PeelChainInfo{Hops: hops, Peeled: format(peeled), Path: path}
+	profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("Peel Chain: %d Hops", hops))
+}
+
+// ---------------------------------------------------------
+// PROVIDER SUPPORT
+// ---------------------------------------------------------
+
+// GetSpends reads the inputs and outputs rawaddr already returned.
+func (p *BlockchainInfoProvider) GetSpends(ctx context.Context, address string) ([]Spend, error) {
+	resp, err := p.rawaddr(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	var spends []Spend
+	for i := len(resp.Txs) - 1; i >= 0; i-- { // Oldest first
+		tx := resp.Txs[i]
+		spent := false
+		for _, in := range tx.Inputs {
+			spent = spent || in.PrevOut.Addr == address
+		}
+		if !spent {
+			continue
+		}
+		s := Spend{Txid: tx.Hash}
+		for _, out := range tx.Out {
+			if out.Addr != address {
+				s.Outputs = append(s.Outputs, SpendOutput{Address: out.Addr, Value: out.Value})
+			}
+		}
+		spends = append(spends, s)
+	}
+	return spends, nil
+}
+
+// GetSpends reads the newest page of address txs with their vin/vout.
+func (p *EsploraProvider) GetSpends(ctx context.Context, address string) ([]Spend, error) {
+	var txs []struct {
+		Txid string `json:"txid"`
+		Vin  []struct {
+			Prevout struct {
+				Address string `json:"scriptpubkey_address"`
+			} `json:"prevout"`
+		} `json:"vin"`
+		Vout []struct {
+			Address string `json:"scriptpubkey_address"`
+			Value   int64  `json:"value"`
+		} `json:"vout"`
+	}
+	if err := getJSON(ctx, p.Client, fmt.Sprintf("%s/address/%s/txs", strings.TrimRight(p.BaseURL, "/"), address), &txs); err != nil {
+		return nil, err
+	}
+	var spends []Spend
+	for i := len(txs) - 1; i >= 0; i-- { // Oldest first
+		spent := false
+		for _, in := range txs[i].Vin {
+			spent = spent || in.Prevout.Address == address
+		}
+		if !spent {
+			continue
+		}
+		s := Spend{Txid: txs[i].Txid}
+		for _, out := range txs[i].Vout {
+			if out.Address != address {
+				s.Outputs = append(s.Outputs, SpendOutput{Address: out.Address, Value: out.Value})
+			}
+		}
+		spends = append(spends, s)
+	}
+	return spends, nil
+}
+
+// GetSpends caches spends like histories.
+func (p *cachedProvider) GetSpends(ctx context.Context, address string) ([]Spend, error) {
+	lister, ok := p.DataProvider.(SpendProvider)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	c, _, ttl := currentCache()
+	if c == nil || ttl <= 0 {
+		return lister.GetSpends(ctx, address)
+	}
+	key := p.key("spends", address)
+	if raw, ok := c.Get(key); ok {
+		var spends []Spend
+		if json.Unmarshal(raw, &spends) == nil {
+			return spends, nil
+		}
+	}
+	spends, err := lister.GetSpends(ctx, address)
+	if err == nil {
+		if raw, err := json.Marshal(spends); err == nil {
+			c.Set(key, raw, ttl)
+		}
+	}
+	return spends, err
+}
@@ -0,0 +1,123 @@
+package validator
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// ADDRESS POISONING (lookalike senders seeding the tx history)
+// ---------------------------------------------------------
+
+// Hex characters a lookalike must share at each end of the address
+// (wallets typically show 0x1234...abcd)
+const poisonMatchChars = 4
+
+// addressPoisoning finds senders of zero-value or dust transfers whose
+// address starts and ends like a real counterparty's. isDust may be nil.
+// Returns nil for non-hex addresses or when there are none.
+func addressPoisoning(address string, txs []Transaction, isDust func(Transaction) bool) *PoisoningInfo {
+	address = strings.ToLower(address)
+	if !isHexAddress(address) {
+		return nil
+	}
+
+	// 1. Suspects: senders of unsolicited zero-value or dust transfers
+	suspects := map[string]int{}
+	for _, tx := range txs {
+		if tx.Internal || !strings.EqualFold(tx.To, address) || strings.EqualFold(tx.From, address) {
+			continue
+		}
+		v, ok := new(big.Int).SetString(tx.Value, 10)
+		if (ok && v.Sign() == 0) || (isDust != nil && isDust(tx)) {
+			suspects[strings.ToLower(tx.From)]++
+		}
+	}
+	if len(suspects) == 0 {
+		return nil
+	}
+
+	// 2. Real counterparties: everyone else the address moved value with
+	real := map[string]bool{}
+	for _, tx := range txs {
+		other := strings.ToLower(tx.From)
+		if strings.EqualFold(tx.From, address) {
+			other = strings.ToLower(tx.To)
+		}
+		if v, ok := new(big.Int).SetString(tx.Value, 10); ok && v.Sign() > 0 && suspects[other] == 0 && isHexAddress(other) {
+			real[other] = true
+		}
+	}
+
+	// 3. A suspect that shares both ends with a real counterparty is a lookalike
+	info := &PoisoningInfo{}
+	lookalikes := map[string]bool{}
+	for _, suspect := range sortedKeysInt(suspects) {
+		if !isHexAddress(suspect) {
+			continue
+		}
+		for _, r := range sortedKeys(real) {
+			if lookalike(suspect, r) {
+				info.Lookalikes = append(info.Lookalikes, Lookalike{Address: suspect, Mimics: r, Transfers: suspects[suspect]})
+				info.Transfers += suspects[suspect]
+				lookalikes[suspect] = true
+				break
+			}
+		}
+	}
+	if len(info.Lookalikes) == 0 {
+		return nil
+	}
+	sort.SliceStable(info.Lookalikes, func(i, j int) bool { return info.Lookalikes[i].Transfers > info.Lookalikes[j].Transfers })
+
+	// 4. Did the address fall for it?
+	for _, tx := range txs {
+		if strings.EqualFold(tx.From, address) && lookalikes[strings.ToLower(tx.To)] {
+			if v, ok := new(big.Int).SetString(tx.Value, 10); ok && v.Sign() > 0 {
+				info.SentToLookalike++
+			}
+		}
+	}
+	return info
+}
+
+// lookalike reports whether two different hex addresses share the first
+// and last poisonMatchChars characters.
+func lookalike(a, b string) bool {
+	if a == b {
+		return false
+	}
+	ha, hb := a[2:], b[2:]
+	return ha[:poisonMatchChars] == hb[:poisonMatchChars] && ha[len(ha)-poisonMatchChars:] == hb[len(hb)-poisonMatchChars:]
+}
+
+// isHexAddress reports whether s is a lowercase 0x-prefixed 20-byte address.
+func isHexAddress(s string) bool {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// poisoningDesc is the reason text.
+func poisoningDesc(p *PoisoningInfo) string {
+	addrs := make([]string, 0, len(p.Lookalikes))
+	for _, l := range p.Lookalikes {
+		addrs = append(addrs, l.Address)
+	}
+	if len(addrs) > 3 {
+		addrs = append(addrs[:3], fmt.Sprintf("+%d More", len(p.Lookalikes)-3))
+	}
+	desc := fmt.Sprintf("Address Poisoning Target: %s from %s (%s)", plural(p.Transfers, "Transfer", "Transfers"), plural(len(p.Lookalikes), "Lookalike Address", "Lookalike Addresses"), strings.Join(addrs, ", "))
+	if p.SentToLookalike > 0 {
+		desc += fmt.Sprintf(" - %s Sent to a Lookalike", plural(p.SentToLookalike, "Tx", "Txs"))
+	}
+	return desc
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// POLICY PROFILES (rule sets and weights per use case)
+// ---------------------------------------------------------
+
+// policies adjust the default rules for one kind of counterparty screening.
+// A rules file can still override any field on top.
+var policies = map[string]func(r *RiskRules){
+	// Exchanges onboarding deposits: sanctions, mixers and laundering
+	// patterns dominate; holdings and history say little about the funds
+	"exchange": func(r *RiskRules) {
+		r.Weights = RiskWeights{Fraud: 0.6, Reputation: 0.35, Lending: 0.05}
+		r.MixerInteraction.Offset = 70
+		r.ThreatCounterparty.Offset = 50
+		r.IndirectExposure.Offset = 75
+		r.PeelChain.Offset = 40
+		r.ExploitPreparation.Offset = 60
+		r.UnregulatedExchange.Offset = 20
+		r.JurisdictionRisk.Offset = 40
+		r.EstablishedHistory.Disabled = true
+		r.SubstantialHoldings.Disabled = true
+		r.Concentration.Disabled = true
+		r.Decisions.ReviewScore = 25
+		r.Decisions.ReviewAlerts = "MEDIUM"
+	},
+	// Lenders underwriting a borrower: age, holdings and steady activity
+	// carry the score; inbound spam does not
+	"lender": func(r *RiskRules) {
+		r.Weights = RiskWeights{Fraud: 0.3, Reputation: 0.2, Lending: 0.5}
+		r.FreshWallet.Offset = 45
+		r.EstablishedHistory.Offset = -20
+		r.SubstantialHoldings.Offset = -20
+		r.Concentration.Offset = 20
+		r.Reactivation.Offset = 25
+		r.Dusting.Disabled = true
+		r.AddressPoisoning.Disabled = true
+		r.Decisions.RejectScore = 50
+	},
+	// NFT marketplaces: wash trading, sybil farms and wallet drainers
+	"nft_marketplace": func(r *RiskRules) {
+		r.Weights = RiskWeights{Fraud: 0.55, Reputation: 0.4, Lending: 0.05}
+		r.WashTrading.Offset = 40
+		r.SybilFarming.Offset = 40
+		r.ThreatApproval.Offset = 50
+		r.RiskyApproval.Offset = 20
+		r.ScamTokenHoldings.Offset = 20
+		r.EstablishedHistory.Disabled = true
+		r.SubstantialHoldings.Disabled = true
+		r.Reactivation.Disabled = true
+		r.Concentration.Disabled = true
+	},
+}
+
+// PolicyNames lists the named policies.
+func PolicyNames() []string {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PolicyRules returns the rules of a named policy; "" or "default" is
+// DefaultRiskRules.
+func PolicyRules(name string) (RiskRules, error) {
+	rules := DefaultRiskRules()
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "default" {
+		return rules, nil
+	}
+	apply, ok := policies[name]
+	if !ok {
+		return rules, fmt.Errorf("unknown policy %q (%s)", name, strings.Join(PolicyNames(), ", "))
+	}
+	apply(&rules)
+	rules.Policy = name
+	return rules, nil
+}
@@ -0,0 +1,336 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// PRICE ORACLE (USD valuation of native balances)
+// ---------------------------------------------------------
+
+// PriceSource returns USD spot prices for native asset symbols (ETH, BTC...).
+// Symbols it does not know are left out of the result.
+type PriceSource interface {
+	Name() string
+	USDPrices(ctx context.Context, symbols []string) (map[string]float64, error)
+}
+
+// Prices move slower than balances but faster than histories
+const DefaultPriceTTL = 5 * time.Minute
+
+// coinGeckoIDs maps the symbols used in balance strings to CoinGecko ids.
+// Custom EVM chains ("NATIVE") and testnet tokens are deliberately absent.
+var coinGeckoIDs = map[string]string{
+	"BTC":   "bitcoin",
+	"ETH":   "ethereum",
+	"SOL":   "solana",
+	"POL":   "polygon-ecosystem-token",
+	"BNB":   "binancecoin",
+	"AVAX":  "avalanche-2",
+	"NEAR":  "near",
+	"ZEC":   "zcash",
+	"BSV":   "bitcoin-cash-sv",
+	"ATOM":  "cosmos",
+	"OSMO":  "osmosis",
+	"TIA":   "celestia",
+	"JUNO":  "juno-network",
+	"AKT":   "akash-network",
+	"STARS": "stargaze",
+	"INJ":   "injective-protocol",
+	"DYDX":  "dydx-chain",
+}
+
+var (
+	pricingMu    sync.Mutex
+	priceSources = []PriceSource{&CoinGeckoPrices{}}
+	priceTTL     = DefaultPriceTTL
+)
+
+// SetPriceSources replaces the price oracle (asked in order) and how long
+// prices are cached. No sources disables USD valuation.
+func SetPriceSources(ttl time.Duration, sources ...PriceSource) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	priceSources, priceTTL = sources, ttl
+}
+
+// PriceSourceNames lists the configured price sources, in the order asked.
+func PriceSourceNames() []string {
+	sources, _ := currentPriceSources()
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+func currentPriceSources() ([]PriceSource, time.Duration) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	return priceSources, priceTTL
+}
+
+// PriceProfile fills BalanceUSD (and each chain's) from the price oracle.
+// Testnet balances and unknown symbols stay unpriced (nil). It is a no-op
+// for a profile that is already priced.
+func PriceProfile(ctx context.Context, profile *WalletProfile) {
+	if profile == nil || profile.Testnet || profile.BalanceUSD != nil {
+		return
+	}
+	if sources, _ := currentPriceSources(); len(sources) == 0 {
+		return
+	}
+
+	balances := []string{profile.Balance}
+	for _, c := range profile.Chains {
+		balances = append(balances, c.Balance)
+	}
+	var symbols []string
+	seen := map[string]bool{}
+	for _, b := range balances {
+		for _, a := range parseBalanceAmounts(b) {
+			if !seen[a.Symbol] && coinGeckoIDs[a.Symbol] != "" {
+				seen[a.Symbol] = true
+				symbols = append(symbols, a.Symbol)
+			}
+		}
+	}
+	if len(symbols) == 0 {
+		return
+	}
+
+	prices, err := usdPrices(ctx, symbols)
+	if err != nil {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, "USD Pricing Unavailable")
+		return
+	}
+
+	if len(profile.Chains) == 0 {
+		profile.BalanceUSD = valueBalance(profile.Balance, prices)
+		if price, ok := prices[profile.Symbol]; ok && profile.BalanceUSD != nil {
+			profile.PriceUSD = &price
+		}
+		return
+	}
+	// Multi-chain: the total is the sum of the priced chains
+	total, priced := 0.0, false
+	for i := range profile.Chains {
+		c := &profile.Chains[i]
+		if c.BalanceUSD = valueBalance(c.Balance, prices); c.BalanceUSD != nil {
+			total += *c.BalanceUSD
+			priced = true
+		}
+	}
+	if priced {
+		profile.BalanceUSD = roundUSD(total)
+	}
+}
+
+// balanceAmount is one "<amount> <SYMBOL>" part of a balance string.
+type balanceAmount struct {
+	Amount float64
+	Symbol string
+}
+
+// parseBalanceAmounts splits balance strings such as "1.5000 ETH" or the
+// multi-chain "1.5000 ETH [Ethereum], 20.0000 POL [Polygon]".
+func parseBalanceAmounts(balance string) []balanceAmount {
+	var out []balanceAmount
+	for _, part := range strings.Split(balance, ", ") {
+		fields := strings.Fields(part)
+		if len(fields) < 2 {
+			continue
+		}
+		amount, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, balanceAmount{Amount: amount, Symbol: strings.ToUpper(fields[1])})
+	}
+	return out
+}
+
+// valueBalance prices a balance string, or returns nil if any part of it
+// has no price (a partial total would understate the wallet).
+func valueBalance(balance string, prices map[string]float64) *float64 {
+	amounts := parseBalanceAmounts(balance)
+	if len(amounts) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, a := range amounts {
+		price, ok := prices[a.Symbol]
+		if !ok {
+			return nil
+		}
+		total += a.Amount * price
+	}
+	return roundUSD(total)
+}
+
+func roundUSD(v float64) *float64 {
+	v = math.Round(v*100) / 100
+	return &v
+}
+
+// usdPrices answers from the response cache first, then asks each source
+// in order for the symbols still missing.
+func usdPrices(ctx context.Context, symbols []string) (map[string]float64, error) {
+	sources, ttl := currentPriceSources()
+	cache, _, _ := currentCache()
+	prices := map[string]float64{}
+
+	missing := symbols
+	if cache != nil && ttl > 0 {
+		missing = nil
+		for _, sym := range symbols {
+			if b, ok := cache.Get(priceCacheKey(sym)); ok {
+				if p, err := strconv.ParseFloat(string(b), 64); err == nil {
+					prices[sym] = p
+					continue
+				}
+			}
+			missing = append(missing, sym)
+		}
+	}
+
+	var failures []string
+	for _, source := range sources {
+		if len(missing) == 0 {
+			break
+		}
+		breaker := providerBreaker(source.Name())
+		if err := breaker.Allow(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+		got, err := source.USDPrices(ctx, missing)
+		breaker.Record(err)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+			continue
+		}
+
+		var still []string
+		for _, sym := range missing {
+			p, ok := got[sym]
+			if !ok {
+				still = append(still, sym)
+				continue
+			}
+			prices[sym] = p
+			if cache != nil && ttl > 0 {
+				cache.Set(priceCacheKey(sym), []byte(strconv.FormatFloat(p, 'f', -1, 64)), ttl)
+			}
+		}
+		missing = still
+	}
+
+	if len(prices) == 0 && len(failures) > 0 {
+		return nil, errors.New(strings.Join(failures, "; "))
+	}
+	return prices, nil
+}
+
+func priceCacheKey(symbol string) string {
+	return "profiler:price:usd:" + symbol
+}
+
+// ---------------------------------------------------------
+// SOURCES
+// ---------------------------------------------------------
+
+// CoinGeckoPrices uses CoinGecko's simple/price endpoint. The public API
+// works without a key; APIKey is sent as a demo key.
+type CoinGeckoPrices struct {
+	BaseURL string // Empty = https://api.coingecko.com/api/v3
+	APIKey  string
+}
+
+func (c *CoinGeckoPrices) Name() string { return "CoinGecko" }
+
+func (c *CoinGeckoPrices) USDPrices(ctx context.Context, symbols []string) (map[string]float64, error) {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.coingecko.com/api/v3"
+	}
+	var ids []string
+	for _, sym := range symbols {
+		if id := coinGeckoIDs[sym]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string]float64{}, nil
+	}
+
+	var resp map[string]struct {
+		USD float64 `json:"usd"`
+	}
+	client := httpClient(10 * time.Second)
+	endpoint := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd", strings.TrimRight(baseURL, "/"), url.QueryEscape(strings.Join(ids, ",")))
+	err := doJSON(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err == nil && c.APIKey != "" {
+			req.Header.Set("x-cg-demo-api-key", c.APIKey)
+		}
+		return req, err
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := map[string]float64{}
+	for _, sym := range symbols {
+		if p, ok := resp[coinGeckoIDs[sym]]; ok && p.USD > 0 {
+			prices[sym] = p.USD
+		}
+	}
+	return prices, nil
+}
+
+// CoinStatsPrices uses the CoinStats coin list, one lookup per symbol.
+// Requires a CoinStats API key.
+type CoinStatsPrices struct {
+	APIKey string
+}
+
+func (c *CoinStatsPrices) Name() string { return "CoinStats" }
+
+func (c *CoinStatsPrices) USDPrices(ctx context.Context, symbols []string) (map[string]float64, error) {
+	client := httpClient(10 * time.Second)
+	prices := map[string]float64{}
+	for _, sym := range symbols {
+		var resp struct {
+			Result []struct {
+				Symbol string  `json:"symbol"`
+				Price  float64 `json:"price"`
+			} `json:"result"`
+		}
+		endpoint := fmt.Sprintf("https://openapiv1.coinstats.app/coins?symbol=%s&currency=USD&limit=1", url.QueryEscape(sym))
+		err := doJSON(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+			if err == nil {
+				req.Header.Set("X-API-KEY", c.APIKey)
+			}
+			return req, err
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		// The list is ranked by market cap, so the first match is the real coin
+		if len(resp.Result) > 0 && strings.EqualFold(resp.Result[0].Symbol, sym) && resp.Result[0].Price > 0 {
+			prices[sym] = resp.Result[0].Price
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ---------------------------------------------------------
+// MULTI-CHAIN PROBING (ambiguous address syntax)
+// ---------------------------------------------------------
+
+// MatchingStrategies returns the strategies whose syntax check accepts
+// the address, in priority order.
+func MatchingStrategies(strategies []ChainStrategy, address string) []ChainStrategy {
+	var matches []ChainStrategy
+	for _, s := range strategies {
+		if s.IsValidSyntax(address) {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+// Probe runs FetchState on every strategy concurrently. It returns the
+// profile of the first strategy (in order) where the address exists on
+// chain, else the first that answered at all. Every probe is listed in the
+// returned profile's Probes, so the other chains are not lost.
+func Probe(ctx context.Context, strategies []ChainStrategy, address string, configFor func(ChainStrategy) string) (*WalletProfile, error) {
+	profiles := make([]*WalletProfile, len(strategies))
+	errs := make([]error, len(strategies))
+
+	var wg sync.WaitGroup
+	for i, s := range strategies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			profiles[i], errs[i] = s.FetchState(ctx, address, configFor(s))
+		}()
+	}
+	wg.Wait()
+
+	var probes []ProbeMatch
+	chosen, fallback := -1, -1
+	for i, s := range strategies {
+		match := ProbeMatch{Strategy: s.Name()}
+		if errs[i] != nil {
+			match.Error = errs[i].Error()
+		}
+		if p := profiles[i]; p != nil {
+			match.Network = p.Network
+			match.Balance = p.Balance
+			match.TxCount = p.TxCount
+			match.Exists = p.IsActive || p.TxCount > 0
+			if match.Exists && chosen < 0 {
+				chosen = i
+			}
+			if fallback < 0 {
+				fallback = i
+			}
+		}
+		probes = append(probes, match)
+	}
+
+	if chosen < 0 {
+		chosen = fallback
+	}
+	if chosen < 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	profile := profiles[chosen]
+	profile.Probes = probes
+	existsOn := 0
+	for _, m := range probes {
+		if m.Exists {
+			existsOn++
+		}
+	}
+	switch {
+	case existsOn > 1:
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("Ambiguous: Exists on %d Chains (see probes)", existsOn))
+	case existsOn == 0:
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("No Activity on %d Matching Chains", len(strategies)))
+	}
+	return profile, nil
+}
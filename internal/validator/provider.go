@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// DATA PROVIDERS (per-chain upstream APIs with failover)
+// ---------------------------------------------------------
+
+// ErrNotSupported is returned by providers that cannot answer a query
+// (e.g. a raw node has no first-seen index). The chain skips them silently.
+var ErrNotSupported = errors.New("not supported by provider")
+
+// TxHistory is what a provider knows about an address's transactions.
+type TxHistory struct {
+	Txs       []Transaction // Oldest first; may be only a recent window
+	Total     int           // Tx count reported by the provider
+	Complete  bool          // Txs covers the full history (first seen is exact)
+	Truncated bool          // Total is a lower bound (provider hit a page cap)
+	Warnings  []string      // Partial failures worth surfacing in the profile
+}
+
+// DataProvider is one upstream source of balances and history for a chain
+// (Etherscan, a JSON-RPC node, Blockchain.com, Esplora, CoinStats...).
+type DataProvider interface {
+	Name() string
+	GetBalance(ctx context.Context, address string) (*big.Int, error) // Base units (wei, sats, lamports)
+	GetTransactions(ctx context.Context, address string) (*TxHistory, error)
+	GetFirstSeen(ctx context.Context, address string) (*time.Time, error)
+}
+
+// ProviderChain queries providers in order and fails over to the next one
+// on any error (HTTP 429, timeouts, API errors). Providers whose circuit
+// breaker is open are skipped without a call.
+type ProviderChain []DataProvider
+
+// failover runs call against each provider until one succeeds. It returns
+// the result, the provider that served it and the errors of the ones before.
+// If every provider was short-circuited the error wraps ErrCircuitOpen.
+func failover[T any](ctx context.Context, pc ProviderChain, call func(DataProvider) (T, error)) (T, string, []string, error) {
+	var zero T
+	var failures []string
+	shortCircuited := 0
+	for _, p := range pc {
+		breaker := providerBreaker(p.Name())
+		if err := breaker.Allow(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+			shortCircuited++
+			continue
+		}
+		v, err := call(p)
+		tripped := breaker.Record(err)
+		if err == nil {
+			return v, p.Name(), failures, nil
+		}
+		if !errors.Is(err, ErrNotSupported) {
+			failure := fmt.Sprintf("%s: %v", p.Name(), err)
+			if tripped {
+				failure += " (circuit opened)"
+				slog.Warn("🔌 [PROVIDER] Circuit opened", "provider", p.Name(), "err", err)
+			}
+			slog.Debug("⚠️ [PROVIDER] Failed, failing over", "provider", p.Name(), "err", err)
+			failures = append(failures, failure)
+		}
+		if ctx.Err() != nil {
+			break // Out of time; the next provider would fail too
+		}
+	}
+	switch {
+	case len(failures) == 0:
+		return zero, "", nil, ErrNotSupported
+	case shortCircuited == len(pc):
+		// Degraded mode: the caller falls back to syntax + sanctions only
+		return zero, "", failures, fmt.Errorf("degraded mode, %w: %s", ErrCircuitOpen, strings.Join(failures, "; "))
+	}
+	return zero, "", failures, errors.New(strings.Join(failures, "; "))
+}
+
+// GetBalance returns the balance from the first provider that answers.
+func (pc ProviderChain) GetBalance(ctx context.Context, address string) (*big.Int, string, []string, error) {
+	return failover(ctx, pc, func(p DataProvider) (*big.Int, error) { return p.GetBalance(ctx, address) })
+}
+
+// GetTransactions returns the history from the first provider that answers.
+func (pc ProviderChain) GetTransactions(ctx context.Context, address string) (*TxHistory, string, []string, error) {
+	return failover(ctx, pc, func(p DataProvider) (*TxHistory, error) { return p.GetTransactions(ctx, address) })
+}
+
+// GetFirstSeen returns the first-seen time from the first provider that has one.
+func (pc ProviderChain) GetFirstSeen(ctx context.Context, address string) (*time.Time, string, []string, error) {
+	return failover(ctx, pc, func(p DataProvider) (*time.Time, error) { return p.GetFirstSeen(ctx, address) })
+}
+
+// loadBalance fills Balance and IsActive from the chain. Failovers are
+// noted in the profile's details.
+func loadBalance(ctx context.Context, pc ProviderChain, address string, decimals, precision int, symbol string, profile *WalletProfile) error {
+	bal, servedBy, failures, err := pc.GetBalance(ctx, address)
+	if err != nil {
+		return err
+	}
+	noteFallback(profile, servedBy, failures)
+
+	f := new(big.Float).Quo(new(big.Float).SetInt(bal), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	profile.Balance = fmt.Sprintf("%.*f %s", precision, f, symbol)
+	setRawBalance(profile, bal, decimals, symbol)
+	if bal.Sign() > 0 {
+		profile.IsActive = true
+	}
+	return nil
+}
+
+// loadHistory fills TxCount, FirstSeen, LastSeen and the activity details
+// from the chain and returns the transactions for the investigator.
+func loadHistory(ctx context.Context, pc ProviderChain, address string, profile *WalletProfile) ([]Transaction, error) {
+	hist, servedBy, failures, err := pc.GetTransactions(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	noteFallback(profile, servedBy, failures)
+
+	profile.TxCount = hist.Total
+	for _, tx := range hist.Txs {
+		if tx.Internal {
+			profile.InternalTxCount++
+		}
+	}
+
+	// A capped provider only gives a lower bound; another may know the total
+	truncated := hist.Truncated
+	if truncated {
+		for _, p := range pc {
+			if p.Name() == servedBy {
+				continue
+			}
+			if other, err := p.GetTransactions(ctx, address); err == nil && other.Total > profile.TxCount {
+				profile.TxCount = other.Total
+				hist.Warnings = append(hist.Warnings, fmt.Sprintf("Tx Count via %s", p.Name()))
+				truncated = false
+				break
+			}
+		}
+	}
+
+	if len(hist.Txs) > 0 {
+		first := time.Unix(hist.Txs[0].TimeStamp, 0)
+		last := time.Unix(hist.Txs[len(hist.Txs)-1].TimeStamp, 0)
+		profile.FirstSeen, profile.LastSeen = &first, &last
+	}
+	if !hist.Complete && profile.TxCount > 0 {
+		// Only a recent window was loaded; ask for the exact first-seen time
+		if t, _, _, err := pc.GetFirstSeen(ctx, address); err == nil && t != nil {
+			profile.FirstSeen = earliestTime(profile.FirstSeen, t)
+		}
+	}
+
+	var activity string
+	switch {
+	case profile.TxCount == 0 && len(hist.Txs) == 0:
+		if !profile.IsActive {
+			activity = "Inactive Account (No Tx History)"
+		}
+	case profile.FirstSeen != nil:
+		profile.IsActive = true
+		activity = fmt.Sprintf("Active | First Seen: %s", profile.FirstSeen.Format("2006-01-02"))
+		if profile.LastSeen != nil {
+			activity += fmt.Sprintf(" | Last Seen: %s", profile.LastSeen.Format("2006-01-02"))
+		}
+	default:
+		profile.IsActive = true
+		activity = fmt.Sprintf("Active | %d Txs (No History Available)", profile.TxCount)
+	}
+	if truncated {
+		activity = appendDetail(activity, fmt.Sprintf("History Truncated (%d+ Tx)", profile.TxCount))
+	}
+	for _, w := range hist.Warnings {
+		activity = appendDetail(activity, w)
+	}
+	profile.ValidationDetails = appendDetail(activity, profile.ValidationDetails)
+
+	return hist.Txs, nil
+}
+
+// setRawBalance records the balance in base units next to the display string.
+func setRawBalance(profile *WalletProfile, raw *big.Int, decimals int, symbol string) {
+	profile.BalanceRaw, profile.Decimals, profile.Symbol = raw, decimals, symbol
+}
+
+// noteFallback records which provider answered in DataSources and, when
+// earlier ones failed, in the details.
+func noteFallback(profile *WalletProfile, servedBy string, failures []string) {
+	if servedBy != "" && !slices.Contains(profile.DataSources, servedBy) {
+		profile.DataSources = append(profile.DataSources, servedBy)
+	}
+	if len(failures) == 0 {
+		return
+	}
+	note := fmt.Sprintf("Fallback: %s (%s)", servedBy, strings.Join(failures, "; "))
+	if !strings.Contains(profile.ValidationDetails, note) {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, note)
+	}
+}
+
+// Validation details fragments that report a failed lookup
+var providerErrorMarkers = []string{"Failed", "Error", "Unavailable", "Offline", "Circuit Open"}
+
+// ProviderErrors returns the validation details fragments reporting a
+// failed lookup (a chain provider, a resolver or the watchlist), i.e. why
+// the profile may be incomplete. Missing USD prices don't count; they
+// never change the score.
+func ProviderErrors(p *WalletProfile) []string {
+	var out []string
+	for _, detail := range strings.Split(p.ValidationDetails, " | ") {
+		if strings.HasPrefix(detail, "USD Pricing") {
+			continue
+		}
+		for _, marker := range providerErrorMarkers {
+			if strings.Contains(detail, marker) {
+				out = append(out, detail)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// appendDetail joins ValidationDetails fragments with " | ".
+func appendDetail(details, detail string) string {
+	switch {
+	case detail == "":
+		return details
+	case details == "":
+		return detail
+	}
+	return details + " | " + detail
+}
@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// CLIENT-SIDE RATE LIMITS (token bucket per upstream host)
+// ---------------------------------------------------------
+
+// TokenBucket allows Rate requests per second with bursts of up to Burst.
+// It is safe for concurrent use; waiters queue up by reserving tokens.
+type TokenBucket struct {
+	Rate  float64
+	Burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full bucket.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{Rate: rate, Burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.Rate
+	if b.tokens > float64(b.Burst) {
+		b.tokens = float64(b.Burst)
+	}
+	b.last = now
+	b.tokens-- // Reserve, even if that means waiting for it
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.Rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++ // Give the reservation back
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Delay is how long a Wait now would block, without reserving a token.
+func (b *TokenBucket) Delay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tokens := min(b.tokens+time.Since(b.last).Seconds()*b.Rate, float64(b.Burst))
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / b.Rate * float64(time.Second))
+}
+
+// Default limits for the free tiers of the rate-limited APIs
+var DefaultRateLimits = map[string]float64{
+	"api.etherscan.io":        5, // Free tier: 5 calls/sec per key
+	"blockchain.info":         1, // Bans bursts with 429s
+	"openapiv1.coinstats.app": 1,
+	"api.coingecko.com":       0.5, // Public API: ~30 calls/min
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = newLimiters(DefaultRateLimits)
+)
+
+func newLimiters(limits map[string]float64) map[string]*TokenBucket {
+	m := map[string]*TokenBucket{}
+	for host, rps := range limits {
+		if rps > 0 {
+			m[host] = NewTokenBucket(rps, int(max(rps, 1)))
+		}
+	}
+	return m
+}
+
+// SetRateLimit sets (rps > 0) or removes (rps <= 0) the limit for a host.
+func SetRateLimit(host string, rps float64) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if rps <= 0 {
+		delete(limiters, host)
+		return
+	}
+	limiters[host] = NewTokenBucket(rps, int(max(rps, 1)))
+}
+
+// HostDelay is how long a request to host would wait for its rate limit
+// now; 0 for hosts without a limit. Requests already queued count, so it
+// grows while workers wait on the same host.
+func HostDelay(host string) time.Duration {
+	limitersMu.Lock()
+	b := limiters[host]
+	limitersMu.Unlock()
+	if b == nil {
+		return 0
+	}
+	return b.Delay()
+}
+
+type hostObserverKey struct{}
+
+// WithHostObserver returns a ctx under which every request to a
+// rate-limited host calls observe with the host first, so a scheduler
+// learns which limits a kind of lookup spends.
+func WithHostObserver(ctx context.Context, observe func(host string)) context.Context {
+	return context.WithValue(ctx, hostObserverKey{}, observe)
+}
+
+// waitForHost blocks until the host's bucket allows another request.
+// Hosts without a limit pass straight through.
+func waitForHost(ctx context.Context, host string) error {
+	limitersMu.Lock()
+	b := limiters[host]
+	limitersMu.Unlock()
+	if b == nil {
+		return nil
+	}
+	if observe, ok := ctx.Value(hostObserverKey{}).(func(string)); ok {
+		observe(host)
+	}
+	return b.Wait(ctx)
+}
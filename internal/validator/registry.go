@@ -0,0 +1,242 @@
+package validator
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// STRATEGY REGISTRY (built-in and third-party chains)
+// ---------------------------------------------------------
+
+// DefaultPriority places a strategy after the built-in chains, which
+// register at 10-90. Lower priorities are tried first.
+const DefaultPriority = 100
+
+type registration struct {
+	strategy ChainStrategy
+	priority int
+	config   string
+	seq      int // Registration order breaks priority ties
+}
+
+// RegisterOption customizes a registration.
+type RegisterOption func(*registration)
+
+// WithPriority sets where the strategy sits in the matching order.
+func WithPriority(priority int) RegisterOption {
+	return func(r *registration) { r.priority = priority }
+}
+
+// WithConfig sets the value passed to FetchState as its config parameter
+// (usually an API key).
+func WithConfig(config string) RegisterOption {
+	return func(r *registration) { r.config = config }
+}
+
+var (
+	registryMu    sync.Mutex
+	registrations []registration
+	registrySeq   int
+)
+
+// Register adds a chain strategy. Registering a Name that is already
+// registered replaces the earlier strategy, so built-ins can be overridden.
+func Register(strategy ChainStrategy, opts ...RegisterOption) {
+	r := registration{strategy: strategy, priority: DefaultPriority}
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registrySeq++
+	r.seq = registrySeq
+	for i, existing := range registrations {
+		if existing.strategy.Name() == strategy.Name() {
+			registrations[i] = r
+			return
+		}
+	}
+	registrations = append(registrations, r)
+}
+
+// Strategies returns the registered strategies in matching order.
+func Strategies() []ChainStrategy {
+	registryMu.Lock()
+	sorted := append([]registration(nil), registrations...)
+	registryMu.Unlock()
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].priority != sorted[j].priority {
+			return sorted[i].priority < sorted[j].priority
+		}
+		return sorted[i].seq < sorted[j].seq
+	})
+	out := make([]ChainStrategy, len(sorted))
+	for i, r := range sorted {
+		out[i] = r.strategy
+	}
+	return out
+}
+
+// StrategyConfig returns the config registered for the strategy's Name.
+func StrategyConfig(strategy ChainStrategy) string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, r := range registrations {
+		if r.strategy.Name() == strategy.Name() {
+			return r.config
+		}
+	}
+	return ""
+}
+
+// Analyze profiles an address with the registered strategies: the first
+// whose syntax matches or, with probe, every match concurrently. The result
+// is priced in USD, always goes through the investigator and is recorded in
+// the score history, if one is set. It returns
+// nil, nil when no strategy accepts the address.
+func Analyze(ctx context.Context, address string, probe bool) (*WalletProfile, error) {
+	matches := MatchingStrategies(Strategies(), address)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	if !probe || len(matches) == 1 || Offline() {
+		return AnalyzeWith(ctx, matches[0], address)
+	}
+	profile, err := Probe(ctx, matches, address, StrategyConfig)
+	return finishProfile(ctx, profile), err
+}
+
+// AnalyzeWith profiles the address with one strategy, skipping the
+// matching against the other chains (e.g. a base58 address that is
+// Solana, not Bitcoin). Otherwise it is Analyze. It returns nil, nil when
+// the strategy rejects the address syntax.
+func AnalyzeWith(ctx context.Context, strategy ChainStrategy, address string) (*WalletProfile, error) {
+	if !strategy.IsValidSyntax(address) {
+		return nil, nil
+	}
+	if Offline() {
+		return offlineProfile(ctx, strategy, address), nil
+	}
+	// EVM Strategy calls Investigate() internally.
+	// Others might not, so we handle that below.
+	profile, err := strategy.FetchState(ctx, address, StrategyConfig(strategy))
+	return finishProfile(ctx, profile), err
+}
+
+// finishProfile prices, investigates (if the strategy didn't) and records
+// a fetched profile.
+func finishProfile(ctx context.Context, profile *WalletProfile) *WalletProfile {
+	// USD value (no-op if the strategy already priced it)
+	PriceProfile(ctx, profile)
+
+	// Safety net: the sanctions check runs even if the strategy didn't call it
+	if profile != nil && profile.RiskScore == 0 && len(profile.RiskReasons) == 0 {
+		Investigate(profile, nil)
+	}
+
+	// Decided again now that provider errors are known (strategies that
+	// investigate do so before their last lookups)
+	if profile != nil {
+		profile.Decision = Decide(currentRiskRules(), profile)
+	}
+
+	// Previous score and delta (no-op unless SetHistory was called)
+	recordHistory(profile)
+	return profile
+}
+
+// ---------------------------------------------------------
+// OFFLINE MODE (syntax, checksums and sanctions only)
+// ---------------------------------------------------------
+
+var (
+	offlineMu sync.Mutex
+	offline   bool
+)
+
+// SetOffline turns offline mode on or off. Offline, Analyze calls no
+// chain-data provider: the address is checked for syntax (checksums
+// included, which IsValidSyntax does) and against the watchlist only, and
+// the profile is Partial. Probing takes the first matching chain.
+func SetOffline(on bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	offline = on
+}
+
+// Offline reports whether offline mode is on.
+func Offline() bool {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	return offline
+}
+
+// offlineProfile screens an address that passed strategy's syntax check
+// against the watchlist, with no chain data. It isn't priced or recorded
+// in the score history.
+func offlineProfile(ctx context.Context, strategy ChainStrategy, address string) *WalletProfile {
+	network := strategy.Name()
+	if i := strings.Index(network, " ("); i > 0 {
+		network = network[:i] // "EVM (Etherscan)"
+	}
+	profile := &WalletProfile{
+		Address:           strings.TrimSpace(address),
+		Network:           strings.ToUpper(network),
+		IsValid:           true,
+		Partial:           true,
+		ValidationDetails: "Offline: Syntax and Sanctions Checked Only",
+	}
+	inv := &Investigator{
+		watchlist: EngineWatchlist{},
+		rules:     currentRiskRules(),
+		now:       time.Now,
+	}
+	inv.Investigate(ctx, profile, nil)
+	return profile
+}
+
+// LookupStrategy returns the registered strategy with the given Name,
+// ignoring case, "-" for "_" and a parenthesized suffix ("evm" finds
+// "EVM (Etherscan)"), or nil.
+func LookupStrategy(name string) ChainStrategy {
+	normalize := func(s string) string {
+		if i := strings.Index(s, " ("); i > 0 {
+			s = s[:i]
+		}
+		return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "-", "_"))
+	}
+	name = normalize(name)
+	for _, s := range Strategies() {
+		if normalize(s.Name()) == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// CanonicalAddress returns the form two spellings of the same address
+// share. Hex EVM addresses and bech32 addresses (with an Avalanche "X-" or
+// "P-" chain prefix) are case-insensitive and come back lower-cased;
+// anything else (base58, NEAR accounts, names) is case-sensitive and
+// comes back as given.
+func CanonicalAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if evmHexRegex.MatchString(address) {
+		return strings.ToLower(address)
+	}
+	body := address
+	if len(address) > 2 && address[1] == '-' {
+		body = address[2:]
+	}
+	if _, _, _, err := bech32Decode(body, 1023); err == nil {
+		return strings.ToLower(address)
+	}
+	return address
+}
@@ -0,0 +1,24 @@
+package validator
+
+import "testing"
+
+func TestCanonicalAddress(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"evm checksummed", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", "0x742d35cc6634c0532925a3b844bc454e4438f44e"},
+		{"bech32 upper", "BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"bech32 lower", " bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq ", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"bech32 mixed case is not bech32", "bc1Qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "bc1Qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"base58 solana", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"},
+		{"base58 bitcoin", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{"near account", "Alice.near", "Alice.near"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalAddress(tt.in); got != tt.want {
+				t.Errorf("CanonicalAddress(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
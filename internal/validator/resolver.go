@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------
+// NAME RESOLUTION (Unstoppable Domains, Solana Name Service)
+// ---------------------------------------------------------
+
+// Unstoppable Domains TLDs (resolved via the UD Resolution API)
+var udTLDs = []string{
+	".crypto", ".x", ".nft", ".wallet", ".blockchain", ".bitcoin",
+	".dao", ".888", ".zil", ".polygon", ".unstoppable",
+}
+
+// Bonfida's public SNS proxy
+const defaultSNSResolver = "https://sns-sdk-proxy.bonfida.workers.dev"
+
+// Name service settings, from SetNameResolvers
+var (
+	resolverMu  sync.Mutex
+	snsResolver = defaultSNSResolver
+	udAPIKey    string
+)
+
+// SetNameResolvers sets the SNS proxy (empty = Bonfida's public one) and
+// the Unstoppable Domains API key (empty = UD names don't resolve).
+func SetNameResolvers(snsURL, unstoppableKey string) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	if snsURL == "" {
+		snsURL = defaultSNSResolver
+	}
+	snsResolver, udAPIKey = snsURL, unstoppableKey
+}
+
+// Preferred record order when a UD domain maps several chains
+var udRecordPreference = []string{
+	"crypto.ETH.address",
+	"crypto.BTC.address",
+	"crypto.SOL.address",
+}
+
+// IsResolvableName reports whether input looks like a human-readable
+// name (rather than an address) that ResolveName can handle.
+func IsResolvableName(input string) bool {
+	name := strings.ToLower(strings.TrimSpace(input))
+	if strings.HasSuffix(name, ".sol") {
+		return len(name) > len(".sol")
+	}
+	for _, tld := range udTLDs {
+		if strings.HasSuffix(name, tld) && len(name) > len(tld) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveName turns a .sol or Unstoppable Domains name into an on-chain
+// address. It returns the address and the naming service used.
+func ResolveName(ctx context.Context, input string) (string, string, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	client := httpClient(10 * time.Second)
+
+	if strings.HasSuffix(name, ".sol") {
+		addr, err := resolveSNS(ctx, client, name)
+		return addr, "SNS", err
+	}
+	addr, err := resolveUD(ctx, client, name)
+	return addr, "UNSTOPPABLE_DOMAINS", err
+}
+
+// resolveSNS uses Bonfida's public SNS proxy: owner of the .sol domain.
+func resolveSNS(ctx context.Context, client *http.Client, name string) (string, error) {
+	resolverMu.Lock()
+	base := snsResolver
+	resolverMu.Unlock()
+
+	var resp struct {
+		Status string `json:"s"`
+		Result string `json:"result"`
+	}
+	label := strings.TrimSuffix(name, ".sol")
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/resolve/%s", strings.TrimRight(base, "/"), url.PathEscape(label)), &resp); err != nil {
+		return "", err
+	}
+	if resp.Status != "ok" || resp.Result == "" {
+		return "", fmt.Errorf("%s is not registered", name)
+	}
+	return resp.Result, nil
+}
+
+// resolveUD queries the Unstoppable Domains Resolution API (requires a key).
+func resolveUD(ctx context.Context, client *http.Client, name string) (string, error) {
+	resolverMu.Lock()
+	apiKey := udAPIKey
+	resolverMu.Unlock()
+	if apiKey == "" {
+		return "", fmt.Errorf("UNSTOPPABLE_API_KEY not set")
+	}
+
+	var body struct {
+		Meta struct {
+			Owner string `json:"owner"`
+		} `json:"meta"`
+		Records map[string]string `json:"records"`
+	}
+	err := doJSON(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", "https://api.unstoppabledomains.com/resolve/domains/"+url.PathEscape(name), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		return req, nil
+	}, &body)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range udRecordPreference {
+		if addr := body.Records[key]; addr != "" {
+			return addr, nil
+		}
+	}
+	// Fall back to the on-chain owner of the domain NFT
+	if owner := body.Meta.Owner; owner != "" && owner != "0x0000000000000000000000000000000000000000" {
+		return owner, nil
+	}
+	return "", fmt.Errorf("%s has no address records", name)
+}
@@ -0,0 +1,202 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+)
+
+// ---------------------------------------------------------
+// RETRIES (backoff + jitter + Retry-After)
+// ---------------------------------------------------------
+
+// HTTPError is a non-2xx upstream response.
+type HTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header (0 if absent)
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+// RetryPolicy controls how transient upstream failures (429, 5xx, network
+// errors) are retried. Other errors are returned immediately.
+type RetryPolicy struct {
+	Attempts  int           // Total tries, including the first (1 = no retry)
+	BaseDelay time.Duration // Wait before the 2nd try; doubles each time
+	MaxDelay  time.Duration // Cap per wait; a longer Retry-After gives up instead
+	Jitter    float64       // +/- fraction of each wait (0..1), spreads bursts
+}
+
+// DefaultRetryPolicy is used unless SetRetryPolicy overrides it.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:  3,
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  5 * time.Second,
+	Jitter:    0.2,
+}
+
+var (
+	retryMu     sync.Mutex
+	retryPolicy = DefaultRetryPolicy
+)
+
+// SetRetryPolicy replaces the policy used for all upstream HTTP calls.
+func SetRetryPolicy(p RetryPolicy) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	if p.Attempts < 1 {
+		p.Attempts = 1
+	}
+	retryPolicy = p
+}
+
+func currentRetryPolicy() RetryPolicy {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	return retryPolicy
+}
+
+// Per-request timeout for provider calls (0 = each provider's own default)
+var httpTimeout time.Duration
+
+// SetHTTPTimeout caps every provider request at d; 0 restores each
+// provider's default (10-15s).
+func SetHTTPTimeout(d time.Duration) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	httpTimeout = d
+}
+
+// httpClient returns a client with the SetHTTPTimeout timeout, or fallback.
+func httpClient(fallback time.Duration) *http.Client {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	if httpTimeout > 0 {
+		return &http.Client{Timeout: httpTimeout}
+	}
+	return &http.Client{Timeout: fallback}
+}
+
+// retryableError marks an error as transient regardless of its type.
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// isRetryable reports whether err is worth another try.
+func isRetryable(err error) bool {
+	var re retryableError
+	if errors.As(err, &re) {
+		return true
+	}
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he.StatusCode == http.StatusTooManyRequests ||
+			he.StatusCode == http.StatusRequestTimeout ||
+			he.StatusCode >= 500
+	}
+	// Transport failures (refused, reset, timeout) come wrapped in *url.Error
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// Do calls fn until it succeeds, fails permanently, runs out of attempts or
+// ctx is done. It returns fn's last error (or ctx's, if cancelled while waiting).
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= p.Attempts || !isRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := p.backoff(attempt)
+		var he *HTTPError
+		if errors.As(err, &he) && he.RetryAfter > 0 {
+			if p.MaxDelay > 0 && he.RetryAfter > p.MaxDelay {
+				return err // Longer than we are willing to wait; let the caller fail over
+			}
+			wait = he.RetryAfter
+		}
+		slog.Debug("🔁 [RETRY] Retrying", "attempt", attempt, "wait", wait, "err", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is BaseDelay * 2^(attempt-1), capped at MaxDelay, with jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// doJSON sends the request built by newReq (rebuilt per attempt so bodies
+// can be replayed) under the retry policy and the host's rate limit, and
+// decodes a 2xx body into target (nil = discard).
+func doJSON(ctx context.Context, client *http.Client, newReq func() (*http.Request, error), target interface{}) error {
+	return currentRetryPolicy().Do(ctx, func() error {
+		req, err := newReq()
+		if err != nil {
+			return err
+		}
+		if err := waitForHost(ctx, req.URL.Host); err != nil {
+			return err
+		}
+		// Traced without the query, which may carry API keys
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			slog.Log(ctx, logging.LevelTrace, "🌐 [HTTP]", "method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "err", err, "took", time.Since(start))
+			return err
+		}
+		defer resp.Body.Close()
+		slog.Log(ctx, logging.LevelTrace, "🌐 [HTTP]", "method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "status", resp.StatusCode, "took", time.Since(start))
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return &HTTPError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+		if target == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(target)
+	})
+}
+
+// parseRetryAfter reads delay-seconds or an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
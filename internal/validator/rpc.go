@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Cause   *struct {
+		Name string `json:"name"` // NEAR-specific, e.g. "UNKNOWN_ACCOUNT"
+	} `json:"cause,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	if e.Cause != nil && e.Cause.Name != "" {
+		return fmt.Sprintf("rpc error %d: %s (%s)", e.Code, e.Message, e.Cause.Name)
+	}
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// jsonRPC performs a single JSON-RPC 2.0 call and decodes "result" into target.
+func jsonRPC(ctx context.Context, client *http.Client, url, method string, params interface{}, target interface{}) error {
+	payload := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	}
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := makeHTTPRequest(ctx, client, "POST", url, "", payload, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, target)
+}
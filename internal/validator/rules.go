@@ -0,0 +1,415 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ---------------------------------------------------------
+// RISK RULES (tunable thresholds, offsets and weights)
+// ---------------------------------------------------------
+
+// Rule is one heuristic's trigger threshold and score offset. What the
+// threshold measures depends on the rule (hours, days, tx/hour, USD);
+// rules without one ignore it. A disabled rule never scores.
+type Rule struct {
+	Threshold float64 `json:"threshold,omitempty"`
+	Offset    float64 `json:"offset"`
+	MinUSD    float64 `json:"min_usd,omitempty"` // Value floor, for rules that need one
+	Disabled  bool    `json:"disabled,omitempty"`
+}
+
+// RiskWeights combine the category scores into the final risk score.
+type RiskWeights struct {
+	Fraud      float64 `json:"fraud"`
+	Reputation float64 `json:"reputation"`
+	Lending    float64 `json:"lending"`
+}
+
+// RiskGrades are the upper bounds (exclusive) of each grade; anything at
+// or above Warning is FAILING.
+type RiskGrades struct {
+	Excellent float64     `json:"excellent"`
+	Low       float64     `json:"low"`
+	Warning   float64     `json:"warning"`
+	Labels    GradeLabels `json:"labels"`
+	Tiers     bool        `json:"tiers,omitempty"` // Also report risk_tier (1-5)
+}
+
+// GradeLabels are the risk_grade strings. Tiers follow the same order:
+// Excellent is 1, Sanctioned is 5.
+type GradeLabels struct {
+	Excellent  string `json:"excellent"`
+	Low        string `json:"low"`
+	Warning    string `json:"warning"`
+	Failing    string `json:"failing"`
+	Sanctioned string `json:"sanctioned"`
+}
+
+var defaultGradeLabels = GradeLabels{
+	Excellent:  "EXCELLENT (Safe)",
+	Low:        "LOW (Neutral)",
+	Warning:    "WARNING (Elevated)",
+	Failing:    "FAILING (High Risk)",
+	Sanctioned: "CRITICAL (Sanctioned)",
+}
+
+// grade returns the label and tier for a combined risk score.
+func (g RiskGrades) grade(score float64) (string, int) {
+	switch {
+	case score < g.Excellent:
+		return g.Labels.Excellent, 1
+	case score < g.Low:
+		return g.Labels.Low, 2
+	case score < g.Warning:
+		return g.Labels.Warning, 3
+	}
+	return g.Labels.Failing, 4
+}
+
+// RiskDecay fades risk events with age: an event HalfLifeDays old counts
+// half, never less than MinFactor. A zero half-life disables decay.
+type RiskDecay struct {
+	HalfLifeDays float64 `json:"half_life_days"`
+	MinFactor    float64 `json:"min_factor"`
+}
+
+// factor is the share of an offset an event at the given time still
+// carries at now.
+func (d RiskDecay) factor(at, now time.Time) float64 {
+	if d.HalfLifeDays <= 0 || at.IsZero() || at.Unix() <= 0 {
+		return 1
+	}
+	days := now.Sub(at).Hours() / 24
+	if days <= 0 {
+		return 1
+	}
+	return math.Max(d.MinFactor, math.Pow(0.5, days/d.HalfLifeDays))
+}
+
+// RiskRules is the investigator's policy. Sanctions hits are not tunable:
+// they always score 100.
+type RiskRules struct {
+	Policy    string        `json:"policy,omitempty"` // Named base policy (see PolicyNames); "" is the default
+	Weights   RiskWeights   `json:"weights"`
+	Grades    RiskGrades    `json:"grades"`
+	Decay     RiskDecay     `json:"decay"` // Mixer interactions and dormancy reactivations
+	Alerts    AlertRules    `json:"alerts"`
+	Decisions DecisionRules `json:"decisions"`
+
+	FreshWallet         Rule `json:"fresh_wallet"`         // Threshold: max age in hours
+	EstablishedHistory  Rule `json:"established_history"`  // Threshold: min age in days
+	Velocity            Rule `json:"velocity"`             // Offset only; the limits are VelocityWindows
+	AutomatedTrader     Rule `json:"automated_trader"`     // MEV bot or DEX market maker (instead of velocity)
+	MixerInteraction    Rule `json:"mixer_interaction"`    // Direct or internal-call contact
+	ThreatCounterparty  Rule `json:"threat_counterparty"`  // Contact with a drainer, scam or sanctioned label
+	VerifiedContract    Rule `json:"verified_contract"`    //
+	UnverifiedContract  Rule `json:"unverified_contract"`  //
+	SafeOwnerThreat     Rule `json:"safe_owner_threat"`    // Known threat among Safe owners
+	ThreatApproval      Rule `json:"threat_approval"`      // Unlimited approval to a known threat
+	RiskyApproval       Rule `json:"risky_approval"`       // Unlimited approval to an EOA/unverified contract
+	SubstantialHoldings Rule `json:"substantial_holdings"` // Threshold: min USD (native + stablecoins)
+	IndirectExposure    Rule `json:"indirect_exposure"`    // Threshold: min % of funds; offset scaled by share / hop
+	PeelChain           Rule `json:"peel_chain"`           // Threshold: min hops (PEEL_HOPS)
+	Dusting             Rule `json:"dusting"`              // Threshold: min distinct dust senders
+	AddressPoisoning    Rule `json:"address_poisoning"`    // Threshold: min lookalike senders
+	Reactivation        Rule `json:"reactivation"`         // Threshold: min months dormant; MinUSD: value moved
+	Concentration       Rule `json:"concentration"`        // Threshold: min % of volume with the top counterparty
+	RapidBridging       Rule `json:"rapid_bridging"`       // Threshold: min bridge deposits within 24h of funding
+	WashTrading         Rule `json:"wash_trading"`         // Threshold: min transfers within the cluster
+	SybilFarming        Rule `json:"sybil_farming"`        // Threshold: min sybil signals
+	ExploitPreparation  Rule `json:"exploit_preparation"`  // Flash loan plus a new contract (Threshold: max age in hours) or a same-block flow (MinUSD)
+	UpgradeableContract Rule `json:"upgradeable_contract"` // Owner can replace the code
+	MintAuthority       Rule `json:"mint_authority"`       // Owner can mint
+	PausableContract    Rule `json:"pausable_contract"`    // Owner can freeze transfers
+	YoungContract       Rule `json:"young_contract"`       // Threshold: max contract age in days
+	HoneypotToken       Rule `json:"honeypot_token"`       // Threshold: min honeypot levers held
+	ScamTokenHoldings   Rule `json:"scam_token_holdings"`  // Threshold: min % of held tokens that are honeypots
+	RegulatedExchange   Rule `json:"regulated_exchange"`   // Funds to/from a KYC exchange
+	UnregulatedExchange Rule `json:"unregulated_exchange"` // Funds to/from a no-KYC exchange
+	JurisdictionRisk    Rule `json:"jurisdiction_risk"`    // Exchange in a FATF-listed country; offset weighted by list
+
+	// Largest incoming transfer still counted as dust, in whole units per
+	// native asset symbol (e.g. "ETH": 0.00001)
+	DustThresholds map[string]float64 `json:"dust_thresholds"`
+
+	// Most txs allowed within each rolling window, e.g. "1h": 20, "7d": 500;
+	// 0 turns a default window off
+	VelocityWindows map[string]float64 `json:"velocity_windows"`
+
+	// FATF level (HIGH or MONITORED) per ISO country code, e.g. "KP": "HIGH";
+	// "" unlists a default
+	Jurisdictions map[string]string `json:"jurisdictions"`
+
+	// Custom heuristics by name, e.g. "bot_burst":
+	// "tx_count > 1000 && age_days < 7 => FRAUD +40 'bot-like burst'"
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+// DefaultRiskRules returns the built-in policy.
+func DefaultRiskRules() RiskRules {
+	return RiskRules{
+		Weights:   RiskWeights{Fraud: 0.5, Reputation: 0.3, Lending: 0.2},
+		Grades:    RiskGrades{Excellent: 10, Low: 35, Warning: 60, Labels: defaultGradeLabels},
+		Decay:     RiskDecay{HalfLifeDays: 365, MinFactor: 0.25},
+		Alerts:    DefaultAlertRules(),
+		Decisions: DefaultDecisionRules(),
+
+		FreshWallet:         Rule{Threshold: 24, Offset: 35},
+		EstablishedHistory:  Rule{Threshold: 365, Offset: -10},
+		Velocity:            Rule{Offset: 25},
+		AutomatedTrader:     Rule{Offset: 0},
+		MixerInteraction:    Rule{Offset: 55},
+		ThreatCounterparty:  Rule{Offset: 40},
+		VerifiedContract:    Rule{Offset: -5},
+		UnverifiedContract:  Rule{Offset: 15},
+		SafeOwnerThreat:     Rule{Offset: 40},
+		ThreatApproval:      Rule{Offset: 40},
+		RiskyApproval:       Rule{Offset: 10},
+		SubstantialHoldings: Rule{Threshold: 10000, Offset: -10},
+		IndirectExposure:    Rule{Threshold: 1, Offset: 60},
+		PeelChain:           Rule{Threshold: 4, Offset: 30},
+		Dusting:             Rule{Threshold: 5, Offset: 5},
+		AddressPoisoning:    Rule{Threshold: 1, Offset: 5},
+		Reactivation:        Rule{Threshold: 12, Offset: 20, MinUSD: 10000},
+		Concentration:       Rule{Threshold: 80, Offset: 10},
+		RapidBridging:       Rule{Threshold: 3, Offset: 25},
+		WashTrading:         Rule{Threshold: 6, Offset: 20},
+		SybilFarming:        Rule{Threshold: 2, Offset: 30},
+		ExploitPreparation:  Rule{Threshold: 24, Offset: 50, MinUSD: 100000},
+		UpgradeableContract: Rule{Offset: 10},
+		MintAuthority:       Rule{Offset: 15},
+		PausableContract:    Rule{Offset: 10},
+		YoungContract:       Rule{Threshold: 30, Offset: 15},
+		HoneypotToken:       Rule{Threshold: 2, Offset: 40},
+		ScamTokenHoldings:   Rule{Threshold: 50, Offset: 10},
+		RegulatedExchange:   Rule{Offset: -15},
+		UnregulatedExchange: Rule{Offset: 15},
+		JurisdictionRisk:    Rule{Offset: 30},
+
+		DustThresholds:  maps.Clone(DefaultDustThresholds),
+		VelocityWindows: maps.Clone(DefaultVelocityWindows),
+		Jurisdictions:   maps.Clone(DefaultJurisdictions),
+	}
+}
+
+var (
+	rulesMu     sync.Mutex
+	activeRules = DefaultRiskRules()
+)
+
+// SetRiskRules replaces the investigator's policy after validating it.
+func SetRiskRules(rules RiskRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	custom, err := compileCustomRules(rules.Custom)
+	if err != nil {
+		return err
+	}
+	rulesMu.Lock()
+	activeRules = rules
+	rulesMu.Unlock()
+
+	customMu.Lock()
+	activeCustom = custom
+	customMu.Unlock()
+	return nil
+}
+
+// ActiveRiskRules returns the rules in effect: SetRiskRules, else the
+// defaults.
+func ActiveRiskRules() RiskRules { return currentRiskRules() }
+
+func currentRiskRules() RiskRules {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	return activeRules
+}
+
+// Validate checks that the policy is internally consistent.
+func (r RiskRules) Validate() error {
+	var problems []string
+	w := r.Weights
+	if w.Fraud < 0 || w.Reputation < 0 || w.Lending < 0 {
+		problems = append(problems, "weights must not be negative")
+	}
+	if sum := w.Fraud + w.Reputation + w.Lending; math.Abs(sum-1) > 0.001 {
+		problems = append(problems, fmt.Sprintf("weights must sum to 1 (got %.3f)", sum))
+	}
+	g := r.Grades
+	if !(0 < g.Excellent && g.Excellent < g.Low && g.Low < g.Warning && g.Warning <= 100) {
+		problems = append(problems, "grades must satisfy 0 < excellent < low < warning <= 100")
+	}
+	for name, label := range map[string]string{
+		"excellent": g.Labels.Excellent, "low": g.Labels.Low, "warning": g.Labels.Warning,
+		"failing": g.Labels.Failing, "sanctioned": g.Labels.Sanctioned,
+	} {
+		if strings.TrimSpace(label) == "" {
+			problems = append(problems, fmt.Sprintf("grades.labels.%s must not be empty", name))
+		}
+	}
+	if r.Decay.HalfLifeDays < 0 {
+		problems = append(problems, "decay.half_life_days must not be negative")
+	}
+	if r.Decay.MinFactor < 0 || r.Decay.MinFactor > 1 {
+		problems = append(problems, "decay.min_factor must be within [0, 1]")
+	}
+
+	problems = append(problems, r.Alerts.validate()...)
+	problems = append(problems, r.Decisions.validate()...)
+	for name, rule := range r.byID() {
+		if rule.Offset < -100 || rule.Offset > 100 {
+			problems = append(problems, fmt.Sprintf("%s.offset must be within [-100, 100]", name))
+		}
+		if rule.Threshold < 0 {
+			problems = append(problems, fmt.Sprintf("%s.threshold must not be negative", name))
+		}
+		if rule.MinUSD < 0 {
+			problems = append(problems, fmt.Sprintf("%s.min_usd must not be negative", name))
+		}
+	}
+	for name, rule := range map[string]Rule{
+		"fresh_wallet": r.FreshWallet, "established_history": r.EstablishedHistory,
+		"substantial_holdings": r.SubstantialHoldings, "peel_chain": r.PeelChain,
+		"dusting": r.Dusting, "address_poisoning": r.AddressPoisoning, "reactivation": r.Reactivation, "concentration": r.Concentration,
+		"rapid_bridging": r.RapidBridging, "young_contract": r.YoungContract,
+		"honeypot_token": r.HoneypotToken, "scam_token_holdings": r.ScamTokenHoldings, "wash_trading": r.WashTrading,
+		"sybil_farming": r.SybilFarming, "exploit_preparation": r.ExploitPreparation,
+	} {
+		if rule.Threshold == 0 {
+			problems = append(problems, fmt.Sprintf("%s.threshold is required", name))
+		}
+	}
+	if _, err := PolicyRules(r.Policy); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if r.Velocity.Threshold != 0 {
+		problems = append(problems, `velocity.threshold was replaced by velocity_windows (e.g. "1h": 20)`)
+	}
+	for window, limit := range r.VelocityWindows {
+		if d, err := parseWindow(window); err != nil || d <= 0 {
+			problems = append(problems, fmt.Sprintf("velocity_windows.%s must be a positive duration like 1h, 24h or 7d", window))
+		}
+		if limit < 0 {
+			problems = append(problems, fmt.Sprintf("velocity_windows.%s must not be negative", window))
+		}
+	}
+	for symbol, t := range r.DustThresholds {
+		if t < 0 {
+			problems = append(problems, fmt.Sprintf("dust_thresholds.%s must not be negative", symbol))
+		}
+	}
+	for code, level := range r.Jurisdictions {
+		if len(code) != 2 {
+			problems = append(problems, fmt.Sprintf("jurisdictions.%s must be an ISO 3166-1 alpha-2 code", code))
+		}
+		switch strings.ToUpper(level) {
+		case JurisdictionHigh, JurisdictionMonitored, "":
+		default:
+			problems = append(problems, fmt.Sprintf("jurisdictions.%s must be HIGH, MONITORED or empty", code))
+		}
+	}
+	for name, src := range r.Custom {
+		if _, err := ParseCustomRule(name, src); err != nil {
+			problems = append(problems, "custom "+err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems) // Map order is random
+		return errors.New("risk rules: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// byID returns every rule by its rules-file key (the reasons' rule_id).
+func (r RiskRules) byID() map[string]Rule {
+	return map[string]Rule{
+		"fresh_wallet": r.FreshWallet, "established_history": r.EstablishedHistory, "velocity": r.Velocity, "automated_trader": r.AutomatedTrader,
+		"mixer_interaction": r.MixerInteraction, "threat_counterparty": r.ThreatCounterparty, "verified_contract": r.VerifiedContract, "unverified_contract": r.UnverifiedContract,
+		"safe_owner_threat": r.SafeOwnerThreat, "threat_approval": r.ThreatApproval, "risky_approval": r.RiskyApproval,
+		"substantial_holdings": r.SubstantialHoldings, "indirect_exposure": r.IndirectExposure,
+		"peel_chain": r.PeelChain, "dusting": r.Dusting, "address_poisoning": r.AddressPoisoning, "reactivation": r.Reactivation, "concentration": r.Concentration,
+		"rapid_bridging": r.RapidBridging, "regulated_exchange": r.RegulatedExchange, "unregulated_exchange": r.UnregulatedExchange,
+		"upgradeable_contract": r.UpgradeableContract, "mint_authority": r.MintAuthority,
+		"pausable_contract": r.PausableContract, "young_contract": r.YoungContract,
+		"honeypot_token": r.HoneypotToken, "scam_token_holdings": r.ScamTokenHoldings, "wash_trading": r.WashTrading,
+		"sybil_farming": r.SybilFarming, "jurisdiction_risk": r.JurisdictionRisk, "exploit_preparation": r.ExploitPreparation,
+	}
+}
+
+// disabledRules returns the keys of the rules switched off.
+func (r RiskRules) disabledRules() map[string]bool {
+	out := map[string]bool{}
+	for id, rule := range r.byID() {
+		if rule.Disabled {
+			out[id] = true
+		}
+	}
+	return out
+}
+
+// LoadRiskRules reads a rules file (.json, .yaml or .yml). Fields left out
+// keep their defaults; unknown fields are an error, so typos don't silently
+// fall back to defaults.
+func LoadRiskRules(path string) (RiskRules, error) {
+	return LoadPolicyRules("", path)
+}
+
+// LoadPolicyRules reads a rules file over a named policy ("" is the
+// default). A policy key in the file takes precedence over the argument.
+func LoadPolicyRules(policy, path string) (RiskRules, error) {
+	rules := DefaultRiskRules()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return rules, fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return rules, err
+		}
+	case ".json":
+	default:
+		return rules, fmt.Errorf("%s: unsupported rules format %q (json, yaml)", path, ext)
+	}
+
+	// The policy is the base the file's fields override
+	var base struct {
+		Policy string `json:"policy"`
+	}
+	if err := json.Unmarshal(data, &base); err == nil && base.Policy != "" {
+		policy = base.Policy
+	}
+	if rules, err = PolicyRules(policy); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := rules.Validate(); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,634 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// ---------------------------------------------------------
+// CUSTOM RULES (heuristics shipped as data)
+// ---------------------------------------------------------
+
+// CustomRule is a heuristic defined in the rules file instead of Go:
+//
+//	tx_count > 1000 && age_days < 7 => FRAUD +40 'bot-like burst'
+//
+// The condition may use the variables in RuleVariables, numbers, 'strings',
+// true/false, arithmetic (+ - * /), comparisons (== != < <= > >=), && || !
+// and parentheses. A variable the profile has no value for (age_days
+// without history, balance_usd on testnet) makes any comparison false.
+type CustomRule struct {
+	Name     string
+	Category string // FRAUD, REPUTATION or LENDING
+	Offset   float64
+	Reason   string
+	cond     exprNode
+}
+
+// RuleVariables documents what a custom rule condition can read.
+var RuleVariables = map[string]string{
+	"network":           "Network name, e.g. 'Ethereum Mainnet'",
+	"symbol":            "Native asset, e.g. 'ETH'",
+	"account_type":      "EVM: 'EOA', 'CONTRACT' or 'SMART_ACCOUNT'",
+	"address_type":      "Bitcoin: 'P2PKH', 'P2WPKH', 'P2TR', ...",
+	"is_active":         "Has a balance or history",
+	"is_contract":       "account_type is CONTRACT or SMART_ACCOUNT",
+	"contract_verified": "Verified source (unknown for EOAs)",
+	"upgradeable":       "Contract code can be replaced (unknown for EOAs)",
+	"privileges":        "Privileged functions the owner holds: MINT, PAUSE, UPGRADE (renounced = 0)",
+	"owner_type":        "Contract owner: 'RENOUNCED', 'SAFE', 'ACCOUNT' or '' (no owner())",
+	"testnet":           "Testnet profile",
+	"tx_count":          "Lifetime transactions",
+	"internal_tx_count": "EVM internal calls",
+	"age_days":          "Days since the first transaction",
+	"age_hours":         "Hours since the first transaction",
+	"idle_days":         "Days since the last transaction",
+	"tx_per_hour":       "tx_count over the wallet's age (at least 1 hour)",
+	"max_txs_1h":        "Most loaded txs within any 1 hour",
+	"max_txs_24h":       "Most loaded txs within any 24 hours",
+	"max_txs_7d":        "Most loaded txs within any 7 days",
+	"activity_pattern":  "'MACHINE', 'HUMAN' or 'MIXED' (20+ loaded txs)",
+	"interval_cv":       "Regularity of the time between txs (stddev / mean; near 0 is clockwork)",
+	"active_hours":      "Hours of the day (UTC) with any loaded tx",
+	"quiet_hours":       "Longest daily run of idle hours (UTC)",
+	"weekend_percent":   "Share of loaded txs on Saturdays and Sundays (UTC)",
+	"bot_class":         "EVM: 'MEV_BOT', 'MARKET_MAKER' or '' (not an automated trader)",
+	"flash_loans":       "EVM: txs with flash-loan providers (Aave, Balancer, dYdX, Maker)",
+	"balance":           "Native balance in whole units",
+	"balance_usd":       "Native balance in USD",
+	"stablecoin_usd":    "Stablecoin balances in USD",
+	"holdings_usd":      "balance_usd + stablecoin_usd",
+	"token_count":       "Non-zero ERC-20 balances",
+	"scam_tokens":       "Held tokens whose owner can block sells (EVM_SCAM_TOKENS)",
+	"nft_collections":   "NFT collections held",
+	"approval_count":    "Live token approvals",
+	"chain_count":       "Networks with activity (multi-network EVM)",
+	"utxo_count":        "Bitcoin: unspent outputs",
+	"dust_count":        "Bitcoin: dust outputs",
+	"utxo_pattern":      "Bitcoin: 'NORMAL', 'DUSTED', 'FRAGMENTED', ...",
+	"txs":               "Transactions loaded for analysis",
+	"incoming_txs":      "Loaded transactions paying the address",
+	"outgoing_txs":      "Loaded transactions sent by the address",
+	"counterparties":    "Distinct counterparties in the loaded transactions",
+	"threat_txs":        "Loaded transactions with counterparties labelled as threats (incoming dust excluded)",
+	"dust_senders":      "Distinct senders of incoming dust transfers",
+	"lookalikes":        "Lookalike addresses that sent zero-value or dust transfers",
+	"exposure_percent":  "Share of funds within EXPOSURE_HOPS of flagged addresses",
+	"exchange_links":    "Counterparties attributed to exchanges",
+	"fatf_vasps":        "Exchanges in FATF-listed jurisdictions",
+	"peel_hops":         "Length of the peel chain starting at the address",
+	"sybil_signals":     "Sybil-farming signals: shared funder, identical tx sequence, airdrop claims",
+	"wash_transfers":    "Transfers within a wash-trading cluster (EVM_WASH_TRADING)",
+	"dormant_months":    "Longest gap between loaded transactions, in months",
+	"top1_percent":      "Share of volume with the largest counterparty",
+	"bridge_deposits":   "Txs into known bridges",
+	"rapid_bridges":     "Bridge deposits within 24h of receiving funds",
+	"top5_percent":      "Share of volume with the 5 largest counterparties",
+}
+
+// ParseCustomRule compiles "<condition> => <CATEGORY> <offset> ['reason']".
+// Without a reason the rule's name is used.
+func ParseCustomRule(name, src string) (*CustomRule, error) {
+	condSrc, action, ok := strings.Cut(src, "=>")
+	if !ok {
+		return nil, fmt.Errorf("rule %s: expected \"<condition> => <CATEGORY> <offset> 'reason'\"", name)
+	}
+	rule := &CustomRule{Name: name, Reason: name}
+
+	action = strings.TrimSpace(action)
+	fields := strings.Fields(action)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("rule %s: action needs a category and an offset", name)
+	}
+	switch rule.Category = strings.ToUpper(fields[0]); rule.Category {
+	case "FRAUD", "REPUTATION", "LENDING":
+	default:
+		return nil, fmt.Errorf("rule %s: unknown category %q (FRAUD, REPUTATION or LENDING)", name, fields[0])
+	}
+	offset, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || offset < -100 || offset > 100 {
+		return nil, fmt.Errorf("rule %s: offset %q must be a number within [-100, 100]", name, fields[1])
+	}
+	rule.Offset = offset
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(action[len(fields[0]):]), fields[1]))
+	if rest != "" {
+		if len(rest) < 2 || (rest[0] != '\'' && rest[0] != '"') || rest[len(rest)-1] != rest[0] {
+			return nil, fmt.Errorf("rule %s: reason must be quoted", name)
+		}
+		rule.Reason = rest[1 : len(rest)-1]
+	}
+
+	p := &exprParser{src: condSrc}
+	if err := p.lex(); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+	if rule.cond, err = p.parseOr(); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("rule %s: unexpected %q", name, p.tokens[p.pos].text)
+	}
+	return rule, nil
+}
+
+// Matches evaluates the condition against the variables from ruleVariables.
+func (r *CustomRule) Matches(vars map[string]any) bool {
+	return truthy(r.cond.eval(vars))
+}
+
+var (
+	customMu     sync.Mutex
+	activeCustom []*CustomRule
+)
+
+// compileCustomRules parses every custom rule, in name order so reasons
+// come out the same way on every run.
+func compileCustomRules(sources map[string]string) ([]*CustomRule, error) {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []*CustomRule
+	for _, name := range names {
+		rule, err := ParseCustomRule(name, sources[name])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func currentCustomRules() []*CustomRule {
+	customMu.Lock()
+	defer customMu.Unlock()
+	return activeCustom
+}
+
+// ruleVariables computes what custom rules can read at now. isDust is the
+// investigator's dust filter, so dust_senders follows its injected rules.
+// Values the profile doesn't have are left nil.
+func ruleVariables(profile *WalletProfile, txs []Transaction, isDust func(Transaction) bool, now time.Time) map[string]any {
+	vars := map[string]any{
+		"network":           profile.Network,
+		"symbol":            profile.Symbol,
+		"account_type":      profile.AccountType,
+		"address_type":      profile.AddressType,
+		"is_active":         profile.IsActive,
+		"is_contract":       profile.AccountType == "CONTRACT" || profile.AccountType == "SMART_ACCOUNT",
+		"contract_verified": nil,
+		"testnet":           profile.Testnet,
+		"tx_count":          float64(profile.TxCount),
+		"internal_tx_count": float64(profile.InternalTxCount),
+		"age_days":          nil,
+		"age_hours":         nil,
+		"idle_days":         nil,
+		"tx_per_hour":       nil,
+		"balance":           nil,
+		"balance_usd":       nil,
+		"stablecoin_usd":    profile.StablecoinTotal,
+		"holdings_usd":      nil,
+		"token_count":       float64(len(profile.TokenHoldings)),
+		"nft_collections":   float64(len(profile.NFTHoldings)),
+		"approval_count":    float64(len(profile.Approvals)),
+		"chain_count":       float64(len(profile.Chains)),
+		"utxo_count":        nil,
+		"dust_count":        nil,
+		"utxo_pattern":      nil,
+	}
+	if profile.ContractVerified != nil {
+		vars["contract_verified"] = *profile.ContractVerified
+	}
+	scamTokens := 0
+	for _, t := range profile.TokenHoldings {
+		if len(t.HoneypotLevers) > 0 {
+			scamTokens++
+		}
+	}
+	vars["scam_tokens"] = float64(scamTokens)
+	vars["upgradeable"], vars["privileges"], vars["owner_type"] = nil, nil, nil
+	if cr := profile.ContractRisk; cr != nil {
+		vars["upgradeable"] = cr.Upgradeable
+		vars["owner_type"] = cr.OwnerType
+		vars["privileges"] = 0.0
+		if cr.OwnerType != "RENOUNCED" {
+			vars["privileges"] = float64(len(cr.Privileges))
+		}
+	}
+	if profile.FirstSeen != nil {
+		hours := now.Sub(*profile.FirstSeen).Hours()
+		vars["age_hours"] = hours
+		vars["age_days"] = hours / 24
+		vars["tx_per_hour"] = float64(profile.TxCount) / math.Max(hours, 1)
+	}
+	if profile.LastSeen != nil {
+		vars["idle_days"] = now.Sub(*profile.LastSeen).Hours() / 24
+	}
+	if profile.BalanceRaw != nil {
+		amount, _ := new(big.Float).Quo(new(big.Float).SetInt(profile.BalanceRaw), new(big.Float).SetFloat64(math.Pow10(profile.Decimals))).Float64()
+		vars["balance"] = amount
+	}
+	if profile.BalanceUSD != nil {
+		vars["balance_usd"] = *profile.BalanceUSD
+		vars["holdings_usd"] = *profile.BalanceUSD + profile.StablecoinTotal
+	}
+	var exposure float64
+	for _, e := range profile.Exposure {
+		exposure += e.Percent
+	}
+	vars["exposure_percent"] = exposure
+	vars["exchange_links"] = float64(len(profile.ExchangeLinks))
+	vars["lookalikes"] = 0.0
+	if profile.AddressPoisoning != nil {
+		vars["lookalikes"] = float64(len(profile.AddressPoisoning.Lookalikes))
+	}
+	times := txTimes(txs)
+	for name, window := range map[string]time.Duration{"max_txs_1h": time.Hour, "max_txs_24h": 24 * time.Hour, "max_txs_7d": 7 * 24 * time.Hour} {
+		count, _ := busiestWindow(times, window)
+		vars[name] = float64(count)
+	}
+	vars["activity_pattern"], vars["interval_cv"], vars["active_hours"], vars["quiet_hours"], vars["weekend_percent"] = nil, nil, nil, nil, nil
+	if t := profile.Temporal; t != nil {
+		vars["activity_pattern"] = t.Pattern
+		vars["interval_cv"] = t.Intervals.CV
+		vars["active_hours"] = float64(t.ActiveHours)
+		vars["quiet_hours"] = float64(t.QuietHours)
+		vars["weekend_percent"] = t.WeekendPercent
+	}
+	vars["flash_loans"] = 0.0
+	if profile.FlashLoans != nil {
+		vars["flash_loans"] = float64(profile.FlashLoans.Txs)
+	}
+	vars["bot_class"] = ""
+	if profile.Bot != nil {
+		vars["bot_class"] = profile.Bot.Class
+	}
+	fatfVASPs := map[string]bool{}
+	for _, l := range profile.ExchangeLinks {
+		if l.JurisdictionRisk != "" {
+			fatfVASPs[l.Entity] = true
+		}
+	}
+	vars["fatf_vasps"] = float64(len(fatfVASPs))
+	vars["dormant_months"] = 0.0
+	if r := findReactivation(profile.Address, txs, 1e-9); r != nil {
+		vars["dormant_months"] = r.Months
+	}
+	var bridgeDeposits, rapidBridges int
+	for _, b := range profile.Bridges {
+		bridgeDeposits += b.Deposits
+		rapidBridges += b.Rapid
+	}
+	vars["bridge_deposits"], vars["rapid_bridges"] = float64(bridgeDeposits), float64(rapidBridges)
+	vars["top1_percent"], vars["top5_percent"] = nil, nil
+	if c := profile.Concentration; c != nil {
+		vars["top1_percent"], vars["top5_percent"] = c.Top1Percent, c.Top5Percent
+	}
+	vars["sybil_signals"] = 0.0
+	if profile.Sybil != nil {
+		vars["sybil_signals"] = float64(len(profile.Sybil.Signals))
+	}
+	vars["wash_transfers"] = 0.0
+	if profile.WashTrading != nil {
+		vars["wash_transfers"] = float64(profile.WashTrading.Transfers)
+	}
+	vars["peel_hops"] = 0.0
+	if profile.PeelChain != nil {
+		vars["peel_hops"] = float64(profile.PeelChain.Hops)
+	}
+	if u := profile.UTXOs; u != nil {
+		vars["utxo_count"] = float64(u.Count)
+		vars["dust_count"] = float64(u.DustCount)
+		vars["utxo_pattern"] = u.Pattern
+	}
+
+	var incoming, outgoing, threats int
+	counterparties := map[string]bool{}
+	dustSenders := map[string]bool{}
+	threatParties := map[string]bool{}
+	for _, cp := range profile.CounterpartyLabels {
+		if _, ok := threatLabel(cp.Labels); ok {
+			threatParties[cp.Address] = true
+		}
+	}
+	for _, tx := range txs {
+		if isDust != nil && isDust(tx) {
+			dustSenders[strings.ToLower(tx.From)] = true
+			continue
+		}
+		other := strings.ToLower(tx.From)
+		if strings.EqualFold(tx.From, profile.Address) {
+			outgoing++
+			other = strings.ToLower(tx.To)
+		} else {
+			incoming++
+		}
+		if other != "" {
+			counterparties[other] = true
+		}
+		if threatParties[other] {
+			threats++
+		}
+	}
+	vars["txs"] = float64(len(txs))
+	vars["incoming_txs"] = float64(incoming)
+	vars["outgoing_txs"] = float64(outgoing)
+	vars["counterparties"] = float64(len(counterparties))
+	vars["threat_txs"] = float64(threats)
+	vars["dust_senders"] = float64(len(dustSenders))
+	return vars
+}
+
+// ---------------------------------------------------------
+// EXPRESSION PARSER (recursive descent)
+// ---------------------------------------------------------
+
+type exprToken struct {
+	kind string // num, str, ident, op
+	text string
+	num  float64
+}
+
+type exprParser struct {
+	src    string
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) lex() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1]))):
+			j := i
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.' || s[j] == '_') {
+				j++
+			}
+			n, err := strconv.ParseFloat(strings.ReplaceAll(s[i:j], "_", ""), 64)
+			if err != nil {
+				return fmt.Errorf("bad number %q", s[i:j])
+			}
+			p.tokens = append(p.tokens, exprToken{kind: "num", text: s[i:j], num: n})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			p.tokens = append(p.tokens, exprToken{kind: "ident", text: s[i:j]})
+			i = j
+		case c == '\'' || c == '"':
+			j := strings.IndexByte(s[i+1:], s[i])
+			if j < 0 {
+				return fmt.Errorf("unterminated string")
+			}
+			p.tokens = append(p.tokens, exprToken{kind: "str", text: s[i+1 : i+1+j]})
+			i += j + 2
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "(", ")"} {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("unexpected %q", string(c))
+			}
+			p.tokens = append(p.tokens, exprToken{kind: "op", text: op})
+			i += len(op)
+		}
+	}
+	return nil
+}
+
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == "op" {
+		for _, op := range ops {
+			if p.tokens[p.pos].text == op {
+				p.pos++
+				return op, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<=", ">=", "<", ">"); ok {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op, left, right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	return p.parseBinary(p.parseProduct, "+", "-")
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/")
+}
+
+func (p *exprParser) parseBinary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op, left, right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op, operand}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case "num":
+		return literalNode{t.num}, nil
+	case "str":
+		return literalNode{t.text}, nil
+	case "ident":
+		switch t.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		}
+		if _, known := RuleVariables[t.text]; !known {
+			return nil, fmt.Errorf("unknown variable %q", t.text)
+		}
+		return varNode(t.text), nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// ---------------------------------------------------------
+// EVALUATION (nil = unknown; type mismatches are unknown too)
+// ---------------------------------------------------------
+
+type exprNode interface {
+	eval(vars map[string]any) any
+}
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(map[string]any) any { return n.value }
+
+type varNode string
+
+func (n varNode) eval(vars map[string]any) any { return vars[string(n)] }
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n unaryNode) eval(vars map[string]any) any {
+	v := n.operand.eval(vars)
+	if n.op == "!" {
+		b, ok := v.(bool)
+		return ok && !b
+	}
+	if f, ok := v.(float64); ok {
+		return -f
+	}
+	return nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n binaryNode) eval(vars map[string]any) any {
+	switch n.op {
+	case "&&":
+		return truthy(n.left.eval(vars)) && truthy(n.right.eval(vars))
+	case "||":
+		return truthy(n.left.eval(vars)) || truthy(n.right.eval(vars))
+	}
+
+	l, r := n.left.eval(vars), n.right.eval(vars)
+	if l == nil || r == nil {
+		if n.op == "==" || n.op == "!=" || n.op == "<" || n.op == "<=" || n.op == ">" || n.op == ">=" {
+			return false
+		}
+		return nil
+	}
+	switch n.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	}
+
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		if ls, ok := l.(string); ok && n.op != "+" && n.op != "-" && n.op != "*" && n.op != "/" {
+			if rs, ok := r.(string); ok {
+				return compareOrdered(n.op, strings.Compare(ls, rs))
+			}
+		}
+		if n.op == "<" || n.op == "<=" || n.op == ">" || n.op == ">=" {
+			return false
+		}
+		return nil
+	}
+	switch n.op {
+	case "+":
+		return lf + rf
+	case "-":
+		return lf - rf
+	case "*":
+		return lf * rf
+	case "/":
+		if rf == 0 {
+			return nil
+		}
+		return lf / rf
+	}
+	cmp := 0
+	if lf < rf {
+		cmp = -1
+	} else if lf > rf {
+		cmp = 1
+	}
+	return compareOrdered(n.op, cmp)
+}
+
+func compareOrdered(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func truthy(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
@@ -0,0 +1,315 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCustomRuleAction(t *testing.T) {
+	tests := []struct {
+		src      string
+		category string
+		offset   float64
+		reason   string
+	}{
+		{"tx_count > 1 => FRAUD 40", "FRAUD", 40, "probe"},
+		{"tx_count > 1 => reputation -5.5 'well known'", "REPUTATION", -5.5, "well known"},
+		{`tx_count > 1 => LENDING +10 "ranked #1 bot"`, "LENDING", 10, "ranked #1 bot"},
+		{"tx_count > 1 =>   FRAUD   100   'x => y'", "FRAUD", 100, "x => y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			rule, err := ParseCustomRule("probe", tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rule.Category != tt.category || rule.Offset != tt.offset || rule.Reason != tt.reason {
+				t.Errorf("got %s %v %q, want %s %v %q", rule.Category, rule.Offset, rule.Reason, tt.category, tt.offset, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseCustomRuleErrors(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"tx_count > 1", "expected"},
+		{"tx_count > 1 => FRAUD", "action needs a category and an offset"},
+		{"tx_count > 1 => RISK 10", `unknown category "RISK"`},
+		{"tx_count > 1 => FRAUD ten", `offset "ten"`},
+		{"tx_count > 1 => FRAUD 101", `offset "101"`},
+		{"tx_count > 1 => FRAUD 10 reason", "reason must be quoted"},
+		{"tx_count > 1 => FRAUD 10 'open", "reason must be quoted"},
+		{"symbol == 'ETH => FRAUD 10", "unterminated string"},
+		{"tx_count > 1.2.3 => FRAUD 10", `bad number "1.2.3"`},
+		{"tx_count @ 1 => FRAUD 10", `unexpected "@"`},
+		{"tx_cnt > 1 => FRAUD 10", `unknown variable "tx_cnt"`},
+		{"(tx_count > 1 => FRAUD 10", "missing )"},
+		{"tx_count > => FRAUD 10", "unexpected end of condition"},
+		{"tx_count > 1 2 => FRAUD 10", `unexpected "2"`},
+		{"tx_count > ) => FRAUD 10", `unexpected ")"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseCustomRule("probe", tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %v, want %q", err, tt.err)
+			}
+			if !strings.HasPrefix(err.Error(), "rule probe: ") {
+				t.Errorf("error %q doesn't name the rule", err)
+			}
+		})
+	}
+}
+
+func TestCustomRuleMatches(t *testing.T) {
+	vars := map[string]any{
+		"tx_count":  8.0,
+		"age_days":  nil, // No history
+		"symbol":    "ETH",
+		"bot_class": "",
+		"testnet":   false,
+		"balance":   2.5,
+	}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		// Precedence: * over +, arithmetic over comparisons, && over ||
+		{"tx_count > 1 + 2 * 3", true},
+		{"tx_count > (1 + 2) * 3", false},
+		{"tx_count - 2 - 3 == 3", true},
+		{"tx_count / 2 / 2 == 2", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!testnet && tx_count >= 8", true},
+		{"-balance < -2", true},
+		{"- -balance == 2.5", true},
+		{"!(tx_count < 10)", false},
+
+		// Strings
+		{"symbol == 'ETH'", true},
+		{`symbol != "BTC"`, true},
+		{"symbol < 'F'", true},
+		{"bot_class == ''", true},
+
+		// Unknowns and type mismatches never match
+		{"age_days < 7", false},
+		{"age_days >= 7", false},
+		{"age_days + 1 > 0", false},
+		{"tx_count / 0 > 1", false},
+		{"tx_count / 0 <= 1", false},
+		{"symbol > 1", false},
+		{"symbol + 1 == 'ETH1'", false},
+		{"tx_count", false},
+		{"!symbol", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			rule, err := ParseCustomRule("probe", tt.cond+" => FRAUD 10")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.Matches(vars); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomRulePrecedence(t *testing.T) {
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		// Unary binds tighter than * and /, which bind tighter than + and -
+		{"1 + 2 * 3 == 7", true},
+		{"(1 + 2) * 3 == 9", true},
+		{"2 * 3 / 6 == 1", true},
+		{"12 / 2 * 3 == 18", true}, // Left to right, not 12 / 6
+		{"10 - 4 - 3 == 3", true},  // Left to right, not 10 - 1
+		{"-2 * 3 == -6", true},
+		{"2 - -3 == 5", true},
+		{"1 + 2 > 2", true}, // Arithmetic before comparison
+
+		// ! binds tighter than &&, which binds tighter than ||
+		{"!false && false", false},
+		{"!(false && false)", true},
+		{"false && false || true", true},
+		{"false && (false || true)", false},
+		{"true || false && false", true},
+		{"1 < 2 && 3 > 2 || 1 > 2", true},
+		{"!!true", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			rule, err := ParseCustomRule("probe", tt.cond+" => FRAUD 10")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.Matches(nil); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Comparisons don't chain
+	if _, err := ParseCustomRule("probe", "1 < 2 == true => FRAUD 10"); err == nil {
+		t.Error("a chained comparison parsed")
+	}
+}
+
+func TestCustomRuleUnknownValues(t *testing.T) {
+	// age_days is nil (no history) and tx_count isn't set at all: both are
+	// unknown, and so is anything computed from them or divided by zero
+	vars := map[string]any{"age_days": nil, "balance": 2.5, "symbol": "ETH"}
+	unknowns := []string{"age_days", "tx_count", "age_days + 1", "-age_days", "age_days * 0", "balance / 0", "0 / 0", "(balance / 0) * 0"}
+	for _, u := range unknowns {
+		for _, op := range []string{"==", "!=", "<", "<=", ">", ">="} {
+			for _, cond := range []string{u + " " + op + " 0", "0 " + op + " " + u, u + " " + op + " " + u} {
+				t.Run(cond, func(t *testing.T) {
+					rule, err := ParseCustomRule("probe", cond+" => FRAUD 10")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if rule.Matches(vars) {
+						t.Error("a comparison with an unknown value matched")
+					}
+				})
+			}
+		}
+	}
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"age_days < 7 || balance > 2", true}, // The known side still decides
+		{"age_days < 7 && balance > 2", false},
+		{"!(age_days < 7)", true}, // The comparison is false, so its negation is true
+		{"!age_days", false},
+		{"balance / 0.5 == 5", true},
+		{"-(balance / 0) < 0", false},
+		{"symbol / 1 == 0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			rule, err := ParseCustomRule("probe", tt.cond+" => FRAUD 10")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.Matches(vars); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzParseCustomRule(f *testing.F) {
+	for _, seed := range []string{
+		"tx_count > 1 + 2 * 3 => FRAUD 40",
+		"!(age_days < 7) && symbol == 'ETH' => REPUTATION -5 'seed'",
+		"balance / 0 > 1 || testnet => LENDING 10",
+		"((((tx_count)))) => FRAUD 1",
+		"- - - balance <= .5 => FRAUD 1",
+		`symbol != "a'b" => FRAUD 1 "x => y"`,
+		"=> FRAUD 1",
+		"(",
+	} {
+		f.Add(seed)
+	}
+	vars := []map[string]any{
+		nil,
+		{"tx_count": 8.0, "age_days": nil, "symbol": "ETH", "testnet": false, "balance": 0.0},
+		{"tx_count": "8", "age_days": true, "symbol": 1.0, "testnet": "no", "balance": nil},
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		rule, err := ParseCustomRule("fuzz", src)
+		if err != nil {
+			if rule != nil {
+				t.Fatalf("got a rule and an error %v", err)
+			}
+			return
+		}
+		if rule.Offset < -100 || rule.Offset > 100 {
+			t.Fatalf("offset %v out of range", rule.Offset)
+		}
+		for _, v := range vars {
+			rule.Matches(v) // Any value types, including none, must evaluate
+		}
+	})
+}
+
+func TestCompileCustomRulesInNameOrder(t *testing.T) {
+	rules, err := compileCustomRules(map[string]string{
+		"zeta":  "tx_count > 1 => FRAUD 1",
+		"alpha": "tx_count > 2 => FRAUD 2",
+		"mid":   "tx_count > 3 => FRAUD 3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "alpha,mid,zeta" {
+		t.Errorf("got order %s", got)
+	}
+	if _, err := compileCustomRules(map[string]string{"ok": "tx_count > 1 => FRAUD 1", "bad": "=> FRAUD 1"}); err == nil {
+		t.Error("a rule without a condition compiled")
+	}
+}
+
+func TestLoadRiskRulesYAMLCustomRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	src := "custom:\n  burst: \"tx_count > 10 => FRAUD 40 'ranked #1 bot'\" # trailing comment\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadRiskRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := compileCustomRules(rules.Custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compiled) != 1 || compiled[0].Reason != "ranked #1 bot" {
+		t.Fatalf("got %+v", compiled)
+	}
+}
+
+func TestLoadRiskRulesYAML(t *testing.T) {
+	tests := []struct {
+		name, src, err string
+	}{
+		{name: "block and flow maps", src: "dusting:\n  threshold: 10\n  offset: 5\nconcentration: {disabled: true}\n"},
+		{name: "duplicate key", src: "dusting:\n  offset: 5\n  offset: 6\n", err: "already defined"},
+		{name: "unknown field", src: "dusting:\n  treshold: 10\n", err: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yml")
+			if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := LoadRiskRules(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rules.Dusting.Threshold != 10 || rules.Dusting.Offset != 5 || !rules.Concentration.Disabled {
+				t.Errorf("got dusting %+v, concentration %+v", rules.Dusting, rules.Concentration)
+			}
+		})
+	}
+}
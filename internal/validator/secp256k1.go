@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ---------------------------------------------------------
+// SECP256K1 (BIP-32 derivation, signature public-key recovery)
+// ---------------------------------------------------------
+
+var (
+	secpP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secpN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secpGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	secpGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	// Square roots mod p are x^((p+1)/4) since p = 3 mod 4
+	secpSqrtExp = new(big.Int).Rsh(new(big.Int).Add(secpP, big.NewInt(1)), 2)
+)
+
+// secpPoint is an affine point; nil coordinates mean the point at infinity.
+type secpPoint struct{ x, y *big.Int }
+
+func (pt secpPoint) isInfinity() bool { return pt.x == nil }
+
+// secpDecompress parses a 33-byte SEC1 compressed public key.
+func secpDecompress(key []byte) (secpPoint, error) {
+	if len(key) != 33 || (key[0] != 0x02 && key[0] != 0x03) {
+		return secpPoint{}, errors.New("secp256k1: not a compressed public key")
+	}
+	x := new(big.Int).SetBytes(key[1:])
+	if x.Cmp(secpP) >= 0 {
+		return secpPoint{}, errors.New("secp256k1: x out of range")
+	}
+
+	// y^2 = x^3 + 7
+	y2 := new(big.Int).Exp(x, big.NewInt(3), secpP)
+	y2.Add(y2, big.NewInt(7)).Mod(y2, secpP)
+	y := new(big.Int).Exp(y2, secpSqrtExp, secpP)
+	if new(big.Int).Exp(y, big.NewInt(2), secpP).Cmp(y2) != 0 {
+		return secpPoint{}, errors.New("secp256k1: point not on curve")
+	}
+	if y.Bit(0) != uint(key[0]&1) {
+		y.Sub(secpP, y)
+	}
+	return secpPoint{x, y}, nil
+}
+
+// compress serializes the point as a 33-byte SEC1 public key.
+func (pt secpPoint) compress() []byte {
+	out := make([]byte, 33)
+	out[0] = 0x02 + byte(pt.y.Bit(0))
+	pt.x.FillBytes(out[1:])
+	return out
+}
+
+func secpAdd(a, b secpPoint) secpPoint {
+	switch {
+	case a.isInfinity():
+		return b
+	case b.isInfinity():
+		return a
+	}
+
+	var lambda *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		if a.y.Cmp(b.y) != 0 || a.y.Sign() == 0 {
+			return secpPoint{} // P + (-P)
+		}
+		// Doubling: 3x^2 / 2y
+		num := new(big.Int).Mul(a.x, a.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(a.y, 1)
+		lambda = num.Mul(num, den.ModInverse(den, secpP))
+	} else {
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		den.Mod(den, secpP)
+		lambda = num.Mul(num, den.ModInverse(den, secpP))
+	}
+	lambda.Mod(lambda, secpP)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, secpP)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, lambda).Sub(y, a.y).Mod(y, secpP)
+	return secpPoint{x, y}
+}
+
+// secpScalarMult returns k*pt (double-and-add).
+func secpScalarMult(pt secpPoint, k *big.Int) secpPoint {
+	result, addend := secpPoint{}, pt
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			result = secpAdd(result, addend)
+		}
+		addend = secpAdd(addend, addend)
+	}
+	return result
+}
+
+// secpScalarBaseMult returns k*G.
+func secpScalarBaseMult(k *big.Int) secpPoint {
+	return secpScalarMult(secpPoint{secpGx, secpGy}, k)
+}
+
+// secpRecoverPubKey recovers the signer's public key from a compact ECDSA
+// signature (r || s) with recovery id 0-3 over a 32-byte message hash.
+func secpRecoverPubKey(hash, sig []byte, recID byte) (secpPoint, error) {
+	if len(sig) != 64 || recID > 3 {
+		return secpPoint{}, errors.New("secp256k1: invalid signature")
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secpN) >= 0 || s.Cmp(secpN) >= 0 {
+		return secpPoint{}, errors.New("secp256k1: signature out of range")
+	}
+
+	// R has x = r (+ n for recID 2/3) and the y parity given by recID
+	x := new(big.Int).Set(r)
+	if recID >= 2 {
+		x.Add(x, secpN)
+	}
+	xBytes := make([]byte, 33)
+	xBytes[0] = 0x02 + recID&1
+	if x.BitLen() > 256 {
+		return secpPoint{}, errors.New("secp256k1: invalid recovery id")
+	}
+	x.FillBytes(xBytes[1:])
+	bigR, err := secpDecompress(xBytes)
+	if err != nil {
+		return secpPoint{}, err
+	}
+
+	// Q = r^-1 (sR - eG)
+	rInv := new(big.Int).ModInverse(r, secpN)
+	e := new(big.Int).SetBytes(hash)
+	u1 := new(big.Int).Neg(e)
+	u1.Mul(u1, rInv).Mod(u1, secpN)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, secpN)
+	q := secpAdd(secpScalarBaseMult(u1), secpScalarMult(bigR, u2))
+	if q.isInfinity() {
+		return secpPoint{}, errors.New("secp256k1: recovered point at infinity")
+	}
+	return q, nil
+}
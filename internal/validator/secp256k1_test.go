@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func hexInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+func TestSecpScalarBaseMult(t *testing.T) {
+	tests := []struct {
+		k    *big.Int
+		x, y string
+	}{
+		{big.NewInt(1), "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+		{big.NewInt(2), "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+		{big.NewInt(3), "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9", "388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+		{new(big.Int).Sub(secpN, big.NewInt(1)), "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777"},
+	}
+	for _, tt := range tests {
+		pt := secpScalarBaseMult(tt.k)
+		if pt.isInfinity() || pt.x.Cmp(hexInt(tt.x)) != 0 || pt.y.Cmp(hexInt(tt.y)) != 0 {
+			t.Errorf("%s*G = (%x, %x)", tt.k, pt.x, pt.y)
+		}
+	}
+	if pt := secpScalarBaseMult(secpN); !pt.isInfinity() {
+		t.Errorf("n*G = (%x, %x), want infinity", pt.x, pt.y)
+	}
+}
+
+func TestSecpCompression(t *testing.T) {
+	for _, k := range []int64{1, 2, 3, 7, 1000} {
+		pt := secpScalarBaseMult(big.NewInt(k))
+		got, err := secpDecompress(pt.compress())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.x.Cmp(pt.x) != 0 || got.y.Cmp(pt.y) != 0 {
+			t.Errorf("%d*G did not round-trip", k)
+		}
+	}
+
+	for _, key := range []string{
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", // Uncompressed prefix
+		"02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", // x = p
+		"020000000000000000000000000000000000000000000000000000000000000005", // x^3 + 7 has no root
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f817",   // 32 bytes
+	} {
+		raw, _ := hex.DecodeString(key)
+		if _, err := secpDecompress(raw); err == nil {
+			t.Errorf("secpDecompress(%s) succeeded", key)
+		}
+	}
+}
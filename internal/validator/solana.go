@@ -5,13 +5,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 )
 
-type SolanaStrategy struct{}
+type SolanaStrategy struct {
+	Testnet bool // Use Solana devnet instead of mainnet-beta
+
+	// JSON-RPC endpoint (core.Config.SolanaRPC). Empty means the public
+	// mainnet-beta (or devnet) endpoint, which is heavily rate limited.
+	RPCURL string
+}
+
+// Public Solana RPC endpoints
+const (
+	solanaMainnetRPC = "https://api.mainnet-beta.solana.com"
+	solanaDevnetRPC  = "https://api.devnet.solana.com"
+)
 
 func (s *SolanaStrategy) Name() string {
 	return "SOLANA"
@@ -19,36 +31,160 @@ func (s *SolanaStrategy) Name() string {
 
 func (s *SolanaStrategy) IsValidSyntax(address string) bool {
 	cleanAddr := strings.TrimSpace(address)
-	matched, _ := regexp.MatchString(`^[1-9A-HJ-NP-Za-km-z]{32,44}$`, cleanAddr)
-	return matched
+	if len(cleanAddr) < 32 || len(cleanAddr) > 44 {
+		return false
+	}
+	// Every Solana address is a 32-byte key; anything else is another chain
+	key, err := base58Decode(cleanAddr)
+	return err == nil && len(key) == 32
+}
+
+// solanaAddressType distinguishes wallets (ed25519 public keys, on-curve)
+// from Program Derived Addresses (off-curve, owned by a program).
+func solanaAddressType(address string) string {
+	key, err := base58Decode(address)
+	if err != nil || len(key) != 32 {
+		return ""
+	}
+	if isOnEd25519Curve(key) {
+		return "WALLET"
+	}
+	return "PDA"
 }
 
 func (s *SolanaStrategy) FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error) {
 	cleanAddr := strings.TrimSpace(address)
 	profile := &WalletProfile{
-		Address: cleanAddr,
-		Network: "SOLANA",
-		IsValid: true,
+		Address:     cleanAddr,
+		Network:     "SOLANA",
+		IsValid:     true,
+		Testnet:     s.Testnet,
+		AddressType: solanaAddressType(cleanAddr),
 	}
+	defer func() {
+		if profile.AddressType == "PDA" {
+			profile.ValidationDetails += " | Off-Curve (Program Derived Address)"
+		}
+	}()
+
+	client := httpClient(15 * time.Second)
 
-	if apiKey == "" {
-		profile.ValidationDetails = "Offline: No CoinStats API Key provided"
+	// 1. Balance & History (native RPC first; CoinStats as fallback/enrichment)
+	providers := s.providers(client, apiKey)
+	if err := loadBalance(ctx, providers, cleanAddr, 9, 9, "SOL", profile); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("Solana Data Error: %v", err)
 		return profile, nil
 	}
+	if _, err := loadHistory(ctx, providers, cleanAddr, profile); err != nil {
+		profile.ValidationDetails = appendDetail(profile.ValidationDetails, fmt.Sprintf("History Unavailable: %v", err))
+	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	baseURL := "https://openapiv1.coinstats.app/wallet"
-	connectionID := "solana"
+	return profile, nil
+}
 
-	// STEP 1: Sync (Trigger and ignore error)
-	syncURL := fmt.Sprintf("%s/transactions", baseURL)
-	syncPayload := map[string]interface{}{
-		"wallets": []map[string]string{{"address": cleanAddr, "connectionId": connectionID}},
+// providers returns the data sources in failover order. CoinStats only
+// indexes mainnet and needs a key.
+func (s *SolanaStrategy) providers(client *http.Client, apiKey string) ProviderChain {
+	rpcURL := s.RPCURL
+	if rpcURL == "" {
+		rpcURL = solanaMainnetRPC
+		if s.Testnet {
+			rpcURL = solanaDevnetRPC
+		}
 	}
-	_ = makeHTTPRequest(ctx, client, "PATCH", syncURL, apiKey, syncPayload, nil)
 
-	// STEP 2: Get Balance
-	balURL := fmt.Sprintf("%s/balance?address=%s&connectionId=%s", baseURL, cleanAddr, connectionID)
+	chain := ProviderChain{&SolanaRPCProvider{Client: client, URL: rpcURL}}
+	if !s.Testnet && apiKey != "" {
+		chain = append(chain, &CoinStatsProvider{Client: client, APIKey: apiKey})
+	}
+	if s.Testnet {
+		return cachedChain("solana:devnet", chain)
+	}
+	return cachedChain("solana", chain)
+}
+
+// ---------------------------------------------------------
+// PROVIDER: Solana JSON-RPC
+// ---------------------------------------------------------
+
+// Max signatures per getSignaturesForAddress call
+const solanaSignatureLimit = 1000
+
+// SolanaRPCProvider uses standard Solana JSON-RPC (getBalance,
+// getSignaturesForAddress).
+type SolanaRPCProvider struct {
+	Client *http.Client
+	URL    string
+}
+
+func (p *SolanaRPCProvider) Name() string {
+	return "Solana RPC"
+}
+
+func (p *SolanaRPCProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	var balResp struct {
+		Value uint64 `json:"value"` // Lamports
+	}
+	if err := jsonRPC(ctx, p.Client, p.URL, "getBalance", []interface{}{address}, &balResp); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(balResp.Value), nil
+}
+
+// GetTransactions returns up to the newest 1000 signatures.
+func (p *SolanaRPCProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	var sigs []struct {
+		Signature string `json:"signature"`
+		BlockTime *int64 `json:"blockTime"`
+	}
+	if err := jsonRPC(ctx, p.Client, p.URL, "getSignaturesForAddress", []interface{}{address, map[string]int{"limit": solanaSignatureLimit}}, &sigs); err != nil {
+		return nil, err
+	}
+
+	hist := &TxHistory{
+		Total:     len(sigs),
+		Complete:  len(sigs) < solanaSignatureLimit,
+		Truncated: len(sigs) == solanaSignatureLimit,
+	}
+	// Newest first; reverse (and skip sigs without a block time)
+	for i := len(sigs) - 1; i >= 0; i-- {
+		if sigs[i].BlockTime == nil {
+			continue
+		}
+		hist.Txs = append(hist.Txs, Transaction{TimeStamp: *sigs[i].BlockTime, Hash: sigs[i].Signature})
+	}
+	return hist, nil
+}
+
+func (p *SolanaRPCProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	return nil, ErrNotSupported
+}
+
+// ---------------------------------------------------------
+// PROVIDER: CoinStats
+// ---------------------------------------------------------
+
+// CoinStatsProvider reads the CoinStats wallet API (paid key). History
+// needs a sync first, hence the sync retry policy.
+type CoinStatsProvider struct {
+	Client *http.Client
+	APIKey string
+}
+
+const (
+	coinStatsBaseURL      = "https://openapiv1.coinstats.app/wallet"
+	coinStatsConnectionID = "solana"
+)
+
+// A fresh wallet's history appears a few seconds after the sync PATCH
+var coinStatsSyncPolicy = RetryPolicy{Attempts: 3, BaseDelay: 2 * time.Second, MaxDelay: 4 * time.Second, Jitter: 0.2}
+
+func (p *CoinStatsProvider) Name() string {
+	return "CoinStats"
+}
+
+func (p *CoinStatsProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	balURL := fmt.Sprintf("%s/balance?address=%s&connectionId=%s", coinStatsBaseURL, address, coinStatsConnectionID)
 	
 	// FIX: Use Slice for Balance Response
 	var balResp []struct {
@@ -57,89 +193,79 @@ func (s *SolanaStrategy) FetchState(ctx context.Context, address string, apiKey
 		Symbol string  `json:"symbol"`
 	}
 
-	if err := makeHTTPRequest(ctx, client, "GET", balURL, apiKey, nil, &balResp); err != nil {
-		profile.ValidationDetails = fmt.Sprintf("CoinStats Error: %v", err)
-		return profile, nil
+	if err := makeHTTPRequest(ctx, p.Client, "GET", balURL, p.APIKey, nil, &balResp); err != nil {
+		return nil, err
 	}
 
-	foundSol := false
 	for _, coin := range balResp {
 		if coin.Symbol == "SOL" {
-			profile.Balance = fmt.Sprintf("%.9f SOL", coin.Amount)
-			if coin.Amount > 0 { profile.IsActive = true }
-			foundSol = true
-			break
+			lamports, _ := new(big.Float).Mul(big.NewFloat(coin.Amount), big.NewFloat(1e9)).Int(nil)
+			return lamports, nil
 		}
 	}
-	if !foundSol { profile.Balance = "0.00000000 SOL" }
+	return new(big.Int), nil
+}
+
+func (p *CoinStatsProvider) GetTransactions(ctx context.Context, address string) (*TxHistory, error) {
+	// STEP 1: Sync (Trigger and ignore error)
+	syncURL := fmt.Sprintf("%s/transactions", coinStatsBaseURL)
+	syncPayload := map[string]interface{}{
+		"wallets": []map[string]string{{"address": address, "connectionId": coinStatsConnectionID}},
+	}
+	_ = makeHTTPRequest(ctx, p.Client, "PATCH", syncURL, p.APIKey, syncPayload, nil)
 
-	// STEP 3: Get Transaction History (WITH RETRY LOGIC)
-	txURL := fmt.Sprintf("%s/transactions?address=%s&connectionId=%s&limit=50", baseURL, cleanAddr, connectionID)
+	// STEP 2: Get Transaction History (retried while the sync runs)
+	txURL := fmt.Sprintf("%s/transactions?address=%s&connectionId=%s&limit=50", coinStatsBaseURL, address, coinStatsConnectionID)
 
 	var txResp struct {
 		Meta struct { TotalCount int `json:"totalCount"` } `json:"meta"`
 		Result []struct { Date string `json:"date"` } `json:"result"`
 	}
 
-	// Retry Loop: Try 3 times, waiting 2 seconds between tries
-	var err error
-	for i := 0; i < 3; i++ {
-		err = makeHTTPRequest(ctx, client, "GET", txURL, apiKey, nil, &txResp)
-		if err == nil {
-			break // Success!
+	// History is empty (or erroring) until the sync finishes; give it time
+	err := coinStatsSyncPolicy.Do(ctx, func() error {
+		if err := makeHTTPRequest(ctx, p.Client, "GET", txURL, p.APIKey, nil, &txResp); err != nil {
+			return retryableError{err}
 		}
-		// Wait before retrying (simulating sync time)
-		time.Sleep(2 * time.Second)
-	}
-
+		return nil
+	})
 	if err != nil {
-		// If it fails after 3 tries, then report Pending
-		profile.ValidationDetails += " | History Sync Pending (Try again in 1 min)"
-		return profile, nil
+		// Still failing after the sync window: the history is still syncing
+		return nil, fmt.Errorf("history sync pending (try again in 1 min): %w", err)
 	}
 
-	profile.TxCount = txResp.Meta.TotalCount
-	if len(txResp.Result) > 0 {
-		profile.IsActive = true
-		lastTx := txResp.Result[0]
-		parsedLast, _ := time.Parse(time.RFC3339, lastTx.Date)
-		profile.LastSeen = &parsedLast
-
-		firstTx := txResp.Result[len(txResp.Result)-1]
-		parsedFirst, _ := time.Parse(time.RFC3339, firstTx.Date)
-		profile.FirstSeen = &parsedFirst
-		
-		profile.ValidationDetails = fmt.Sprintf("Active | Last Seen: %s", parsedLast.Format("2006-01-02"))
-	} else {
-		if !profile.IsActive {
-			profile.ValidationDetails = "Inactive Account (No Tx History)"
+	// Newest first; reverse to oldest first
+	hist := &TxHistory{Total: txResp.Meta.TotalCount, Complete: len(txResp.Result) >= txResp.Meta.TotalCount}
+	for i := len(txResp.Result) - 1; i >= 0; i-- {
+		parsed, err := time.Parse(time.RFC3339, txResp.Result[i].Date)
+		if err != nil {
+			continue
 		}
+		hist.Txs = append(hist.Txs, Transaction{TimeStamp: parsed.Unix()})
 	}
+	return hist, nil
+}
 
-	return profile, nil
+func (p *CoinStatsProvider) GetFirstSeen(ctx context.Context, address string) (*time.Time, error) {
+	return nil, ErrNotSupported
 }
 
 func makeHTTPRequest(ctx context.Context, client *http.Client, method, url, apiKey string, payload interface{}, target interface{}) error {
-	var body *bytes.Buffer
+	var jsonBytes []byte
 	if payload != nil {
-		jsonBytes, _ := json.Marshal(payload)
-		body = bytes.NewBuffer(jsonBytes)
-	} else {
-		body = bytes.NewBuffer(nil)
+		jsonBytes, _ = json.Marshal(payload)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil { return err }
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-KEY", apiKey)
-
-	resp, err := client.Do(req)
-	if err != nil { return err }
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 { return fmt.Errorf("HTTP %d", resp.StatusCode) }
+	return doJSON(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
 
-	if target != nil { return json.NewDecoder(resp.Body).Decode(target) }
-	return nil
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-KEY", apiKey)
+		}
+		return req, nil
+	}, target)
 }
\ No newline at end of file
@@ -0,0 +1,186 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// SYBIL FARMING (wallet farms gaming airdrops)
+// ---------------------------------------------------------
+
+const (
+	// A funder paying the same amount to this many wallets runs a farm
+	sybilMinFanout = 20
+	// Amounts within this share of the wallet's own funding are "the same"
+	sybilAmountTolerance = 0.01
+	// Siblings (same funder, same amount) whose histories are compared
+	maxSybilSiblings = 5
+	// Outgoing txs compared, oldest first
+	sybilSequenceLength = 20
+	// A sibling with this share of the same tx sequence is a copy
+	sybilSequenceMatch = 0.8
+	// Distinct airdrop distributors claimed from
+	sybilMinClaims = 2
+)
+
+// Airdrop claim contracts (merkle distributors)
+var knownAirdropContracts = map[string]string{
+	"0x090d4613473dee047c3f2706764f49e0821d256e": "Uniswap",
+	"0x67a24ce4321ab3af51c2d0a4801c3e111d88c9d9": "Arbitrum",
+	"0xfedfaf1a10335448b7fa0268f56d2b44dbd357de": "Optimism",
+}
+
+// detectSybil collects the signals. Airdrop claims come from the loaded
+// history; the funder's fan-out and the siblings' sequences (1 + up to 5
+// history lookups) only when deep is set.
+func detectSybil(ctx context.Context, pc ProviderChain, address string, txs []Transaction, deep bool) *SybilInfo {
+	address = strings.ToLower(address)
+	s := &SybilInfo{}
+
+	claimed := map[string]bool{}
+	for _, tx := range txs {
+		if name, ok := knownAirdropContracts[strings.ToLower(tx.To)]; ok && strings.EqualFold(tx.From, address) && !claimed[name] {
+			claimed[name] = true
+			s.AirdropClaims = append(s.AirdropClaims, name)
+		}
+	}
+	sort.Strings(s.AirdropClaims)
+
+	if deep {
+		loadSybilFunder(ctx, pc, address, txs, s)
+	}
+	if len(s.AirdropClaims) >= sybilMinClaims {
+		s.Signals = append(s.Signals, fmt.Sprintf("%d Airdrop Claims (%s)", len(s.AirdropClaims), strings.Join(s.AirdropClaims, ", ")))
+	}
+	if len(s.Signals) == 0 {
+		return nil
+	}
+	return s
+}
+
+// loadSybilFunder finds the wallet's first funder, counts the wallets it
+// paid the same amount, and compares their tx sequences with the wallet's.
+func loadSybilFunder(ctx context.Context, pc ProviderChain, address string, txs []Transaction, s *SybilInfo) {
+	funding := firstFunding(address, txs)
+	if funding == nil {
+		return
+	}
+	funder := strings.ToLower(funding.From)
+	if _, exchange := lookupLabel(funder); exchange {
+		return // Exchanges pay out round amounts to everyone
+	}
+	if _, bridge := knownBridges[funder]; bridge {
+		return
+	}
+	amount, _ := new(big.Float).SetString(funding.Value)
+	hist, _, _, err := pc.GetTransactions(ctx, funder)
+	if err != nil || amount == nil {
+		return
+	}
+
+	var siblings []string
+	seen := map[string]bool{address: true}
+	for _, tx := range hist.Txs {
+		to := strings.ToLower(tx.To)
+		if !strings.EqualFold(tx.From, funder) || seen[to] || to == "" {
+			continue
+		}
+		if v, ok := new(big.Float).SetString(tx.Value); ok && sameAmount(v, amount) {
+			seen[to] = true
+			siblings = append(siblings, to)
+		}
+	}
+	if len(siblings)+1 < sybilMinFanout {
+		return
+	}
+	s.Funder, s.FunderFanout = funder, len(siblings)+1
+	s.Signals = append(s.Signals, fmt.Sprintf("Shared Funder %s (%d Wallets, Same Amount)", funder, s.FunderFanout))
+
+	own := outgoingSequence(address, txs)
+	if len(own) < 5 {
+		return // Too short to call a copy
+	}
+	for _, sibling := range siblings[:min(len(siblings), maxSybilSiblings)] {
+		if ctx.Err() != nil {
+			break
+		}
+		h, _, _, err := pc.GetTransactions(ctx, sibling)
+		if err != nil {
+			continue
+		}
+		if sequenceSimilarity(own, outgoingSequence(sibling, h.Txs)) >= sybilSequenceMatch {
+			s.Siblings = append(s.Siblings, sibling)
+		}
+	}
+	if len(s.Siblings) > 0 {
+		s.Signals = append(s.Signals, fmt.Sprintf("Identical Tx Sequence with %d Wallets", len(s.Siblings)))
+	}
+}
+
+// firstFunding is the oldest incoming tx carrying value.
+func firstFunding(address string, txs []Transaction) *Transaction {
+	var first *Transaction
+	for i := range txs {
+		tx := &txs[i]
+		if tx.Internal || tx.From == "" || strings.EqualFold(tx.From, address) || tx.Value == "" || tx.Value == "0" {
+			continue
+		}
+		if first == nil || tx.TimeStamp < first.TimeStamp {
+			first = tx
+		}
+	}
+	return first
+}
+
+func sameAmount(a, b *big.Float) bool {
+	diff := new(big.Float).Sub(a, b)
+	tolerance := new(big.Float).Mul(b, big.NewFloat(sybilAmountTolerance))
+	return diff.Abs(diff).Cmp(tolerance) <= 0
+}
+
+// outgoingSequence is the recipients of the address's first outgoing txs.
+func outgoingSequence(address string, txs []Transaction) []string {
+	sorted := append([]Transaction(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].TimeStamp < sorted[j].TimeStamp })
+	var seq []string
+	for _, tx := range sorted {
+		if strings.EqualFold(tx.From, address) && !tx.Internal && len(seq) < sybilSequenceLength {
+			seq = append(seq, strings.ToLower(tx.To))
+		}
+	}
+	return seq
+}
+
+// sequenceSimilarity is the longest common subsequence over the longer
+// sequence's length.
+func sequenceSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lcs[i][j] = lcs[i-1][j-1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i-1][j], lcs[i][j-1])
+			}
+		}
+	}
+	return float64(lcs[len(a)][len(b)]) / float64(max(len(a), len(b)))
+}
+
+// sybilDetail is the human-readable ValidationDetails fragment.
+func sybilDetail(s *SybilInfo) string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("Sybil Signals: %d", len(s.Signals))
+}
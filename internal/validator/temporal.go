@@ -0,0 +1,136 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ---------------------------------------------------------
+// TEMPORAL PROFILE (when an address is active, and how regularly)
+// ---------------------------------------------------------
+
+const (
+	// Fewest timed txs worth profiling
+	temporalMinTxs = 20
+	// Intervals this regular (stddev / mean) look scheduled
+	periodicMaxCV = 0.25
+	// Idle hours in a row, every day, that look like sleep
+	humanMinQuietHours = 5
+)
+
+// Activity patterns
+const (
+	PatternMachine = "MACHINE" // Scheduled intervals or round-the-clock activity
+	PatternHuman   = "HUMAN"   // Irregular intervals and a daily quiet period
+	PatternMixed   = "MIXED"
+)
+
+// temporalProfile builds the activity profile of a history. Returns nil
+// with fewer than temporalMinTxs timed txs.
+func temporalProfile(txs []Transaction) *TemporalProfile {
+	times := txTimes(txs)
+	if len(times) < temporalMinTxs {
+		return nil
+	}
+
+	p := &TemporalProfile{Txs: len(times)}
+	for _, ts := range times {
+		t := time.Unix(ts, 0).UTC()
+		p.HourHistogram[t.Hour()]++
+		if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			p.WeekendTxs++
+		} else {
+			p.WeekdayTxs++
+		}
+	}
+	p.WeekendPercent = math.Round(float64(p.WeekendTxs)/float64(len(times))*1000) / 10
+
+	// Active hours, and the longest idle run around the clock
+	run := 0
+	for i := 0; i < 48; i++ {
+		if p.HourHistogram[i%24] > 0 {
+			if i < 24 {
+				p.ActiveHours++
+			}
+			run = 0
+			continue
+		}
+		run++
+		p.QuietHours = max(p.QuietHours, run)
+	}
+
+	// Intervals
+	intervals := make([]int64, 0, len(times)-1)
+	var sum float64
+	for i := 1; i < len(times); i++ {
+		d := times[i] - times[i-1]
+		intervals = append(intervals, d)
+		sum += float64(d)
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+	mean := sum / float64(len(intervals))
+	var variance float64
+	for _, d := range intervals {
+		variance += (float64(d) - mean) * (float64(d) - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(intervals)))
+	percentile := func(q float64) int64 { return intervals[int(q*float64(len(intervals)-1))] }
+	p.Intervals = IntervalStats{
+		Min:    intervals[0],
+		P10:    percentile(0.1),
+		Median: percentile(0.5),
+		P90:    percentile(0.9),
+		Max:    intervals[len(intervals)-1],
+		Mean:   math.Round(mean*10) / 10,
+		StdDev: math.Round(stddev*10) / 10,
+	}
+	if mean > 0 {
+		p.Intervals.CV = math.Round(stddev/mean*1000) / 1000
+	}
+
+	// Pattern
+	machine := false
+	if mean > 0 && p.Intervals.CV <= periodicMaxCV {
+		machine = true
+		p.Signals = append(p.Signals, fmt.Sprintf("Periodic Intervals (Every %s, CV %.2f)", formatInterval(p.Intervals.Median), p.Intervals.CV))
+	}
+	if p.ActiveHours == 24 && roundTheClock(p.HourHistogram, len(times)) {
+		machine = true
+		p.Signals = append(p.Signals, "Round-the-Clock Activity (24/24 Hours)")
+	}
+	switch {
+	case machine:
+		p.Pattern = PatternMachine
+	case p.QuietHours >= humanMinQuietHours:
+		p.Pattern = PatternHuman
+		p.Signals = append(p.Signals, fmt.Sprintf("Daily Quiet Period (%dh)", p.QuietHours))
+	default:
+		p.Pattern = PatternMixed
+	}
+	return p
+}
+
+// roundTheClock reports whether even the quietest hour of the day carries
+// at least a third of an average hour's txs.
+func roundTheClock(hours [24]int, total int) bool {
+	quietest := hours[0]
+	for _, n := range hours {
+		quietest = min(quietest, n)
+	}
+	return float64(quietest)*3 >= float64(total)/24
+}
+
+// formatInterval prints seconds as "45s", "10m", "6h" or "2d".
+func formatInterval(seconds int64) string {
+	switch {
+	case seconds < 60:
+		return fmt.Sprintf("%ds", seconds)
+	case seconds < 3600:
+		return fmt.Sprintf("%dm", seconds/60)
+	case seconds < 86400:
+		return fmt.Sprintf("%dh", seconds/3600)
+	}
+	return fmt.Sprintf("%dd", seconds/86400)
+}
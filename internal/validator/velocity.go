@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// VELOCITY (busiest rolling windows, from tx timestamps)
+// ---------------------------------------------------------
+
+// DefaultVelocityWindows are the most txs an address may make within each
+// rolling window ("1h", "24h", "7d"; Go durations plus a "d" suffix)
+// before the velocity rule scores it.
+var DefaultVelocityWindows = map[string]float64{
+	"1h":  20,
+	"24h": 150,
+	"7d":  500,
+}
+
+// parseWindow reads a window length: a Go duration ("90m", "24h") or a
+// number of days ("7d").
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		return time.Duration(n * 24 * float64(time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	return d, nil
+}
+
+// txTimes returns the sorted timestamps of the distinct txs (internal calls
+// share their parent's hash). Untimed txs are skipped.
+func txTimes(txs []Transaction) []int64 {
+	seen := map[string]bool{}
+	var times []int64
+	for _, tx := range txs {
+		if tx.TimeStamp == 0 {
+			continue
+		}
+		if tx.Hash != "" {
+			if seen[tx.Hash] {
+				continue
+			}
+			seen[tx.Hash] = true
+		}
+		times = append(times, tx.TimeStamp)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times
+}
+
+// busiestWindow returns the most timestamps within any stretch of length
+// window, and where that stretch starts.
+func busiestWindow(times []int64, window time.Duration) (count int, start int64) {
+	span := int64(window.Seconds())
+	i := 0
+	for j := range times {
+		for times[j]-times[i] >= span {
+			i++
+		}
+		if n := j - i + 1; n > count {
+			count, start = n, times[i]
+		}
+	}
+	return count, start
+}
+
+// velocityWindows measures the busiest stretch of every configured window,
+// shortest first. Windows with a threshold of 0 are off.
+func velocityWindows(txs []Transaction, windows map[string]float64) []VelocityWindow {
+	times := txTimes(txs)
+	if len(times) == 0 {
+		return nil
+	}
+	type window struct {
+		name string
+		d    time.Duration
+	}
+	var sorted []window
+	for name, threshold := range windows {
+		if d, err := parseWindow(name); err == nil && threshold > 0 {
+			sorted = append(sorted, window{name, d})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].d < sorted[j].d })
+
+	var out []VelocityWindow
+	for _, w := range sorted {
+		count, start := busiestWindow(times, w.d)
+		out = append(out, VelocityWindow{
+			Window:    w.name,
+			MaxTxs:    count,
+			Start:     time.Unix(start, 0).UTC(),
+			Threshold: windows[w.name],
+			Exceeded:  float64(count) > windows[w.name],
+		})
+	}
+	return out
+}
+
+// worstBurst returns the exceeded window furthest over its threshold.
+func worstBurst(windows []VelocityWindow) (VelocityWindow, bool) {
+	var worst VelocityWindow
+	found := false
+	for _, w := range windows {
+		if w.Exceeded && (!found || float64(w.MaxTxs)/w.Threshold > float64(worst.MaxTxs)/worst.Threshold) {
+			worst, found = w, true
+		}
+	}
+	return worst, found
+}
+
+// burstDesc is the reason text.
+func burstDesc(w VelocityWindow) string {
+	return fmt.Sprintf("High Velocity Behavior (Potential Bot): %d Txs within %s (From %s, Limit %.0f)", w.MaxTxs, w.Window, w.Start.Format("2006-01-02 15:04 UTC"), w.Threshold)
+}
@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// WASH TRADING (tokens and NFTs circulating in a closed cluster)
+// ---------------------------------------------------------
+
+const (
+	// Counterparties checked for the cluster, by token/NFT transfers with
+	// the address; each costs 2 API calls
+	maxWashCandidates = 5
+	// Cluster members may receive at most this share of their transfers
+	// from outside the cluster (mints excluded)
+	washMaxExternalShare = 0.1
+)
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// tokenTransfer is one ERC-20, ERC-721 or ERC-1155 transfer.
+type tokenTransfer struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// fetchTokenTransfers returns the newest ERC-20 and NFT transfers of the
+// address (up to 1000 of each).
+func fetchTokenTransfers(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, address, apiKey string) ([]tokenTransfer, error) {
+	var transfers []tokenTransfer
+	for _, action := range []string{"tokentx", "tokennfttx"} {
+		url := fmt.Sprintf("%s?chainid=%s&module=account&action=%s&address=%s&page=1&offset=1000&sort=desc&apikey=%s", baseURL, chain.ID, action, address, apiKey)
+		var resp struct {
+			Status  string          `json:"status"`
+			Message string          `json:"message"`
+			Result  []tokenTransfer `json:"result"`
+		}
+		if err := getJSON(ctx, client, url, &resp); err != nil {
+			return transfers, err
+		}
+		if resp.Status == "0" && resp.Message != "No transactions found" {
+			return transfers, fmt.Errorf("%s: %s", action, resp.Message)
+		}
+		for _, t := range resp.Result {
+			transfers = append(transfers, tokenTransfer{From: strings.ToLower(t.From), To: strings.ToLower(t.To)})
+		}
+	}
+	return transfers, nil
+}
+
+// loadWashTrading checks whether the address's main token counterparties
+// form a closed cycle with it.
+func loadWashTrading(ctx context.Context, client *http.Client, baseURL string, chain EVMChain, address, apiKey string, profile *WalletProfile) {
+	origin := strings.ToLower(address)
+	own, err := fetchTokenTransfers(ctx, client, baseURL, chain, origin, apiKey)
+	if err != nil || len(own) == 0 {
+		return
+	}
+	transfers := map[string][]tokenTransfer{origin: own}
+	for _, candidate := range washCandidates(origin, own) {
+		if ctx.Err() != nil {
+			return
+		}
+		if t, err := fetchTokenTransfers(ctx, client, baseURL, chain, candidate, apiKey); err == nil {
+			transfers[candidate] = t
+		}
+	}
+	profile.WashTrading = findWashCluster(origin, transfers)
+}
+
+// washCandidates ranks the address's counterparties by transfers exchanged.
+func washCandidates(origin string, transfers []tokenTransfer) []string {
+	counts := map[string]int{}
+	for _, t := range transfers {
+		other := t.From
+		if other == origin {
+			other = t.To
+		}
+		if other != origin && other != zeroAddress && other != "" {
+			counts[other]++
+		}
+	}
+	candidates := make([]string, 0, len(counts))
+	for c := range counts {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] != counts[candidates[j]] {
+			return counts[candidates[i]] > counts[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates[:min(len(candidates), maxWashCandidates)]
+}
+
+// findWashCluster returns the addresses that both receive from and send
+// back to origin (directly or through each other), if the cluster gets
+// almost nothing from outside. transfers holds each address's own list;
+// a transfer is counted once, from its sender's list.
+func findWashCluster(origin string, transfers map[string][]tokenTransfer) *WashTradingInfo {
+	edges := map[string]map[string]int{}
+	for sender, list := range transfers {
+		for _, t := range list {
+			if t.From != sender || t.To == sender || transfers[t.To] == nil {
+				continue
+			}
+			if edges[sender] == nil {
+				edges[sender] = map[string]int{}
+			}
+			edges[sender][t.To]++
+		}
+	}
+
+	// The cluster is origin's strongly connected component
+	forward := reachable(origin, func(a string) []string { return sortedKeysInt(edges[a]) })
+	backward := reachable(origin, func(a string) []string {
+		var from []string
+		for sender, to := range edges {
+			if to[a] > 0 {
+				from = append(from, sender)
+			}
+		}
+		return from
+	})
+	var members []string
+	inCluster := map[string]bool{}
+	for a := range forward {
+		if backward[a] {
+			members = append(members, a)
+			inCluster[a] = true
+		}
+	}
+	if len(members) < 2 {
+		return nil
+	}
+	sort.Strings(members)
+
+	internal, incoming, external := 0, 0, 0
+	for _, m := range members {
+		for to, n := range edges[m] {
+			if inCluster[to] {
+				internal += n
+			}
+		}
+		for _, t := range transfers[m] {
+			if t.To != m || t.From == m || t.From == zeroAddress {
+				continue
+			}
+			incoming++
+			if !inCluster[t.From] {
+				external++
+			}
+		}
+	}
+	if incoming == 0 || float64(external)/float64(incoming) > washMaxExternalShare {
+		return nil
+	}
+	return &WashTradingInfo{
+		Members:        members,
+		Transfers:      internal,
+		ExternalInflow: float64(external*10000/incoming) / 100,
+	}
+}
+
+func reachable(start string, next func(string) []string) map[string]bool {
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+		for _, b := range next(a) {
+			if !seen[b] {
+				seen[b] = true
+				queue = append(queue, b)
+			}
+		}
+	}
+	return seen
+}
+
+func sortedKeysInt(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// washDetail is the human-readable ValidationDetails fragment.
+func washDetail(w *WashTradingInfo) string {
+	if w == nil {
+		return ""
+	}
+	return fmt.Sprintf("Wash Cluster: %d Addresses", len(w.Members))
+}
@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ---------------------------------------------------------
+// PROFILE WEBHOOK (each finished profile, signed, to a case system)
+// ---------------------------------------------------------
+
+// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+// request body, keyed with the webhook secret. Receivers recompute it over
+// the raw body and compare in constant time.
+const WebhookSignatureHeader = "X-Profiler-Signature-256"
+
+// WebhookProfiles returns a func that POSTs a profile as JSON to url,
+// signed with secret (see WebhookSignatureHeader). Transient failures are
+// retried with the usual policy; the error is the last one.
+func WebhookProfiles(url, secret string) func(ctx context.Context, p *WalletProfile) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(ctx context.Context, p *WalletProfile) error {
+		body, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		signature := "sha256=" + SignWebhook(secret, body)
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		return doJSON(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Profiler-Event", "profile")
+				req.Header.Set(WebhookSignatureHeader, signature)
+			}
+			return req, err
+		}, nil)
+	}
+}
+
+// SignWebhook is the hex HMAC-SHA256 of body keyed with secret.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ---------------------------------------------------------
+// BIP-32 EXTENDED PUBLIC KEYS (xpub / ypub / zpub)
+// ---------------------------------------------------------
+
+// extendedKeyVersion maps a SLIP-132 version prefix to the script type its
+// addresses use (BIP-44 P2PKH, BIP-49 P2SH-P2WPKH, BIP-84 P2WPKH).
+type extendedKeyVersion struct {
+	Prefix     string
+	ScriptType string
+	Testnet    bool
+}
+
+var extendedKeyVersions = map[uint32]extendedKeyVersion{
+	0x0488b21e: {"xpub", "P2PKH", false},
+	0x049d7cb2: {"ypub", "P2SH-P2WPKH", false},
+	0x04b24746: {"zpub", "P2WPKH", false},
+	0x043587cf: {"tpub", "P2PKH", true},
+	0x044a5262: {"upub", "P2SH-P2WPKH", true},
+	0x045f1cf6: {"vpub", "P2WPKH", true},
+}
+
+// extendedPubKey is a decoded BIP-32 node (public half only).
+type extendedPubKey struct {
+	Version   extendedKeyVersion
+	Depth     byte
+	ChainCode []byte
+	Key       secpPoint
+}
+
+// isExtendedPubKeySyntax is a cheap prefix/length check before decoding.
+func isExtendedPubKeySyntax(s string) bool {
+	if len(s) != 111 {
+		return false
+	}
+	for _, v := range extendedKeyVersions {
+		if strings.HasPrefix(s, v.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseExtendedPubKey decodes and checks a Base58Check xpub/ypub/zpub (or
+// testnet tpub/upub/vpub). Private keys (xprv...) are rejected.
+func parseExtendedPubKey(s string) (*extendedPubKey, error) {
+	payload, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 78 {
+		return nil, fmt.Errorf("extended key: %d bytes, want 78", len(payload))
+	}
+	version, ok := extendedKeyVersions[binary.BigEndian.Uint32(payload[:4])]
+	if !ok {
+		return nil, errors.New("extended key: unknown version (private keys are not accepted)")
+	}
+	key, err := secpDecompress(payload[45:78])
+	if err != nil {
+		return nil, err
+	}
+	return &extendedPubKey{
+		Version:   version,
+		Depth:     payload[4],
+		ChainCode: payload[13:45],
+		Key:       key,
+	}, nil
+}
+
+// child derives the non-hardened child i (CKDpub).
+func (k *extendedPubKey) child(i uint32) (*extendedPubKey, error) {
+	if i >= 0x80000000 {
+		return nil, errors.New("extended key: cannot derive hardened child from a public key")
+	}
+	data := binary.BigEndian.AppendUint32(k.Key.compress(), i)
+	mac := hmac.New(sha512.New, k.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(secpN) >= 0 {
+		return nil, fmt.Errorf("extended key: invalid child %d", i) // Probability < 2^-127
+	}
+	key := secpAdd(secpScalarBaseMult(il), k.Key)
+	if key.isInfinity() {
+		return nil, fmt.Errorf("extended key: invalid child %d", i)
+	}
+	return &extendedPubKey{Version: k.Version, Depth: k.Depth + 1, ChainCode: sum[32:], Key: key}, nil
+}
+
+// address encodes the node's public key with the script type of its version.
+func (k *extendedPubKey) address() (string, error) {
+	h := hash160(k.Key.compress())
+	p2pkh, p2sh, hrp := byte(0x00), byte(0x05), "bc"
+	if k.Version.Testnet {
+		p2pkh, p2sh, hrp = 0x6f, 0xc4, "tb"
+	}
+
+	switch k.Version.ScriptType {
+	case "P2WPKH":
+		return encodeSegwitAddress(hrp, 0, h)
+	case "P2SH-P2WPKH":
+		redeemScript := append([]byte{0x00, 0x14}, h...) // OP_0 <20-byte hash>
+		return base58CheckEncode(append([]byte{p2sh}, hash160(redeemScript)...)), nil
+	}
+	return base58CheckEncode(append([]byte{p2pkh}, h...)), nil
+}
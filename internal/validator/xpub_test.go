@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// Public derivations along BIP-32 test vector 1: each non-hardened step
+// from a parent xpub must give the vector's child xpub.
+func TestExtendedPubKeyChild(t *testing.T) {
+	tests := []struct {
+		parent string
+		i      uint32
+		child  string
+	}{
+		{ // m/0H -> m/0H/1
+			"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw", 1,
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+		{ // m/0H/1/2H -> m/0H/1/2H/2
+			"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5", 2,
+			"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+		},
+		{ // m/0H/1/2H/2 -> m/0H/1/2H/2/1000000000
+			"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV", 1000000000,
+			"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		},
+	}
+	for _, tt := range tests {
+		parent, err := parseExtendedPubKey(tt.parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := parseExtendedPubKey(tt.child)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parent.child(tt.i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Depth != want.Depth || !bytes.Equal(got.ChainCode, want.ChainCode) || !bytes.Equal(got.Key.compress(), want.Key.compress()) {
+			t.Errorf("child %d of depth %d: got %x %x, want %x %x", tt.i, parent.Depth, got.ChainCode, got.Key.compress(), want.ChainCode, want.Key.compress())
+		}
+	}
+
+	parent, _ := parseExtendedPubKey(tests[0].parent)
+	if _, err := parent.child(0x80000000); err == nil {
+		t.Error("derived a hardened child from a public key")
+	}
+}
+
+// The first receive address of BIP-84's test account zpub.
+func TestExtendedPubKeyAddress(t *testing.T) {
+	const zpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	if !isExtendedPubKeySyntax(zpub) {
+		t.Fatal("zpub syntax rejected")
+	}
+	account, err := parseExtendedPubKey(zpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Version.ScriptType != "P2WPKH" || account.Depth != 3 {
+		t.Errorf("got %+v", account.Version)
+	}
+	receive, err := account.child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := receive.child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := first.address(); err != nil || got != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Errorf("m/84'/0'/0'/0/0 = %s, %v", got, err)
+	}
+
+	// Private key 1 under each script type
+	g := secpScalarBaseMult(big.NewInt(1))
+	for _, tt := range []struct {
+		version uint32
+		want    string
+	}{
+		{0x0488b21e, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{0x049d7cb2, "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN"},
+		{0x04b24746, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{0x045f1cf6, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+	} {
+		k := &extendedPubKey{Version: extendedKeyVersions[tt.version], Key: g}
+		if got, err := k.address(); err != nil || got != tt.want {
+			t.Errorf("%s: got %s, %v; want %s", k.Version.Prefix, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseExtendedPubKeyErrors(t *testing.T) {
+	for _, s := range []string{
+		// BIP-32 test vector 1's master private key
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LETDqkTDnzKHt6z5W8o6jtCvJDc6xzJLf7",
+		// Vector 1's m/0H xpub with its last character changed
+		"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnx",
+		"xpub661MyMwAqRbc",
+	} {
+		if _, err := parseExtendedPubKey(s); err == nil {
+			t.Errorf("parseExtendedPubKey(%s) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type ZcashStrategy struct{}
+
+func (z *ZcashStrategy) Name() string {
+	return "ZCASH"
+}
+
+// zcashAddressType classifies a Zcash address. Transparent addresses are
+// Base58Check with 2-byte version prefixes; shielded addresses are valid
+// but their balances and history are private by design.
+func zcashAddressType(address string) string {
+	switch {
+	case strings.HasPrefix(address, "t1"), strings.HasPrefix(address, "t3"):
+		payload, err := base58CheckDecode(address)
+		if err != nil || len(payload) != 22 || payload[0] != 0x1C {
+			return ""
+		}
+		switch payload[1] {
+		case 0xB8:
+			return "P2PKH"
+		case 0xBD:
+			return "P2SH"
+		}
+	case strings.HasPrefix(address, "zs1"):
+		hrp, data, enc, err := bech32Decode(address, 90)
+		if err == nil && hrp == "zs" && enc == encodingBech32 {
+			if payload, err := convertBits(data, 5, 8, false); err == nil && len(payload) == 43 {
+				return "SAPLING"
+			}
+		}
+	case strings.HasPrefix(address, "u1"):
+		// Unified addresses (ZIP-316) are bech32m and longer than BIP-173 allows
+		hrp, _, enc, err := bech32Decode(address, 1023)
+		if err == nil && hrp == "u" && enc == encodingBech32m {
+			return "UNIFIED"
+		}
+	case strings.HasPrefix(address, "zc"):
+		payload, err := base58CheckDecode(address)
+		if err == nil && len(payload) == 66 && payload[0] == 0x16 && payload[1] == 0x9A {
+			return "SPROUT"
+		}
+	}
+	return ""
+}
+
+func (z *ZcashStrategy) IsValidSyntax(address string) bool {
+	return zcashAddressType(strings.TrimSpace(address)) != ""
+}
+
+func (z *ZcashStrategy) FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error) {
+	cleanAddr := strings.TrimSpace(address)
+	profile := &WalletProfile{
+		Address: cleanAddr,
+		Network: "ZCASH",
+		IsValid: true,
+	}
+
+	addrType := zcashAddressType(cleanAddr)
+	profile.AddressType = addrType
+	if addrType != "P2PKH" && addrType != "P2SH" {
+		// Still screened against the watchlist by the caller
+		profile.ValidationDetails = fmt.Sprintf("Shielded Address (%s) - Balance & History Private", addrType)
+		return profile, nil
+	}
+
+	client := httpClient(15 * time.Second)
+	url := fmt.Sprintf("https://api.blockchair.com/zcash/dashboards/address/%s", cleanAddr)
+	if apiKey != "" {
+		url += "?key=" + apiKey
+	}
+
+	var respObj struct {
+		Data map[string]struct {
+			Address struct {
+				Balance            int64  `json:"balance"` // Zatoshis
+				TransactionCount   int    `json:"transaction_count"`
+				FirstSeenReceiving string `json:"first_seen_receiving"`
+				LastSeenReceiving  string `json:"last_seen_receiving"`
+				FirstSeenSpending  string `json:"first_seen_spending"`
+				LastSeenSpending   string `json:"last_seen_spending"`
+			} `json:"address"`
+		} `json:"data"`
+	}
+
+	// 1. Fetch Data (Blockchair free tier works without a key, but is throttled)
+	if err := getJSON(ctx, client, url, &respObj); err != nil {
+		profile.ValidationDetails = fmt.Sprintf("Blockchair Error: %v", err)
+		return profile, nil
+	}
+
+	entry, ok := respObj.Data[cleanAddr]
+	if !ok {
+		profile.ValidationDetails = "Blockchair Error: address missing from response"
+		return profile, nil
+	}
+
+	// 2. Parse Balance (Zatoshis -> ZEC)
+	profile.Balance = fmt.Sprintf("%.8f ZEC", float64(entry.Address.Balance)/1e8)
+	setRawBalance(profile, big.NewInt(entry.Address.Balance), 8, "ZEC")
+	profile.TxCount = entry.Address.TransactionCount
+
+	// 3. Dates (earliest receive/spend = first seen, latest = last seen)
+	profile.FirstSeen = earliestTime(parseBlockchairTime(entry.Address.FirstSeenReceiving), parseBlockchairTime(entry.Address.FirstSeenSpending))
+	profile.LastSeen = latestTime(parseBlockchairTime(entry.Address.LastSeenReceiving), parseBlockchairTime(entry.Address.LastSeenSpending))
+
+	if profile.TxCount > 0 {
+		profile.IsActive = true
+		profile.ValidationDetails = fmt.Sprintf("Active Transparent Account (%s)", addrType)
+		if profile.LastSeen != nil {
+			profile.ValidationDetails += fmt.Sprintf(" | Last Active: %s", profile.LastSeen.Format("2006-01-02"))
+		}
+	} else {
+		profile.ValidationDetails = "Inactive Account (Zero Transactions)"
+	}
+
+	return profile, nil
+}
+
+// parseBlockchairTime parses Blockchair's "2006-01-02 15:04:05" (UTC) format.
+func parseBlockchairTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func earliestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func latestTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
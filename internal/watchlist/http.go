@@ -0,0 +1,236 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ---------------------------------------------------------
+// HTTP API (the engine's endpoints, mountable in any server)
+// ---------------------------------------------------------
+
+// Register adds the engine's lookup endpoints for store to mux: /check
+// (GET for one address, POST for a batch), /search and /check-name. Both
+// the engine and the validator's unified server (serve --engine) serve
+// them from here.
+func Register(mux *http.ServeMux, store *Store) {
+	h := handlers{store}
+	mux.HandleFunc("/check", h.checkAddress)
+	mux.HandleFunc("/search", h.search)
+	mux.HandleFunc("/check-name", h.checkName)
+}
+
+type handlers struct {
+	store *Store
+}
+
+// MaxBatchCheck is the most addresses one POST /check may carry.
+const MaxBatchCheck = 1000
+
+// BatchCheckRequest is the body of POST /check.
+type BatchCheckRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// BatchCheckResponse is the engine's POST /check response: one result per
+// distinct address, in request order, and the list that answered.
+type BatchCheckResponse struct {
+	Results     []Result     `json:"results"`
+	ListVersion *ListVersion `json:"list_version,omitempty"`
+}
+
+// GET /check?address=0x...
+// Sanctions lookup for one address.
+//
+// POST /check {"addresses": ["0x...", ...]}
+// Sanctions lookup for up to MaxBatchCheck addresses in one query. Hits
+// carry the currency and source; GET /check a hit for its SDN entity.
+func (h handlers) checkAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.checkBatch(w, r)
+		return
+	}
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		http.Error(w, "Missing address parameter", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.store.Check(address)
+	if err != nil {
+		http.Error(w, "Lookup failed", http.StatusInternalServerError)
+		return
+	}
+
+	// Hits linked to an SDN entity also list its other addresses, so clients
+	// can spot counterparties of the same entity they never queried
+	resp := map[string]interface{}{"sanctioned": res.Sanctioned}
+	if res.Sanctioned {
+		resp["currency"], resp["source"] = res.Currency, res.Source
+		if res.EntityID != "" {
+			resp["entity_id"], resp["entity_name"], resp["co_listed"] = res.EntityID, res.EntityName, res.CoListed
+		}
+	}
+	// Which list answered, so clients can keep it as screening evidence
+	if version, err := h.store.ListVersion(r.Context()); err == nil && version != (ListVersion{}) {
+		resp["list_version"] = version
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h handlers) checkBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchCheckRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	var addresses []string
+	seen := map[string]bool{}
+	for _, a := range req.Addresses {
+		if a != "" && !seen[a] {
+			seen[a] = true
+			addresses = append(addresses, a)
+		}
+	}
+	if len(addresses) == 0 {
+		http.Error(w, "Missing addresses", http.StatusBadRequest)
+		return
+	}
+	if len(addresses) > MaxBatchCheck {
+		http.Error(w, "Too many addresses (max "+strconv.Itoa(MaxBatchCheck)+")", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	results, err := h.store.BatchCheck(addresses)
+	if err != nil {
+		http.Error(w, "Lookup failed", http.StatusInternalServerError)
+		return
+	}
+	resp := BatchCheckResponse{Results: make([]Result, len(addresses))}
+	for i, a := range addresses {
+		resp.Results[i] = *results[a]
+	}
+	if version, err := h.store.ListVersion(r.Context()); err == nil && version != (ListVersion{}) {
+		resp.ListVersion = &version
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GET /search?q=lazarus[&limit=25][&all=true]
+// Full-text search over sanctioned entity names, aliases and programs.
+// By default only entities with at least one crypto address are returned.
+func (h handlers) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "Missing q parameter", http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	cryptoOnly := r.URL.Query().Get("all") != "true"
+
+	entities, err := h.store.Search(r.Context(), q, limit, cryptoOnly)
+	if err != nil {
+		http.Error(w, "Search failed", http.StatusInternalServerError)
+		return
+	}
+	if entities == nil {
+		entities = []Entity{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"query":   q,
+		"count":   len(entities),
+		"results": entities,
+	})
+}
+
+// GET /check-name?name=Kim+Jong+Un[&threshold=0.9][&limit=10]
+// Fuzzy-screens a person or company name against every SDN name and alias.
+func (h handlers) checkName(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Missing name parameter", http.StatusBadRequest)
+		return
+	}
+
+	threshold := DefaultNameThreshold
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t <= 0 || t > 1 {
+			http.Error(w, "Invalid threshold (0-1]", http.StatusBadRequest)
+			return
+		}
+		threshold = t
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	matches, err := h.store.ScreenName(r.Context(), name, threshold, limit)
+	if err != nil {
+		http.Error(w, "Screening failed", http.StatusInternalServerError)
+		return
+	}
+	if matches == nil {
+		matches = []NameMatch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(NameScreening{
+		Query:      name,
+		Normalized: NormalizeName(name),
+		Threshold:  threshold,
+		Match:      len(matches) > 0,
+		Count:      len(matches),
+		Matches:    matches,
+	})
+}
+
+// ---------------------------------------------------------
+// SYNC LOOP
+// ---------------------------------------------------------
+
+// SyncLoop syncs the store now and then every interval, until ctx is
+// done. An interrupted sync rolls back.
+func (s *Store) SyncLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.syncOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("🔹 [SYNC] Sync Loop Stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Store) syncOnce(ctx context.Context) {
+	if !s.NeedsUpdate(ctx) {
+		slog.Info("✅ [SYNC] Database is up to date.")
+		return
+	}
+
+	slog.Info("⬇️ [SYNC] Update Detected. Starting OFAC Download...")
+	if err := s.Sync(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			slog.Warn("⚠️ [SYNC] Sync Cancelled. Transaction rolled back.")
+			return
+		}
+		slog.Error("❌ [SYNC] Download Failed", "err", err)
+		return
+	}
+	slog.Info("✅ [SYNC] Database Update Complete.")
+}
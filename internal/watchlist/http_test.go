@@ -0,0 +1,83 @@
+package watchlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckHandler(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+	mux := http.NewServeMux()
+	Register(mux, s)
+	clean := "0x0000000000000000000000000000000000000000"
+
+	t.Run("GET", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/check?address="+lazarusETH, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body)
+		}
+		var res Result
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Sanctioned || res.EntityID != "1001" {
+			t.Errorf("got %+v", res)
+		}
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		body := fmt.Sprintf(`{"addresses": [%q, %q, "", %q, %q]}`, clean, lazarusETH, garantexETH, clean)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("POST", "/check", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body)
+		}
+		var resp BatchCheckResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		want := []struct {
+			address    string
+			sanctioned bool
+		}{{clean, false}, {lazarusETH, true}, {garantexETH, true}}
+		if len(resp.Results) != len(want) {
+			t.Fatalf("got %d results, want one per distinct address: %+v", len(resp.Results), resp.Results)
+		}
+		for i, w := range want {
+			if r := resp.Results[i]; r.Address != w.address || r.Sanctioned != w.sanctioned {
+				t.Errorf("result %d: got %+v, want %s sanctioned=%v", i, r, w.address, w.sanctioned)
+			}
+		}
+		if resp.ListVersion == nil || resp.ListVersion.LastModified != fixtureDate {
+			t.Errorf("list version %+v", resp.ListVersion)
+		}
+	})
+
+	tooMany := make([]string, MaxBatchCheck+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0x%040d", i)
+	}
+	tooManyBody, _ := json.Marshal(BatchCheckRequest{Addresses: tooMany})
+	for _, tt := range []struct {
+		name, body string
+		status     int
+	}{
+		{"invalid JSON", `{"addresses": [`, http.StatusBadRequest},
+		{"no addresses", `{"addresses": ["", ""]}`, http.StatusBadRequest},
+		{"too many addresses", string(tooManyBody), http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("POST", "/check", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package watchlist
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations are plain SQL files named NNNN_description.sql.
+// They are applied in order on Open and recorded in schema_migrations,
+// so each one runs exactly once per database. Never edit a migration
+// that has shipped; add a new file instead.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+func loadMigrations() ([]migration, error) {
+	files, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, f := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(f, "migrations/"), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: bad version prefix", f)
+		}
+		body, err := migrationFS.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(body)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %d", migrations[i].version)
+		}
+	}
+	return migrations, nil
+}
+
+// migrate brings the schema up to date.
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT,
+		applied_at DATETIME
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	if err := s.baselineLegacy(); err != nil {
+		return err
+	}
+
+	applied := map[int]bool{}
+	rows, err := s.db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s: %w", m.name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations(version, name, applied_at) VALUES(?, ?, ?)", m.version, m.name, time.Now()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %s: %w", m.name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.Info("🔹 [DB] Applied migration", "migration", m.name)
+	}
+	return nil
+}
+
+// baselineLegacy marks migrations as applied on databases created before
+// schema_migrations existed, so ALTER TABLE statements don't run twice.
+func (s *Store) baselineLegacy() error {
+	var recorded int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&recorded); err != nil {
+		return err
+	}
+	if recorded > 0 {
+		return nil
+	}
+
+	var legacy []migration
+	if s.hasTable("sanctioned_addresses") {
+		legacy = append(legacy, migration{version: 1, name: "0001_init"})
+	}
+	if s.hasColumn("sanctioned_addresses", "entity_id") {
+		legacy = append(legacy, migration{version: 2, name: "0002_entities"})
+	}
+
+	for _, m := range legacy {
+		if _, err := s.db.Exec("INSERT INTO schema_migrations(version, name, applied_at) VALUES(?, ?, ?)", m.version, m.name, time.Now()); err != nil {
+			return err
+		}
+		slog.Info("🔹 [DB] Baselined existing schema", "migration", m.name)
+	}
+	return nil
+}
+
+func (s *Store) hasTable(name string) bool {
+	var n int
+	_ = s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n)
+	return n > 0
+}
+
+func (s *Store) hasColumn(table, column string) bool {
+	var n int
+	_ = s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n)
+	return n > 0
+}
@@ -0,0 +1,121 @@
+package watchlist
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// appliedVersions lists the migrations recorded in s.
+func appliedVersions(t *testing.T, s *Store) []int {
+	t.Helper()
+	rows, err := s.db.Query("SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var versions []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.version != i+1 || m.sql == "" {
+			t.Errorf("migration %d: %+v", i, m)
+		}
+	}
+}
+
+func TestMigrateFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := appliedVersions(t, s); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("applied %v", got)
+	}
+	for _, table := range []string{"sanctioned_addresses", "metadata", "entities"} {
+		if !s.hasTable(table) {
+			t.Errorf("no %s table", table)
+		}
+	}
+	if !s.hasColumn("sanctioned_addresses", "entity_id") {
+		t.Error("no sanctioned_addresses.entity_id")
+	}
+	var appliedAt string
+	s.db.QueryRow("SELECT applied_at FROM schema_migrations WHERE version = 2").Scan(&appliedAt)
+	s.Close()
+
+	// Reopening runs nothing again; 0002's ALTER TABLE would fail if it did
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	var again string
+	s.db.QueryRow("SELECT applied_at FROM schema_migrations WHERE version = 2").Scan(&again)
+	if got := appliedVersions(t, s); !reflect.DeepEqual(got, []int{1, 2}) || again != appliedAt {
+		t.Errorf("after reopen: applied %v at %q, first at %q", got, again, appliedAt)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema []string
+	}{
+		{"original schema", []string{
+			"CREATE TABLE sanctioned_addresses (address TEXT PRIMARY KEY, currency TEXT, source TEXT, updated_at DATETIME)",
+			"CREATE TABLE metadata (key TEXT PRIMARY KEY, value TEXT)",
+		}},
+		{"with entities", []string{
+			"CREATE TABLE sanctioned_addresses (address TEXT PRIMARY KEY, currency TEXT, source TEXT, updated_at DATETIME, entity_id TEXT)",
+			"CREATE TABLE metadata (key TEXT PRIMARY KEY, value TEXT)",
+			"CREATE TABLE entities (profile_id TEXT PRIMARY KEY, name TEXT, aliases TEXT, programs TEXT, updated_at DATETIME)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A database from before schema_migrations, with data in it
+			path := filepath.Join(t.TempDir(), "watchlist.db")
+			db, err := sql.Open("sqlite3", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, stmt := range append(tt.schema, "INSERT INTO sanctioned_addresses(address, currency, source) VALUES('"+lazarusETH+"', 'ETH', 'OFAC')") {
+				if _, err := db.Exec(stmt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			db.Close()
+
+			s, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			if got := appliedVersions(t, s); !reflect.DeepEqual(got, []int{1, 2}) {
+				t.Errorf("applied %v", got)
+			}
+			if !s.hasColumn("sanctioned_addresses", "entity_id") || !s.hasTable("entities") {
+				t.Error("schema not brought up to date")
+			}
+			if res, err := s.Check(lazarusETH); err != nil || !res.Sanctioned {
+				t.Errorf("existing data lost: %+v, %v", res, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+-- Sanctioned addresses and sync metadata (original engine schema)
+CREATE TABLE IF NOT EXISTS sanctioned_addresses (
+	address TEXT PRIMARY KEY,
+	currency TEXT,
+	source TEXT,
+	updated_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_address ON sanctioned_addresses(address);
+CREATE TABLE IF NOT EXISTS metadata (key TEXT PRIMARY KEY, value TEXT);
@@ -0,0 +1,10 @@
+-- Sanctioned entity names/programs, linked from each address
+CREATE TABLE IF NOT EXISTS entities (
+	profile_id TEXT PRIMARY KEY,
+	name TEXT,
+	aliases TEXT,
+	programs TEXT,
+	updated_at DATETIME
+);
+ALTER TABLE sanctioned_addresses ADD COLUMN entity_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_entity ON sanctioned_addresses(entity_id);
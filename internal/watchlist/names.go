@@ -0,0 +1,266 @@
+package watchlist
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultNameThreshold is the lowest confidence ScreenName reports unless
+// the caller asks for another.
+const DefaultNameThreshold = 0.85
+
+// NameMatch is a sanctioned entity whose name or alias resembles the
+// screened name. Score is the confidence, from 0 to 1 (exact match).
+type NameMatch struct {
+	Entity
+	MatchedName string  `json:"matched_name"` // The name or alias that matched
+	Score       float64 `json:"score"`
+}
+
+// NameScreening is the engine's /check-name response.
+type NameScreening struct {
+	Query      string      `json:"query"`
+	Normalized string      `json:"normalized"`
+	Threshold  float64     `json:"threshold"`
+	Match      bool        `json:"match"` // At least one entity scored >= Threshold
+	Count      int         `json:"count"`
+	Matches    []NameMatch `json:"matches"`
+}
+
+// ScreenName compares name against every SDN name and alias (people and
+// companies, with or without crypto addresses) and returns the entities
+// scoring at least threshold, best first. Names are normalized (case,
+// accents, punctuation, legal suffixes) and scored with Jaro-Winkler in
+// both word orders and with Levenshtein distance; the best wins.
+func (s *Store) ScreenName(ctx context.Context, name string, threshold float64, limit int) ([]NameMatch, error) {
+	query := NormalizeName(name)
+	if query == "" {
+		return nil, nil
+	}
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultNameThreshold
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT profile_id, name, aliases, programs FROM entities")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []NameMatch
+	for rows.Next() {
+		var e Entity
+		var aliases, programs string
+		if err := rows.Scan(&e.ProfileID, &e.Name, &aliases, &programs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.Aliases = splitList(aliases, " | ")
+
+		best := NameMatch{}
+		for _, candidate := range append([]string{e.Name}, e.Aliases...) {
+			if score := nameScore(query, NormalizeName(candidate)); score > best.Score {
+				best.MatchedName, best.Score = candidate, score
+			}
+		}
+		best.Score = math.Round(best.Score*1000) / 1000
+		if best.Score >= threshold {
+			e.Programs = splitList(programs, ", ")
+			best.Entity = e
+			matches = append(matches, best)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	for i := range matches {
+		addrs, err := s.entityAddresses(ctx, matches[i].ProfileID)
+		if err != nil {
+			return nil, err
+		}
+		matches[i].Addresses = addrs
+	}
+	return matches, nil
+}
+
+// --- NORMALIZATION ---
+
+// Words that don't tell two parties apart: legal forms and honorifics
+var nameNoise = map[string]bool{
+	"llc": true, "ltd": true, "limited": true, "inc": true, "incorporated": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "plc": true,
+	"gmbh": true, "ag": true, "sa": true, "srl": true, "bv": true, "nv": true,
+	"jsc": true, "ojsc": true, "pjsc": true, "cjsc": true, "ooo": true, "oao": true, "zao": true,
+	"fze": true, "fzco": true, "the": true,
+	"mr": true, "mrs": true, "ms": true, "dr": true,
+}
+
+// Latin letters with diacritics, folded to ASCII
+var nameFold = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// NormalizeName lowercases name, folds accents, turns punctuation into
+// spaces and drops legal suffixes and honorifics:
+// "Société Générale, S.A." -> "societe generale".
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case nameFold[r] != "":
+			b.WriteString(nameFold[r])
+		case r == '.' || r == '\'' || r == '’':
+			// Initials and apostrophes join: "S.A." -> "sa", "O'Neil" -> "oneil"
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	kept := words[:0]
+	for _, w := range words {
+		if !nameNoise[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words // The name was nothing but noise words; keep them
+	}
+	return strings.Join(kept, " ")
+}
+
+// --- SCORING ---
+
+// nameScore is the best of Jaro-Winkler (as written and with the words
+// sorted, so "Kim Jong Un" matches "Un Kim Jong") and Levenshtein
+// similarity of two normalized names.
+func nameScore(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	score := jaroWinkler(a, b)
+	if sorted := jaroWinkler(sortWords(a), sortWords(b)); sorted > score {
+		score = sorted
+	}
+	if lev := levenshteinSimilarity(a, b); lev > score {
+		score = lev
+	}
+	return score
+}
+
+func sortWords(s string) string {
+	words := strings.Fields(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity (0-1) of a and b,
+// boosting shared prefixes of up to 4 characters.
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		lo, hi := max(0, i-window), min(len(s2), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Matched characters that appear in a different order
+	transpositions, k := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// levenshteinSimilarity is 1 - editDistance / longer length.
+func levenshteinSimilarity(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	longer := max(len(s1), len(s2))
+	if longer == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s1); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(s2)])/float64(longer)
+}
@@ -0,0 +1,192 @@
+package watchlist
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Société Générale, S.A.", "societe generale"},
+		{"LAZARUS GROUP", "lazarus group"},
+		{"  Kim   Jong-Un ", "kim jong un"},
+		{"O'Neil Trading Ltd.", "oneil trading"},
+		{"O’Neil", "oneil"},
+		{"Garantex Europe OÜ", "garantex europe ou"},
+		{"The Co. Inc.", "the co inc"}, // Nothing but noise words: kept
+		{"Mr. Łukasz Wójcik", "lukasz wojcik"},
+		{"Straße GmbH", "strasse"},
+		{"APT38", "apt38"},
+		{"Ким Чен Ын", "ким чен ын"}, // Non-Latin letters are kept, lowercased
+		{"", ""},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.name); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// round3 rounds to 3 decimals, as ScreenName reports scores.
+func round3(f float64) float64 { return math.Round(f*1000) / 1000 }
+
+func TestJaroWinkler(t *testing.T) {
+	// Winkler's examples
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dwayne", "duane", 0.84},
+		{"dixon", "dicksonx", 0.813},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"a", "a", 1},
+		{"", "abc", 0},
+		{"société", "societe", 0.886}, // Runes, not bytes
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := round3(jaroWinkler(tt.a, tt.b)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := round3(jaroWinkler(tt.b, tt.a)); got != tt.want {
+				t.Errorf("reversed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"kitten", "sitting", 0.571}, // 3 edits over 7
+		{"flaw", "lawn", 0.5},
+		{"lazarus", "lazarus", 1},
+		{"", "", 1},
+		{"", "abc", 0},
+		{"garantex", "garantexx", 0.889},
+		{"ün", "un", 0.5}, // One rune substituted, not two bytes
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := round3(levenshteinSimilarity(tt.a, tt.b)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := round3(levenshteinSimilarity(tt.b, tt.a)); got != tt.want {
+				t.Errorf("reversed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameScore(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"kim jong un", "kim jong un", 1},
+		{"un kim jong", "kim jong un", 1},         // Word order
+		{"lazarus group", "lazaros group", 0.969}, // Sorted, the names share the prefix "grou"
+		{"", "kim jong un", 0},
+		{"kim jong un", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := nameScore(tt.a, tt.b); round3(got) != round3(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The best of the three measures, never below any of them
+	for _, pair := range [][2]string{{"garantex europe", "garantex"}, {"hidden cobra", "cobra hidden"}, {"apt38", "apt 38"}} {
+		a, b := pair[0], pair[1]
+		score := nameScore(a, b)
+		if score < jaroWinkler(a, b) || score < jaroWinkler(sortWords(a), sortWords(b)) || score < levenshteinSimilarity(a, b) || score > 1 {
+			t.Errorf("nameScore(%q, %q) = %v", a, b, score)
+		}
+	}
+}
+
+func TestScreenName(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+	ctx := context.Background()
+
+	tests := []struct {
+		query       string
+		threshold   float64
+		wantID      string
+		wantMatched string
+		wantScore   float64
+	}{
+		{"Lazarus Group", 0, "1001", "LAZARUS GROUP", 1},
+		{"hidden cobra", 0, "1001", "HIDDEN COBRA", 1}, // An alias
+		{"Un, Kim Jong", 0, "1003", "KIM Jong Un", 1},
+		{"Societe Generale de Crypto SA", 0, "1004", "Société Générale de Crypto, S.A.", 1},
+		{"Lazaruss Group", 0, "1001", "LAZARUS GROUP", 0},
+		{"Garantex Europe", 0.8, "1002", "GARANTEX EUROPE OU", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches, err := s.ScreenName(ctx, tt.query, tt.threshold, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) == 0 {
+				t.Fatal("no match")
+			}
+			m := matches[0]
+			if m.ProfileID != tt.wantID || m.MatchedName != tt.wantMatched {
+				t.Errorf("best match %s %q, want %s %q", m.ProfileID, m.MatchedName, tt.wantID, tt.wantMatched)
+			}
+			if tt.wantScore != 0 && m.Score != tt.wantScore {
+				t.Errorf("score %v, want %v", m.Score, tt.wantScore)
+			}
+			if m.Score < DefaultNameThreshold && tt.threshold == 0 {
+				t.Errorf("score %v below the default threshold", m.Score)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Score > matches[i-1].Score {
+					t.Errorf("matches not best first: %v", matches)
+				}
+			}
+		})
+	}
+
+	// Lazarus comes back with its addresses and programs
+	matches, err := s.ScreenName(ctx, "LAZARUS GROUP", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches[0].Addresses) != 2 || len(matches[0].Programs) != 2 {
+		t.Errorf("got addresses %v, programs %v", matches[0].Addresses, matches[0].Programs)
+	}
+
+	for _, query := range []string{"", "...", "Completely Unrelated Name"} {
+		if matches, err := s.ScreenName(ctx, query, 0, 0); err != nil || len(matches) != 0 {
+			t.Errorf("%q: got %v, %v", query, matches, err)
+		}
+	}
+
+	// A low threshold finds more; the limit caps them
+	all, err := s.ScreenName(ctx, "Lazarus", 0.01, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) < 2 {
+		t.Fatalf("threshold 0.01 matched %d entities", len(all))
+	}
+	if capped, err := s.ScreenName(ctx, "Lazarus", 0.01, 1); err != nil || len(capped) != 1 || capped[0].ProfileID != all[0].ProfileID {
+		t.Errorf("limit 1: got %v, %v", capped, err)
+	}
+}
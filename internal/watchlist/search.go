@@ -0,0 +1,132 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Entity is a sanctioned party together with its attributed crypto addresses.
+type Entity struct {
+	ProfileID string   `json:"profile_id"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Programs  []string `json:"programs,omitempty"`
+	Addresses []Result `json:"addresses"`
+}
+
+// Search finds sanctioned entities whose name, alias or program matches q
+// (e.g. "lazarus", "garantex", "DPRK3"). Every term must match; terms are
+// prefix-matched. When cryptoOnly is set, entities without any crypto
+// address are skipped.
+func (s *Store) Search(ctx context.Context, q string, limit int, cryptoOnly bool) ([]Entity, error) {
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+
+	filter := ""
+	if cryptoOnly {
+		filter = " AND EXISTS (SELECT 1 FROM sanctioned_addresses a WHERE a.entity_id = e.profile_id)"
+	}
+
+	var rows *sql.Rows
+	var err error
+	if s.hasFTS {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT e.profile_id, e.name, e.aliases, e.programs
+			FROM entities_fts f JOIN entities e ON e.profile_id = f.profile_id
+			WHERE entities_fts MATCH ?`+filter+`
+			ORDER BY f.rank LIMIT ?`, ftsQuery(terms), limit)
+	} else {
+		// Fallback: every term must appear somewhere in name/aliases/programs
+		where := make([]string, len(terms))
+		args := make([]interface{}, 0, len(terms)+1)
+		for i, t := range terms {
+			where[i] = "(e.name || ' ' || e.aliases || ' ' || e.programs) LIKE ?"
+			args = append(args, "%"+t+"%")
+		}
+		args = append(args, limit)
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT e.profile_id, e.name, e.aliases, e.programs
+			FROM entities e
+			WHERE `+strings.Join(where, " AND ")+filter+`
+			ORDER BY e.name LIMIT ?`, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []Entity
+	for rows.Next() {
+		var e Entity
+		var aliases, programs string
+		if err := rows.Scan(&e.ProfileID, &e.Name, &aliases, &programs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e.Aliases = splitList(aliases, " | ")
+		e.Programs = splitList(programs, ", ")
+		entities = append(entities, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range entities {
+		addrs, err := s.entityAddresses(ctx, entities[i].ProfileID)
+		if err != nil {
+			return nil, err
+		}
+		entities[i].Addresses = addrs
+	}
+	return entities, nil
+}
+
+func (s *Store) entityAddresses(ctx context.Context, profileID string) ([]Result, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT address, currency, source FROM sanctioned_addresses WHERE entity_id = ? ORDER BY currency, address", profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addrs := []Result{}
+	for rows.Next() {
+		r := Result{Sanctioned: true}
+		if err := rows.Scan(&r.Address, &r.Currency, &r.Source); err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, r)
+	}
+	return addrs, rows.Err()
+}
+
+// rebuildFTS repopulates the FTS index from the entities table.
+func rebuildFTS(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM entities_fts"); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO entities_fts(profile_id, name, aliases, programs) SELECT profile_id, name, aliases, programs FROM entities")
+	return err
+}
+
+// ftsQuery quotes each term so user input can't inject FTS5 syntax,
+// and turns it into a prefix match: lazarus -> "lazarus"*
+func ftsQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func splitList(s, sep string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, sep)
+}
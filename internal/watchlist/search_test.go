@@ -0,0 +1,126 @@
+package watchlist
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func profileIDs(entities []Entity) []string {
+	ids := []string{}
+	for _, e := range entities {
+		ids = append(ids, e.ProfileID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TestSearch runs with either backend: FTS5 under -tags sqlite_fts5, the
+// LIKE fallback otherwise.
+func TestSearch(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+	t.Logf("FTS5: %v", s.hasFTS)
+
+	tests := []struct {
+		name       string
+		q          string
+		limit      int
+		cryptoOnly bool
+		want       []string
+	}{
+		{"name", "lazarus", 0, false, []string{"1001"}},
+		{"alias", "cobra", 0, false, []string{"1001"}},
+		{"prefix", "garan", 0, false, []string{"1002"}},
+		{"program", "DPRK", 0, false, []string{"1001", "1003"}},
+		{"crypto only", "DPRK", 0, true, []string{"1001"}},
+		{"limit", "DPRK", 1, false, nil},
+		{"every term", "garantex europe", 0, false, []string{"1002"}},
+		{"terms of different entities", "lazarus garantex", 0, false, []string{}},
+		{"name parts joined", "kim jong", 0, false, []string{"1003"}},
+		{"quote in query", `lazarus"`, 0, false, nil},
+		{"fts syntax as text", "lazarus OR garantex", 0, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Search(context.Background(), tt.q, tt.limit, tt.cryptoOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.limit > 0 && len(got) > tt.limit {
+				t.Errorf("%d results over a limit of %d", len(got), tt.limit)
+			}
+			if tt.want != nil && !reflect.DeepEqual(profileIDs(got), tt.want) {
+				t.Errorf("got %v, want %v", profileIDs(got), tt.want)
+			}
+		})
+	}
+
+	if got, err := s.Search(context.Background(), "  ", 0, false); err != nil || got != nil {
+		t.Errorf("blank query: %v, %v", got, err)
+	}
+}
+
+func TestSearchEntity(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+
+	got, err := s.Search(context.Background(), "lazarus", 0, false)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+	want := Entity{
+		ProfileID: "1001",
+		Name:      "LAZARUS GROUP",
+		Aliases:   []string{"HIDDEN COBRA", "APT38"},
+		Programs:  []string{"DPRK3", "CYBER2"},
+		Addresses: []Result{
+			{Address: lazarusETH, Currency: "ETH", Source: "OFAC", Sanctioned: true},
+			{Address: lazarusBTC, Currency: "XBT", Source: "OFAC", Sanctioned: true},
+		},
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("got  %+v\nwant %+v", got[0], want)
+	}
+}
+
+func TestSearchFTS(t *testing.T) {
+	s := openTestStore(t)
+	if !s.hasFTS {
+		t.Skip("SQLite built without FTS5; run with -tags sqlite_fts5")
+	}
+	syncFixture(t, s)
+
+	// FTS matches word prefixes, not substrings
+	for q, want := range map[string][]string{
+		"laz":   {"1001"},
+		"zarus": {},
+		"apt":   {"1001"},
+	} {
+		got, err := s.Search(context.Background(), q, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(profileIDs(got), want) {
+			t.Errorf("%q: got %v, want %v", q, profileIDs(got), want)
+		}
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{[]string{"lazarus"}, `"lazarus"*`},
+		{[]string{"garantex", "europe"}, `"garantex"* "europe"*`},
+		{[]string{`la"zarus`}, `"la""zarus"*`},
+		{[]string{"OR", "NEAR(x)"}, `"OR"* "NEAR(x)"*`},
+	}
+	for _, tt := range tests {
+		if got := ftsQuery(tt.terms); got != tt.want {
+			t.Errorf("ftsQuery(%q) = %s, want %s", tt.terms, got, tt.want)
+		}
+	}
+}
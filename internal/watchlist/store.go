@@ -0,0 +1,166 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/piyushdaiya/crypto-profiler/pkg/profile"
+)
+
+// Result is the outcome of a single address lookup.
+type Result = profile.Result
+
+// ListVersion identifies the list data a check ran against.
+type ListVersion = profile.ListVersion
+
+// Store wraps the local SQLite sanctions database.
+// It is used by the Watchlist Engine (server) and can be embedded
+// directly by the validator for small, single-binary deployments.
+type Store struct {
+	db     *sql.DB
+	cfg    SyncConfig
+	client *http.Client
+	hasFTS bool // SQLite built with FTS5 (-tags sqlite_fts5)
+}
+
+// Open connects to (and initializes) the watchlist database at path.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping db: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.ConfigureSync(DefaultSyncConfig()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.initSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// --- DATABASE INIT ---
+func (s *Store) initSchema() error {
+	if err := s.migrate(); err != nil {
+		return err
+	}
+
+	// The FTS index is derived data rebuilt on every sync, and FTS5 is only
+	// available with -tags sqlite_fts5, so it lives outside the migrations.
+	// Without it, Search falls back to LIKE matching.
+	_, err := s.db.Exec("CREATE VIRTUAL TABLE IF NOT EXISTS entities_fts USING fts5(profile_id UNINDEXED, name, aliases, programs)")
+	s.hasFTS = err == nil
+	return nil
+}
+
+// --- LOOKUPS ---
+
+// Check looks up a single address. A missing row is not an error;
+// it simply yields Sanctioned=false. A hit linked to an SDN entity also
+// lists the entity's other (co-listed) addresses.
+func (s *Store) Check(address string) (*Result, error) {
+	res := &Result{Address: address}
+
+	var entityID, entityName sql.NullString
+	err := s.db.QueryRow(`
+		SELECT a.currency, a.source, a.entity_id, e.name
+		FROM sanctioned_addresses a LEFT JOIN entities e ON e.profile_id = a.entity_id
+		WHERE a.address = ?`, address).Scan(&res.Currency, &res.Source, &entityID, &entityName)
+	if err == sql.ErrNoRows {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Sanctioned = true
+	if entityID.String == "" {
+		return res, nil
+	}
+	res.EntityID, res.EntityName = entityID.String, entityName.String
+	addrs, err := s.entityAddresses(context.Background(), res.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		if a.Address != address {
+			res.CoListed = append(res.CoListed, a)
+		}
+	}
+	return res, nil
+}
+
+// ListVersion returns the version of the loaded list.
+func (s *Store) ListVersion(ctx context.Context) (ListVersion, error) {
+	var v ListVersion
+	rows, err := s.db.QueryContext(ctx, "SELECT key, value FROM metadata WHERE key IN ('last_modified', 'synced_at')")
+	if err != nil {
+		return v, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return v, err
+		}
+		if key == "last_modified" {
+			v.LastModified = value
+		} else {
+			v.SyncedAt = value
+		}
+	}
+	return v, rows.Err()
+}
+
+// BatchCheck looks up many addresses in a single query.
+// Every input address gets an entry in the returned map.
+func (s *Store) BatchCheck(addresses []string) (map[string]*Result, error) {
+	results := make(map[string]*Result, len(addresses))
+	if len(addresses) == 0 {
+		return results, nil
+	}
+
+	args := make([]interface{}, len(addresses))
+	for i, addr := range addresses {
+		args[i] = addr
+		results[addr] = &Result{Address: addr}
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(addresses)), ",")
+	rows, err := s.db.Query("SELECT address, currency, source FROM sanctioned_addresses WHERE address IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var addr, currency, source string
+		if err := rows.Scan(&addr, &currency, &source); err != nil {
+			return nil, err
+		}
+		results[addr] = &Result{
+			Address:    addr,
+			Sanctioned: true,
+			Currency:   currency,
+			Source:     source,
+		}
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,136 @@
+package watchlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Addresses in testdata/sdn_advanced.xml
+const (
+	lazarusETH  = "0x098B716B8Aaf21512996dC57EB0615e2383E2f96"
+	lazarusBTC  = "bc1qa5wkgaew2dkv56kfvj49j0av5nml45x9ek9hz6"
+	garantexETH = "0x7FF9cFad3877F21d41Da833E2F775dB0569eE3D9"
+	garantexXYZ = "xyz1qqqqqqqqqqqqqqqqqqqqqq" // A currency Sync learns from the file
+	fixtureDate = "Tue, 01 Oct 2024 12:00:00 GMT"
+)
+
+// openTestStore opens a new database in a temporary directory.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "watchlist.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// serveFixture serves testdata/sdn_advanced.xml, modified at lastMod.
+func serveFixture(t *testing.T, lastMod string) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile("testdata/sdn_advanced.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastMod)
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// syncFixture loads testdata/sdn_advanced.xml into s.
+func syncFixture(t *testing.T, s *Store) {
+	t.Helper()
+	if err := s.ConfigureSync(SyncConfig{SourceURL: serveFixture(t, fixtureDate).URL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+
+	res, err := s.Check(lazarusETH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Sanctioned || res.Currency != "ETH" || res.Source != "OFAC" || res.EntityID != "1001" || res.EntityName != "LAZARUS GROUP" {
+		t.Errorf("got %+v", res)
+	}
+	if len(res.CoListed) != 1 || res.CoListed[0].Address != lazarusBTC || res.CoListed[0].Currency != "XBT" {
+		t.Errorf("co-listed %+v, want the entity's BTC address only", res.CoListed)
+	}
+
+	// Learned from its FeatureTypeValue, not the built-in IDs
+	if res, err := s.Check(garantexXYZ); err != nil || !res.Sanctioned || res.Currency != "XYZ" {
+		t.Errorf("learned currency: %+v, %v", res, err)
+	}
+
+	for _, address := range []string{
+		"0x0000000000000000000000000000000000000000",
+		"0x123",                   // Too short to be an address; skipped
+		"Pyongyang, Korea, North", // Not a currency feature
+		"0x098b716b8aaf21512996dc57eb0615e2383e2f96", // Case matters to the store; callers normalize
+	} {
+		res, err := s.Check(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Sanctioned || res.Address != address {
+			t.Errorf("%s: got %+v", address, res)
+		}
+	}
+}
+
+func TestBatchCheck(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+
+	clean := "0x0000000000000000000000000000000000000000"
+	results, err := s.BatchCheck([]string{lazarusETH, clean, garantexETH, lazarusETH})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("got %d results, want one per distinct address", len(results))
+	}
+	if r := results[lazarusETH]; !r.Sanctioned || r.Currency != "ETH" {
+		t.Errorf("%s: %+v", lazarusETH, r)
+	}
+	if r := results[garantexETH]; !r.Sanctioned {
+		t.Errorf("%s: %+v", garantexETH, r)
+	}
+	if r := results[clean]; r == nil || r.Sanctioned || r.Address != clean {
+		t.Errorf("%s: %+v", clean, r)
+	}
+
+	if results, err := s.BatchCheck(nil); err != nil || len(results) != 0 {
+		t.Errorf("empty batch: %v, %v", results, err)
+	}
+}
+
+func TestListVersion(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	if v, err := s.ListVersion(ctx); err != nil || v != (ListVersion{}) {
+		t.Fatalf("before the first sync: %+v, %v", v, err)
+	}
+	syncFixture(t, s)
+	v, err := s.ListVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.LastModified != fixtureDate || v.SyncedAt == "" {
+		t.Errorf("got %+v", v)
+	}
+}
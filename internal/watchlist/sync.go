@@ -0,0 +1,371 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OFACAdvancedURL is the official SDN "advanced" XML feed.
+const OFACAdvancedURL = "https://www.treasury.gov/ofac/downloads/sanctions/1.0/sdn_advanced.xml"
+
+// SyncConfig controls where and how the OFAC list is fetched.
+// Air-gapped deployments point SourceURL at an internal mirror.
+type SyncConfig struct {
+	SourceURL   string        // OFAC SDN advanced XML (or a mirror)
+	HTTPTimeout time.Duration // Applies to the HEAD check and the full download; 0 = no limit
+	ProxyURL    string        // Optional outbound proxy; empty falls back to HTTPS_PROXY/HTTP_PROXY
+}
+
+// DefaultSyncConfig returns the settings used when nothing is configured.
+func DefaultSyncConfig() SyncConfig {
+	return SyncConfig{
+		SourceURL:   OFACAdvancedURL,
+		HTTPTimeout: 10 * time.Minute,
+	}
+}
+
+// ConfigureSync replaces the store's sync settings.
+func (s *Store) ConfigureSync(cfg SyncConfig) error {
+	if cfg.SourceURL == "" {
+		cfg.SourceURL = OFACAdvancedURL
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	s.cfg = cfg
+	s.client = &http.Client{Timeout: cfg.HTTPTimeout, Transport: transport}
+	return nil
+}
+
+// NeedsUpdate compares the remote Last-Modified header with the one
+// recorded during the previous sync.
+func (s *Store) NeedsUpdate(ctx context.Context) bool {
+	var localLastMod string
+	_ = s.db.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key='last_modified'").Scan(&localLastMod)
+
+	// Databases synced before entity names were stored need a full reload
+	var entityCount int
+	_ = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM entities").Scan(&entityCount)
+	if entityCount == 0 {
+		return true
+	}
+
+	// The HEAD check should be quick even when the download timeout is generous
+	headCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(headCtx, http.MethodHead, s.cfg.SourceURL, nil)
+	if err != nil {
+		return true
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		slog.Warn("⚠️ [SYNC] Could not check remote headers", "err", err)
+		return true // Fail open
+	}
+	defer resp.Body.Close()
+
+	remoteLastMod := resp.Header.Get("Last-Modified")
+	return localLastMod != remoteLastMod
+}
+
+// --- XML STRUCTURES ---
+
+// Flattened Reference Value
+type FeatureTypeValue struct {
+	ID    string `xml:"ID,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Distinct Party (The Sanctioned Person)
+type DistinctParty struct {
+	FixedRef string    `xml:"FixedRef,attr"`
+	Profile  []Profile `xml:"Profile"`
+}
+type Profile struct {
+	ID       string     `xml:"ID,attr"`
+	Identity []Identity `xml:"Identity"`
+	Feature  []Feature  `xml:"Feature"`
+}
+type Feature struct {
+	FeatureTypeID string           `xml:"FeatureTypeID,attr"`
+	Version       []FeatureVersion `xml:"FeatureVersion"`
+}
+type FeatureVersion struct {
+	VersionDetail []VersionDetail `xml:"VersionDetail"`
+}
+type VersionDetail struct {
+	Value string `xml:",chardata"`
+}
+
+// Names (Identity -> Alias -> DocumentedName -> NamePartValue)
+type Identity struct {
+	Alias []Alias `xml:"Alias"`
+}
+type Alias struct {
+	Primary        bool             `xml:"Primary,attr"`
+	DocumentedName []DocumentedName `xml:"DocumentedName"`
+}
+type DocumentedName struct {
+	NamePart []DocumentedNamePart `xml:"DocumentedNamePart"`
+}
+type DocumentedNamePart struct {
+	Value []string `xml:"NamePartValue"`
+}
+
+// Sanctions Entry (links a Profile to its programs, e.g. "DPRK3", "CYBER2")
+type SanctionsEntry struct {
+	ProfileID string             `xml:"ProfileID,attr"`
+	Measure   []SanctionsMeasure `xml:"SanctionsMeasure"`
+}
+type SanctionsMeasure struct {
+	Comment string `xml:"Comment"`
+}
+
+// names returns the primary name and every other alias of a profile.
+func (p Profile) names() (primary string, aliases []string) {
+	for _, id := range p.Identity {
+		for _, a := range id.Alias {
+			for _, dn := range a.DocumentedName {
+				var parts []string
+				for _, np := range dn.NamePart {
+					for _, v := range np.Value {
+						if v = strings.TrimSpace(v); v != "" {
+							parts = append(parts, v)
+						}
+					}
+				}
+				name := strings.Join(parts, " ")
+				if name == "" {
+					continue
+				}
+				if a.Primary && primary == "" {
+					primary = name
+				} else {
+					aliases = append(aliases, name)
+				}
+			}
+		}
+	}
+	if primary == "" && len(aliases) > 0 {
+		primary, aliases = aliases[0], aliases[1:]
+	}
+	return primary, aliases
+}
+
+// Sync downloads the OFAC SDN list and upserts every digital currency
+// address it finds. The whole load runs in one transaction: if ctx is
+// cancelled mid-way the transaction is rolled back and the previous
+// data set stays intact.
+func (s *Store) Sync(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.SourceURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("source returned HTTP %d", resp.StatusCode)
+	}
+
+	lastMod := resp.Header.Get("Last-Modified")
+	slog.Info("🔹 [SYNC] Header Last-Modified", "last_modified", lastMod)
+
+	decoder := xml.NewDecoder(resp.Body)
+
+	// PRE-FILL MAP with known IDs provided by user
+	cryptoTypeMap := map[string]string{
+		"344":  "XBT",
+		"345":  "ETH",
+		"686":  "ZEC",
+		"687":  "DASH",
+		"688":  "BTG",
+		"689":  "ETC",
+		"706":  "BSV",
+		"726":  "BCH",
+		"746":  "XVG",
+		"992":  "TRX",
+		"998":  "USDC",
+		"1007": "ARB",
+		"1008": "BSC",
+		"1167": "SOL",
+		// Additional IDs often found in OFAC data
+		"573": "XMR",
+		"572": "LTC",
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT OR REPLACE INTO sanctioned_addresses(address, currency, source, updated_at, entity_id) VALUES(?, ?, 'OFAC', ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	entityStmt, err := tx.PrepareContext(ctx, "INSERT OR REPLACE INTO entities(profile_id, name, aliases, programs, updated_at) VALUES(?, ?, ?, '', ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer entityStmt.Close()
+
+	// Programs live in <SanctionsEntries>, after all parties; applied at the end
+	programs := map[string][]string{}
+
+	now := time.Now()
+	count := 0
+	loaded := 0
+
+	slog.Info("🔹 [SYNC] Parsing XML Stream...")
+
+	for {
+		// Bail out between elements on shutdown; nothing is committed yet
+		if err := ctx.Err(); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// A body cut short is an error, not the end of the list
+		t, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			tx.Rollback()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("reading source: %w", err)
+		}
+
+		switch se := t.(type) {
+		case xml.StartElement:
+
+			// STEP 1: Catch "FeatureTypeValue" (Dynamic Learning)
+			// We still listen for these to catch any NEW currencies OFAC might add in the future
+			if se.Name.Local == "FeatureTypeValue" {
+				var ft FeatureTypeValue
+				if err := decoder.DecodeElement(&ft, &se); err != nil {
+					continue
+				}
+
+				if strings.Contains(ft.Value, "Digital Currency Address") {
+					parts := strings.Split(ft.Value, "-")
+					currency := "UNKNOWN"
+					if len(parts) > 1 {
+						currency = strings.TrimSpace(parts[1])
+					}
+					// Only add if we don't already have it hardcoded
+					if _, exists := cryptoTypeMap[ft.ID]; !exists {
+						cryptoTypeMap[ft.ID] = currency
+						slog.Debug("🔹 [SYNC] Learned new currency", "id", ft.ID, "currency", currency)
+					}
+				}
+			}
+
+			// STEP 2: Scan Parties
+			if se.Name.Local == "DistinctParty" {
+				var p DistinctParty
+				if err := decoder.DecodeElement(&p, &se); err != nil {
+					continue
+				}
+
+				for _, profile := range p.Profile {
+					entityID := profile.ID
+					if entityID == "" {
+						entityID = p.FixedRef
+					}
+					name, aliases := profile.names()
+					_, _ = entityStmt.ExecContext(ctx, entityID, name, strings.Join(aliases, " | "), now)
+
+					for _, feature := range profile.Feature {
+						// Is this FeatureID in our crypto map?
+						if currency, isCrypto := cryptoTypeMap[feature.FeatureTypeID]; isCrypto {
+							for _, v := range feature.Version {
+								for _, d := range v.VersionDetail {
+									addr := strings.TrimSpace(d.Value)
+									if len(addr) > 10 {
+										_, err = stmt.ExecContext(ctx, addr, currency, now, entityID)
+										if err == nil {
+											loaded++
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+				count++
+				if count%10000 == 0 {
+					slog.Debug("🔹 [SYNC] Scanned Parties...", "count", count)
+				}
+			}
+
+			// STEP 3: Collect Programs per Profile
+			if se.Name.Local == "SanctionsEntry" {
+				var e SanctionsEntry
+				if err := decoder.DecodeElement(&e, &se); err != nil {
+					continue
+				}
+				for _, m := range e.Measure {
+					if c := strings.TrimSpace(m.Comment); c != "" {
+						programs[e.ProfileID] = append(programs[e.ProfileID], c)
+					}
+				}
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for profileID, progs := range programs {
+		_, _ = tx.ExecContext(ctx, "UPDATE entities SET programs = ? WHERE profile_id = ?", strings.Join(progs, ", "), profileID)
+	}
+
+	if s.hasFTS {
+		if err := rebuildFTS(ctx, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	_, _ = tx.ExecContext(ctx, "INSERT OR REPLACE INTO metadata(key, value) VALUES('last_modified', ?)", lastMod)
+	_, _ = tx.ExecContext(ctx, "INSERT OR REPLACE INTO metadata(key, value) VALUES('synced_at', ?)", time.Now().UTC().Format(time.RFC3339))
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	slog.Info("✅ [SYNC] Done", "parties", count, "addresses", loaded)
+
+	if loaded == 0 {
+		slog.Warn("⚠️ [SYNC] 0 addresses loaded. Double check FeatureType IDs.")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// partialFixture returns the fixture with lazarusETH replaced by address,
+// cut off before the second party.
+func partialFixture(t *testing.T, address string) []byte {
+	t.Helper()
+	body, err := os.ReadFile("testdata/sdn_advanced.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = bytes.ReplaceAll(body, []byte(lazarusETH), []byte(address))
+	cut := bytes.Index(body, []byte(`<DistinctParty FixedRef="1002">`))
+	if cut < 0 {
+		t.Fatal("fixture has no second party")
+	}
+	return body[:cut]
+}
+
+// assertFixtureIntact fails unless s still holds the synced fixture and
+// nothing of a later, failed sync that listed address.
+func assertFixtureIntact(t *testing.T, s *Store, address string) {
+	t.Helper()
+	if res, err := s.Check(address); err != nil || res.Sanctioned {
+		t.Errorf("failed sync left %s behind: %+v, %v", address, res, err)
+	}
+	if res, err := s.Check(lazarusETH); err != nil || !res.Sanctioned {
+		t.Errorf("failed sync lost %s: %+v, %v", lazarusETH, res, err)
+	}
+	if v, err := s.ListVersion(context.Background()); err != nil || v.LastModified != fixtureDate {
+		t.Errorf("list version after a failed sync: %+v, %v", v, err)
+	}
+}
+
+func TestSyncCancelled(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+
+	// Sends part of a newer list, then stalls until the request is gone
+	const newAddress = "0x1111111111111111111111111111111111111111"
+	body := partialFixture(t, newAddress)
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 02 Oct 2024 12:00:00 GMT")
+		w.Write(body)
+		w.(http.Flusher).Flush()
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	if err := s.ConfigureSync(SyncConfig{SourceURL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if err := s.Sync(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	assertFixtureIntact(t, s, newAddress)
+}
+
+func TestSyncLoop(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.ConfigureSync(SyncConfig{SourceURL: serveFixture(t, fixtureDate).URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.SyncLoop(ctx, time.Hour)
+		close(done)
+	}()
+
+	// The first sync runs straight away, not after an interval
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if v, _ := s.ListVersion(context.Background()); v.LastModified == fixtureDate {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no sync at start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SyncLoop still running after cancel")
+	}
+}
+
+func TestSyncBodyCutShort(t *testing.T) {
+	s := openTestStore(t)
+	syncFixture(t, s)
+
+	// Sends part of a newer list, then drops the connection
+	const newAddress = "0x2222222222222222222222222222222222222222"
+	body := partialFixture(t, newAddress)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 02 Oct 2024 12:00:00 GMT")
+		w.Write(body)
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}))
+	defer srv.Close()
+	if err := s.ConfigureSync(SyncConfig{SourceURL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Sync(context.Background()); err == nil {
+		t.Fatal("sync of a truncated body succeeded")
+	}
+	assertFixtureIntact(t, s, newAddress)
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// SCREENING JOBS (large batches in the background, serve mode)
+// ---------------------------------------------------------
+
+const (
+	// Addresses accepted per job, unless SERVE_MAX_JOB says otherwise
+	defaultMaxJob = 10000
+	// How long a finished job's results are kept, unless SERVE_JOB_TTL says otherwise
+	defaultJobTTL = 24 * time.Hour
+)
+
+// Job states
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobDone      = "done"
+	jobCancelled = "cancelled"
+)
+
+// jobQueue runs screening jobs one at a time, in the order they were
+// submitted, each with the batch worker pool. One at a time is as fast
+// as several: they would share the same provider rate limits. Jobs live
+// in memory, so a restart loses them.
+type jobQueue struct {
+	server *profileServer
+	max    int           // Addresses per job
+	ttl    time.Duration // Finished jobs are dropped this long after
+
+	mu    sync.Mutex
+	jobs  map[string]*screeningJob
+	queue chan *screeningJob
+}
+
+// screeningJob is one submitted batch.
+type screeningJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	Count      int        `json:"count"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	addresses []string
+	chain     validator.ChainStrategy
+	probe     bool
+	cancel    context.CancelFunc
+	progress  *batchProgress
+	final     *progressStatus
+	profiles  []*validator.WalletProfile // In request order, as far as done
+	summary   batchSummary
+}
+
+// jobResponse is GET /v1/jobs/{id}: the job, its progress, and its
+// profiles from offset on (up to limit).
+type jobResponse struct {
+	*screeningJob
+	Progress progressStatus             `json:"progress"`
+	Summary  *batchSummary              `json:"summary,omitempty"` // Once done
+	Offset   int                        `json:"offset"`
+	Profiles []*validator.WalletProfile `json:"profiles"`
+}
+
+// newJobQueue takes jobs of up to max addresses (SERVE_MAX_JOB), kept
+// for ttl once done (SERVE_JOB_TTL), and starts the runner, which stops
+// with ctx.
+func newJobQueue(ctx context.Context, s *profileServer, max int, ttl time.Duration) *jobQueue {
+	if max < 1 {
+		logging.Fatal("Invalid SERVE_MAX_JOB", "value", max)
+	}
+	q := &jobQueue{server: s, max: max, ttl: ttl, jobs: map[string]*screeningJob{}, queue: make(chan *screeningJob, 1024)}
+	go q.run(ctx)
+	return q
+}
+
+// run screens the queued jobs until ctx is done; a job still running
+// then is cancelled.
+func (q *jobQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.queue:
+			q.screen(ctx, job)
+		}
+	}
+}
+
+func (q *jobQueue) screen(ctx context.Context, job *screeningJob) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	q.mu.Lock()
+	if job.Status != jobQueued { // Cancelled while queued
+		q.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	job.Status, job.StartedAt, job.cancel = jobRunning, &now, cancel
+	job.progress.begin(len(job.addresses))
+	q.publish(job)
+	q.mu.Unlock()
+	slog.Info("📋 [JOBS] Started", "id", job.ID, "addresses", len(job.addresses))
+
+	s := q.server
+	start := time.Now()
+	screen := func(ctx context.Context, address string) *validator.WalletProfile {
+		p := s.screen(ctx, address, job.probe, job.chain, job.ID)
+		job.progress.add(address, p, false)
+		return p
+	}
+	defer primeWatchlist(ctx, job.addresses, job.chain)()
+	screenPool(ctx, job.addresses, s.workers, job.chain, screen, func(p *validator.WalletProfile) {
+		q.mu.Lock()
+		job.profiles = append(job.profiles, p)
+		job.summary.add(p)
+		q.mu.Unlock()
+	})
+	job.progress.end()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	finished := time.Now().UTC()
+	final := job.progress.current()
+	final.Event, final.Address = "done", ""
+	job.FinishedAt, job.final = &finished, &final
+	job.summary.Duration = time.Since(start).Round(time.Millisecond).String()
+	switch {
+	case job.Status != jobRunning: // Cancelled by DELETE
+	case ctx.Err() != nil: // The server is stopping
+		job.Status = jobCancelled
+	default:
+		job.Status = jobDone
+	}
+	q.publish(job)
+	slog.Info("✅ [JOBS] Finished", "id", job.ID, "status", job.Status, "duration", job.summary.Duration)
+}
+
+// sweep drops jobs that finished more than ttl ago. Callers hold q.mu.
+func (q *jobQueue) sweep() {
+	for id, job := range q.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > q.ttl {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// POST /v1/jobs {"addresses": ["0x...", "bc1..."], "chain": "", "probe": false}
+// Queues up to SERVE_MAX_JOB addresses and answers 202 with the job at
+// once; GET /v1/jobs/{id} follows it.
+func (q *jobQueue) createHandler(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	var addresses []string
+	for _, a := range req.Addresses {
+		if a = strings.TrimSpace(a); a != "" {
+			addresses = append(addresses, a)
+		}
+	}
+	if len(addresses) == 0 {
+		http.Error(w, "Missing addresses", http.StatusBadRequest)
+		return
+	}
+	if len(addresses) > q.max {
+		http.Error(w, fmt.Sprintf("Too many addresses: %d (max %d)", len(addresses), q.max), http.StatusRequestEntityTooLarge)
+		return
+	}
+	chain, err := q.server.requestChain(req.Chain)
+	if err != nil {
+		http.Error(w, "Invalid chain: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := make([]byte, 12)
+	rand.Read(id)
+	job := &screeningJob{
+		ID:        hex.EncodeToString(id),
+		Status:    jobQueued,
+		Count:     len(addresses),
+		CreatedAt: time.Now().UTC(),
+		addresses: addresses,
+		chain:     chain,
+		probe:     req.Probe,
+		progress:  &batchProgress{},
+		summary:   batchSummary{Grades: map[string]int{}, Workers: min(q.server.workers, len(addresses))},
+	}
+	q.mu.Lock()
+	q.sweep()
+	select {
+	case q.queue <- job:
+		q.jobs[job.ID] = job
+	default:
+		q.mu.Unlock()
+		http.Error(w, "Too many queued jobs", http.StatusServiceUnavailable)
+		return
+	}
+	resp := q.response(job, 0, 0)
+	q.publish(job)
+	q.mu.Unlock()
+
+	slog.Info("📋 [JOBS] Queued", "id", job.ID, "addresses", len(addresses))
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, resp)
+}
+
+// GET /v1/jobs/{id}[?offset=0&limit=1000]
+// The job's status and progress, and the profiles done so far, in
+// request order; offset and limit page through them (limit=0: none).
+func (q *jobQueue) getHandler(w http.ResponseWriter, r *http.Request) {
+	offset, limit := 0, -1
+	for name, dst := range map[string]*int{"offset": &offset, "limit": &limit} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	q.mu.Lock()
+	q.sweep()
+	job := q.jobs[r.PathValue("id")]
+	if job == nil {
+		q.mu.Unlock()
+		http.Error(w, "Unknown job", http.StatusNotFound)
+		return
+	}
+	resp := q.response(job, offset, limit)
+	q.mu.Unlock()
+	writeJSON(w, resp)
+}
+
+// DELETE /v1/jobs/{id}
+// Cancels a queued or running job, keeping the profiles done so far, or
+// deletes a finished one with its results (204).
+func (q *jobQueue) cancelHandler(w http.ResponseWriter, r *http.Request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[r.PathValue("id")]
+	if job == nil {
+		http.Error(w, "Unknown job", http.StatusNotFound)
+		return
+	}
+	switch job.Status {
+	case jobQueued:
+		now := time.Now().UTC()
+		job.Status, job.FinishedAt = jobCancelled, &now
+		q.publish(job)
+	case jobRunning: // Published when its screening stops
+		job.Status = jobCancelled
+		job.cancel()
+	default:
+		delete(q.jobs, job.ID)
+		slog.Info("📋 [JOBS] Deleted", "id", job.ID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	slog.Info("📋 [JOBS] Cancelled", "id", job.ID)
+	writeJSON(w, q.response(job, 0, 0))
+}
+
+// publish sends the job's status and progress, without profiles, to the
+// event stream. Callers hold q.mu.
+func (q *jobQueue) publish(job *screeningJob) {
+	q.server.stream.publish(eventJob, job.ID, q.response(job, 0, 0))
+}
+
+// response copies what a client sees of job. Callers hold q.mu.
+func (q *jobQueue) response(job *screeningJob, offset, limit int) jobResponse {
+	copied := *job
+	resp := jobResponse{screeningJob: &copied, Offset: offset, Profiles: []*validator.WalletProfile{}}
+	switch {
+	case job.final != nil:
+		resp.Progress = *job.final
+	case job.StartedAt != nil:
+		resp.Progress = job.progress.current()
+	default:
+		resp.Progress = progressStatus{Event: "progress", Total: job.Count, Chains: map[string]int{}}
+	}
+	if job.Status == jobDone {
+		summary := job.summary
+		resp.Summary = &summary
+	}
+	if offset < len(job.profiles) {
+		end := len(job.profiles)
+		if limit >= 0 {
+			end = min(end, offset+limit)
+		}
+		resp.Profiles = append(resp.Profiles, job.profiles[offset:end]...)
+	}
+	return resp
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/config"
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/segmentio/kafka-go"
+)
+
+// ---------------------------------------------------------
+// KAFKA WORKER (screening requests from a Kafka topic)
+// ---------------------------------------------------------
+
+const (
+	defaultKafkaGroup       = "profiler"
+	defaultKafkaTopic       = "screening.requests"
+	defaultKafkaStartOffset = "earliest"
+	// Attempts at a failing screening before it is dead-lettered
+	defaultKafkaMaxAttempts = 5
+	// Wait before the first retry; the nth waits n times as long
+	defaultKafkaRetryDelay = 10 * time.Second
+	// Without a heartbeat for this long, a member loses its partitions
+	kafkaSessionTimeout = 30 * time.Second
+	kafkaHeartbeatEvery = 3 * time.Second
+	// How often finished records are committed
+	kafkaCommitEvery = time.Second
+	// How long one fetch waits for records, and a produced batch for more
+	kafkaFetchWait = 2 * time.Second
+	kafkaBatchWait = 10 * time.Millisecond
+	// Bounds on producing results, and on the commit when a session ends
+	kafkaProduceTimeout = 30 * time.Second
+	kafkaCommitTimeout  = 10 * time.Second
+)
+
+// kafkaWorker screens the addresses produced to a Kafka topic: members
+// of one consumer group share its partitions, hand their records to the
+// batch worker pool, and commit a partition's offset only past records
+// whose profile and alerts are produced, so a crash or a rebalance
+// re-screens rather than loses them (at-least-once). Screenings with
+// provider errors are retried in place with a growing delay, then
+// dead-lettered.
+type kafkaWorker struct {
+	brokers     []string
+	tls         bool
+	group       string
+	topic       string
+	profiles    string // Output topics
+	alerts      string
+	dead        string
+	latest      bool // Without a committed offset, start at the end
+	maxAttempts int
+	retryDelay  time.Duration
+
+	probe   bool
+	testnet bool
+	chain   validator.ChainStrategy // --chain; a message's "chain" overrides it
+	evm     *validator.EVMStrategy
+	workers int
+	timeout time.Duration // Per address
+}
+
+// kafkaDeadLetter is a record that will not be screened: malformed, or
+// still failing after the last attempt.
+type kafkaDeadLetter struct {
+	Message   string                   `json:"message"` // As received
+	Topic     string                   `json:"topic"`
+	Partition int                      `json:"partition"`
+	Offset    int64                    `json:"offset"`
+	Attempts  int                      `json:"attempts"`
+	Error     string                   `json:"error"`
+	Profile   *validator.WalletProfile `json:"profile,omitempty"` // The last, incomplete attempt
+	At        time.Time                `json:"at"`
+}
+
+// newKafkaWorker takes KAFKA_BROKERS (required) and the other KAFKA_*
+// settings.
+func newKafkaWorker(cfg *config.Config) *kafkaWorker {
+	k := cfg.Kafka
+	w := &kafkaWorker{
+		tls:         k.TLS,
+		group:       k.Group,
+		topic:       k.Topic,
+		profiles:    k.ProfilesTopic,
+		alerts:      k.AlertsTopic,
+		dead:        k.DeadTopic,
+		maxAttempts: k.MaxAttempts,
+		retryDelay:  k.RetryDelay,
+	}
+	for _, b := range strings.Split(k.Brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			w.brokers = append(w.brokers, b)
+		}
+	}
+	if len(w.brokers) == 0 {
+		logging.Fatal("kafka needs KAFKA_BROKERS (host:9092[,host2:9092])")
+	}
+	for _, t := range []string{w.group, w.topic, w.profiles, w.alerts, w.dead} {
+		if strings.TrimSpace(t) == "" {
+			logging.Fatal("kafka needs a group and every topic (KAFKA_GROUP, KAFKA_*TOPIC)")
+		}
+	}
+	switch k.StartOffset {
+	case "earliest":
+	case "latest":
+		w.latest = true
+	default:
+		logging.Fatal("Invalid KAFKA_START_OFFSET (earliest or latest)", "value", k.StartOffset)
+	}
+	if w.maxAttempts < 1 {
+		logging.Fatal("Invalid KAFKA_MAX_ATTEMPTS", "value", w.maxAttempts)
+	}
+	if w.retryDelay <= 0 {
+		logging.Fatal("Invalid KAFKA_RETRY_DELAY", "value", w.retryDelay)
+	}
+	return w
+}
+
+// runKafka consumes until SIGINT/SIGTERM, reconnecting with a growing
+// backoff whenever the cluster can't be reached. On a signal it stops
+// fetching, lets the screenings in flight finish and be committed, and
+// leaves the group.
+func runKafka(w *kafkaWorker) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backoff := time.Second
+	for {
+		connected, err := w.consume(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if connected {
+			backoff = time.Second
+		}
+		slog.Warn("⚠️ [KAFKA] Disconnected, retrying", "err", err, "in", backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+	slog.Info("🛑 [KAFKA] Worker stopped")
+}
+
+// consume checks the topics, joins the group and screens records until
+// ctx is done or a fetch fails for good; the reader itself follows
+// rebalances and moved leaders. connected reports whether it got as far
+// as consuming.
+func (w *kafkaWorker) consume(ctx context.Context) (connected bool, err error) {
+	var tlsConfig *tls.Config
+	if w.tls {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport := &kafka.Transport{TLS: tlsConfig}
+	defer transport.CloseIdleConnections()
+	if err := w.checkTopics(ctx, transport); err != nil {
+		return false, err
+	}
+
+	startOffset := kafka.FirstOffset
+	if w.latest {
+		startOffset = kafka.LastOffset
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:           w.brokers,
+		GroupID:           w.group,
+		Topic:             w.topic,
+		Dialer:            &kafka.Dialer{Timeout: 10 * time.Second, DualStack: true, TLS: tlsConfig},
+		StartOffset:       startOffset,
+		MaxWait:           kafkaFetchWait,
+		SessionTimeout:    kafkaSessionTimeout,
+		HeartbeatInterval: kafkaHeartbeatEvery,
+		RebalanceTimeout:  w.timeout + time.Minute,
+		Logger:            kafka.LoggerFunc(kafkaLogger(slog.LevelDebug)),
+		ErrorLogger:       kafka.LoggerFunc(kafkaLogger(slog.LevelWarn)),
+	})
+	// Murmur2 on the key, as the Java client partitions
+	producer := &kafka.Writer{
+		Addr:         kafka.TCP(w.brokers...),
+		Balancer:     &kafka.Murmur2Balancer{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: kafkaBatchWait,
+		Transport:    transport,
+	}
+	slog.Info("✅ [KAFKA] Consuming", "topic", w.topic, "group", w.group, "workers", w.workers, "profiles", w.profiles, "alerts", w.alerts, "dead", w.dead)
+
+	offsets := newKafkaOffsets()
+	commit := func(ctx context.Context) {
+		pending := offsets.uncommitted()
+		if len(pending) == 0 {
+			return
+		}
+		// The reader commits after the message it is given
+		msgs := make([]kafka.Message, 0, len(pending))
+		for p, at := range pending {
+			msgs = append(msgs, kafka.Message{Topic: w.topic, Partition: p, Offset: at - 1})
+		}
+		if err := reader.CommitMessages(ctx, msgs...); err != nil {
+			slog.Warn("⚠️ [KAFKA] Commit failed", "err", err)
+			return
+		}
+		offsets.committed(pending)
+	}
+	work := context.WithoutCancel(ctx)
+	commits, stopCommits := context.WithCancel(ctx)
+	defer stopCommits()
+	go func() {
+		t := time.NewTicker(kafkaCommitEvery)
+		defer t.Stop()
+		for {
+			select {
+			case <-commits.Done():
+				return
+			case <-t.C:
+				commit(commits)
+			}
+		}
+	}()
+
+	// Screenings in flight finish after a signal, so they can be
+	// committed before the reader leaves the group; only their retry waits
+	// are cut short
+	slots := make(chan struct{}, w.workers)
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		cctx, cancel := context.WithTimeout(work, kafkaCommitTimeout)
+		defer cancel()
+		commit(cctx)
+		if err := reader.Close(); err != nil {
+			slog.Warn("⚠️ [KAFKA] Leaving the group failed", "err", err)
+		}
+		producer.Close()
+	}()
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return true, nil
+		}
+		m, err := reader.FetchMessage(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			return true, err
+		}
+		offsets.start(m.Partition, m.Offset)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if w.handle(work, ctx, producer, m) {
+				offsets.finish(m.Partition, m.Offset)
+			}
+		}()
+	}
+}
+
+// checkTopics fails unless the input and output topics all exist.
+func (w *kafkaWorker) checkTopics(ctx context.Context, transport *kafka.Transport) error {
+	client := &kafka.Client{Addr: kafka.TCP(w.brokers...), Timeout: kafkaCommitTimeout, Transport: transport}
+	meta, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{w.topic, w.profiles, w.alerts, w.dead}})
+	if err != nil {
+		return err
+	}
+	for _, t := range meta.Topics {
+		if t.Error != nil {
+			return fmt.Errorf("topic %s: %w", t.Name, t.Error)
+		}
+	}
+	return nil
+}
+
+// kafkaLogger logs the reader's messages at level.
+func kafkaLogger(level slog.Level) func(string, ...any) {
+	return func(msg string, args ...any) {
+		slog.Log(context.Background(), level, "🔌 [KAFKA] "+fmt.Sprintf(msg, args...))
+	}
+}
+
+// handle screens one record, retrying a screening with provider errors,
+// and produces its results or dead-letters it. It reports whether the
+// record is done, so its offset may be committed; it isn't when retry
+// ends (a rebalance or a signal) before its results are produced.
+func (w *kafkaWorker) handle(ctx, retry context.Context, producer *kafka.Writer, r kafka.Message) bool {
+	source := recordSource(r)
+	req, err := parseQueueMessage(r.Value)
+	if err != nil {
+		return w.deadLetter(ctx, retry, producer, r, 0, err.Error(), nil)
+	}
+	chain := w.chain
+	if strings.TrimSpace(req.Chain) != "" {
+		if chain, err = forceChain(req.Chain, w.evm, w.testnet); err != nil {
+			return w.deadLetter(ctx, retry, producer, r, 0, "Invalid chain: "+err.Error(), nil)
+		}
+	}
+
+	var p *validator.WalletProfile
+	for attempt := 1; ; attempt++ {
+		p = screenAddress(ctx, req.Address, w.probe || req.Probe, w.testnet, chain, w.timeout, slog.LevelDebug)
+		if finished(p) {
+			break
+		}
+		errs := strings.Join(validator.ProviderErrors(p), " | ")
+		if attempt >= w.maxAttempts {
+			return w.deadLetter(ctx, retry, producer, r, attempt, fmt.Sprintf("Provider errors after %d attempts: %s", attempt, errs), p)
+		}
+		delay := w.retryDelay * time.Duration(attempt)
+		slog.Warn("🔁 [KAFKA] Screening incomplete, retrying", "address", req.Address, "attempt", attempt, "in", delay, "errors", errs)
+		if !sleepCtx(retry, delay) {
+			return false
+		}
+	}
+
+	msgs := []kafka.Message{kafkaMessage(w.profiles, p.Address, source, p)}
+	for _, a := range p.Alerts {
+		msgs = append(msgs, kafkaMessage(w.alerts, p.Address, source, queueAlert{Address: p.Address, Network: p.Network, Alert: a}))
+	}
+	published := w.produce(ctx, retry, producer, "profile", func(ctx context.Context) error {
+		return producer.WriteMessages(ctx, msgs...)
+	})
+	if !published {
+		return false
+	}
+	slog.Info("📤 [KAFKA] Screened", "address", req.Address, "network", p.Network, "score", p.RiskScore, "grade", p.RiskGrade, "alerts", len(p.Alerts), "source", source)
+	return true
+}
+
+// deadLetter produces a record that won't be screened to the dead topic.
+func (w *kafkaWorker) deadLetter(ctx, retry context.Context, producer *kafka.Writer, r kafka.Message, attempts int, reason string, p *validator.WalletProfile) bool {
+	d := kafkaDeadLetter{Message: string(r.Value), Topic: r.Topic, Partition: r.Partition, Offset: r.Offset, Attempts: attempts, Error: reason, Profile: p, At: time.Now().UTC()}
+	source := recordSource(r)
+	msg := kafkaMessage(w.dead, "", source, d)
+	msg.Key = r.Key
+	if !w.produce(ctx, retry, producer, "dead letter", func(ctx context.Context) error { return producer.WriteMessages(ctx, msg) }) {
+		return false
+	}
+	slog.Warn("☠️ [KAFKA] Dead-lettered", "source", source, "attempts", attempts, "reason", reason)
+	return true
+}
+
+// produce runs send until it succeeds, waiting KAFKA_RETRY_DELAY
+// between failures, and reports whether it did before retry ended. A
+// retry may produce some results twice; consumers keep the latest per
+// key.
+func (w *kafkaWorker) produce(ctx, retry context.Context, producer *kafka.Writer, what string, send func(context.Context) error) bool {
+	for {
+		pctx, cancel := context.WithTimeout(ctx, kafkaProduceTimeout)
+		err := send(pctx)
+		cancel()
+		if err == nil {
+			return true
+		}
+		slog.Warn("⚠️ [KAFKA] Produce failed, retrying", "what", what, "err", err, "in", w.retryDelay)
+		if !sleepCtx(retry, w.retryDelay) {
+			return false
+		}
+	}
+}
+
+// kafkaMessage is v as JSON for topic, keyed by key (none if empty),
+// with the record it answers in a header.
+func kafkaMessage(topic, key, source string, v any) kafka.Message {
+	data, _ := json.Marshal(v)
+	m := kafka.Message{Topic: topic, Value: data, Headers: []kafka.Header{
+		{Key: "content-type", Value: []byte("application/json")},
+		{Key: "profiler-source", Value: []byte(source)},
+	}}
+	if key != "" {
+		m.Key = []byte(key)
+	}
+	return m
+}
+
+// recordSource is where a record is: topic/partition/offset.
+func recordSource(r kafka.Message) string {
+	return fmt.Sprintf("%s/%d/%d", r.Topic, r.Partition, r.Offset)
+}
+
+// sleepCtx waits d, and reports false if ctx ended first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// ---------------------------------------------------------
+// Offsets of the partitions consumed
+// ---------------------------------------------------------
+
+// kafkaOffsets tracks, per partition, the records being screened, so a
+// partition's commit never passes a record still in flight however the
+// worker pool finishes them.
+type kafkaOffsets struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	next int64 // After the last record started
+	// Started, not done; a record fetched again after a rebalance counts
+	// twice
+	inflight  map[int64]int
+	committed int64 // -1 for none
+}
+
+func newKafkaOffsets() *kafkaOffsets {
+	return &kafkaOffsets{partitions: map[int]*partitionOffsets{}}
+}
+
+// start marks the record at offset as being screened.
+func (o *kafkaOffsets) start(p int, offset int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	po := o.partitions[p]
+	if po == nil {
+		po = &partitionOffsets{inflight: map[int64]int{}, committed: -1}
+		o.partitions[p] = po
+	}
+	po.inflight[offset]++
+	po.next = max(po.next, offset+1)
+}
+
+// finish marks the record at offset as done.
+func (o *kafkaOffsets) finish(p int, offset int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	po := o.partitions[p]
+	if po.inflight[offset]--; po.inflight[offset] <= 0 {
+		delete(po.inflight, offset)
+	}
+}
+
+// uncommitted returns the offset to commit of each partition that moved
+// since its last commit: its first record still in flight, or after the
+// last one started.
+func (o *kafkaOffsets) uncommitted() map[int]int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	pending := map[int]int64{}
+	for p, po := range o.partitions {
+		at := po.next
+		for offset := range po.inflight {
+			at = min(at, offset)
+		}
+		if at != po.committed {
+			pending[p] = at
+		}
+	}
+	return pending
+}
+
+// committed records a successful commit of offsets.
+func (o *kafkaOffsets) committed(offsets map[int]int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for p, at := range offsets {
+		o.partitions[p].committed = at
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKafkaOffsets(t *testing.T) {
+	o := newKafkaOffsets()
+	for _, off := range []int64{10, 11, 12} {
+		o.start(0, off)
+	}
+	o.start(1, 0)
+
+	// Records finishing out of order: nothing passes the one in flight
+	o.finish(0, 12)
+	o.finish(0, 11)
+	if got := o.uncommitted(); !reflect.DeepEqual(got, map[int]int64{0: 10, 1: 0}) {
+		t.Errorf("committed past a record in flight: %v", got)
+	}
+	o.committed(map[int]int64{0: 10, 1: 0})
+	if got := o.uncommitted(); len(got) != 0 {
+		t.Errorf("committed twice: %v", got)
+	}
+	o.finish(0, 10)
+	o.finish(1, 0)
+	if got := o.uncommitted(); !reflect.DeepEqual(got, map[int]int64{0: 13, 1: 1}) {
+		t.Errorf("all done: %v", got)
+	}
+	o.committed(map[int]int64{0: 13, 1: 1})
+
+	// A record never finished (its screening was cut short) holds its
+	// partition back
+	o.start(0, 13)
+	o.start(0, 14)
+	o.finish(0, 14)
+	if got := o.uncommitted(); len(got) != 0 {
+		t.Errorf("committed past an unfinished record: %v", got)
+	}
+
+	// After a rebalance the partition comes back from its last commit:
+	// records fetched again count until every copy is done
+	o.start(0, 13)
+	o.start(0, 14)
+	o.finish(0, 13)
+	if got := o.uncommitted(); len(got) != 0 {
+		t.Errorf("committed past a record fetched again: %v", got)
+	}
+	o.finish(0, 13)
+	o.finish(0, 14)
+	if got := o.uncommitted(); !reflect.DeepEqual(got, map[int]int64{0: 15}) {
+		t.Errorf("after the rebalance: %v", got)
+	}
+}
+
+func TestKafkaMessage(t *testing.T) {
+	m := kafkaMessage("out", "0xabc", "in/2/7", queueAlert{Address: "0xabc", Network: "Ethereum Mainnet"})
+	if m.Topic != "out" || string(m.Key) != "0xabc" || m.Headers[1].Key != "profiler-source" || string(m.Headers[1].Value) != "in/2/7" {
+		t.Errorf("got %+v", m)
+	}
+	if m := kafkaMessage("dead", "", "in/0/0", kafkaDeadLetter{}); m.Key != nil {
+		t.Errorf("empty key isn't null: %q", m.Key)
+	}
+}
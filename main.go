@@ -3,14 +3,25 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/piyushdaiya/crypto-profiler/internal/config"
+	"github.com/piyushdaiya/crypto-profiler/internal/core"
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
 	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
 )
 
 func main() {
@@ -19,79 +30,887 @@ func main() {
 	// in Docker Compose (where env vars are injected directly) without a .env file.
 	if err := godotenv.Load(); err != nil {
 		// Only log if you really want to see it, otherwise silent is better for Docker
-		// log.Println("No .env file found, relying on OS environment variables")
+		// slog.Debug("No .env file found, relying on OS environment variables")
+	}
+	// Settings: the defaults, then crypto-profiler.yaml (or --config /
+	// CONFIG_FILE), then the environment and .env
+	cfg := defaultConfig()
+	configPath, configRequired := config.Path(os.Args[1:])
+	configLoaded, err := config.Load(cfg, configPath, configRequired)
+	if err != nil {
+		logging.Fatal("Invalid config", "err", err)
 	}
 
 	// 2. Input Validation
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: ./validator <address>")
+	// TESTNET=true in the environment is equivalent to --testnet
+	flag.String("config", "", "Settings file (env CONFIG_FILE, default ./crypto-profiler.yaml if present); env vars override it")
+	testnet := flag.Bool("testnet", cfg.Chains.Testnet, "Use testnets (Sepolia, Bitcoin testnet3, Solana devnet, NEAR testnet, Fuji)")
+	probe := flag.Bool("probe", cfg.Chains.ProbeAll, "Query every chain whose address syntax matches, not just the first")
+	serveEngine := flag.Bool("engine", cfg.Serve.Engine, "Serve mode: also be the Watchlist Engine (/check, /search, /check-name) on WATCHLIST_DB_PATH, synced with OFAC, in the same server (env SERVE_ENGINE)")
+	offline := flag.Bool("offline", cfg.Chains.Offline, "Check syntax, checksums and sanctions only, calling no chain-data provider (env OFFLINE; the profile is partial)")
+	chain := flag.String("chain", "", "Skip auto-detection: a chain (solana, bitcoin, evm...) or an EVM network (polygon, base, 137...)")
+	nameMode := flag.Bool("name", false, "Screen a person or company name against SDN names and aliases instead of an address")
+	policy := flag.String("policy", cfg.Rules.Policy, "Risk policy profile: exchange, lender or nft_marketplace (env RISK_POLICY, default rules if empty)")
+	watch := flag.Bool("watch", false, "Add the address to the monitored list (MONITOR_FILE) and exit")
+	unwatch := flag.Bool("unwatch", false, "Remove the address from the monitored list and exit")
+	note := flag.String("note", "", "Free-text note stored with --watch, e.g. a customer ID")
+	batch := flag.String("batch", "", "Screen every address in a file (one per line, - for stdin) and stream JSON Lines")
+	format := flag.String("format", cfg.Output.Format, "Output format: table, json, jsonl or csv (env OUTPUT_FORMAT; default table on a terminal, else json, jsonl in batch mode)")
+	tmpl := flag.String("template", cfg.Output.Template, "Write each profile through a Go template instead of --format, e.g. '{{.RiskGrade}} {{.Address}}' (env OUTPUT_TEMPLATE)")
+	noCache := flag.Bool("no-cache", false, "Screen again even if the address has a cached profile younger than PROFILE_CACHE_TTL")
+	noColor := flag.Bool("no-color", false, "Don't color the table format by risk (also NO_COLOR)")
+	webhookURL := flag.String("webhook-url", cfg.Output.WebhookURL, "Also POST each finished profile there as JSON, signed with WEBHOOK_SECRET (env WEBHOOK_URL)")
+	report := flag.String("report", "", "Also write a standalone risk report of the address to this file (HTML, or a PDF compliance report for a .pdf path)")
+	checkpointPath := flag.String("checkpoint", cfg.Batch.Checkpoint, "Batch mode: record finished profiles in this file and skip the addresses it already holds, so an interrupted run resumes (env BATCH_CHECKPOINT)")
+	progressJSON := flag.String("progress-json", "", "Batch mode: also write the progress as JSON Lines to this file, one line per finished address (\"-\" for stderr)")
+	batchWorkers := flag.Int("workers", 0, "Addresses screened at once in batch mode (env BATCH_WORKERS, default 4)")
+	interval := flag.Duration("interval", cfg.Watch.Interval, "How often watch mode re-profiles the address (env WATCH_INTERVAL)")
+	monitor := flag.Bool("monitor", false, "Re-score the monitored list every MONITOR_INTERVAL and print alerts as JSON Lines (with serve: in the server, alerts to /v1/stream)")
+	failAbove := flag.Float64("fail-above", cfg.Output.FailAbove, "Exit 2 when a risk score is above this, 3 on a sanctions hit, 4 on provider errors (env FAIL_ABOVE; off if negative)")
+	nameThreshold := flag.Float64("name-threshold", cfg.Rules.NameThreshold, "Lowest name-match confidence reported, 0-1 (env NAME_MATCH_THRESHOLD, default 0.85)")
+	quiet := flag.Bool("quiet", false, "Log warnings and errors only")
+	verbose := flag.Bool("verbose", false, "Also log provider failovers, retries and cache hits")
+	debug := flag.Bool("debug", false, "Log everything, every outbound HTTP request included, with source locations")
+	args := parseArgs()
+
+	// Logs go to stderr, so stdout is only the result. The flags override
+	// LOG_LEVEL; LOG_FORMAT=json writes JSON records.
+	logCfg, err := logging.ParseConfig(cfg.Logging.Level, cfg.Logging.Format)
+	if err != nil {
+		logging.Fatal("Invalid logging config", "err", err)
+	}
+	switch {
+	case *debug:
+		logCfg.Level, logCfg.Source = logging.LevelTrace, true
+	case *verbose:
+		logCfg.Level = slog.LevelDebug
+	case *quiet:
+		logCfg.Level = slog.LevelWarn
+	}
+	if err := logging.Setup(logCfg, os.Stderr); err != nil {
+		logging.Fatal("Invalid logging config", "err", err)
+	}
+	if configLoaded {
+		slog.Debug("⚙️ Config loaded", "file", configPath)
 	}
-	address := strings.TrimSpace(os.Args[1])
 
-	// 3. Load Keys (os.Getenv works for both .env files AND Docker Compose)
-	etherscanKey := os.Getenv("ETHERSCAN_API_KEY")
-	coinstatsKey := os.Getenv("COINSTATS_API_KEY")
+	// Subcommands: serve, worker, kafka, watch <address>, diff <address>, tui
+	command := ""
+	if len(args) > 0 && (args[0] == "serve" || args[0] == "worker" || args[0] == "kafka" || args[0] == "watch" || args[0] == "diff" || args[0] == "tui") {
+		command, args = args[0], args[1:]
+	}
+	if len(args) < 1 && command != "serve" && command != "worker" && command != "kafka" && command != "tui" && !*monitor && *batch == "" && !stdinPiped() {
+		logging.Fatal("Usage: ./validator [--testnet] [--probe | --chain solana] [--policy lender] <address>... | --batch <file> | --watch [--note text] <address> | --unwatch <address> | --monitor | serve | worker | kafka | watch [--interval 5m] <address> | diff <address> | tui | --name [--name-threshold 0.9] <name>")
+	}
+	address := ""
+	if len(args) > 0 {
+		address = strings.TrimSpace(args[0])
+	}
+	// Several addresses are screened together, like a batch; every other
+	// mode takes one (--name reads them as one name)
+	if len(args) > 1 && !*nameMode {
+		if command != "" || *watch || *unwatch {
+			logging.Fatal("Only plain screening takes several addresses", "addresses", len(args))
+		}
+		if *batch != "" {
+			logging.Fatal("Give addresses as arguments or --batch, not both")
+		}
+	}
 
-	// 4. Register Strategies
-	strategies := []validator.ChainStrategy{
-		&validator.EVMStrategy{},     // Check EVM (0x...)
-		&validator.BitcoinStrategy{}, // Check Bitcoin (Starts with 1, 3, bc1) <--- MOVED UP
-		&validator.SolanaStrategy{},  // Check Solana (Generic Base58)         <--- MOVED DOWN
+	// Monitored list: MONITOR_FILE (default monitor.json)
+	monitorFile := cfg.Monitor.File
+	if *watch || *unwatch {
+		if address == "" {
+			logging.Fatal("Usage: ./validator --watch [--note text] <address> | --unwatch <address>")
+		}
+		store, err := validator.NewFileMonitorStore(monitorFile)
+		if err != nil {
+			logging.Fatal("Invalid MONITOR_FILE", "err", err)
+		}
+		if *unwatch {
+			removed, err := store.Remove(address)
+			if err != nil {
+				logging.Fatal("⚠️ Monitor update failed", "err", err)
+			}
+			if !removed {
+				logging.Fatal("⚠️ Not monitored", "address", address)
+			}
+			slog.Info("🗑️ Stopped monitoring", "address", address)
+			return
+		}
+		entry := validator.MonitoredAddress{Address: address, Note: *note, AddedAt: time.Now().UTC()}
+		if err := store.Put(entry); err != nil {
+			logging.Fatal("⚠️ Monitor update failed", "err", err)
+		}
+		slog.Info("👀 Monitoring", "address", address, "file", monitorFile)
+		return
 	}
 
-	var result *validator.WalletProfile
+	// Sanctions checks: the Watchlist Engine, or its database in-process
+	validator.SetWatchlistEngine(cfg.Watchlist.EngineURL, cfg.Watchlist.DBPath)
+
+	// Name screening needs only the watchlist, not the chain strategies
+	if *nameMode {
+		if *nameThreshold < 0 || *nameThreshold > 1 {
+			logging.Fatal("Invalid name threshold (0-1)", "value", *nameThreshold)
+		}
+		name := strings.TrimSpace(strings.Join(args, " "))
+		slog.Info("🔍 Screening name", "name", name)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		screening, err := validator.CheckName(ctx, name, *nameThreshold)
+		if err != nil {
+			logging.Fatal("⚠️ Name screening failed", "err", err)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(screening); err != nil {
+			slog.Error("Error encoding JSON", "err", err)
+		}
+		return
+	}
 
-	// 5. Run Strategy Matching
-	for _, strategy := range strategies {
-		if strategy.IsValidSyntax(address) {
-			
-			configParam := ""
-			switch strategy.Name() {
-			case "EVM (Etherscan)":
-				configParam = etherscanKey
-			case "SOLANA":
-				configParam = coinstatsKey
-			case "BITCOIN":
-				configParam = ""
+	// 3. Load Keys (from the environment, .env or the config file)
+	etherscanKey := cfg.APIKeys.Etherscan
+	coinstatsKey := cfg.APIKeys.CoinStats
+	blockchairKey := cfg.APIKeys.Blockchair // Optional (Zcash)
+	nearblocksKey := cfg.APIKeys.NearBlocks // Optional (NEAR history)
+	glacierKey := cfg.APIKeys.Glacier       // Optional (Avalanche X/P)
+
+	// Node / indexer endpoints (empty = public defaults)
+	nodes := core.Config{
+		EvmRPC:     cfg.Providers.EVMRPC,     // Used when ETHERSCAN_API_KEY is empty
+		SolanaRPC:  cfg.Providers.SolanaRPC,  // Primary Solana source; CoinStats only enriches
+		BitcoinRPC: cfg.Providers.BTCEsplora, // Esplora, e.g. self-hosted mempool.space
+	}
+	// .sol names via SNS_RESOLVER_URL; UD names need UNSTOPPABLE_API_KEY
+	validator.SetNameResolvers(cfg.Providers.SNSResolver, cfg.APIKeys.Unstoppable)
+
+	// Retries for transient upstream failures (429, 5xx, timeouts)
+	if cfg.Retry.Attempts < 1 {
+		logging.Fatal("Invalid RETRY_ATTEMPTS", "value", cfg.Retry.Attempts)
+	}
+	if cfg.Retry.BaseDelay < 0 {
+		logging.Fatal("Invalid RETRY_BASE_DELAY", "value", cfg.Retry.BaseDelay)
+	}
+	if cfg.Retry.MaxDelay < 0 {
+		logging.Fatal("Invalid RETRY_MAX_DELAY", "value", cfg.Retry.MaxDelay)
+	}
+	validator.SetRetryPolicy(validator.RetryPolicy{Attempts: cfg.Retry.Attempts, BaseDelay: cfg.Retry.BaseDelay, MaxDelay: cfg.Retry.MaxDelay})
+
+	// HTTP_TIMEOUT caps each provider request (default 10-15s per provider)
+	if cfg.Timeouts.HTTP < 0 {
+		logging.Fatal("Invalid HTTP_TIMEOUT", "value", cfg.Timeouts.HTTP)
+	}
+	validator.SetHTTPTimeout(cfg.Timeouts.HTTP)
+
+	// Circuit breakers: skip a provider after N consecutive failures
+	// (BREAKER_THRESHOLD=0 disables them)
+	if cfg.Breaker.Cooldown < 0 {
+		logging.Fatal("Invalid BREAKER_COOLDOWN", "value", cfg.Breaker.Cooldown)
+	}
+	validator.SetBreakerConfig(cfg.Breaker.Threshold, cfg.Breaker.Cooldown)
+
+	// Client-side rate limits: RATE_LIMITS="api.etherscan.io=2,blockchain.info=0.5" (0 removes one)
+	if limits := cfg.Providers.RateLimits; limits != "" {
+		for _, entry := range strings.Split(limits, ",") {
+			host, rps, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			n, err := strconv.ParseFloat(rps, 64)
+			if !ok || host == "" || err != nil {
+				logging.Fatal("Invalid RATE_LIMITS entry (want host=rps)", "value", entry)
 			}
+			validator.SetRateLimit(host, n)
+		}
+	}
+
+	// Response cache: CACHE_BACKEND=memory (default), redis (REDIS_URL) or off
+	balanceTTL, historyTTL, priceTTL := cfg.Cache.BalanceTTL, cfg.Cache.HistoryTTL, cfg.Cache.PriceTTL
+	for name, ttl := range map[string]time.Duration{"CACHE_BALANCE_TTL": balanceTTL, "CACHE_HISTORY_TTL": historyTTL, "CACHE_PRICE_TTL": priceTTL} {
+		if ttl < 0 {
+			logging.Fatal("Invalid "+name, "value", ttl)
+		}
+	}
+	switch backend := cfg.Cache.Backend; backend {
+	case "", "memory":
+		validator.SetCache(validator.NewMemoryCache(), balanceTTL, historyTTL)
+	case "redis":
+		redisCache, err := validator.NewRedisCache(cfg.Cache.RedisURL)
+		if err != nil {
+			logging.Fatal("Invalid REDIS_URL", "err", err)
+		}
+		validator.SetCache(redisCache, balanceTTL, historyTTL)
+	case "off":
+		validator.SetCache(nil, 0, 0)
+	default:
+		logging.Fatal("Invalid CACHE_BACKEND (memory, redis or off)", "value", backend)
+	}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-			defer cancel()
+	// HISTORY_FILE keeps every computed profile, so reruns report the
+	// trend and diff has a previous run
+	var history validator.HistoryStore
+	if path := cfg.Cache.HistoryFile; path != "" {
+		if history, err = validator.NewFileHistory(path); err != nil {
+			logging.Fatal("Invalid HISTORY_FILE", "err", err)
+		}
+		validator.SetHistory(history)
+	}
 
-			fmt.Printf("🔍 Analyzing %s on %s...\n", address, strategy.Name())
-			
-			// EVM Strategy calls Investigate() internally.
-			// Others might not, so we handle that below.
-			res, err := strategy.FetchState(ctx, address, configParam)
+	// USD pricing: PRICE_ORACLE="coingecko,coinstats" (asked in order) or off
+	oracle := cfg.Providers.PriceOracle
+	if oracle == "" {
+		oracle = "coingecko"
+		if coinstatsKey != "" {
+			oracle += ",coinstats"
+		}
+	}
+	var priceSources []validator.PriceSource
+	for _, name := range strings.Split(oracle, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "coingecko":
+			priceSources = append(priceSources, &validator.CoinGeckoPrices{APIKey: cfg.APIKeys.CoinGecko})
+		case "coinstats":
+			if coinstatsKey == "" {
+				logging.Fatal("PRICE_ORACLE=coinstats requires COINSTATS_API_KEY")
+			}
+			priceSources = append(priceSources, &validator.CoinStatsPrices{APIKey: coinstatsKey})
+		case "off":
+		default:
+			logging.Fatal("Invalid PRICE_ORACLE (coingecko, coinstats or off)", "value", name)
+		}
+	}
+	validator.SetPriceSources(priceTTL, priceSources...)
+
+	// LABELS_FILE adds exchange labels (CSV: address,entity,regulated)
+	if path := cfg.Labels.ExchangesFile; path != "" {
+		extra, err := validator.LoadLabels(path)
+		if err != nil {
+			logging.Fatal("Invalid LABELS_FILE", "err", err)
+		}
+		validator.SetLabels(extra)
+	}
+
+	// LABEL_PROVIDERS="builtin,csv,watchlist,etherscan" labels counterparties (asked in order)
+	providers := cfg.Labels.Providers
+	var labelProviders []validator.Labels
+	for _, name := range strings.Split(providers, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "builtin":
+			labelProviders = append(labelProviders, validator.BuiltinLabels{})
+		case "csv":
+			csvLabels, err := validator.LoadCSVLabels(cfg.Labels.AddressesFile)
 			if err != nil {
-				log.Printf("⚠️ Error validating: %v", err)
+				logging.Fatal("LABEL_PROVIDERS=csv needs a valid ADDRESS_LABELS_FILE", "err", err)
 			}
-			
-			// 6. Post-Process Safety Net
-			// Ensure Sanctions check runs even if the strategy didn't call it.
-			if res != nil && res.RiskScore == 0 && len(res.RiskReasons) == 0 {
-				validator.Investigate(res, nil)
+			labelProviders = append(labelProviders, csvLabels)
+		case "watchlist":
+			labelProviders = append(labelProviders, &validator.WatchlistLabels{})
+		case "etherscan":
+			if etherscanKey == "" {
+				logging.Fatal("LABEL_PROVIDERS=etherscan requires ETHERSCAN_API_KEY")
 			}
+			labelProviders = append(labelProviders, &validator.EtherscanLabels{APIKey: etherscanKey})
+		default:
+			logging.Fatal("Invalid LABEL_PROVIDERS (builtin, csv, watchlist or etherscan)", "value", name)
+		}
+	}
+	validator.SetLabelProviders(labelProviders...)
+
+	// --policy picks a named rule set; RISK_RULES_FILE overrides the
+	// investigator's thresholds, offsets and weights on top of it
+	if path := cfg.Rules.File; path != "" {
+		rules, err := validator.LoadPolicyRules(*policy, path)
+		if err != nil {
+			logging.Fatal("Invalid RISK_RULES_FILE", "err", err)
+		}
+		if err := validator.SetRiskRules(rules); err != nil {
+			logging.Fatal("Invalid RISK_RULES_FILE", "err", err)
+		}
+	} else if *policy != "" {
+		rules, err := validator.PolicyRules(*policy)
+		if err != nil {
+			logging.Fatal("Invalid policy", "err", err)
+		}
+		if err := validator.SetRiskRules(rules); err != nil {
+			logging.Fatal("Invalid policy", "err", err)
+		}
+	}
+
+	// EVM_CHAINS=all (or "1,polygon,base") profiles a 0x address on several networks
+	evmChains, err := validator.ParseEVMChains(cfg.Chains.EVM, *testnet)
+	if err != nil {
+		logging.Fatal("Invalid EVM_CHAINS", "err", err)
+	}
+
+	// 4. Register Strategies
+	evmStrategy := &validator.EVMStrategy{
+		Chains:         evmChains,
+		Testnet:        *testnet,
+		RPCURL:         nodes.EvmRPC,
+		SkipTokens:     cfg.EVM.SkipTokens, // Native balance only
+		FetchNFTs:      cfg.EVM.NFTs,       // Opt-in NFT summary
+		FetchApprovals: cfg.EVM.Approvals,  // Opt-in approval exposure
+		FetchDeposits:  cfg.EVM.Deposits,   // Opt-in deposit-address attribution
+		CheckTokens:    cfg.EVM.ScamTokens, // Opt-in honeypot token checks
+
+		FetchWashTrading: cfg.EVM.WashTrading, // Opt-in wash-trading clusters
+		FetchSybil:       cfg.EVM.Sybil,       // Opt-in funder/sibling checks
+
+		MaxTxs:       cfg.EVM.MaxTxs, // 0 = the strategy's default
+		ExposureHops: cfg.EVM.ExposureHops,
+		PeelHops:     cfg.EVM.PeelHops,
+	}
+	if cfg.EVM.MaxTxs < 0 {
+		logging.Fatal("Invalid EVM_MAX_TXS", "value", cfg.EVM.MaxTxs)
+	}
+	if n := cfg.EVM.ExposureHops; n < 0 || n > validator.MaxExposureHops {
+		logging.Fatal(fmt.Sprintf("Invalid EXPOSURE_HOPS (0-%d)", validator.MaxExposureHops), "value", n)
+	}
+	if n := cfg.EVM.PeelHops; n < 0 || n > validator.MaxPeelHops {
+		logging.Fatal(fmt.Sprintf("Invalid PEEL_HOPS (0-%d)", validator.MaxPeelHops), "value", n)
+	}
+	if cfg.Bitcoin.GapLimit <= 0 {
+		logging.Fatal("Invalid BTC_GAP_LIMIT", "value", cfg.Bitcoin.GapLimit)
+	}
+	// Lower priority = tried first; generic base58 (Solana) goes last.
+	// Downstream programs can add chains the same way (validator.Register).
+	// CHAINS="evm,bitcoin" registers only those (empty = all).
+	enabledChains, err := parseChains(cfg.Chains.Enabled)
+	if err != nil {
+		logging.Fatal("Invalid CHAINS", "err", err)
+	}
+	register := func(name string, s validator.ChainStrategy, opts ...validator.RegisterOption) {
+		if enabledChains == nil || enabledChains[name] {
+			validator.Register(s, opts...)
+		}
+	}
+
+	// Check EVM (0x...)
+	register("evm", evmStrategy, validator.WithPriority(10), validator.WithConfig(etherscanKey))
+	// Check Bitcoin (Starts with 1, 3, bc1) <--- MOVED UP
+	register("bitcoin", &validator.BitcoinStrategy{
+		Testnet:       *testnet,
+		EsploraURL:    nodes.BitcoinRPC,
+		PreferEsplora: cfg.Bitcoin.PreferEsplora, // Esplora first, blockchain.info as fallback
+		GapLimit:      cfg.Bitcoin.GapLimit,      // xpub/ypub/zpub scanning
+		PeelHops:      cfg.EVM.PeelHops,
+	}, validator.WithPriority(20))
+	if !*testnet {
+		// No public testnet indexers for these; mainnet only
+		// Check Cosmos SDK chains (cosmos1, osmo1, celestia1...)
+		register("cosmos", &validator.CosmosStrategy{}, validator.WithPriority(30))
+		// Check Zcash (t1/t3 transparent, zs1/u1/zc shielded)
+		register("zcash", &validator.ZcashStrategy{}, validator.WithPriority(40), validator.WithConfig(blockchairKey))
+		// Check Bitcoin SV (bsv:1..., same format as BTC legacy)
+		register("bitcoin_sv", &validator.BSVStrategy{}, validator.WithPriority(50))
+	}
+	// Check Lightning (lnbc invoices, 66-hex node pubkeys)
+	register("lightning", &validator.LightningStrategy{
+		Testnet:  *testnet,
+		GraphURL: cfg.Providers.LNGraph, // Empty = mempool.space
+	}, validator.WithPriority(60))
+	// Check NEAR (64-hex implicit, *.near named)
+	register("near", &validator.NearStrategy{Testnet: *testnet, RPCURL: cfg.Providers.NearRPC}, validator.WithPriority(70), validator.WithConfig(nearblocksKey))
+	// Check Avalanche X/P-Chain (X-avax1..., P-avax1...)
+	register("avalanche", &validator.AvalancheStrategy{Testnet: *testnet}, validator.WithPriority(80), validator.WithConfig(glacierKey))
+	// Check Solana (Generic Base58) <--- MOVED DOWN
+	register("solana", &validator.SolanaStrategy{Testnet: *testnet, RPCURL: nodes.SolanaRPC}, validator.WithPriority(90), validator.WithConfig(coinstatsKey))
+
+	// --chain skips syntax matching: a registered chain, or an EVM network
+	// (the EVM strategy on that network only)
+	var forced validator.ChainStrategy
+	if *chain != "" {
+		if forced, err = forceChain(*chain, evmStrategy, *testnet); err != nil {
+			logging.Fatal("Invalid --chain", "err", err)
+		}
+	}
+
+	// Offline: syntax, checksums and the watchlist only (air-gapped hosts,
+	// or a free pre-check before spending API quota)
+	if *offline {
+		if *monitor || command == "watch" || command == "diff" {
+			logging.Fatal("--offline has no chain data to monitor, watch or diff")
+		}
+		validator.SetOffline(true)
+	}
+
+	// Continuous monitoring: re-score the saved addresses until interrupted
+	if *monitor && command != "serve" {
+		runMonitor(cfg, *probe)
+		return
+	}
+
+	// Batch and serve mode: addresses screened at once, time per address
+	workers := cfg.Batch.Workers
+	if workers < 1 {
+		logging.Fatal("Invalid BATCH_WORKERS", "value", workers)
+	}
+	if *batchWorkers < 0 {
+		logging.Fatal("Invalid --workers", "value", *batchWorkers)
+	} else if *batchWorkers > 0 {
+		workers = *batchWorkers
+	}
+	timeout := cfg.Timeouts.Batch
+	if timeout <= 0 {
+		logging.Fatal("Invalid BATCH_TIMEOUT", "value", timeout)
+	}
 
-			result = res
-			break
+	// REST API: ./validator serve [--engine] [--monitor]
+	if command == "serve" {
+		s := &profileServer{
+			probe:   *probe,
+			testnet: *testnet,
+			chain:   forced,
+			evm:     evmStrategy,
+			workers: workers,
+			timeout: timeout,
+		}
+		if *serveEngine {
+			s.engine = openEngine(cfg)
 		}
+		if *monitor {
+			s.monitor, s.monitorInterval = newMonitor(cfg, *probe)
+		}
+		runServer(s, cfg)
+		return
+	}
+
+	// Queue worker: ./validator worker (QUEUE_URL, a NATS JetStream server)
+	if command == "worker" {
+		w := newQueueWorker(cfg)
+		w.probe, w.testnet, w.chain, w.evm, w.workers, w.timeout = *probe, *testnet, forced, evmStrategy, workers, timeout
+		runWorker(w)
+		return
 	}
 
+	// Kafka worker: ./validator kafka (KAFKA_BROKERS)
+	if command == "kafka" {
+		w := newKafkaWorker(cfg)
+		w.probe, w.testnet, w.chain, w.evm, w.workers, w.timeout = *probe, *testnet, forced, evmStrategy, workers, timeout
+		runKafka(w)
+		return
+	}
+
+	// Profile cache: a completed profile is reused for PROFILE_CACHE_TTL
+	// (0 = off) by the single-address, batch and tui modes. Offline
+	// screening spends no quota, so it isn't cached.
+	var profiles *profileCache
+	if ttl := cfg.Cache.ProfileTTL; ttl > 0 && !*offline {
+		path := cfg.Cache.ProfileFile
+		if path == "" {
+			if dir, err := os.UserCacheDir(); err == nil {
+				path = filepath.Join(dir, "crypto-profiler", "profiles.db")
+			}
+		}
+		if path != "" {
+			store, err := validator.NewFileCache(path)
+			if err != nil {
+				logging.Fatal("Invalid PROFILE_CACHE_FILE", "err", err)
+			}
+			profiles = newProfileCache(store, ttl, *chain, *testnet, *probe, *noCache)
+		}
+	}
+
+	// Batch mode: --batch <file>, or addresses piped on stdin. Several
+	// addresses as arguments are screened the same way, but print as one
+	// JSON array by default.
+	isBatch := command == "" && (*batch != "" || (len(args) == 0 && stdinPiped()))
+	many := isBatch || (command == "" && len(args) > 1)
+	if *format == "" {
+		switch {
+		case stdoutTerminal():
+			*format = "table"
+		case isBatch:
+			*format = "jsonl"
+		default:
+			*format = "json"
+		}
+	}
+	// Tables on a terminal are colored by risk, unless NO_COLOR (no-color.org)
+	color := stdoutTerminal() && !*noColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	out, err := newProfileWriter(*format, os.Stdout, many, color)
+	if err != nil {
+		logging.Fatal("Invalid --format", "err", err)
+	}
+	if *tmpl != "" {
+		if out, err = newTemplateWriter(*tmpl, os.Stdout); err != nil {
+			logging.Fatal("Invalid --template", "err", err)
+		}
+	}
+	// Webhook sink: every profile of a single or batch run, as it is written
+	if *webhookURL != "" {
+		if command != "" {
+			logging.Fatal("--webhook-url takes single and batch runs only")
+		}
+		secret := cfg.Output.WebhookSecret
+		if secret == "" {
+			logging.Fatal("--webhook-url needs WEBHOOK_SECRET, the key the payloads are signed with")
+		}
+		out = &webhookWriter{profileWriter: out, post: validator.WebhookProfiles(*webhookURL, secret)}
+	}
+	if *report != "" && (command != "" || many) {
+		logging.Fatal("--report takes a single address")
+	}
+
+	// Watch mode: re-profile one address, print what changed
+	if command == "watch" {
+		if *interval <= 0 {
+			logging.Fatal("Invalid --interval", "value", *interval)
+		}
+		if *format == "csv" || *tmpl != "" {
+			logging.Fatal("Invalid --format: watch mode prints table or JSON lines")
+		}
+		screen := func(ctx context.Context) *validator.WalletProfile {
+			return screenAddress(ctx, address, *probe, *testnet, forced, 20*time.Second, slog.LevelDebug)
+		}
+		os.Exit(runWatch(address, *interval, screen, *format == "table"))
+	}
+	// Interactive session: screen, drill into reasons, pivot to counterparties
+	if command == "tui" {
+		screen := func(ctx context.Context, address string) *validator.WalletProfile {
+			return profiles.screen(ctx, address, func(ctx context.Context, address string) *validator.WalletProfile {
+				return screenAddress(ctx, address, *probe, *testnet, forced, 20*time.Second, slog.LevelDebug)
+			})
+		}
+		os.Exit(runTUI(os.Stdin, os.Stdout, color, screen))
+	}
+	// Diff: this run against the last one stored in HISTORY_FILE
+	if command == "diff" {
+		if history == nil {
+			logging.Fatal("diff needs HISTORY_FILE, the local profile store")
+		}
+		if *format == "csv" || *tmpl != "" {
+			logging.Fatal("Invalid --format: diff prints table or JSON")
+		}
+		// Off while screening, so the previous run is still the last one
+		validator.SetHistory(nil)
+		result := screenAddress(context.Background(), address, *probe, *testnet, forced, 20*time.Second, slog.LevelInfo)
+		validator.SetHistory(history)
+		if !result.IsValid {
+			logging.Fatal("Invalid address", "details", result.ValidationDetails)
+		}
+		prev, err := validator.LastRecord(result.Address, result.Network)
+		if err != nil {
+			logging.Fatal("⚠️ History read failed", "err", err)
+		}
+		validator.RecordHistory(result)
+		if prev == nil {
+			slog.Info("📭 No stored run to compare with; this one is now the baseline", "address", result.Address, "network", result.Network)
+		} else if err := writeDiff(os.Stdout, diffRecord(prev, result, time.Now().UTC()), *format == "table"); err != nil {
+			slog.Error("Error writing output", "err", err)
+		}
+		if code, why := exitStatus(result, *failAbove); code != exitClean {
+			slog.Warn("🚦 Exit", "code", code, "reason", why)
+			os.Exit(code)
+		}
+		return
+	}
+	if *checkpointPath != "" && !many {
+		logging.Fatal("--checkpoint needs a batch (--batch, stdin or several addresses)")
+	}
+	if *progressJSON != "" && !many {
+		logging.Fatal("--progress-json needs a batch (--batch, stdin or several addresses)")
+	}
+	if many {
+		var addresses []string
+		if isBatch {
+			addresses = batchAddresses(*batch)
+		} else {
+			for _, a := range args {
+				addresses = append(addresses, strings.TrimSpace(a))
+			}
+		}
+		var checkpoint *batchCheckpoint
+		if *checkpointPath != "" {
+			if checkpoint, err = openCheckpoint(*checkpointPath); err != nil {
+				logging.Fatal("Invalid --checkpoint", "err", err)
+			}
+		}
+		progress := &batchProgress{interval: cfg.Batch.ProgressInterval}
+		if progress.interval < 0 {
+			logging.Fatal("Invalid BATCH_PROGRESS_INTERVAL", "value", progress.interval)
+		}
+		switch *progressJSON {
+		case "":
+		case "-":
+			progress.stream = nopWriteCloser{os.Stderr}
+		default:
+			if progress.stream, err = os.Create(*progressJSON); err != nil {
+				logging.Fatal("Invalid --progress-json", "err", err)
+			}
+		}
+		code := runBatch(addresses, *probe, *testnet, forced, profiles, checkpoint, progress, workers, timeout, out, *failAbove)
+		if err := checkpoint.Close(); err != nil {
+			slog.Error("⚠️ Checkpoint write failed", "err", err)
+		}
+		if err := progress.Close(); err != nil {
+			slog.Error("⚠️ Progress write failed", "err", err)
+		}
+		os.Exit(code)
+	}
+
+	// 5-6. Resolve, match and analyze (or reuse a cached profile)
+	result := profiles.screen(context.Background(), address, func(ctx context.Context, address string) *validator.WalletProfile {
+		return screenAddress(ctx, address, *probe, *testnet, forced, 20*time.Second, slog.LevelInfo)
+	})
+
+	// 7. Output Result
+	if err := out.Write(result); err != nil {
+		slog.Error("Error writing output", "err", err)
+	} else if err := out.Close(); err != nil {
+		slog.Error("Error writing output", "err", err)
+	}
+	if *report != "" {
+		if err := writeReport(*report, result); err != nil {
+			logging.Fatal("⚠️ Report failed", "err", err)
+		}
+		slog.Info("📄 Report written", "path", *report)
+	}
+	if code, why := exitStatus(result, *failAbove); code != exitClean {
+		slog.Warn("🚦 Exit", "code", code, "reason", why)
+		os.Exit(code)
+	}
+}
+
+// screenAddress resolves a name, matches the address to its chain (or
+// takes chain, if set) and analyzes it (see validator.Analyze). It always returns a profile; one
+// that could not be analyzed is invalid with the reason in
+// validation_details. timeout bounds the analysis; progress is logged at
+// the progress level (Debug where it would be noise: watch, serve).
+func screenAddress(ctx context.Context, address string, probe, testnet bool, chain validator.ChainStrategy, timeout time.Duration, progress slog.Level) *validator.WalletProfile {
+	var result *validator.WalletProfile
+
+	// Names (.sol, .crypto, .x ...) are resolved to an address before matching
+	resolvedFrom := ""
+	if validator.IsResolvableName(address) && validator.Offline() {
+		result = &validator.WalletProfile{
+			Address:           address,
+			Network:           "UNKNOWN",
+			IsValid:           false,
+			Testnet:           testnet,
+			ValidationDetails: "Name Resolution Skipped (--offline)",
+		}
+	} else if validator.IsResolvableName(address) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		resolved, service, err := validator.ResolveName(ctx, address)
+		cancel()
+		if err != nil {
+			result = &validator.WalletProfile{
+				Address:           address,
+				Network:           "UNKNOWN",
+				IsValid:           false,
+				Testnet:           testnet,
+				ValidationDetails: fmt.Sprintf("Name Resolution Failed: %v", err),
+			}
+		} else {
+			slog.Log(ctx, progress, "🔗 Resolved", "name", address, "address", resolved, "service", service)
+			resolvedFrom = address
+			address = resolved
+		}
+	}
+
+	// Run Strategy Matching (skipped if name resolution already failed)
 	if result == nil {
+		matches := validator.MatchingStrategies(validator.Strategies(), address)
+		if chain != nil {
+			// --chain: that strategy only, if it takes the syntax
+			matches = validator.MatchingStrategies([]validator.ChainStrategy{chain}, address)
+		}
+		switch {
+		case chain == nil && probe && len(matches) > 1:
+			// Ambiguous syntax: every matching chain is queried concurrently
+			slog.Log(ctx, progress, "🔍 Probing", "address", address, "chains", len(matches))
+		case len(matches) > 0:
+			slog.Log(ctx, progress, "🔍 Analyzing", "address", address, "chain", matches[0].Name())
+		}
+
+		// Fetch, price and investigate (see validator.Analyze)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		var res *validator.WalletProfile
+		var err error
+		if chain != nil {
+			res, err = validator.AnalyzeWith(ctx, chain, address)
+		} else {
+			res, err = validator.Analyze(ctx, address, probe)
+		}
+		if err != nil {
+			slog.Warn("⚠️ Error validating", "address", address, "err", err)
+		}
+		result = res
+	}
+
+	if result == nil {
+		details := "Invalid Format or No Matching Chain Strategy"
+		if chain != nil {
+			details = fmt.Sprintf("Invalid Format for %s (--chain)", chain.Name())
+		}
 		result = &validator.WalletProfile{
 			Address:           address,
 			Network:           "UNKNOWN",
 			IsValid:           false,
-			ValidationDetails: "Invalid Format or No Matching Chain Strategy",
+			Testnet:           testnet,
+			ValidationDetails: details,
 		}
 	}
 
-	// 7. Output Result
+	if result.Partial {
+		result.Testnet = testnet // Offline profiles come from no chain to say so
+	}
+	result.ResolvedFrom = resolvedFrom
+	return result
+}
+
+// chainNames are the built-in chains CHAINS can enable.
+var chainNames = []string{"evm", "bitcoin", "cosmos", "zcash", "bitcoin_sv", "lightning", "near", "avalanche", "solana"}
+
+// parseChains reads CHAINS ("evm, bitcoin"); nil means every chain.
+func parseChains(s string) (map[string]bool, error) {
+	if s = strings.TrimSpace(s); s == "" || s == "all" {
+		return nil, nil
+	}
+	enabled := map[string]bool{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(chainNames, name) {
+			return nil, fmt.Errorf("unknown chain %q (%s)", name, strings.Join(chainNames, ", "))
+		}
+		enabled[name] = true
+	}
+	return enabled, nil
+}
+
+// forceChain returns the strategy for a --chain value: a registered chain
+// or, for EVM networks, a copy of evm limited to them.
+func forceChain(name string, evm *validator.EVMStrategy, testnet bool) (validator.ChainStrategy, error) {
+	if s := validator.LookupStrategy(name); s != nil {
+		return s, nil
+	}
+	chains, err := validator.ParseEVMChains(name, testnet)
+	if err == nil && len(chains) > 0 && validator.LookupStrategy("evm") == nil {
+		return nil, fmt.Errorf("%q is an EVM network, but evm is not in CHAINS", name)
+	}
+	if err != nil || len(chains) == 0 {
+		var names []string
+		for _, s := range validator.Strategies() {
+			names = append(names, strings.ToLower(strings.Fields(s.Name())[0]))
+		}
+		return nil, fmt.Errorf("%q is not a chain (%s) or EVM network", name, strings.Join(names, ", "))
+	}
+	network := *evm
+	network.Chains = chains
+	return &network, nil
+}
+
+// runMonitor re-scores the monitored list every MONITOR_INTERVAL (default
+// 1h) and prints each alert as a JSON line. Score alerts fire when a score
+// crosses MONITOR_THRESHOLD (default: the FAILING bound of the rules).
+func runMonitor(cfg *config.Config, probe bool) {
+	m, interval := newMonitor(cfg, probe)
 	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
 	encoder.SetEscapeHTML(false)
-	if err := encoder.Encode(result); err != nil {
-		log.Printf("Error encoding JSON: %v", err)
+	webhook := m.Alert
+	m.Alert = func(a validator.MonitorAlert) {
+		if err := encoder.Encode(a); err != nil {
+			slog.Error("Error encoding JSON", "err", err)
+		}
+		if webhook != nil {
+			webhook(a)
+		}
 	}
-}
\ No newline at end of file
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	slog.Info("👀 Monitoring", "file", cfg.Monitor.File, "interval", interval)
+	if err := m.Run(ctx, interval); err != nil && !errors.Is(err, context.Canceled) {
+		logging.Fatal("⚠️ Monitor stopped", "err", err)
+	}
+	slog.Info("🛑 Monitor stopped")
+}
+
+// newMonitor sets up the monitor of the list in MONITOR_FILE from
+// MONITOR_INTERVAL and MONITOR_THRESHOLD, and returns it with its
+// interval. Its Alert POSTs to MONITOR_WEBHOOK_URL, or is nil without one.
+func newMonitor(cfg *config.Config, probe bool) (*validator.Monitor, time.Duration) {
+	store, err := validator.NewFileMonitorStore(cfg.Monitor.File)
+	if err != nil {
+		logging.Fatal("Invalid MONITOR_FILE", "err", err)
+	}
+	interval := cfg.Monitor.Interval
+	if interval <= 0 {
+		logging.Fatal("Invalid MONITOR_INTERVAL", "value", interval)
+	}
+	threshold := cfg.Monitor.Threshold // 0 = the FAILING bound
+	if threshold < 0 || threshold > 100 {
+		logging.Fatal("Invalid MONITOR_THRESHOLD (0-100)", "value", threshold)
+	}
+
+	m := &validator.Monitor{Store: store, Threshold: threshold, Probe: probe}
+	if url := cfg.Monitor.WebhookURL; url != "" {
+		m.Alert = validator.WebhookAlerts(url)
+	}
+	return m, interval
+}
+
+// parseArgs parses the flags and returns the positional arguments. Flags
+// may also follow them: ./validator watch <address> --interval 5m
+func parseArgs() []string {
+	flag.Parse()
+	var args []string
+	for flag.NArg() > 0 {
+		args = append(args, flag.Arg(0))
+		flag.CommandLine.Parse(flag.Args()[1:])
+	}
+	return args
+}
+
+// defaultConfig is the settings before the config file and the
+// environment: the built-in defaults of every mode.
+func defaultConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Labels.Providers = "builtin"
+	cfg.Retry.Attempts = validator.DefaultRetryPolicy.Attempts
+	cfg.Retry.BaseDelay = validator.DefaultRetryPolicy.BaseDelay
+	cfg.Retry.MaxDelay = validator.DefaultRetryPolicy.MaxDelay
+	cfg.Breaker.Threshold = validator.DefaultBreakerThreshold
+	cfg.Breaker.Cooldown = validator.DefaultBreakerCooldown
+	cfg.Timeouts.Batch = defaultBatchTimeout
+	cfg.Cache.Backend = "memory"
+	cfg.Cache.BalanceTTL = validator.DefaultBalanceTTL
+	cfg.Cache.HistoryTTL = validator.DefaultHistoryTTL
+	cfg.Cache.PriceTTL = validator.DefaultPriceTTL
+	cfg.Cache.ProfileTTL = defaultProfileCacheTTL
+	cfg.Bitcoin.GapLimit = validator.DefaultGapLimit
+	cfg.Output.FailAbove = -1
+	cfg.Batch.Workers = defaultBatchWorkers
+	cfg.Batch.ProgressInterval = defaultProgressInterval
+	cfg.Watch.Interval = defaultWatchInterval
+	cfg.Monitor.File = "monitor.json"
+	cfg.Monitor.Interval = validator.DefaultMonitorInterval
+	cfg.Serve.Port = "8081"
+	cfg.Serve.MaxJob = defaultMaxJob
+	cfg.Serve.JobTTL = defaultJobTTL
+	cfg.Serve.MaxBatch = defaultMaxBatch
+	cfg.Queue.Stream = defaultQueueStream
+	cfg.Queue.Consumer = defaultQueueConsumer
+	cfg.Queue.ProfilesSubject = defaultProfilesSubject
+	cfg.Queue.AlertsSubject = defaultAlertsSubject
+	cfg.Queue.DeadSubject = defaultDeadSubject
+	cfg.Queue.MaxDeliver = defaultQueueMaxDeliver
+	cfg.Queue.RetryDelay = defaultQueueRetryDelay
+	cfg.Kafka.Group = defaultKafkaGroup
+	cfg.Kafka.Topic = defaultKafkaTopic
+	cfg.Kafka.ProfilesTopic = defaultProfilesSubject
+	cfg.Kafka.AlertsTopic = defaultAlertsSubject
+	cfg.Kafka.DeadTopic = defaultDeadSubject
+	cfg.Kafka.StartOffset = defaultKafkaStartOffset
+	cfg.Kafka.MaxAttempts = defaultKafkaMaxAttempts
+	cfg.Kafka.RetryDelay = defaultKafkaRetryDelay
+
+	// serve --engine syncs like the engine
+	sync := watchlist.DefaultSyncConfig()
+	cfg.Engine.SyncInterval = 12 * time.Hour
+	cfg.Engine.OFACURL = sync.SourceURL
+	cfg.Engine.HTTPTimeout = sync.HTTPTimeout
+	return cfg
+}
@@ -0,0 +1,559 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// OUTPUT FORMATS (json, jsonl, csv, table, --template)
+// ---------------------------------------------------------
+
+// Risk reasons flattened into each CSV row or shown in a table, highest
+// offset first
+const topReasonCount = 3
+
+// ANSI colors for the table format on a terminal (off with --no-color or
+// NO_COLOR)
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// Reasons adding at least this much are red in a table; smaller ones are
+// yellow, and reasons that lower the score green
+const redReasonOffset = 20
+
+// stdoutTerminal reports whether stdout is a terminal rather than a pipe
+// or file.
+func stdoutTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// profileWriter writes profiles in one output format. Close finishes the
+// output (the closing bracket of a JSON array, the CSV flush).
+type profileWriter interface {
+	Write(p *validator.WalletProfile) error
+	Close() error
+}
+
+// newProfileWriter returns the writer for format: "json" (indented; an
+// array when many is set), "jsonl" (one profile per line), "csv" (a
+// header, then one flattened row per profile) or "table" (for terminals: a
+// summary of one profile, or one aligned row per profile, colored by risk
+// when color is set).
+func newProfileWriter(format string, w io.Writer, many, color bool) (profileWriter, error) {
+	switch format {
+	case "json":
+		return &jsonWriter{w: w, many: many}, nil
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &jsonlWriter{enc: enc}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "table":
+		t := &tableWriter{out: w, many: many, color: color}
+		t.w = tabwriter.NewWriter(&t.buf, 0, 0, 2, ' ', 0)
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (json, jsonl, csv or table)", format)
+}
+
+// templateFuncs are available to --template on top of text/template's
+// builtins.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":       strings.Join,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"alerts":     alertTriggers,
+	"sanctioned": sanctioned,
+	"reasons":    topReasons,
+	"time":       csvTime,
+}
+
+// newTemplateWriter returns a writer that executes text (text/template)
+// over each profile, e.g. "{{.RiskGrade}} {{.Address}}", ending each one
+// with a newline unless the template already does. A profile the template
+// renders as nothing is skipped, so {{if}} can filter.
+func newTemplateWriter(text string, w io.Writer) (profileWriter, error) {
+	tmpl, err := template.New("profile").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &templateWriter{tmpl: tmpl, w: w}, nil
+}
+
+// templateWriter writes each profile through a user template.
+type templateWriter struct {
+	tmpl *template.Template
+	w    io.Writer
+}
+
+func (t *templateWriter) Write(p *validator.WalletProfile) error {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, p); err != nil {
+		return err
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *templateWriter) Close() error { return nil }
+
+// webhookWriter also POSTs every profile it writes to a webhook
+// (--webhook-url). A failed delivery is logged and doesn't stop the run.
+type webhookWriter struct {
+	profileWriter
+	post   func(ctx context.Context, p *validator.WalletProfile) error
+	failed int
+}
+
+func (w *webhookWriter) Write(p *validator.WalletProfile) error {
+	if err := w.post(context.Background(), p); err != nil {
+		slog.Warn("⚠️ Webhook delivery failed", "address", p.Address, "err", err)
+		w.failed++
+	}
+	return w.profileWriter.Write(p)
+}
+
+func (w *webhookWriter) Close() error {
+	if w.failed > 0 {
+		slog.Warn("⚠️ Profiles not delivered to the webhook", "count", w.failed)
+	}
+	return w.profileWriter.Close()
+}
+
+// jsonWriter writes one indented profile, or an array of them.
+type jsonWriter struct {
+	w     io.Writer
+	many  bool
+	count int
+}
+
+func (j *jsonWriter) Write(p *validator.WalletProfile) error {
+	data, err := marshalIndent(p, j.many)
+	if err != nil {
+		return err
+	}
+	prefix := ""
+	if j.many {
+		prefix = ",\n  "
+		if j.count == 0 {
+			prefix = "[\n  "
+		}
+	}
+	j.count++
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, data)
+	if err == nil && !j.many {
+		_, err = io.WriteString(j.w, "\n")
+	}
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.many {
+		return nil
+	}
+	if j.count == 0 {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// marshalIndent indents like json.Encoder (no HTML escaping), one level
+// deeper inside an array.
+func marshalIndent(p *validator.WalletProfile, nested bool) ([]byte, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	prefix := ""
+	if nested {
+		prefix = "  "
+	}
+	enc.SetIndent(prefix, "  ")
+	if err := enc.Encode(p); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(b.String(), "\n")), nil
+}
+
+// jsonlWriter writes one compact profile per line.
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(p *validator.WalletProfile) error { return j.enc.Encode(p) }
+func (j *jsonlWriter) Close() error                           { return nil }
+
+// csvWriter flattens profiles for spreadsheet review.
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+var csvColumns = []string{
+	"address", "resolved_from", "network", "is_valid", "validation_details", "is_active",
+	"balance", "balance_usd", "tx_count", "first_seen", "last_seen", "account_type",
+	"risk_score", "risk_grade", "risk_tier", "fraud_risk", "reputation_risk", "lending_risk",
+	"risk_policy", "sanctioned", "alerts", "reason_count", "top_reason_1", "top_reason_2", "top_reason_3",
+	"decision", "decision_rule",
+}
+
+func (c *csvWriter) Write(p *validator.WalletProfile) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write(csvColumns); err != nil {
+			return err
+		}
+	}
+	row := []string{
+		p.Address, p.ResolvedFrom, p.Network, strconv.FormatBool(p.IsValid), p.ValidationDetails, strconv.FormatBool(p.IsActive),
+		p.Balance, csvFloat(p.BalanceUSD), strconv.Itoa(p.TxCount), csvTime(p.FirstSeen), csvTime(p.LastSeen), p.AccountType,
+		strconv.FormatFloat(p.RiskScore, 'f', -1, 64), p.RiskGrade, csvInt(p.RiskTier),
+		strconv.FormatFloat(p.RiskBreakdown.Fraud, 'f', -1, 64),
+		strconv.FormatFloat(p.RiskBreakdown.Reputation, 'f', -1, 64),
+		strconv.FormatFloat(p.RiskBreakdown.Lending, 'f', -1, 64),
+		p.RiskPolicy, strconv.FormatBool(sanctioned(p)), alertTriggers(p), strconv.Itoa(len(p.RiskReasons)),
+	}
+	row = append(row, topReasons(p, topReasonCount)...)
+	if d := p.Decision; d != nil {
+		row = append(row, d.Action, d.Rule)
+	} else {
+		row = append(row, "", "")
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// topReasons formats the n reasons with the highest offsets ("rule_id
+// +40: Description"); missing ones are empty.
+func topReasons(p *validator.WalletProfile, n int) []string {
+	reasons := sortedReasons(p)
+	out := make([]string, n)
+	for i := 0; i < n && i < len(reasons); i++ {
+		r := reasons[i]
+		out[i] = fmt.Sprintf("%s %+g: %s", r.RuleID, r.Offset, r.Description)
+	}
+	return out
+}
+
+// sortedReasons returns the reasons, highest offset first.
+func sortedReasons(p *validator.WalletProfile) []validator.RiskReason {
+	reasons := append([]validator.RiskReason(nil), p.RiskReasons...)
+	sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].Offset > reasons[j].Offset })
+	return reasons
+}
+
+// alertTriggers joins the alert triggers: "SANCTIONS_HIT;HIGH_RISK_SCORE".
+func alertTriggers(p *validator.WalletProfile) string {
+	triggers := make([]string, 0, len(p.Alerts))
+	for _, a := range p.Alerts {
+		triggers = append(triggers, a.Trigger)
+	}
+	return strings.Join(triggers, ";")
+}
+
+// sanctioned reports a direct sanctions hit.
+func sanctioned(p *validator.WalletProfile) bool {
+	for _, r := range p.RiskReasons {
+		if r.RuleID == "sanctions" {
+			return true
+		}
+	}
+	return false
+}
+
+func csvFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 2, 64)
+}
+
+func csvInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// tableWriter is for reading in a terminal. One profile is a summary block
+// (grade, alerts, top reasons); many are an aligned table, written on
+// Close so the columns line up. Colors are added after alignment, since
+// tabwriter would count the escape codes as text.
+type tableWriter struct {
+	out    io.Writer
+	buf    bytes.Buffer
+	w      *tabwriter.Writer
+	many   bool
+	header bool
+	color  bool
+	lines  int     // Lines written to w so far
+	paints []paint // Text to color once aligned, in line and column order
+}
+
+// paint colors the first occurrence of text on a line of the table (after
+// the previous paint on the same line); no color only skips past it.
+type paint struct {
+	line  int
+	text  string
+	color string
+}
+
+// paint colors text on the line about to be written.
+func (t *tableWriter) paint(text, color string) {
+	if t.color && text != "" {
+		t.paints = append(t.paints, paint{t.lines, text, color})
+	}
+}
+
+// println writes one line of cells separated by tabs.
+func (t *tableWriter) println(cells ...string) error {
+	t.lines++
+	_, err := fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+	return err
+}
+
+func (t *tableWriter) Write(p *validator.WalletProfile) error {
+	if t.many {
+		return t.row(p)
+	}
+	return t.summary(p)
+}
+
+func (t *tableWriter) Close() error {
+	if err := t.w.Flush(); err != nil {
+		return err
+	}
+	lines := strings.SplitAfter(t.buf.String(), "\n")
+	from := map[int]int{} // Where the next paint on each line may start
+	for _, p := range t.paints {
+		line := lines[p.line]
+		i := strings.Index(line[from[p.line]:], p.text)
+		if i < 0 {
+			continue
+		}
+		i += from[p.line]
+		if p.color == "" {
+			from[p.line] = i + len(p.text)
+			continue
+		}
+		lines[p.line] = line[:i] + p.color + p.text + ansiReset + line[i+len(p.text):]
+		from[p.line] = i + len(p.color) + len(p.text) + len(ansiReset)
+	}
+	t.buf.Reset()
+	_, err := io.WriteString(t.out, strings.Join(lines, ""))
+	return err
+}
+
+// gradeColor is green below the WARNING bound, yellow for WARNING and red
+// from FAILING up (sanctioned included); invalid profiles are yellow.
+func gradeColor(p *validator.WalletProfile) string {
+	grades := validator.ActiveRiskRules().Grades
+	switch {
+	case !p.IsValid, p.RiskScore >= grades.Low && p.RiskScore < grades.Warning:
+		return ansiYellow
+	case p.RiskScore < grades.Low:
+		return ansiGreen
+	}
+	return ansiRed
+}
+
+// reasonColor is red for reasons adding redReasonOffset or more, yellow
+// for smaller additions and green for reasons that lower the score.
+func reasonColor(offset float64) string {
+	switch {
+	case offset >= redReasonOffset:
+		return ansiRed
+	case offset > 0:
+		return ansiYellow
+	case offset < 0:
+		return ansiGreen
+	}
+	return ""
+}
+
+// decisionColor is green for APPROVE, yellow for REVIEW, red for REJECT.
+func decisionColor(action string) string {
+	switch action {
+	case validator.DecisionApprove:
+		return ansiGreen
+	case validator.DecisionReview:
+		return ansiYellow
+	case validator.DecisionReject:
+		return ansiRed
+	}
+	return ""
+}
+
+// severityColor is red for CRITICAL and HIGH alerts, yellow for MEDIUM.
+func severityColor(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return ansiRed
+	case "MEDIUM":
+		return ansiYellow
+	}
+	return ""
+}
+
+// alertsColor is the color of the most severe alert.
+func alertsColor(alerts []validator.Alert) string {
+	color := ""
+	for _, a := range alerts {
+		if c := severityColor(a.Severity); c == ansiRed {
+			return c
+		} else if c != "" {
+			color = c
+		}
+	}
+	return color
+}
+
+// row writes one profile as a table row: the grade and the top reason.
+func (t *tableWriter) row(p *validator.WalletProfile) error {
+	if !t.header {
+		t.header = true
+		if err := t.println("ADDRESS", "NETWORK", "SCORE", "GRADE", "DECISION", "ALERTS", "TOP REASON"); err != nil {
+			return err
+		}
+	}
+	t.paint(p.Address, "")
+	t.paint(p.Network, "")
+	if !p.IsValid {
+		t.paint("INVALID", gradeColor(p))
+		return t.println(p.Address, p.Network, "-", "INVALID", "-", "-", p.ValidationDetails)
+	}
+	score := fmt.Sprintf("%.1f", p.RiskScore)
+	alerts, top := alertTriggers(p), topReasons(p, 1)[0]
+	if alerts == "" {
+		alerts = "-"
+	}
+	t.paint(score, gradeColor(p))
+	t.paint(p.RiskGrade, gradeColor(p))
+	decision := "-"
+	if p.Decision != nil {
+		decision = p.Decision.Action
+		t.paint(decision, decisionColor(decision))
+	}
+	t.paint(alerts, alertsColor(p.Alerts))
+	if top == "" {
+		top = "-"
+	} else {
+		t.paint(top, reasonColor(sortedReasons(p)[0].Offset))
+	}
+	return t.println(p.Address, p.Network, score, p.RiskGrade, decision, alerts, top)
+}
+
+// summary writes one profile as labelled lines.
+func (t *tableWriter) summary(p *validator.WalletProfile) error {
+	line := func(label, color, format string, args ...any) {
+		value := fmt.Sprintf(format, args...)
+		t.paint(label, "")
+		t.paint(value, color)
+		t.println(label, value)
+	}
+	address := p.Address
+	if p.ResolvedFrom != "" {
+		address = fmt.Sprintf("%s (%s)", p.Address, p.ResolvedFrom)
+	}
+	line("Address", "", "%s", address)
+	line("Network", "", "%s", p.Network)
+	if p.Cached != nil {
+		line("Cached", "", "%s ago (--no-cache to screen again)", time.Duration(p.Cached.AgeSeconds)*time.Second)
+	}
+	if !p.IsValid {
+		line("Status", gradeColor(p), "INVALID: %s", p.ValidationDetails)
+		return nil
+	}
+	if p.Balance != "" {
+		balance := p.Balance
+		if p.BalanceUSD != nil {
+			balance = fmt.Sprintf("%s ($%.2f)", p.Balance, *p.BalanceUSD)
+		}
+		line("Balance", "", "%s", balance)
+	}
+	if !p.Partial {
+		activity := fmt.Sprintf("%d txs", p.TxCount)
+		if p.FirstSeen != nil && p.LastSeen != nil {
+			activity += fmt.Sprintf(", %s to %s", p.FirstSeen.Format("2006-01-02"), p.LastSeen.Format("2006-01-02"))
+		}
+		line("Activity", "", "%s", activity)
+	}
+	line("Risk Score", gradeColor(p), "%.1f / 100", p.RiskScore)
+	line("Grade", gradeColor(p), "%s", p.RiskGrade)
+	if d := p.Decision; d != nil {
+		line("Decision", decisionColor(d.Action), "%s (%s: %s)", d.Action, d.Rule, d.Reason)
+	}
+	if !p.Partial {
+		b := p.RiskBreakdown
+		line("Breakdown", "", "Fraud %.1f, Reputation %.1f, Lending %.1f", b.Fraud, b.Reputation, b.Lending)
+	}
+	if p.RiskPolicy != "" {
+		line("Policy", "", "%s", p.RiskPolicy)
+	}
+	if p.Partial {
+		line("Checked", "", "Syntax and sanctions only (--offline)")
+	}
+	for i, a := range p.Alerts {
+		label := ""
+		if i == 0 {
+			label = "Alerts"
+		}
+		line(label, severityColor(a.Severity), "%s %s: %s", a.Severity, a.Trigger, a.Message)
+	}
+
+	reasons := sortedReasons(p)
+	for i, r := range reasons[:min(len(reasons), topReasonCount)] {
+		label := ""
+		if i == 0 {
+			label = "Top Reasons"
+		}
+		line(label, reasonColor(r.Offset), "%+g %s: %s", r.Offset, r.RuleID, r.Description)
+	}
+	if more := len(reasons) - topReasonCount; more > 0 {
+		line("", "", "(%d more; --format json for all)", more)
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ---------------------------------------------------------
+// PDF COMPLIANCE REPORT (screening evidence for auditors)
+// ---------------------------------------------------------
+
+// writePDFReport writes the compliance report: the decision, when the
+// address was screened and against which sanctions list version, the
+// alerts, reasons and data sources. The profile JSON is attached to the
+// PDF and its SHA-256 printed on every page, so an auditor can check the
+// report against the record it came from. The file is created read-only
+// and never replaces an existing one.
+func writePDFReport(path string, d reportData) error {
+	// The same bytes --format json prints for this run
+	record, err := marshalIndent(d.Profile, false)
+	if err != nil {
+		return err
+	}
+	record = append(record, '\n')
+	sum := sha256.Sum256(record)
+
+	doc := newPDFReport(d, hex.EncodeToString(sum[:]), record)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o444)
+	if err != nil {
+		return err
+	}
+	if err := doc.pdf.Output(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newPDFReport lays the report out; nothing is written until Output.
+func newPDFReport(d reportData, digest string, record []byte) *pdfDoc {
+	doc := newPDFDoc("Evidence SHA-256 (profile.json): " + digest)
+	pdf := doc.pdf
+	pdf.SetTitle("Wallet Screening Report: "+d.Profile.Address, true)
+	pdf.SetSubject(d.Decision.Verdict+": "+d.Decision.Reason, true)
+	pdf.SetProducer("crypto-profiler", false)
+	pdf.SetCreationDate(d.GeneratedAt)
+	pdf.SetModificationDate(d.GeneratedAt)
+	pdf.SetAttachments([]fpdf.Attachment{{Content: record, Filename: "profile.json", Description: "Screening record"}})
+	pdf.AddPage()
+	renderPDFReport(doc, d, digest)
+	return doc
+}
+
+// Decision banner colors (RGB)
+var pdfVerdictColors = map[string][3]int{
+	"BLOCK":    {183, 28, 28},
+	"ESCALATE": {230, 81, 0},
+	"REVIEW":   {249, 168, 37},
+	"CLEAR":    {46, 125, 50},
+}
+
+func renderPDFReport(doc *pdfDoc, d reportData, digest string) {
+	p := d.Profile
+	doc.line(pdfBold, 18, 0, "Wallet Screening Report")
+	doc.pdf.SetTextColor(117, 117, 117)
+	doc.line(pdfRegular, 9, 0, "Generated "+d.GeneratedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	doc.pdf.SetTextColor(0, 0, 0)
+	doc.space(10)
+
+	// Decision banner, then why
+	color, ok := pdfVerdictColors[d.Decision.Verdict]
+	if !ok {
+		color = [3]int{117, 117, 117}
+	}
+	doc.banner(color, "DECISION: "+d.Decision.Verdict)
+	doc.paragraph(pdfRegular, 10, 0, d.Decision.Reason)
+
+	doc.heading("Screening")
+	address := p.Address
+	if p.ResolvedFrom != "" {
+		address += " (" + p.ResolvedFrom + ")"
+	}
+	doc.field("Address", address)
+	network := p.Network
+	if p.Testnet {
+		network += " (testnet)"
+	}
+	doc.field("Network", network)
+	doc.field("Screened At", d.ScreenedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	doc.field("Sanctions List", "OFAC SDN List (U.S. Treasury)")
+	lastModified, syncedAt := "unknown (watchlist unavailable or never synced)", "unknown"
+	if v := p.SanctionsList; v != nil {
+		if v.LastModified != "" {
+			lastModified = v.LastModified
+		}
+		if v.SyncedAt != "" {
+			syncedAt = v.SyncedAt
+		}
+	}
+	doc.field("OFAC Last-Modified", lastModified)
+	doc.field("List Synced At", syncedAt)
+	doc.field("Watchlist", watchlistSource())
+	if p.IsValid {
+		doc.field("Risk Score", fmt.Sprintf("%.1f / 100 (%s)", p.RiskScore, p.RiskGrade))
+		b := p.RiskBreakdown
+		doc.field("Breakdown", fmt.Sprintf("Fraud %.1f, Reputation %.1f, Lending %.1f", b.Fraud, b.Reputation, b.Lending))
+		doc.field("Policy", fmt.Sprintf("%s (risk schema v%s)", d.Policy, p.RiskSchema))
+		if p.Balance != "" {
+			doc.field("Balance", strings.TrimSpace(p.Balance+" "+usdText(p.BalanceUSD)))
+		}
+		activity := fmt.Sprintf("%d txs", p.TxCount)
+		if p.FirstSeen != nil && p.LastSeen != nil {
+			activity += fmt.Sprintf(", %s to %s", p.FirstSeen.Format("2006-01-02"), p.LastSeen.Format("2006-01-02"))
+		}
+		doc.field("Activity", activity)
+	}
+	doc.field("Details", p.ValidationDetails)
+
+	doc.heading("Alerts")
+	if len(p.Alerts) == 0 {
+		doc.paragraph(pdfRegular, 10, 0, "None.")
+	}
+	for _, a := range p.Alerts {
+		doc.paragraph(pdfBold, 10, 0, fmt.Sprintf("%s %s: %s", a.Severity, a.Trigger, a.Message))
+		doc.paragraph(pdfRegular, 10, 12, "Recommended action: "+a.Action)
+		doc.space(3)
+	}
+
+	doc.heading("Risk Reasons")
+	if len(d.Reasons) == 0 {
+		doc.paragraph(pdfRegular, 10, 0, "No risk factors.")
+	}
+	for _, r := range d.Reasons {
+		doc.paragraph(pdfRegular, 10, 0, fmt.Sprintf("%+g  %s (%s, %s): %s", r.Offset, r.RuleID, r.Category, r.Severity, r.Description))
+		if e := r.Evidence; e != nil {
+			for _, h := range e.TxHashes {
+				doc.paragraph(pdfMono, 8, 12, "tx "+h)
+			}
+			for _, a := range e.Addresses {
+				doc.paragraph(pdfMono, 8, 12, "address "+a)
+			}
+			for _, t := range e.Timestamps {
+				doc.paragraph(pdfMono, 8, 12, "at "+t.UTC().Format(time.RFC3339))
+			}
+		}
+		doc.space(3)
+	}
+
+	doc.heading("Data Sources")
+	for _, s := range d.Sources {
+		doc.field(s.Name, s.Detail)
+	}
+
+	doc.heading("Evidence")
+	doc.paragraph(pdfRegular, 10, 0, "The complete screening record is attached to this PDF as profile.json. Its SHA-256 digest, printed at the foot of every page, is:")
+	doc.paragraph(pdfMono, 9, 12, digest)
+	doc.paragraph(pdfRegular, 10, 0, "Extract the attachment and recompute the digest to confirm the record is unaltered. Scores and reasons reflect the data sources above at the screening time; on-chain activity and sanctions lists change.")
+	doc.space(24)
+	doc.line(pdfRegular, 10, 0, "Reviewed by: ______________________________    Date: ________________")
+}
+
+func usdText(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return fmt.Sprintf("($%.2f)", *f)
+}
+
+// ---------------------------------------------------------
+// PDF LAYOUT (A4 text pages in the standard fonts, nothing embedded)
+// ---------------------------------------------------------
+
+const (
+	pdfMargin     = 50.0 // Points
+	pdfLabelWidth = 130.0
+)
+
+// Fonts, as family and style
+type pdfFont struct{ family, style string }
+
+var (
+	pdfRegular = pdfFont{"Helvetica", ""}
+	pdfBold    = pdfFont{"Helvetica", "B"}
+	pdfMono    = pdfFont{"Courier", ""}
+)
+
+// pdfDoc lays text out top to bottom; fpdf starts a new page when one
+// fills and draws the header and footer on each.
+type pdfDoc struct {
+	pdf   *fpdf.Fpdf
+	toWin func(string) string // UTF-8 to the fonts' code page (cp1252)
+}
+
+func newPDFDoc(footer string) *pdfDoc {
+	pdf := fpdf.New("P", "pt", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCatalogSort(true)
+	pdf.AliasNbPages("{nb}")
+	d := &pdfDoc{pdf: pdf, toWin: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	_, height := pdf.GetPageSize()
+	pdf.SetHeaderFunc(func() {
+		pdf.SetTextColor(117, 117, 117)
+		pdf.SetFont(pdfRegular.family, pdfRegular.style, 8)
+		pdf.SetXY(pdfMargin, 22)
+		pdf.CellFormat(0, 10, "Wallet Screening Report", "", 0, "L", false, 0, "")
+		pdf.SetX(pdfMargin)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetY(pdfMargin)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetTextColor(117, 117, 117)
+		pdf.SetFont(pdfMono.family, pdfMono.style, 6.5)
+		pdf.Text(pdfMargin, height-28, d.text(footer))
+		pdf.SetTextColor(0, 0, 0)
+	})
+	return d
+}
+
+// text converts s for the standard fonts. Typographic punctuation is kept;
+// emoji and other characters the fonts lack are dropped rather than shown
+// as dots.
+func (d *pdfDoc) text(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b.WriteByte(' ')
+		case r == '→':
+			b.WriteString("->")
+		case r < 0x20:
+		case r < 0x80:
+			b.WriteRune(r)
+		default:
+			if c := d.toWin(string(r)); c != "." {
+				b.WriteString(c)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (d *pdfDoc) space(h float64) { d.pdf.Ln(h) }
+
+// line writes one line of text, x points in from the margin.
+func (d *pdfDoc) line(font pdfFont, size, x float64, text string) {
+	d.pdf.SetFont(font.family, font.style, size)
+	d.pdf.SetX(pdfMargin + x)
+	d.pdf.CellFormat(0, size*1.4, d.text(text), "", 1, "L", false, 0, "")
+}
+
+// paragraph writes text wrapped to the page width, x points in. Words
+// wider than a line (hashes, addresses) are split.
+func (d *pdfDoc) paragraph(font pdfFont, size, x float64, text string) {
+	d.pdf.SetFont(font.family, font.style, size)
+	d.pdf.SetX(pdfMargin + x)
+	d.pdf.MultiCell(0, size*1.4, d.text(text), "", "L", false)
+}
+
+func (d *pdfDoc) heading(text string) {
+	_, height := d.pdf.GetPageSize()
+	if d.pdf.GetY()+60 > height-pdfMargin { // Keep a heading with its first lines
+		d.pdf.AddPage()
+	}
+	d.space(12)
+	d.line(pdfBold, 13, 0, text)
+	width, _ := d.pdf.GetPageSize()
+	y := d.pdf.GetY() + 2
+	d.pdf.SetDrawColor(204, 204, 204)
+	d.pdf.SetLineWidth(0.5)
+	d.pdf.Line(pdfMargin, y, width-pdfMargin, y)
+	d.space(8)
+}
+
+// field writes a label and its value, wrapped in the value column.
+func (d *pdfDoc) field(label, value string) {
+	d.pdf.SetFont(pdfBold.family, pdfBold.style, 10)
+	d.pdf.SetX(pdfMargin)
+	d.pdf.CellFormat(pdfLabelWidth, 14, d.text(label), "", 0, "L", false, 0, "")
+	d.pdf.SetFont(pdfRegular.family, pdfRegular.style, 10)
+	if value = d.text(value); value == "" {
+		d.pdf.Ln(14)
+		return
+	}
+	d.pdf.MultiCell(0, 14, value, "", "L", false)
+}
+
+// banner writes text in white on a full-width colored bar.
+func (d *pdfDoc) banner(color [3]int, text string) {
+	d.pdf.SetFillColor(color[0], color[1], color[2])
+	d.pdf.SetTextColor(255, 255, 255)
+	d.pdf.SetFont(pdfBold.family, pdfBold.style, 15)
+	margin := d.pdf.GetCellMargin()
+	d.pdf.SetCellMargin(10)
+	d.pdf.CellFormat(0, 30, d.text(text), "", 1, "LM", true, 0, "")
+	d.pdf.SetCellMargin(margin)
+	d.pdf.SetTextColor(0, 0, 0)
+	d.space(4)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// renderUncompressed lays out the report for p with its text readable in
+// the output.
+func renderUncompressed(t *testing.T, p *validator.WalletProfile, digest string, record []byte) (*pdfDoc, []byte) {
+	t.Helper()
+	doc := newPDFReport(newReportData(p, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)), digest, record)
+	doc.pdf.SetCompression(false)
+	var out bytes.Buffer
+	if err := doc.pdf.Output(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) || !bytes.HasSuffix(bytes.TrimSpace(out.Bytes()), []byte("%%EOF")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	return doc, out.Bytes()
+}
+
+func TestWritePDFReport(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := &validator.WalletProfile{Address: "0x742d35cc6634c0532925a3b844bc454e4438f44e", Network: "Ethereum Mainnet", Symbol: "ETH"}
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := writePDFReport(path, newReportData(p, now)); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm()&0o222 != 0 {
+		t.Errorf("report is writable: %v, %v", info.Mode(), err)
+	}
+
+	if err := writePDFReport(path, newReportData(p, now)); !errors.Is(err, os.ErrExist) {
+		t.Errorf("overwrote an existing report: %v", err)
+	}
+}
+
+func TestPDFReportContents(t *testing.T) {
+	p := &validator.WalletProfile{Address: "0x742d35cc6634c0532925a3b844bc454e4438f44e", Network: "Ethereum Mainnet", Symbol: "ETH"}
+	record, err := marshalIndent(p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record = append(record, '\n')
+	sum := sha256.Sum256(record)
+	digest := hex.EncodeToString(sum[:])
+
+	doc, pdf := renderUncompressed(t, p, digest, record)
+	if n := doc.pdf.PageCount(); n != 1 {
+		t.Errorf("got %d pages, want 1", n)
+	}
+	for _, want := range []string{
+		"(Evidence SHA-256 \\(profile.json\\): " + digest + ")", // Footer
+		"(Page 1 of 1)",
+		"(Wallet Screening Report)",
+		"(DECISION: ",
+		"/CreationDate (D:20260102030405",
+		"/EmbeddedFiles",
+	} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("no %q", want)
+		}
+	}
+
+	// The attachment is the record: fpdf stores its MD5 with it
+	if attached := md5.Sum(record); !bytes.Contains(pdf, []byte(fmt.Sprintf("/CheckSum <%x>", attached))) {
+		t.Error("profile.json isn't the record")
+	}
+}
+
+func TestPDFReportPages(t *testing.T) {
+	p := &validator.WalletProfile{Address: "0x742d35cc6634c0532925a3b844bc454e4438f44e", Network: "Ethereum Mainnet", Symbol: "ETH", IsValid: true}
+	for i := 0; i < 60; i++ {
+		p.RiskReasons = append(p.RiskReasons, validator.RiskReason{
+			RuleID:      fmt.Sprintf("rule_%d", i),
+			Category:    "FRAUD",
+			Offset:      1,
+			Description: strings.Repeat("A long description that wraps onto a second line. ", 3),
+			Evidence:    &validator.Evidence{TxHashes: []string{"0x" + strings.Repeat("ab", 32)}},
+		})
+	}
+	doc, pdf := renderUncompressed(t, p, strings.Repeat("0", 64), []byte("{}\n"))
+	n := doc.pdf.PageCount()
+	if n < 3 {
+		t.Fatalf("got %d pages for 60 reasons", n)
+	}
+	for page := 1; page <= n; page++ {
+		if !bytes.Contains(pdf, []byte(fmt.Sprintf("(Page %d of %d)", page, n))) {
+			t.Errorf("page %d isn't numbered", page)
+		}
+	}
+	if got := bytes.Count(pdf, []byte("(Evidence SHA-256 ")); got != n {
+		t.Errorf("footer on %d of %d pages", got, n)
+	}
+}
+
+func TestPDFText(t *testing.T) {
+	doc := newPDFDoc("")
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"  trimmed\t", "trimmed"},
+		{"a\tb", "a b"},
+		{"(x) \\ y", "(x) \\ y"}, // fpdf escapes when it writes
+		{"café", "caf\xe9"},
+		{"€5 — “ok”", "\x805 \x97 \x93ok\x94"},
+		{"A → B", "A -> B"},
+		{"🚨 alert", "alert"},
+		{"line\nbreak", "linebreak"},
+		{"ЛАЗАРЬ", ""}, // Not in the fonts' code page
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := doc.text(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
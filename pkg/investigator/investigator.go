@@ -0,0 +1,74 @@
+// Package investigator is the risk-scoring engine on its own, for Go
+// services that fetch chain data themselves. Every dependency is injected:
+// the sanctions watchlist, the counterparty label providers, the rules and
+// the clock. The implementation lives in internal/validator and the types
+// it reads and writes in pkg/profile; this package re-exports both.
+//
+//	inv, err := investigator.New(
+//		investigator.WithWatchlist(myWatchlist),
+//		investigator.WithLabels(investigator.BuiltinLabels{}),
+//		investigator.WithRules(rules),
+//	)
+//	inv.Investigate(ctx, profile, txs) // Sets the score, grade, reasons, alerts and decision
+package investigator
+
+import (
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/piyushdaiya/crypto-profiler/pkg/profile"
+)
+
+// The engine and what it reads and writes
+type (
+	Investigator    = validator.Investigator
+	Option          = validator.InvestigatorOption
+	WatchlistClient = profile.WatchlistClient
+	EngineResponse  = profile.EngineResponse
+	WatchlistResult = profile.Result
+	ListVersion     = profile.ListVersion
+	WalletProfile   = profile.WalletProfile
+	Transaction     = profile.Transaction
+	RiskRules       = validator.RiskRules
+	RiskReason      = profile.RiskReason
+	Alert           = profile.Alert
+	Decision        = profile.Decision
+	Labels          = profile.Labels
+	Label           = profile.Label
+)
+
+// Default dependencies
+type (
+	// EngineWatchlist queries the engine or database set with SetWatchlistEngine.
+	EngineWatchlist = validator.EngineWatchlist
+	// BuiltinLabels serves the built-in threats, bridges, DEXs and exchanges.
+	BuiltinLabels = validator.BuiltinLabels
+)
+
+// New returns an Investigator. Dependencies not given default to the
+// process-wide ones (EngineWatchlist, the built-in labels, the default
+// rules and the system clock).
+func New(opts ...Option) (*Investigator, error) { return validator.NewInvestigator(opts...) }
+
+// SetWatchlistEngine points EngineWatchlist at the Watchlist Engine at
+// engineURL (empty = http://localhost:8080), or, with a dbPath, at that
+// database opened in-process instead.
+func SetWatchlistEngine(engineURL, dbPath string) { validator.SetWatchlistEngine(engineURL, dbPath) }
+
+// WithWatchlist sets the sanctions watchlist client.
+func WithWatchlist(client WatchlistClient) Option { return validator.WithWatchlist(client) }
+
+// WithLabels sets the counterparty label providers, asked in order.
+func WithLabels(providers ...Labels) Option { return validator.WithLabelProviders(providers...) }
+
+// WithRules sets the policy; New validates it.
+func WithRules(rules RiskRules) Option { return validator.WithRules(rules) }
+
+// WithClock sets what "now" is for ages, idle times and decay.
+func WithClock(now func() time.Time) Option { return validator.WithClock(now) }
+
+// DefaultRules returns the built-in policy.
+func DefaultRules() RiskRules { return validator.DefaultRiskRules() }
+
+// PolicyRules returns a named policy (exchange, lender, nft_marketplace).
+func PolicyRules(name string) (RiskRules, error) { return validator.PolicyRules(name) }
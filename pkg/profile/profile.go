@@ -0,0 +1,345 @@
+// Package profile defines the wallet profile the profiler produces and the
+// types around it: what a chain strategy returns, what the investigator
+// adds, and the stores and label providers it reads and writes. It depends
+// on nothing else in this module, so strategies, clients and stores built
+// outside it can use the types directly; internal/validator and the public
+// profiler and investigator packages all share them.
+package profile
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+type WalletProfile struct {
+	Address           string     `json:"address"`
+	ResolvedFrom      string     `json:"resolved_from,omitempty"` // Original name, e.g. "alice.sol"
+	Network           string     `json:"network"`
+	AddressType       string     `json:"address_type,omitempty"` // e.g. P2PKH, P2WPKH, P2TR
+	IsValid           bool       `json:"is_valid"`
+	ValidationDetails string     `json:"validation_details"`
+	IsActive          bool       `json:"is_active"`
+	Balance           string     `json:"balance"`               // Display string, e.g. "0.0042 ETH"
+	BalanceRaw        *big.Int   `json:"balance_raw,omitempty"` // Base units (wei, sats, lamports)
+	Decimals          int        `json:"decimals,omitempty"`
+	Symbol            string     `json:"symbol,omitempty"`
+	BalanceUSD        *float64   `json:"balance_usd,omitempty"` // nil if unpriced (testnet, unknown asset)
+	PriceUSD          *float64   `json:"price_usd,omitempty"`   // Native asset's unit price (single network)
+	TxCount           int        `json:"tx_count"`
+	InternalTxCount   int        `json:"internal_tx_count,omitempty"` // EVM internal calls (txlistinternal)
+	FirstSeen         *time.Time `json:"first_seen,omitempty"`
+	LastSeen          *time.Time `json:"last_seen,omitempty"`
+	Testnet           bool       `json:"testnet,omitempty"`
+
+	// EVM only: EOA, CONTRACT or SMART_ACCOUNT (Safe, ERC-4337, EIP-7702)
+	AccountType      string `json:"account_type,omitempty"`
+	ContractName     string `json:"contract_name,omitempty"`
+	ContractVerified *bool  `json:"contract_verified,omitempty"` // nil if unknown
+
+	// EVM only: Gnosis Safe threshold and owners (nested Safes expanded)
+	Safe *SafeInfo `json:"safe,omitempty"`
+
+	// EVM only: upgradability and owner privileges of a plain contract
+	ContractRisk *ContractRisk `json:"contract_risk,omitempty"`
+
+	// Sanctioned addresses linked to an SDN entity: its other addresses
+	SanctionedEntity *SanctionedEntity `json:"sanctioned_entity,omitempty"`
+
+	// --- NEW: Advanced Risk Scoring ---
+	RiskScore     float64      `json:"risk_score"`            // Combined Score (0-100)
+	RiskGrade     string       `json:"risk_grade"`            // EXCELLENT, NEUTRAL, FAILING, etc.
+	RiskTier      int          `json:"risk_tier,omitempty"`   // 1 (best) to 5 (sanctioned); grades.tiers only
+	RiskBreakdown RiskCategory `json:"risk_breakdown"`        // Fraud, Reputation, Lending
+	RiskReasons   []RiskReason `json:"risk_reasons"`          // Explainable offsets
+	RiskSchema    string       `json:"risk_schema"`           // RiskSchemaVersion
+	RiskPolicy    string       `json:"risk_policy,omitempty"` // Named policy the rules are based on
+	Alerts        []Alert      `json:"alerts,omitempty"`      // Conditions to route on (rules: alerts)
+	Decision      *Decision    `json:"decision,omitempty"`    // APPROVE, REVIEW or REJECT (rules: decisions)
+
+	// HISTORY_FILE only: the previous stored run for this address
+	RiskTrend *RiskTrend `json:"risk_trend,omitempty"`
+
+	// EVM only: non-zero ERC-20 balances; stablecoins summed 1:1 in USD
+	TokenHoldings   []TokenBalance `json:"token_holdings,omitempty"`
+	StablecoinTotal float64        `json:"stablecoin_total_usd,omitempty"`
+
+	// EVM only (no Tron strategy, so no TRC-20): per-stablecoin balances and
+	// recent (30d) transfer volume, summed across chains. Sanctions and fraud
+	// flows are mostly stablecoins.
+	Stablecoins []StablecoinActivity `json:"stablecoins,omitempty"`
+
+	// EVM only (EVM_NFTS=true): NFTs currently held, per collection
+	NFTHoldings []NFTCollection `json:"nft_holdings,omitempty"`
+
+	// EVM only (EVM_APPROVALS=true): live ERC-20 / NFT operator approvals
+	Approvals []TokenApproval `json:"approvals,omitempty"`
+
+	// Per-chain breakdown (multi-network EVM mode only)
+	Chains []ChainActivity `json:"chains,omitempty"`
+
+	// Bitcoin extended keys (xpub/ypub/zpub): the used derived addresses
+	DerivedAddresses []DerivedAddress `json:"derived_addresses,omitempty"`
+
+	// Bitcoin addresses only: the current UTXO set, summarized
+	UTXOs *UTXOSummary `json:"utxos,omitempty"`
+
+	// Lightning only: node metadata and the decoded invoice
+	Lightning *LightningInfo `json:"lightning,omitempty"`
+
+	// EVM only: lookalikes of real counterparties that sent zero-value or dust
+	// transfers (set by the investigator)
+	AddressPoisoning *PoisoningInfo `json:"address_poisoning,omitempty"`
+
+	// Share of volume with the largest counterparties (set by the investigator)
+	Concentration *ConcentrationInfo `json:"concentration,omitempty"`
+
+	// EVM and Bitcoin (PEEL_HOPS > 0): remainders passed on hop after hop,
+	// each hop splitting off a small amount
+	PeelChain *PeelChainInfo `json:"peel_chain,omitempty"`
+
+	// Counterparties that a label provider knows (largest first)
+	CounterpartyLabels []CounterpartyLabels `json:"counterparty_labels,omitempty"`
+
+	// EVM only: counterparties attributed to exchanges (hot wallets, and
+	// deposit addresses with EVM_DEPOSITS=true)
+	ExchangeLinks []ExchangeLink `json:"exchange_links,omitempty"`
+
+	// EVM only: activity with known cross-chain bridges
+	Bridges []BridgeUsage `json:"bridges,omitempty"`
+
+	// Busiest stretch of each velocity window (RiskRules.VelocityWindows)
+	Velocity []VelocityWindow `json:"velocity,omitempty"`
+
+	// When the address transacts (hours, weekdays) and how regularly
+	Temporal *TemporalProfile `json:"temporal,omitempty"`
+
+	// EVM only: MEV bot or DEX market maker, instead of the velocity
+	// heuristic's "Potential Bot" (set by the investigator)
+	Bot *BotInfo `json:"bot,omitempty"`
+
+	// EVM only: flash-loan provider activity and exploit-preparation signals
+	FlashLoans *FlashLoanInfo `json:"flash_loans,omitempty"`
+
+	// EVM only: airdrop-farming signals (funder checks need EVM_SYBIL=true)
+	Sybil *SybilInfo `json:"sybil,omitempty"`
+
+	// EVM only (EVM_WASH_TRADING=true): a closed cluster trading tokens or
+	// NFTs among itself
+	WashTrading *WashTradingInfo `json:"wash_trading,omitempty"`
+
+	// EVM only (EXPOSURE_HOPS > 0): share of funds within N hops of
+	// sanctioned or mixer addresses, per hop
+	Exposure []HopExposure `json:"exposure,omitempty"`
+
+	// Probe mode only: every chain whose syntax matched, and what it found
+	Probes []ProbeMatch `json:"probes,omitempty"`
+
+	// Upstream providers that served the balance, history or UTXOs
+	// (chains with provider failover: EVM, Bitcoin, Solana)
+	DataSources []string `json:"data_sources,omitempty"`
+
+	// When the investigator ran, and the sanctions list version it checked
+	// against (nil if the watchlist was unavailable or never synced)
+	ScreenedAt    *time.Time   `json:"screened_at,omitempty"`
+	SanctionsList *ListVersion `json:"sanctions_list,omitempty"`
+
+	// Offline mode (SetOffline): only syntax and sanctions were checked, so
+	// the score covers sanctions alone
+	Partial bool `json:"partial,omitempty"`
+
+	// CLI only: served from the local profile cache (PROFILE_CACHE_TTL)
+	// instead of screened again
+	Cached *CachedProfile `json:"cached,omitempty"`
+
+	// Extra addresses the investigator screens against the watchlist
+	// (every derived address, used or not); not serialized
+	ScreenAddresses []string `json:"-"`
+}
+
+// CachedProfile says when a cached profile was screened, and how long ago.
+type CachedProfile struct {
+	StoredAt   time.Time `json:"stored_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+}
+
+// DerivedAddress is one address derived from an extended public key.
+type DerivedAddress struct {
+	Path    string `json:"path"` // Relative to the key: 0/i receive, 1/i change
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	TxCount int    `json:"tx_count"`
+}
+
+// UTXOSummary describes how an address holds its coins. Pattern is EMPTY,
+// NORMAL, DUSTED (several dust outputs), FRAGMENTED (many unspent receipts)
+// or CONSOLIDATED (long history swept into one or two outputs).
+type UTXOSummary struct {
+	Count       int    `json:"count"`
+	Total       string `json:"total"`
+	Largest     string `json:"largest,omitempty"`
+	Unconfirmed int    `json:"unconfirmed,omitempty"`
+	DustCount   int    `json:"dust_count"` // Outputs of 1000 sats or less
+	DustValue   string `json:"dust_value,omitempty"`
+	DustOutputs []UTXO `json:"dust_outputs,omitempty"` // First 20
+	Pattern     string `json:"pattern"`
+}
+
+// UTXO is one unspent output paying the address.
+type UTXO struct {
+	Txid      string `json:"txid"`
+	Vout      int    `json:"vout"`
+	Value     int64  `json:"value"` // Satoshis
+	Confirmed bool   `json:"confirmed"`
+}
+
+// ChainActivity is the activity of one address on a single EVM network.
+type ChainActivity struct {
+	ChainID     string     `json:"chain_id"`
+	Network     string     `json:"network"`
+	AccountType string     `json:"account_type,omitempty"`
+	Safe        *SafeInfo  `json:"safe,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	Balance     string     `json:"balance"`
+	BalanceRaw  *big.Int   `json:"balance_raw,omitempty"`
+	Decimals    int        `json:"decimals,omitempty"`
+	Symbol      string     `json:"symbol,omitempty"`
+	BalanceUSD  *float64   `json:"balance_usd,omitempty"`
+	TxCount     int        `json:"tx_count"`
+	FirstSeen   *time.Time `json:"first_seen,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	Details     string     `json:"details,omitempty"`
+}
+
+// LightningInfo describes a Lightning node (and the invoice that named it).
+type LightningInfo struct {
+	NodePubKey string            `json:"node_pubkey"`
+	Alias      string            `json:"alias,omitempty"`
+	Channels   int               `json:"active_channels"`
+	Invoice    *LightningInvoice `json:"invoice,omitempty"`
+}
+
+// LightningInvoice is the screening-relevant part of a BOLT11 invoice.
+type LightningInvoice struct {
+	Payee             string    `json:"payee"`
+	AmountBTC         float64   `json:"amount_btc,omitempty"` // 0 = any amount
+	PaymentHash       string    `json:"payment_hash,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Timestamp         time.Time `json:"timestamp"`
+	Expiry            int64     `json:"expiry_seconds"`
+	FallbackAddresses []string  `json:"fallback_addresses,omitempty"` // On-chain, screened too
+	SignatureValid    bool      `json:"signature_valid"`
+}
+
+// Expired reports whether the invoice can no longer be paid.
+func (inv *LightningInvoice) Expired() bool {
+	return time.Now().After(inv.Timestamp.Add(time.Duration(inv.Expiry) * time.Second))
+}
+
+// SafeInfo is a Gnosis Safe's signing policy.
+type SafeInfo struct {
+	Threshold  int         `json:"threshold"`
+	OwnerCount int         `json:"owner_count"`
+	Owners     []SafeOwner `json:"owners"`
+}
+
+// SafeOwner is one Safe signer; Safe is set if the owner is itself a Safe.
+type SafeOwner struct {
+	Address string    `json:"address"`
+	Safe    *SafeInfo `json:"safe,omitempty"`
+}
+
+// TokenBalance is one ERC-20 holding.
+type TokenBalance struct {
+	ChainID    string  `json:"chain_id"`
+	Contract   string  `json:"contract"`
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name,omitempty"`
+	Decimals   int     `json:"decimals"`
+	Balance    string  `json:"balance"` // e.g. "1500.000000 USDT"
+	Amount     float64 `json:"amount"`
+	Stablecoin bool    `json:"stablecoin,omitempty"`
+
+	// EVM_SCAM_TOKENS=true: sell-blocking levers the token's owner holds
+	HoneypotLevers []string `json:"honeypot_levers,omitempty"`
+}
+
+// StablecoinActivity is one stablecoin's balance and recent flows (USD, 1:1).
+type StablecoinActivity struct {
+	Symbol     string  `json:"symbol"` // USDT, USDC, DAI, PYUSD
+	BalanceUSD float64 `json:"balance_usd"`
+	InflowUSD  float64 `json:"inflow_usd"`
+	OutflowUSD float64 `json:"outflow_usd"`
+	Transfers  int     `json:"transfers"` // Within the flow window
+}
+
+// NFTCollection summarizes the NFTs held from one collection.
+type NFTCollection struct {
+	ChainID  string `json:"chain_id"`
+	Contract string `json:"contract"`
+	Name     string `json:"name,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Standard string `json:"standard"` // ERC-721 or ERC-1155
+	Count    int    `json:"count"`    // Distinct token IDs held
+}
+
+// TokenApproval is a live allowance granted by the profiled address.
+type TokenApproval struct {
+	ChainID         string `json:"chain_id"`
+	Token           string `json:"token"`
+	Spender         string `json:"spender"`
+	SpenderLabel    string `json:"spender_label,omitempty"`    // Threat label or verified contract name
+	SpenderType     string `json:"spender_type,omitempty"`     // EOA, CONTRACT, SMART_ACCOUNT
+	SpenderVerified *bool  `json:"spender_verified,omitempty"` // nil if unknown
+	Standard        string `json:"standard"`                   // ERC-20 or NFT (ApprovalForAll)
+	Amount          string `json:"amount"`                     // Raw units, UNLIMITED or ALL
+	Unlimited       bool   `json:"unlimited"`
+}
+
+type RiskCategory struct {
+	Fraud      float64 `json:"fraud_risk"`
+	Reputation float64 `json:"reputation_risk"`
+	Lending    float64 `json:"lending_risk"`
+}
+
+// RiskSchemaVersion versions the risk fields of the profile (reasons,
+// rule IDs, evidence). It changes only when they change incompatibly.
+const RiskSchemaVersion = "1"
+
+type RiskReason struct {
+	RuleID      string    `json:"rule_id"`  // Stable: the rules-file key, e.g. "mixer_interaction"
+	Category    string    `json:"category"` // "FRAUD", "REPUTATION"
+	Severity    string    `json:"severity"` // INFO, LOW, MEDIUM, HIGH or CRITICAL (from the offset)
+	Description string    `json:"description"`
+	Offset      float64   `json:"offset"` // e.g. +15.5 or -5.0
+	Evidence    *Evidence `json:"evidence,omitempty"`
+}
+
+// Evidence is what triggered a RiskReason (lists capped at 20 entries).
+type Evidence struct {
+	TxHashes   []string    `json:"tx_hashes,omitempty"`
+	Addresses  []string    `json:"addresses,omitempty"`
+	Timestamps []time.Time `json:"timestamps,omitempty"`
+}
+
+type Transaction struct {
+	TimeStamp int64  `json:"timeStamp"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Hash      string `json:"hash"`
+	Internal  bool   `json:"internal,omitempty"` // EVM internal call (contract-to-contract)
+
+	// EVM only (Block 0 = unknown): where the tx landed and what it paid.
+	// TxIndex and GasPrice are for top-level txs only.
+	Block           int64  `json:"blockNumber,omitempty"`
+	TxIndex         int    `json:"transactionIndex,omitempty"` // Position in the block
+	GasPrice        string `json:"gasPrice,omitempty"`         // Effective, in wei
+	ContractAddress string `json:"contractAddress,omitempty"`  // Deployed by this tx
+}
+
+type ChainStrategy interface {
+	Name() string
+	IsValidSyntax(address string) bool
+	FetchState(ctx context.Context, address string, apiKey string) (*WalletProfile, error)
+}
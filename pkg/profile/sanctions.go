@@ -0,0 +1,58 @@
+package profile
+
+import "context"
+
+// ---------------------------------------------------------
+// SANCTIONS WATCHLIST
+// ---------------------------------------------------------
+
+// WatchlistClient screens an address against the sanctions watchlist.
+type WatchlistClient interface {
+	Check(ctx context.Context, address string) (*EngineResponse, error)
+}
+
+// BatchWatchlistClient is a WatchlistClient that can also screen many
+// addresses in one call. The investigator uses it when the client has it.
+// The result has an entry for every address; hits need not carry their
+// SDN entity, so callers Check a hit for it.
+type BatchWatchlistClient interface {
+	WatchlistClient
+	BatchCheck(ctx context.Context, addresses []string) (map[string]*EngineResponse, error)
+}
+
+// EngineResponse is the Watchlist Engine's answer for one address.
+type EngineResponse struct {
+	Sanctioned bool   `json:"sanctioned"`
+	Currency   string `json:"currency"`
+	Source     string `json:"source"`
+
+	// Hits linked to an SDN entity: its ID, name and other addresses
+	EntityID   string   `json:"entity_id,omitempty"`
+	EntityName string   `json:"entity_name,omitempty"`
+	CoListed   []Result `json:"co_listed,omitempty"`
+
+	// The list data that answered (older engines omit it)
+	ListVersion *ListVersion `json:"list_version,omitempty"`
+}
+
+// Result is the outcome of a single address lookup.
+// JSON tags mirror the engine's /check response.
+type Result struct {
+	Address    string `json:"address"`
+	Sanctioned bool   `json:"sanctioned"`
+	Currency   string `json:"currency,omitempty"`
+	Source     string `json:"source,omitempty"`
+
+	// Check only: the SDN entity the address is listed under and the
+	// entity's other addresses (empty for entries without entity linkage)
+	EntityID   string   `json:"entity_id,omitempty"`
+	EntityName string   `json:"entity_name,omitempty"`
+	CoListed   []Result `json:"co_listed,omitempty"`
+}
+
+// ListVersion identifies the list data a check ran against, for audit
+// trails. Both are empty until the first sync.
+type ListVersion struct {
+	LastModified string `json:"last_modified,omitempty"` // OFAC's Last-Modified header for the loaded file
+	SyncedAt     string `json:"synced_at,omitempty"`     // When it was loaded (RFC 3339)
+}
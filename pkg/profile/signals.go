@@ -0,0 +1,222 @@
+package profile
+
+import (
+	"math/big"
+	"time"
+)
+
+// ---------------------------------------------------------
+// SIGNALS (set on a profile by strategies and the investigator)
+// ---------------------------------------------------------
+
+// Alert is one condition a downstream system should act on. Severity is
+// set by the alert rule, not derived from the offsets.
+type Alert struct {
+	Trigger  string   `json:"trigger"`
+	Severity string   `json:"severity"` // LOW, MEDIUM, HIGH or CRITICAL
+	Message  string   `json:"message"`
+	Action   string   `json:"recommended_action"`
+	RuleIDs  []string `json:"rule_ids,omitempty"` // The reasons behind it
+}
+
+// Decision is what the policy says to do with a profile, and the decision
+// rule that said it, so integrators route on one field instead of each
+// mapping scores to actions.
+type Decision struct {
+	Action string `json:"action"` // APPROVE, REVIEW or REJECT
+	Rule   string `json:"rule"`   // The rules-file key that decided, e.g. decisions.reject_score
+	Reason string `json:"reason"`
+}
+
+// SanctionedEntity is the SDN entity a sanctioned address is listed under.
+// CoListed are its other addresses; Transacted are those the profiled
+// wallet sent to or received from.
+type SanctionedEntity struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	CoListed   []string `json:"co_listed"`
+	Transacted []string `json:"transacted,omitempty"`
+}
+
+// RiskTrend compares a profile's score with the previous stored run.
+type RiskTrend struct {
+	PreviousScore float64   `json:"previous_score"`
+	PreviousGrade string    `json:"previous_grade"`
+	PreviousAt    time.Time `json:"previous_at"`
+	Delta         float64   `json:"delta"`     // Current minus previous
+	Direction     string    `json:"direction"` // RISING, FALLING or UNCHANGED
+}
+
+// ContractRisk describes what a contract's owner can do to its users.
+type ContractRisk struct {
+	Proxy          string   `json:"proxy,omitempty"`          // EIP-1167 or PROXY (upgradeable)
+	Implementation string   `json:"implementation,omitempty"` // Code the proxy delegates to
+	Upgradeable    bool     `json:"upgradeable"`
+	Token          bool     `json:"token,omitempty"`      // ERC-20 interface in the verified ABI
+	Privileges     []string `json:"privileges,omitempty"` // MINT, PAUSE, UPGRADE, BLACKLIST, TAX, TRADING, LIMIT (verified ABI only)
+	Owner          string   `json:"owner,omitempty"`      // owner(), if the contract has one
+	OwnerType      string   `json:"owner_type,omitempty"` // RENOUNCED, SAFE or ACCOUNT
+	Indicators     []string `json:"indicators,omitempty"` // Rug-pull levers found (set by the investigator)
+}
+
+// PoisoningInfo lists the lookalike addresses that sent zero-value or dust
+// transfers to the profiled address, hoping it copies one from its history.
+type PoisoningInfo struct {
+	Lookalikes      []Lookalike `json:"lookalikes"`
+	Transfers       int         `json:"transfers"`                   // Zero-value/dust transfers from lookalikes
+	SentToLookalike int         `json:"sent_to_lookalike,omitempty"` // Txs the address then sent to one (funds lost)
+}
+
+// Lookalike is one poisoning address and the counterparty it imitates.
+type Lookalike struct {
+	Address   string `json:"address"`
+	Mimics    string `json:"mimics"`
+	Transfers int    `json:"transfers"`
+}
+
+// ConcentrationInfo is how much of the address's volume goes through its
+// largest counterparties (by value moved, or tx count if values are unknown).
+type ConcentrationInfo struct {
+	Counterparties  int     `json:"counterparties"`
+	TopCounterparty string  `json:"top_counterparty"`
+	Top1Percent     float64 `json:"top1_percent"`
+	Top5Percent     float64 `json:"top5_percent"`
+}
+
+// PeelChainInfo describes a chain of hops that each split off a small
+// amount and pass the rest to the next address.
+type PeelChainInfo struct {
+	Hops   int      `json:"hops"`
+	Peeled string   `json:"peeled"` // Total split off, e.g. "0.04200000 BTC"
+	Path   []string `json:"path"`   // Addresses passing the remainder, in order
+}
+
+// CounterpartyLabels are the labels found for one counterparty.
+type CounterpartyLabels struct {
+	Address string  `json:"address"`
+	Txs     int     `json:"txs"`
+	Labels  []Label `json:"labels"`
+}
+
+// ExchangeLink is a counterparty attributed to an exchange.
+type ExchangeLink struct {
+	Entity    string `json:"entity"`
+	Regulated bool   `json:"regulated"`
+	Address   string `json:"address"`
+	Via       string `json:"via"`  // HOT_WALLET or DEPOSIT_ADDRESS
+	Sent      int    `json:"sent"` // Txs from the profiled address
+	Received  int    `json:"received"`
+
+	Jurisdiction     string `json:"jurisdiction,omitempty"`
+	JurisdictionRisk string `json:"jurisdiction_risk,omitempty"` // FATF list: HIGH or MONITORED
+}
+
+// BridgeUsage is the address's activity with one bridge.
+type BridgeUsage struct {
+	Bridge   string `json:"bridge"`
+	Deposits int    `json:"deposits"` // Txs into the bridge
+	Releases int    `json:"releases"` // Txs from the bridge
+	Volume   string `json:"volume"`   // Native value deposited
+	Rapid    int    `json:"rapid"`    // Deposits within 24h of receiving funds
+}
+
+// VelocityWindow is the busiest stretch of one window length.
+type VelocityWindow struct {
+	Window    string    `json:"window"`
+	MaxTxs    int       `json:"max_txs"`
+	Start     time.Time `json:"start"` // First tx of the busiest stretch
+	Threshold float64   `json:"threshold"`
+	Exceeded  bool      `json:"exceeded,omitempty"`
+}
+
+// TemporalProfile describes when an address transacts. Hours and days
+// are UTC.
+type TemporalProfile struct {
+	Txs            int           `json:"txs"`
+	HourHistogram  [24]int       `json:"hour_histogram"`
+	ActiveHours    int           `json:"active_hours"` // Hours of the day with any tx
+	QuietHours     int           `json:"quiet_hours"`  // Longest run of idle hours (across midnight)
+	WeekdayTxs     int           `json:"weekday_txs"`
+	WeekendTxs     int           `json:"weekend_txs"`
+	WeekendPercent float64       `json:"weekend_percent"`
+	Intervals      IntervalStats `json:"intervals"`
+	Pattern        string        `json:"pattern"` // MACHINE, HUMAN or MIXED
+	Signals        []string      `json:"signals,omitempty"`
+}
+
+// IntervalStats is the distribution of the time between consecutive txs,
+// in seconds. CV (stddev / mean) near 0 is clockwork.
+type IntervalStats struct {
+	Min    int64   `json:"min"`
+	P10    int64   `json:"p10"`
+	Median int64   `json:"median"`
+	P90    int64   `json:"p90"`
+	Max    int64   `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	CV     float64 `json:"cv"`
+}
+
+// BotInfo is the behavioral class of an automated trader and what gave
+// it away.
+type BotInfo struct {
+	Class       string   `json:"class"` // MEV_BOT or MARKET_MAKER
+	Sandwiches  int      `json:"sandwiches,omitempty"`
+	Bundles     int      `json:"bundles,omitempty"`
+	TopOfBlock  int      `json:"top_of_block,omitempty"`
+	GasSpikes   int      `json:"gas_spikes,omitempty"`
+	DeFiPercent float64  `json:"defi_percent,omitempty"` // Share of its txs into DEXs
+	Signals     []string `json:"signals"`
+	EvidenceTxs []string `json:"-"` // The txs behind the signals, for the risk reason
+}
+
+// FlashLoanInfo is the address's activity with flash-loan providers and
+// the exploit-preparation signals around it.
+type FlashLoanInfo struct {
+	Providers    []string `json:"providers"`
+	Txs          int      `json:"txs"`                     // Txs with a provider (internal calls included)
+	NewContracts []string `json:"new_contracts,omitempty"` // Deployed shortly before a flash loan
+	Signals      []string `json:"signals"`
+
+	// The largest amount (base units) that came in and went out again within
+	// one block; the investigator prices it
+	SameBlockFlow *big.Int `json:"-"`
+	EvidenceTxs   []string `json:"-"` // The flash-loan txs, for the risk reason
+}
+
+// SybilInfo lists the sybil-farming signals found for an address.
+type SybilInfo struct {
+	Funder        string   `json:"funder,omitempty"`
+	FunderFanout  int      `json:"funder_fanout,omitempty"`  // Wallets funded with the same amount
+	Siblings      []string `json:"siblings,omitempty"`       // Same funder, same tx sequence
+	AirdropClaims []string `json:"airdrop_claims,omitempty"` // Distributors claimed from
+	Signals       []string `json:"signals"`
+}
+
+// WashTradingInfo is a cluster of addresses (the profiled one included)
+// passing tokens or NFTs around in a cycle, funded from nowhere else.
+type WashTradingInfo struct {
+	Members        []string `json:"members"`
+	Transfers      int      `json:"transfers"`               // Within the cluster
+	ExternalInflow float64  `json:"external_inflow_percent"` // Of the members' incoming transfers
+}
+
+// HopExposure is the share of an address's funds that reaches a sanctioned
+// or mixer address at exactly this many hops (1 = a direct counterparty).
+type HopExposure struct {
+	Hop     int      `json:"hop"`
+	Network string   `json:"network,omitempty"`
+	Percent float64  `json:"percent"`           // Of the value moved, 0-100
+	Flagged []string `json:"flagged,omitempty"` // Labels of the flagged addresses reached
+}
+
+// ProbeMatch is one chain's answer when an address is probed on every
+// chain whose syntax it matches.
+type ProbeMatch struct {
+	Strategy string `json:"strategy"`
+	Network  string `json:"network,omitempty"`
+	Exists   bool   `json:"exists"` // Balance or tx history on this chain
+	Balance  string `json:"balance,omitempty"`
+	TxCount  int    `json:"tx_count"`
+	Error    string `json:"error,omitempty"`
+}
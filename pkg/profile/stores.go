@@ -0,0 +1,91 @@
+package profile
+
+import (
+	"math/big"
+	"time"
+)
+
+// ---------------------------------------------------------
+// STORES AND PROVIDERS (implemented by callers or the engine)
+// ---------------------------------------------------------
+
+// ScoreRecord is one computed profile, keyed by address + timestamp.
+type ScoreRecord struct {
+	Address       string       `json:"address"`
+	Network       string       `json:"network"`
+	Timestamp     time.Time    `json:"timestamp"`
+	RiskScore     float64      `json:"risk_score"`
+	RiskGrade     string       `json:"risk_grade"`
+	RiskBreakdown RiskCategory `json:"risk_breakdown"`
+	RiskReasons   []RiskReason `json:"risk_reasons"`
+
+	// What the diff command compares besides the score (absent from
+	// records written before it; Counterparties is then nil)
+	Balance        string   `json:"balance,omitempty"`
+	BalanceRaw     *big.Int `json:"balance_raw,omitempty"`
+	Decimals       int      `json:"decimals,omitempty"`
+	Symbol         string   `json:"symbol,omitempty"`
+	BalanceUSD     *float64 `json:"balance_usd,omitempty"`
+	TxCount        int      `json:"tx_count,omitempty"`
+	Counterparties []string `json:"counterparties"`
+}
+
+// HistoryStore persists score records. Implementations must be safe for
+// concurrent use.
+type HistoryStore interface {
+	// Last returns the most recent record for the address on the network.
+	Last(address, network string) (*ScoreRecord, error)
+	Save(rec ScoreRecord) error
+}
+
+// MonitoredAddress is a saved address and the outcome of its last check.
+// Last* fields are empty until the first check.
+type MonitoredAddress struct {
+	Address     string    `json:"address"`
+	Note        string    `json:"note,omitempty"` // Free text, e.g. a customer ID
+	AddedAt     time.Time `json:"added_at"`
+	LastChecked time.Time `json:"last_checked"`
+	Network     string    `json:"network,omitempty"`
+	LastScore   float64   `json:"last_score"`
+	LastGrade   string    `json:"last_grade,omitempty"`
+	LastStatus  string    `json:"last_status,omitempty"` // CLEAR or SANCTIONED
+}
+
+// MonitorAlert is a change worth a compliance review: the score crossed
+// the threshold, or the watchlist status flipped.
+type MonitorAlert struct {
+	Address       string    `json:"address"`
+	Network       string    `json:"network"`
+	Note          string    `json:"note,omitempty"`
+	Trigger       string    `json:"trigger"`
+	PreviousScore float64   `json:"previous_score"`
+	Score         float64   `json:"score"`
+	PreviousGrade string    `json:"previous_grade,omitempty"`
+	Grade         string    `json:"grade"`
+	Threshold     float64   `json:"threshold,omitempty"` // Score triggers only
+	Status        string    `json:"status"`
+	At            time.Time `json:"at"`
+}
+
+// MonitorStore persists the monitored addresses. Implementations must be
+// safe for concurrent use.
+type MonitorStore interface {
+	List() ([]MonitoredAddress, error)
+	// Put adds the address or replaces its entry.
+	Put(entry MonitoredAddress) error
+	// Remove reports whether the address was monitored.
+	Remove(address string) (bool, error)
+}
+
+// Label attributes an address to an entity and a category.
+type Label struct {
+	Entity   string `json:"entity"`   // e.g. "Tornado Cash Router"
+	Category string `json:"category"` // MIXER, DRAINER, SCAM, SANCTIONED, EXCHANGE, BRIDGE, DEFI, OTHER
+	Source   string `json:"source"`   // Provider, e.g. "builtin", "csv", "etherscan"
+}
+
+// Labels is an address label provider. Lookup takes a lowercase address
+// and must be safe for concurrent use; failures yield no labels.
+type Labels interface {
+	Lookup(address string) []Label
+}
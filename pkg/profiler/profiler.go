@@ -0,0 +1,137 @@
+// Package profiler is the public API for programs that embed the validator
+// and add their own chains. The profile types are defined in pkg/profile
+// and the implementation lives in internal/validator; this package
+// re-exports what a third-party strategy needs.
+//
+//	profiler.Register(&TronStrategy{}, profiler.WithPriority(15), profiler.WithConfig(os.Getenv("TRONGRID_API_KEY")))
+//	profile, err := profiler.Analyze(ctx, "TN3W4H6rK2ce4vX9YnFQHwKENnHjoxb3m9", false)
+package profiler
+
+import (
+	"context"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/piyushdaiya/crypto-profiler/pkg/profile"
+)
+
+// Types a strategy implements and returns
+type (
+	ChainStrategy  = profile.ChainStrategy
+	WalletProfile  = profile.WalletProfile
+	Transaction    = profile.Transaction
+	RiskCategory   = profile.RiskCategory
+	RiskReason     = profile.RiskReason
+	RegisterOption = validator.RegisterOption
+	RiskRules      = validator.RiskRules
+	HistoryStore   = profile.HistoryStore
+	ScoreRecord    = profile.ScoreRecord
+	Labels         = profile.Labels
+	Label          = profile.Label
+
+	Monitor          = validator.Monitor
+	MonitorStore     = profile.MonitorStore
+	MonitoredAddress = profile.MonitoredAddress
+	MonitorAlert     = profile.MonitorAlert
+)
+
+// Built-in strategies, for programs that register their own set
+type (
+	EVMStrategy       = validator.EVMStrategy
+	BitcoinStrategy   = validator.BitcoinStrategy
+	SolanaStrategy    = validator.SolanaStrategy
+	CosmosStrategy    = validator.CosmosStrategy
+	ZcashStrategy     = validator.ZcashStrategy
+	BSVStrategy       = validator.BSVStrategy
+	LightningStrategy = validator.LightningStrategy
+	NearStrategy      = validator.NearStrategy
+	AvalancheStrategy = validator.AvalancheStrategy
+)
+
+// DefaultPriority places a strategy after the built-in chains.
+const DefaultPriority = validator.DefaultPriority
+
+// Register adds (or, for a Name already registered, replaces) a strategy.
+func Register(strategy ChainStrategy, opts ...RegisterOption) {
+	validator.Register(strategy, opts...)
+}
+
+// WithPriority sets the matching order; lower is tried first.
+func WithPriority(priority int) RegisterOption { return validator.WithPriority(priority) }
+
+// WithConfig sets the config (API key) passed to FetchState.
+func WithConfig(config string) RegisterOption { return validator.WithConfig(config) }
+
+// Strategies returns the registered strategies in matching order.
+func Strategies() []ChainStrategy { return validator.Strategies() }
+
+// Analyze profiles an address with the registered strategies (every
+// syntactic match concurrently if probe is set) and scores it.
+func Analyze(ctx context.Context, address string, probe bool) (*WalletProfile, error) {
+	return validator.Analyze(ctx, address, probe)
+}
+
+// AnalyzeWith profiles an address with one strategy, skipping syntax
+// matching against the other chains.
+func AnalyzeWith(ctx context.Context, strategy ChainStrategy, address string) (*WalletProfile, error) {
+	return validator.AnalyzeWith(ctx, strategy, address)
+}
+
+// LookupStrategy returns the registered strategy called name ("solana",
+// "evm"), or nil.
+func LookupStrategy(name string) ChainStrategy { return validator.LookupStrategy(name) }
+
+// Investigate scores a profile; strategies may call it with their txs.
+func Investigate(profile *WalletProfile, txs []Transaction) {
+	validator.Investigate(profile, txs)
+}
+
+// DefaultRiskRules returns the investigator's built-in policy.
+func DefaultRiskRules() RiskRules { return validator.DefaultRiskRules() }
+
+// LoadRiskRules reads a rules file (.json, .yaml or .yml) over the defaults.
+func LoadRiskRules(path string) (RiskRules, error) { return validator.LoadRiskRules(path) }
+
+// PolicyRules returns a named policy (exchange, lender, nft_marketplace).
+func PolicyRules(name string) (RiskRules, error) { return validator.PolicyRules(name) }
+
+// PolicyNames lists the named policies.
+func PolicyNames() []string { return validator.PolicyNames() }
+
+// LoadPolicyRules reads a rules file over a named policy.
+func LoadPolicyRules(policy, path string) (RiskRules, error) {
+	return validator.LoadPolicyRules(policy, path)
+}
+
+// SetRiskRules replaces the investigator's policy after validating it.
+func SetRiskRules(rules RiskRules) error { return validator.SetRiskRules(rules) }
+
+// SetHistory stores every analyzed score so later runs report a trend; nil disables it.
+func SetHistory(h HistoryStore) { validator.SetHistory(h) }
+
+// NewFileHistory opens a JSON Lines score history at path.
+func NewFileHistory(path string) (HistoryStore, error) {
+	h, err := validator.NewFileHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// NewFileMonitorStore opens a JSON monitored-address list at path.
+func NewFileMonitorStore(path string) (MonitorStore, error) {
+	s, err := validator.NewFileMonitorStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WebhookAlerts returns a Monitor.Alert func that POSTs each alert to url.
+func WebhookAlerts(url string) func(MonitorAlert) { return validator.WebhookAlerts(url) }
+
+// SignWebhook is the hex HMAC-SHA256 a profile webhook sends in its
+// X-Profiler-Signature-256 header, for receivers to check the body against.
+func SignWebhook(secret string, body []byte) string { return validator.SignWebhook(secret, body) }
+
+// SetLabelProviders replaces the counterparty label providers, asked in order.
+func SetLabelProviders(providers ...Labels) { validator.SetLabelProviders(providers...) }
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// HTML REPORT (one standalone file per profile, for case files)
+// ---------------------------------------------------------
+
+// reportData is what the report template renders.
+type reportData struct {
+	Profile     *validator.WalletProfile
+	GeneratedAt time.Time
+	ScreenedAt  time.Time // When the investigator ran; GeneratedAt if it never did
+	Decision    reportDecision
+	Policy      string
+	GaugeDash   float64 // Filled length of the gauge arc
+	GaugeColor  string
+	Reasons     []validator.RiskReason // Highest offset first
+	Sources     []reportSource
+}
+
+// reportDecision is the screening outcome in one word, with why.
+type reportDecision struct {
+	Verdict string // BLOCK, ESCALATE, REVIEW, CLEAR, INCOMPLETE or NOT SCREENED
+	Reason  string
+}
+
+// reportSource is one data source cited in the report.
+type reportSource struct {
+	Name   string
+	Detail string
+}
+
+// Length of the gauge's semicircle (radius 80)
+const gaugeLength = math.Pi * 80
+
+// writeReport renders the profile as a self-contained HTML file: no
+// scripts, fonts or images are loaded, so it can be attached to a case
+// file and opened offline. A path ending in .pdf gets the PDF compliance
+// report instead (see writePDFReport).
+func writeReport(path string, p *validator.WalletProfile) error {
+	data := newReportData(p, time.Now().UTC())
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		return writePDFReport(path, data)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func newReportData(p *validator.WalletProfile, now time.Time) reportData {
+	rules := validator.ActiveRiskRules()
+	policy := p.RiskPolicy
+	if policy == "" {
+		policy = "default"
+	}
+	color := "#c62828" // FAILING and above
+	switch {
+	case !p.IsValid:
+		color = "#9e9e9e"
+	case p.RiskScore < rules.Grades.Excellent:
+		color = "#2e7d32"
+	case p.RiskScore < rules.Grades.Low:
+		color = "#7cb342"
+	case p.RiskScore < rules.Grades.Warning:
+		color = "#f9a825"
+	}
+
+	var sources []reportSource
+	if p.Network != "" && p.Network != "UNKNOWN" {
+		chain := reportSource{Name: "Chain Data", Detail: p.Network}
+		if len(p.DataSources) > 0 {
+			chain.Detail += " via " + strings.Join(p.DataSources, ", ")
+		}
+		sources = append(sources, chain)
+	}
+	sources = append(sources, reportSource{Name: "Sanctions", Detail: "OFAC SDN List (U.S. Treasury), " + listVersion(p) + ", " + watchlistSource()})
+	if names := validator.PriceSourceNames(); len(names) > 0 && (p.PriceUSD != nil || p.BalanceUSD != nil) {
+		sources = append(sources, reportSource{Name: "USD Prices", Detail: strings.Join(names, ", ")})
+	}
+	if len(p.CounterpartyLabels) > 0 || len(p.ExchangeLinks) > 0 {
+		sources = append(sources, reportSource{Name: "Counterparty Labels", Detail: reportLabelSources(p)})
+	}
+	sources = append(sources, reportSource{Name: "Risk Rules", Detail: fmt.Sprintf("Policy %s, risk schema v%s", policy, p.RiskSchema)})
+
+	screenedAt := now
+	if p.ScreenedAt != nil {
+		screenedAt = p.ScreenedAt.UTC()
+	}
+	return reportData{
+		Profile:     p,
+		GeneratedAt: now,
+		ScreenedAt:  screenedAt,
+		Decision:    screeningDecision(p, rules),
+		Policy:      policy,
+		GaugeDash:   math.Round(min(max(p.RiskScore, 0), 100)/100*gaugeLength*10) / 10,
+		GaugeColor:  color,
+		Reasons:     sortedReasons(p),
+		Sources:     sources,
+	}
+}
+
+// screeningDecision sums the profile up for an auditor: BLOCK on a
+// CRITICAL alert (a sanctions hit), ESCALATE on a HIGH alert, REVIEW on
+// any other alert or a FAILING grade, else CLEAR. A skipped sanctions
+// check makes an otherwise clear result INCOMPLETE.
+func screeningDecision(p *validator.WalletProfile, rules validator.RiskRules) reportDecision {
+	if !p.IsValid {
+		return reportDecision{"NOT SCREENED", "Invalid address: " + p.ValidationDetails}
+	}
+	rank := map[string]int{"LOW": 1, "MEDIUM": 1, "HIGH": 2, "CRITICAL": 3}
+	var top *validator.Alert
+	for i, a := range p.Alerts {
+		if top == nil || rank[a.Severity] > rank[top.Severity] {
+			top = &p.Alerts[i]
+		}
+	}
+	if top != nil {
+		verdict := [...]string{"REVIEW", "REVIEW", "ESCALATE", "BLOCK"}[rank[top.Severity]]
+		return reportDecision{verdict, fmt.Sprintf("%s alert %s: %s. Recommended action: %s", top.Severity, top.Trigger, top.Message, top.Action)}
+	}
+	for _, r := range p.RiskReasons {
+		if r.RuleID == "watchlist_unavailable" {
+			return reportDecision{"INCOMPLETE", "The sanctions check was skipped (watchlist unavailable); screen again before relying on this result"}
+		}
+	}
+	if p.RiskScore >= rules.Grades.Warning {
+		return reportDecision{"REVIEW", fmt.Sprintf("Risk score %.1f is graded %s, with no alert raised", p.RiskScore, p.RiskGrade)}
+	}
+	return reportDecision{"CLEAR", fmt.Sprintf("No sanctions hit and no alerts; risk score %.1f (%s)", p.RiskScore, p.RiskGrade)}
+}
+
+// listVersion describes the sanctions list version the profile was
+// screened against.
+func listVersion(p *validator.WalletProfile) string {
+	v := p.SanctionsList
+	if v == nil {
+		return "list version unknown"
+	}
+	var parts []string
+	if v.LastModified != "" {
+		parts = append(parts, "Last-Modified "+v.LastModified)
+	}
+	if v.SyncedAt != "" {
+		parts = append(parts, "synced "+v.SyncedAt)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// watchlistSource names where sanctions hits come from.
+func watchlistSource() string {
+	url, path := validator.WatchlistEngine()
+	if path != "" {
+		return "embedded database " + path
+	}
+	return "watchlist engine " + url
+}
+
+// reportLabelSources lists the label providers behind the counterparty labels.
+func reportLabelSources(p *validator.WalletProfile) string {
+	seen := map[string]bool{}
+	var names []string
+	for _, c := range p.CounterpartyLabels {
+		for _, l := range c.Labels {
+			if !seen[l.Source] {
+				seen[l.Source] = true
+				names = append(names, l.Source)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return "builtin"
+	}
+	return strings.Join(names, ", ")
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return "—"
+		}
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	},
+	"stamp":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05 MST") },
+	"offset": func(f float64) string { return fmt.Sprintf("%+g", f) },
+	"usd": func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return fmt.Sprintf("($%.2f)", *f)
+	},
+	"lower": strings.ToLower,
+}).Parse(reportHTML))
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Wallet Risk Report: {{.Profile.Address}}</title>
+<style>
+  body { font: 14px/1.45 -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; color: #212121; margin: 0; background: #f5f5f5; }
+  main { max-width: 960px; margin: 0 auto; padding: 32px; background: #fff; }
+  h1 { font-size: 22px; margin: 0 0 4px; }
+  h2 { font-size: 16px; margin: 28px 0 8px; padding-bottom: 4px; border-bottom: 2px solid #eee; }
+  .muted { color: #757575; }
+  .mono { font-family: ui-monospace, Menlo, Consolas, monospace; word-break: break-all; }
+  .top { display: flex; gap: 32px; align-items: center; flex-wrap: wrap; }
+  .gauge { text-align: center; }
+  .gauge .score { font-size: 30px; font-weight: 700; }
+  .grade { display: inline-block; padding: 2px 10px; border-radius: 12px; color: #fff; font-weight: 600; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { text-align: left; vertical-align: top; padding: 6px 8px; border-bottom: 1px solid #eee; }
+  th { background: #fafafa; font-weight: 600; }
+  table.facts th { width: 180px; background: none; }
+  .sev { font-weight: 600; font-size: 12px; }
+  .sev.critical { color: #b71c1c; } .sev.high { color: #c62828; } .sev.medium { color: #ef6c00; } .sev.low, .sev.info { color: #546e7a; }
+  .alert { border-left: 4px solid #c62828; background: #fff5f5; padding: 8px 12px; margin: 6px 0; }
+  .alert.medium, .alert.low { border-color: #ef6c00; background: #fff8ee; }
+  footer { margin-top: 32px; font-size: 12px; }
+  @media print { body { background: #fff; } main { padding: 0; } }
+</style>
+</head>
+<body>
+<main>
+<h1>Wallet Risk Report</h1>
+<div class="muted">Generated {{stamp .GeneratedAt}}</div>
+
+<h2>Summary</h2>
+<div class="top">
+  <div class="gauge">
+    <svg width="200" height="110" viewBox="0 0 200 110" role="img" aria-label="Risk score {{printf "%.1f" .Profile.RiskScore}} of 100">
+      <path d="M20 100 A80 80 0 0 1 180 100" fill="none" stroke="#eee" stroke-width="18"/>
+      <path d="M20 100 A80 80 0 0 1 180 100" fill="none" stroke="{{.GaugeColor}}" stroke-width="18" stroke-dasharray="{{.GaugeDash}} 1000"/>
+    </svg>
+    <div class="score">{{printf "%.1f" .Profile.RiskScore}}<span class="muted" style="font-size:14px"> / 100</span></div>
+    {{if .Profile.RiskGrade}}<span class="grade" style="background: {{.GaugeColor}}">{{.Profile.RiskGrade}}</span>{{end}}
+  </div>
+  <table class="facts" style="flex: 1">
+    <tr><th>Decision</th><td><b>{{.Decision.Verdict}}</b>: {{.Decision.Reason}}</td></tr>
+    <tr><th>Address</th><td class="mono">{{.Profile.Address}}</td></tr>
+    {{if .Profile.ResolvedFrom}}<tr><th>Resolved From</th><td>{{.Profile.ResolvedFrom}}</td></tr>{{end}}
+    <tr><th>Network</th><td>{{.Profile.Network}}{{if .Profile.Testnet}} (testnet){{end}}</td></tr>
+    {{if .Profile.AccountType}}<tr><th>Account Type</th><td>{{.Profile.AccountType}}</td></tr>{{end}}
+    <tr><th>Balance</th><td>{{.Profile.Balance}} {{usd .Profile.BalanceUSD}}</td></tr>
+    <tr><th>Transactions</th><td>{{.Profile.TxCount}}</td></tr>
+    <tr><th>First Seen</th><td>{{date .Profile.FirstSeen}}</td></tr>
+    <tr><th>Last Seen</th><td>{{date .Profile.LastSeen}}</td></tr>
+    <tr><th>Breakdown</th><td>Fraud {{printf "%.1f" .Profile.RiskBreakdown.Fraud}} · Reputation {{printf "%.1f" .Profile.RiskBreakdown.Reputation}} · Lending {{printf "%.1f" .Profile.RiskBreakdown.Lending}}</td></tr>
+    <tr><th>Policy</th><td>{{.Policy}}</td></tr>
+    <tr><th>Details</th><td>{{.Profile.ValidationDetails}}</td></tr>
+  </table>
+</div>
+
+{{if .Profile.Alerts}}
+<h2>Alerts</h2>
+{{range .Profile.Alerts}}
+<div class="alert {{lower .Severity}}"><span class="sev {{lower .Severity}}">{{.Severity}}</span> <b>{{.Trigger}}</b>: {{.Message}}<br><span class="muted">Recommended action: {{.Action}}</span></div>
+{{end}}
+{{end}}
+
+<h2>Risk Reasons</h2>
+{{if .Reasons}}
+<table>
+  <tr><th>Offset</th><th>Severity</th><th>Rule</th><th>Category</th><th>Description</th><th>Evidence</th></tr>
+  {{range .Reasons}}
+  <tr>
+    <td>{{offset .Offset}}</td>
+    <td><span class="sev {{lower .Severity}}">{{.Severity}}</span></td>
+    <td class="mono">{{.RuleID}}</td>
+    <td>{{.Category}}</td>
+    <td>{{.Description}}</td>
+    <td class="mono">{{with .Evidence}}{{range .TxHashes}}{{.}}<br>{{end}}{{range .Addresses}}{{.}}<br>{{end}}{{range .Timestamps}}{{stamp .}}<br>{{end}}{{end}}</td>
+  </tr>
+  {{end}}
+</table>
+{{else}}
+<p class="muted">No risk factors.</p>
+{{end}}
+
+{{if or .Profile.CounterpartyLabels .Profile.ExchangeLinks .Profile.Bridges .Profile.Concentration}}
+<h2>Counterparties</h2>
+{{with .Profile.Concentration}}
+<p>{{.Counterparties}} counterparties; the largest (<span class="mono">{{.TopCounterparty}}</span>) has {{printf "%.1f" .Top1Percent}}% of the volume, the top 5 have {{printf "%.1f" .Top5Percent}}%.</p>
+{{end}}
+{{if .Profile.CounterpartyLabels}}
+<table>
+  <tr><th>Address</th><th>Txs</th><th>Labels</th></tr>
+  {{range .Profile.CounterpartyLabels}}
+  <tr><td class="mono">{{.Address}}</td><td>{{.Txs}}</td><td>{{range $i, $l := .Labels}}{{if $i}}; {{end}}{{$l.Entity}} ({{$l.Category}}, {{$l.Source}}){{end}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+{{if .Profile.ExchangeLinks}}
+<h3>Exchanges</h3>
+<table>
+  <tr><th>Entity</th><th>Regulated</th><th>Via</th><th>Sent</th><th>Received</th><th>Jurisdiction</th></tr>
+  {{range .Profile.ExchangeLinks}}
+  <tr><td>{{.Entity}}</td><td>{{if .Regulated}}Yes{{else}}No{{end}}</td><td>{{.Via}}</td><td>{{.Sent}}</td><td>{{.Received}}</td><td>{{.Jurisdiction}}{{if .JurisdictionRisk}} ({{.JurisdictionRisk}}){{end}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+{{if .Profile.Bridges}}
+<h3>Bridges</h3>
+<table>
+  <tr><th>Bridge</th><th>Deposits</th><th>Releases</th><th>Volume</th><th>Rapid</th></tr>
+  {{range .Profile.Bridges}}
+  <tr><td>{{.Bridge}}</td><td>{{.Deposits}}</td><td>{{.Releases}}</td><td>{{.Volume}}</td><td>{{.Rapid}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+{{end}}
+
+<h2>Data Sources</h2>
+<table class="facts">
+  {{range .Sources}}<tr><th>{{.Name}}</th><td>{{.Detail}}</td></tr>{{end}}
+</table>
+
+<footer class="muted">
+Screened {{stamp .ScreenedAt}}. Scores and reasons reflect the data sources above at that time; on-chain activity and sanctions lists change.
+</footer>
+</main>
+</body>
+</html>
+`
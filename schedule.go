@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// BATCH SCHEDULER (interleaving chains under shared rate limits)
+// ---------------------------------------------------------
+
+// batchScheduler hands a worker pool the addresses of a batch, one lane
+// per chain, so workers don't all queue on one provider's rate limit
+// (e.g. Etherscan) while addresses of other chains wait behind them in
+// the file. Each lane learns the rate-limited hosts its lookups call, and
+// a free worker takes the lane whose hosts are ready soonest, then the
+// one with the fewest addresses in flight, then the earliest in the
+// input, so a single-chain batch still runs in input order.
+type batchScheduler struct {
+	mu    sync.Mutex
+	lanes []*scheduleLane // In order of first appearance
+}
+
+// scheduleLane is the addresses of one chain still to screen.
+type scheduleLane struct {
+	name    string
+	queue   []int // Input indices, in order
+	hosts   map[string]bool
+	running int
+}
+
+// newBatchScheduler sorts the addresses into lanes by the chain that will
+// screen them: chain if forced, else the first matching strategy.
+func newBatchScheduler(addresses []string, chain validator.ChainStrategy) *batchScheduler {
+	s := &batchScheduler{}
+	byName := map[string]*scheduleLane{}
+	for i, address := range addresses {
+		name := laneName(address, chain)
+		l := byName[name]
+		if l == nil {
+			l = &scheduleLane{name: name, hosts: map[string]bool{}}
+			byName[name] = l
+			s.lanes = append(s.lanes, l)
+		}
+		l.queue = append(l.queue, i)
+	}
+	if len(s.lanes) > 1 {
+		counts := make([]any, 0, len(s.lanes))
+		for _, l := range s.lanes {
+			counts = append(counts, slog.Int(l.name, len(l.queue)))
+		}
+		slog.Debug("🔀 [SCHED] Interleaving chains", slog.Group("lanes", counts...))
+	}
+	return s
+}
+
+// laneName is the chain an address goes to; names resolve later, so they
+// share a lane, as do addresses no chain accepts.
+func laneName(address string, chain validator.ChainStrategy) string {
+	if validator.IsResolvableName(address) {
+		return "names"
+	}
+	strategies := validator.Strategies()
+	if chain != nil {
+		strategies = []validator.ChainStrategy{chain}
+	}
+	if matches := validator.MatchingStrategies(strategies, address); len(matches) > 0 {
+		return matches[0].Name()
+	}
+	return "invalid"
+}
+
+// next takes the next address to screen, or false once every lane is
+// empty. The returned ctx reports the lane's hosts; call done when the
+// address is screened.
+func (s *batchScheduler) next(ctx context.Context) (int, context.Context, func(), bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var best *scheduleLane
+	var bestDelay time.Duration
+	for _, l := range s.lanes {
+		if len(l.queue) == 0 {
+			continue
+		}
+		delay := l.delay()
+		switch {
+		case best == nil, delay < bestDelay:
+		case delay == bestDelay && l.running < best.running:
+		case delay == bestDelay && l.running == best.running && l.queue[0] < best.queue[0]:
+		default:
+			continue
+		}
+		best, bestDelay = l, delay
+	}
+	if best == nil {
+		return 0, ctx, nil, false
+	}
+	i := best.queue[0]
+	best.queue = best.queue[1:]
+	best.running++
+	ctx = validator.WithHostObserver(ctx, func(host string) {
+		s.mu.Lock()
+		best.hosts[host] = true
+		s.mu.Unlock()
+	})
+	done := func() {
+		s.mu.Lock()
+		best.running--
+		s.mu.Unlock()
+	}
+	return i, ctx, done, true
+}
+
+// delay is how long the lane's next lookup would wait on the slowest of
+// its rate limits.
+func (l *scheduleLane) delay() time.Duration {
+	var d time.Duration
+	for host := range l.hosts {
+		d = max(d, validator.HostDelay(host))
+	}
+	return d
+}
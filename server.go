@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/piyushdaiya/crypto-profiler/internal/config"
+	"github.com/piyushdaiya/crypto-profiler/internal/httpapi"
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+	"github.com/piyushdaiya/crypto-profiler/internal/watchlist"
+	"google.golang.org/grpc"
+)
+
+// ---------------------------------------------------------
+// SERVE MODE (REST API over the same strategies and investigator)
+// ---------------------------------------------------------
+
+const (
+	// Addresses accepted per batch request, unless SERVE_MAX_BATCH says otherwise
+	defaultMaxBatch = 100
+	// Largest request body read
+	maxRequestBody = 1 << 20
+)
+
+// profileServer answers profile requests. Every request shares the
+// registered strategies, so provider rate limits hold across requests.
+type profileServer struct {
+	probe    bool
+	testnet  bool
+	chain    validator.ChainStrategy // --chain; a request's "chain" overrides it
+	evm      *validator.EVMStrategy  // Copied for per-request EVM networks
+	workers  int                     // Per batch request
+	timeout  time.Duration           // Per address
+	maxBatch int
+	engine   *serveEngine // --engine: the watchlist endpoints too; nil = profiles only
+	stream   *streamHub   // GET /v1/stream
+
+	// --monitor: the monitored list re-scored in the server, its alerts
+	// streamed; nil = off
+	monitor         *validator.Monitor
+	monitorInterval time.Duration
+
+	// /v1/graphql's schema, built on first use
+	graphqlOnce sync.Once
+	graphql     *graphql.Schema
+	graphqlErr  error
+}
+
+// serveEngine is the Watchlist Engine run inside serve mode (--engine):
+// its store answers the engine's endpoints and the profiles' own
+// sanctions checks, and is synced with OFAC every interval.
+type serveEngine struct {
+	store    *watchlist.Store
+	interval time.Duration
+}
+
+// profileRequest is the body of POST /v1/profile.
+type profileRequest struct {
+	Address string `json:"address"`
+	Chain   string `json:"chain,omitempty"` // As --chain
+	Probe   bool   `json:"probe,omitempty"` // As --probe
+}
+
+// batchRequest is the body of POST /v1/profile/batch.
+type batchRequest struct {
+	Addresses []string `json:"addresses"`
+	Chain     string   `json:"chain,omitempty"`
+	Probe     bool     `json:"probe,omitempty"`
+}
+
+// batchResponse lists the profiles in request order, duplicates included.
+type batchResponse struct {
+	Count    int                        `json:"count"`
+	Profiles []*validator.WalletProfile `json:"profiles"`
+	Summary  batchSummary               `json:"summary"`
+}
+
+// openEngine opens the watchlist database for serve --engine, configured
+// like the engine (WATCHLIST_DB_PATH, SYNC_INTERVAL, OFAC_URL,
+// SYNC_HTTP_TIMEOUT, SYNC_PROXY_URL), and makes the profiles check
+// sanctions against it in-process.
+func openEngine(cfg *config.Config) *serveEngine {
+	path := cfg.Watchlist.DBPath
+	if path == "" {
+		path = "./watchlist.db"
+	}
+	store, err := watchlist.Open(path)
+	if err != nil {
+		logging.Fatal("❌ [ENGINE] DB Error", "path", path, "err", err)
+	}
+	sync := watchlist.SyncConfig{SourceURL: cfg.Engine.OFACURL, HTTPTimeout: cfg.Engine.HTTPTimeout, ProxyURL: cfg.Engine.Proxy}
+	if err := store.ConfigureSync(sync); err != nil {
+		logging.Fatal("❌ [ENGINE] Sync Config Error", "err", err)
+	}
+	interval := cfg.Engine.SyncInterval
+	if interval <= 0 {
+		logging.Fatal("❌ [ENGINE] Sync interval must be positive")
+	}
+	validator.UseWatchlist(store)
+	slog.Info("🔹 [ENGINE] Watchlist opened", "path", path, "source", sync.SourceURL, "interval", interval)
+	return &serveEngine{store: store, interval: interval}
+}
+
+// runServer serves the REST API on SERVE_PORT (default 8081) until
+// SIGINT/SIGTERM, then lets in-flight requests finish. With an engine,
+// the watchlist endpoints and its sync loop run in the same process, as
+// does the monitor with one. With SERVE_GRPC_PORT, ProfileService is
+// served over gRPC on that port too.
+// Every route goes through the shared middleware: request logs, the
+// SERVE_API_KEYS check and the /metrics counters.
+func runServer(s *profileServer, cfg *config.Config) {
+	port := cfg.Serve.Port
+	s.maxBatch = cfg.Serve.MaxBatch
+	if s.maxBatch < 1 {
+		logging.Fatal("Invalid SERVE_MAX_BATCH", "value", s.maxBatch)
+	}
+
+	keys := httpapi.ParseKeys(cfg.Serve.APIKeys)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/profile", s.profileHandler)
+	mux.HandleFunc("/v1/profile/batch", s.batchHandler)
+	mux.HandleFunc("/v1/graphql", s.graphqlHandler)
+	metrics := httpapi.NewMetrics("profiler")
+	mux.Handle("GET /metrics", metrics)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	// Cancelled on SIGINT/SIGTERM (docker stop, Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s.stream = newStreamHub(ctx)
+	mux.HandleFunc("GET /v1/stream", s.stream.handler)
+	jobs := newJobQueue(ctx, s, cfg.Serve.MaxJob, cfg.Serve.JobTTL)
+	mux.HandleFunc("POST /v1/jobs", jobs.createHandler)
+	mux.HandleFunc("GET /v1/jobs/{id}", jobs.getHandler)
+	mux.HandleFunc("DELETE /v1/jobs/{id}", jobs.cancelHandler)
+
+	var wg sync.WaitGroup
+	if s.engine != nil {
+		watchlist.Register(mux, s.engine.store)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.engine.store.SyncLoop(ctx, s.engine.interval)
+		}()
+	}
+	if s.monitor != nil {
+		webhook := s.monitor.Alert
+		s.monitor.Alert = func(a validator.MonitorAlert) {
+			s.stream.publish(eventAlert, "", a)
+			if webhook != nil {
+				webhook(a)
+			}
+		}
+		slog.Info("👀 Monitoring", "interval", s.monitorInterval)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.monitor.Run(ctx, s.monitorInterval)
+		}()
+	}
+
+	// SERVE_GRPC_PORT: ProfileService over gRPC too, on its own port
+	var grpcSrv *grpc.Server
+	if cfg.Serve.GRPCPort != "" {
+		grpcSrv = newGRPCServer(s, keys)
+		if err := serveGRPC(grpcSrv, cfg.Serve.GRPCPort, stop); err != nil {
+			logging.Fatal("❌ [SERVE] gRPC Error", "port", cfg.Serve.GRPCPort, "err", err)
+		}
+	}
+
+	srv := &http.Server{Addr: ":" + port, Handler: httpapi.Handler(mux, keys, metrics), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		slog.Info("✅ [SERVE] Listening", "port", port, "workers", s.workers, "max_batch", s.maxBatch, "engine", s.engine != nil, "auth", len(keys) > 0)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("❌ [SERVE] HTTP Server Error", "err", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	slog.Info("🔹 [SERVE] Shutdown signal received. Draining...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("⚠️ [SERVE] HTTP Shutdown", "err", err)
+	}
+	if grpcSrv != nil {
+		stopGRPC(shutdownCtx, grpcSrv)
+	}
+
+	// The sync loop rolls back an open transaction before the DB closes
+	wg.Wait()
+	if s.engine != nil {
+		validator.UseWatchlist(nil)
+		if err := s.engine.store.Close(); err != nil {
+			slog.Warn("⚠️ [ENGINE] DB Close", "err", err)
+		}
+	}
+	slog.Info("✅ [SERVE] Stopped.")
+}
+
+// POST /v1/profile {"address": "0x...", "chain": "polygon", "probe": false}
+// Profiles one address. An address no chain accepts is still a 200, with
+// is_valid false and the reason in validation_details, as in the CLI.
+func (s *profileServer) profileHandler(w http.ResponseWriter, r *http.Request) {
+	var req profileRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	req.Address = strings.TrimSpace(req.Address)
+	if req.Address == "" {
+		http.Error(w, "Missing address", http.StatusBadRequest)
+		return
+	}
+	chain, err := s.requestChain(req.Chain)
+	if err != nil {
+		http.Error(w, "Invalid chain: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, s.screen(r.Context(), req.Address, req.Probe, chain, ""))
+}
+
+// POST /v1/profile/batch {"addresses": ["0x...", "bc1..."], "chain": "", "probe": false}
+// Profiles up to SERVE_MAX_BATCH addresses with the worker pool and
+// returns them in request order, with the batch summary.
+func (s *profileServer) batchHandler(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	var addresses []string
+	for _, a := range req.Addresses {
+		if a = strings.TrimSpace(a); a != "" {
+			addresses = append(addresses, a)
+		}
+	}
+	if len(addresses) == 0 {
+		http.Error(w, "Missing addresses", http.StatusBadRequest)
+		return
+	}
+	if len(addresses) > s.maxBatch {
+		http.Error(w, fmt.Sprintf("Too many addresses: %d (max %d)", len(addresses), s.maxBatch), http.StatusRequestEntityTooLarge)
+		return
+	}
+	chain, err := s.requestChain(req.Chain)
+	if err != nil {
+		http.Error(w, "Invalid chain: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	resp := batchResponse{Summary: batchSummary{Grades: map[string]int{}, Workers: min(s.workers, len(addresses))}}
+	screen := func(ctx context.Context, address string) *validator.WalletProfile {
+		return s.screen(ctx, address, req.Probe, chain, "")
+	}
+	defer primeWatchlist(r.Context(), addresses, chain)()
+	screenPool(r.Context(), addresses, s.workers, chain, screen, func(p *validator.WalletProfile) {
+		resp.Profiles = append(resp.Profiles, p)
+		resp.Summary.add(p)
+	})
+	resp.Count = len(resp.Profiles)
+	resp.Summary.Duration = time.Since(start).Round(time.Millisecond).String()
+	writeJSON(w, resp)
+}
+
+// screen profiles one address for a request and publishes it on the
+// event stream; job is the job it belongs to, if any.
+func (s *profileServer) screen(ctx context.Context, address string, probe bool, chain validator.ChainStrategy, job string) *validator.WalletProfile {
+	p := screenAddress(ctx, address, s.probe || probe, s.testnet, chain, s.timeout, slog.LevelDebug)
+	if ctx.Err() == nil {
+		s.stream.publish(eventProfile, job, streamProfile{Job: job, Profile: p})
+	}
+	return p
+}
+
+// requestChain resolves a request's "chain", falling back to --chain.
+func (s *profileServer) requestChain(name string) (validator.ChainStrategy, error) {
+	if strings.TrimSpace(name) == "" {
+		return s.chain, nil
+	}
+	return forceChain(name, s.evm, s.testnet)
+}
+
+// decodeRequest reads a POSTed JSON body into v, answering the error
+// itself when it can't.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON body: %v", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		slog.Warn("⚠️ [SERVE] Write failed", "err", err)
+	}
+}
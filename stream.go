@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// EVENT STREAM (results pushed to dashboards, serve mode)
+// ---------------------------------------------------------
+
+const (
+	// Events kept for clients resuming with Last-Event-ID
+	streamBacklog = 1024
+	// Events queued per client; a client further behind is disconnected
+	streamBuffer = 256
+	// Comment sent to idle clients, so proxies keep the connection open
+	streamHeartbeat = 15 * time.Second
+)
+
+// Event types
+const (
+	eventProfile = "profile" // A profile the server screened
+	eventJob     = "job"     // A screening job changed status
+	eventAlert   = "alert"   // A monitoring alert (serve --monitor)
+)
+
+// streamEvent is one Server-Sent Event, encoded once for every client.
+type streamEvent struct {
+	id   uint64
+	kind string
+	job  string // The job it belongs to, for ?job=
+	data []byte
+}
+
+// streamHub fans the server's events out to the clients of GET
+// /v1/stream. It keeps the last streamBacklog events, so a client that
+// reconnects (EventSource does, with Last-Event-ID) misses nothing in
+// between.
+type streamHub struct {
+	done <-chan struct{} // Closed when the server stops
+
+	mu      sync.Mutex
+	seq     uint64
+	backlog []streamEvent
+	clients map[*streamClient]struct{}
+}
+
+type streamClient struct {
+	events chan streamEvent // Closed when the client fell behind
+	kinds  map[string]bool  // Nil: every type
+	job    string
+}
+
+// streamProfile is the data of a profile event.
+type streamProfile struct {
+	Job     string                   `json:"job,omitempty"` // Set for a job's profiles
+	Profile *validator.WalletProfile `json:"profile"`
+}
+
+// newStreamHub returns a hub whose clients are let go once ctx is done.
+func newStreamHub(ctx context.Context) *streamHub {
+	return &streamHub{done: ctx.Done(), clients: map[*streamClient]struct{}{}}
+}
+
+func (c *streamClient) wants(e streamEvent) bool {
+	return (c.kinds == nil || c.kinds[e.kind]) && (c.job == "" || c.job == e.job)
+}
+
+// publish sends v, as JSON, to every client that wants it.
+func (h *streamHub) publish(kind, job string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("⚠️ [STREAM] Encoding failed", "event", kind, "err", err)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	e := streamEvent{id: h.seq, kind: kind, job: job, data: data}
+	if len(h.backlog) == streamBacklog {
+		h.backlog = append(h.backlog[:0], h.backlog[1:]...)
+	}
+	h.backlog = append(h.backlog, e)
+	for c := range h.clients {
+		if !c.wants(e) {
+			continue
+		}
+		select {
+		case c.events <- e:
+		default:
+			// Too slow: let it reconnect and catch up from the backlog
+			close(c.events)
+			delete(h.clients, c)
+		}
+	}
+}
+
+// subscribe registers c and returns the backlog events after lastID that
+// it wants, to be sent before anything from c.events.
+func (h *streamHub) subscribe(c *streamClient, lastID uint64) []streamEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var missed []streamEvent
+	for _, e := range h.backlog {
+		if e.id > lastID && c.wants(e) {
+			missed = append(missed, e)
+		}
+	}
+	h.clients[c] = struct{}{}
+	return missed
+}
+
+func (h *streamHub) unsubscribe(c *streamClient) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+}
+
+// GET /v1/stream[?events=profile,job,alert][&job=<id>]
+// A Server-Sent Events stream of every profile the server screens (REST,
+// GraphQL and jobs), job status changes and, with --monitor, monitoring
+// alerts, as they happen. events picks the types, job one job's events.
+func (h *streamHub) handler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	c := &streamClient{events: make(chan streamEvent, streamBuffer), job: r.URL.Query().Get("job")}
+	if v := r.URL.Query().Get("events"); v != "" {
+		c.kinds = map[string]bool{}
+		for _, kind := range strings.Split(v, ",") {
+			switch kind = strings.TrimSpace(kind); kind {
+			case eventProfile, eventJob, eventAlert:
+				c.kinds[kind] = true
+			default:
+				http.Error(w, fmt.Sprintf("Invalid event type %q (profile, job, alert)", kind), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	var lastID uint64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastID, _ = strconv.ParseUint(v, 10, 64)
+	}
+
+	missed := h.subscribe(c, lastID)
+	defer h.unsubscribe(c)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx would hold the events back
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	flusher.Flush()
+	slog.Debug("📺 [STREAM] Client connected", "events", r.URL.Query().Get("events"), "job", c.job, "resumed", len(missed))
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e, ok := <-c.events:
+			if !ok {
+				slog.Warn("⚠️ [STREAM] Client too slow, disconnected", "remote", r.RemoteAddr)
+				return
+			}
+			writeEvent(w, e)
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes e in the text/event-stream format; the JSON is one
+// line, so one data field holds it.
+func writeEvent(w http.ResponseWriter, e streamEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.id, e.kind, e.data)
+}
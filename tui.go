@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// INTERACTIVE SESSION (tui: screen, drill down, pivot)
+// ---------------------------------------------------------
+
+const tuiHelp = `Commands:
+  <address>   Screen an address (or a name: bonfida.sol, brad.crypto)
+  r           Risk reasons of the current profile; r N for one in detail
+  c           Counterparties; p N screens counterparty N
+  h           Session history; o N reopens entry N
+  b           Back to the profile viewed before
+  v           View the current profile again
+  j           The current profile as JSON
+  w <file>    Save the session history (JSON Lines)
+  q           Quit
+A bare number picks from the last list shown.`
+
+// tuiSession is one analyst's run of screenings. Every profile screened is
+// kept in history, in order; current is the one on screen, and trail the
+// ones viewed before it (b goes back along it).
+type tuiSession struct {
+	out     io.Writer
+	color   bool
+	screen  func(ctx context.Context, address string) *validator.WalletProfile
+	history []*validator.WalletProfile
+	current int
+	trail   []int
+	pick    func(n int) // What a bare number does: the last list shown
+}
+
+// runTUI reads commands from in until q or end of input. Ctrl+C cancels
+// a screening in progress and returns to the prompt.
+func runTUI(in io.Reader, out io.Writer, color bool, screen func(ctx context.Context, address string) *validator.WalletProfile) int {
+	s := &tuiSession{out: out, color: color, screen: screen, current: -1}
+	fmt.Fprintln(out, "🔎 Crypto Profiler: enter an address to screen, ? for help")
+	lines := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, s.prompt())
+		if !lines.Scan() {
+			fmt.Fprintln(out)
+			return exitClean
+		}
+		if !s.do(strings.TrimSpace(lines.Text())) {
+			return exitClean
+		}
+	}
+}
+
+func (s *tuiSession) prompt() string {
+	if p := s.profile(); p != nil {
+		return fmt.Sprintf("[%d/%d %s] > ", s.current+1, len(s.history), shortAddress(p.Address))
+	}
+	return "> "
+}
+
+// do runs one command line; false quits.
+func (s *tuiSession) do(line string) bool {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	if n, err := strconv.Atoi(cmd); err == nil && arg == "" {
+		if s.pick == nil {
+			fmt.Fprintln(s.out, "Nothing to pick from; list reasons (r), counterparties (c) or history (h) first")
+			return true
+		}
+		s.pick(n)
+		return true
+	}
+
+	switch cmd {
+	case "":
+	case "q", "quit", "exit":
+		return false
+	case "?", "help":
+		fmt.Fprintln(s.out, tuiHelp)
+	case "r":
+		if arg == "" {
+			s.reasons()
+		} else if n, ok := s.number(arg); ok {
+			s.reason(n)
+		}
+	case "c":
+		s.counterparties()
+	case "p":
+		if n, ok := s.number(arg); ok {
+			s.pivot(n)
+		}
+	case "h":
+		s.historyList()
+	case "o":
+		if n, ok := s.number(arg); ok {
+			s.open(n)
+		}
+	case "b":
+		if len(s.trail) == 0 {
+			fmt.Fprintln(s.out, "No previous profile")
+			return true
+		}
+		s.current, s.trail = s.trail[len(s.trail)-1], s.trail[:len(s.trail)-1]
+		s.view()
+	case "v":
+		s.view()
+	case "j":
+		s.json()
+	case "w":
+		s.save(arg)
+	default:
+		if arg != "" {
+			fmt.Fprintf(s.out, "Unknown command %q (? for help)\n", cmd)
+			return true
+		}
+		s.screenAddress(cmd)
+	}
+	return true
+}
+
+// number parses a 1-based list position.
+func (s *tuiSession) number(arg string) (int, bool) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		fmt.Fprintf(s.out, "Expected a number, got %q\n", arg)
+		return 0, false
+	}
+	return n, true
+}
+
+// profile is the profile on screen, nil before the first screening.
+func (s *tuiSession) profile() *validator.WalletProfile {
+	if s.current < 0 {
+		return nil
+	}
+	return s.history[s.current]
+}
+
+// screenAddress profiles address, adds it to the history and shows it.
+func (s *tuiSession) screenAddress(address string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(s.out, "⏳ Screening %s...\n", address)
+	p := s.screen(ctx, address)
+	if ctx.Err() != nil {
+		fmt.Fprintln(s.out, "\nCancelled")
+		return
+	}
+	s.history = append(s.history, p)
+	s.goTo(len(s.history) - 1)
+}
+
+// view shows the current profile as the table summary, then what can be
+// drilled into.
+func (s *tuiSession) view() {
+	p := s.profile()
+	if p == nil {
+		fmt.Fprintln(s.out, "Nothing screened yet; enter an address")
+		return
+	}
+	w, _ := newProfileWriter("table", s.out, false, s.color)
+	w.Write(p)
+	w.Close()
+	fmt.Fprintf(s.out, "\n[r] %d reasons  [c] %d counterparties  [h] history  [j] json  [?] help\n",
+		len(p.RiskReasons), len(validator.Counterparties(p)))
+	s.pick = nil
+}
+
+func (s *tuiSession) reasons() {
+	p := s.profile()
+	if p == nil || len(p.RiskReasons) == 0 {
+		fmt.Fprintln(s.out, "No risk reasons")
+		return
+	}
+	for i, r := range sortedReasons(p) {
+		fmt.Fprintf(s.out, "%3d. %s\n", i+1, s.paint(fmt.Sprintf("%+g %s: %s", r.Offset, r.RuleID, r.Description), reasonColor(r.Offset)))
+	}
+	s.pick = s.reason
+}
+
+// reason shows reason n (highest offset first) with its evidence.
+func (s *tuiSession) reason(n int) {
+	p := s.profile()
+	if p == nil {
+		return
+	}
+	reasons := sortedReasons(p)
+	if n < 1 || n > len(reasons) {
+		fmt.Fprintf(s.out, "No reason %d (1-%d)\n", n, len(reasons))
+		return
+	}
+	r := reasons[n-1]
+	fmt.Fprintf(s.out, "%s\n  Rule      %s\n  Category  %s\n  Severity  %s\n  Offset    %+g\n",
+		s.paint(r.Description, reasonColor(r.Offset)), r.RuleID, r.Category, r.Severity, r.Offset)
+	if r.Evidence == nil {
+		return
+	}
+	for _, a := range r.Evidence.Addresses {
+		fmt.Fprintf(s.out, "  Address   %s\n", a)
+	}
+	for _, h := range r.Evidence.TxHashes {
+		fmt.Fprintf(s.out, "  Tx        %s\n", h)
+	}
+	for _, t := range r.Evidence.Timestamps {
+		fmt.Fprintf(s.out, "  At        %s\n", csvTime(&t))
+	}
+}
+
+func (s *tuiSession) counterparties() {
+	p := s.profile()
+	if p == nil {
+		return
+	}
+	cps := validator.Counterparties(p)
+	if len(cps) == 0 {
+		fmt.Fprintln(s.out, "No known counterparties")
+		return
+	}
+	for i, c := range cps {
+		fmt.Fprintf(s.out, "%3d. %s  %s\n", i+1, c.Address, c.Why)
+	}
+	fmt.Fprintln(s.out, "Pick a number to screen that counterparty")
+	s.pick = s.pivot
+}
+
+// pivot screens counterparty n of the current profile.
+func (s *tuiSession) pivot(n int) {
+	p := s.profile()
+	if p == nil {
+		return
+	}
+	cps := validator.Counterparties(p)
+	if n < 1 || n > len(cps) {
+		fmt.Fprintf(s.out, "No counterparty %d (1-%d)\n", n, len(cps))
+		return
+	}
+	s.screenAddress(cps[n-1].Address)
+}
+
+func (s *tuiSession) historyList() {
+	if len(s.history) == 0 {
+		fmt.Fprintln(s.out, "Nothing screened yet")
+		return
+	}
+	for i, p := range s.history {
+		marker := " "
+		if i == s.current {
+			marker = "*"
+		}
+		grade := p.RiskGrade
+		if !p.IsValid {
+			grade = "INVALID"
+		}
+		fmt.Fprintf(s.out, "%s%2d. %s  %s  %.1f  %s\n", marker, i+1, p.Address, p.Network, p.RiskScore, s.paint(grade, gradeColor(p)))
+	}
+	s.pick = s.open
+}
+
+// open makes history entry n current.
+func (s *tuiSession) open(n int) {
+	if n < 1 || n > len(s.history) {
+		fmt.Fprintf(s.out, "No history entry %d (1-%d)\n", n, len(s.history))
+		return
+	}
+	s.goTo(n - 1)
+}
+
+// goTo shows history entry i, remembering the one it leaves.
+func (s *tuiSession) goTo(i int) {
+	if s.current >= 0 && s.current != i {
+		s.trail = append(s.trail, s.current)
+	}
+	s.current = i
+	s.view()
+}
+
+func (s *tuiSession) json() {
+	p := s.profile()
+	if p == nil {
+		return
+	}
+	data, err := marshalIndent(p, false)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Fprintf(s.out, "%s\n", data)
+}
+
+// save writes every profile of the session to path, one JSON line each.
+func (s *tuiSession) save(path string) {
+	if path == "" {
+		fmt.Fprintln(s.out, "Usage: w <file>")
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(s.out, "⚠️ Save failed: %v\n", err)
+		return
+	}
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	for _, p := range s.history {
+		if err = enc.Encode(p); err != nil {
+			break
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintf(s.out, "⚠️ Save failed: %v\n", err)
+		return
+	}
+	fmt.Fprintf(s.out, "💾 Saved %d profiles to %s\n", len(s.history), path)
+}
+
+// paint colors text for the terminal, if colors are on.
+func (s *tuiSession) paint(text, color string) string {
+	if !s.color || color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// shortAddress abbreviates a long address for the prompt: 0x742d...f44e.
+func shortAddress(address string) string {
+	if len(address) <= 14 {
+		return address
+	}
+	return address[:6] + "..." + address[len(address)-4:]
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// WATCH MODE (one address, re-profiled until something critical)
+// ---------------------------------------------------------
+
+// How often watch mode re-profiles, unless WATCH_INTERVAL says otherwise
+const defaultWatchInterval = 5 * time.Minute
+
+// watchDelta is what changed between two checks of the watched address.
+type watchDelta struct {
+	Address        string            `json:"address"`
+	At             time.Time         `json:"at"`
+	Changes        []string          `json:"changes"` // Readable, one per change
+	PreviousScore  float64           `json:"previous_score"`
+	Score          float64           `json:"score"`
+	PreviousGrade  string            `json:"previous_grade"`
+	Grade          string            `json:"grade"`
+	NewTxs         int               `json:"new_txs,omitempty"`
+	PreviousStatus string            `json:"previous_status"`
+	Status         string            `json:"status"` // Watchlist: CLEAR or SANCTIONED
+	NewAlerts      []validator.Alert `json:"new_alerts,omitempty"`
+	Critical       bool              `json:"critical,omitempty"`
+}
+
+// runWatch profiles the address every interval and prints only what
+// changed: readable lines when human is set, else one JSON object per
+// check with changes. It returns the exit status: exitCritical as soon as
+// a CRITICAL alert appears (including on the first check), 0 when
+// interrupted.
+func runWatch(address string, interval time.Duration, screen func(context.Context) *validator.WalletProfile, human bool) int {
+	// Cancelled on SIGINT/SIGTERM (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	prev := screen(ctx)
+	if !prev.IsValid {
+		logging.Fatal("Invalid address", "details", prev.ValidationDetails)
+	}
+	status := validator.WatchlistStatus(prev)
+	slog.Info("👀 Watching", "address", address, "network", prev.Network, "interval", interval, "score", prev.RiskScore, "grade", prev.RiskGrade, "txs", prev.TxCount, "watchlist", status)
+	if critical := criticalAlerts(prev.Alerts); len(critical) > 0 {
+		printDelta(watchDelta{
+			Address: address, At: time.Now().UTC(), Changes: alertChanges(critical),
+			PreviousScore: prev.RiskScore, Score: prev.RiskScore, PreviousGrade: prev.RiskGrade, Grade: prev.RiskGrade,
+			PreviousStatus: status, Status: status, NewAlerts: critical, Critical: true,
+		}, human)
+		return exitCritical
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+		cur := screen(ctx)
+		if ctx.Err() != nil {
+			return 0
+		}
+		if !cur.IsValid {
+			slog.Warn("⚠️ [WATCH] Check failed", "details", cur.ValidationDetails)
+			continue
+		}
+		delta := diffProfiles(prev, cur, status)
+		if len(delta.Changes) > 0 {
+			printDelta(delta, human)
+		}
+		if delta.Critical {
+			return exitCritical
+		}
+		prev, status = cur, delta.Status
+	}
+}
+
+// diffProfiles compares two checks. status is the last known watchlist
+// status; an unavailable watchlist keeps it rather than reporting a change.
+func diffProfiles(prev, cur *validator.WalletProfile, status string) watchDelta {
+	d := watchDelta{
+		Address:        cur.Address,
+		At:             time.Now().UTC(),
+		PreviousScore:  prev.RiskScore,
+		Score:          cur.RiskScore,
+		PreviousGrade:  prev.RiskGrade,
+		Grade:          cur.RiskGrade,
+		PreviousStatus: status,
+		Status:         status,
+	}
+	if s := validator.WatchlistStatus(cur); s != validator.StatusUnknown {
+		d.Status = s
+	}
+
+	if d.Status != d.PreviousStatus {
+		d.Changes = append(d.Changes, fmt.Sprintf("Watchlist %s → %s", d.PreviousStatus, d.Status))
+	}
+	if fmt.Sprintf("%.1f", prev.RiskScore) != fmt.Sprintf("%.1f", cur.RiskScore) {
+		d.Changes = append(d.Changes, fmt.Sprintf("Score %.1f → %.1f", prev.RiskScore, cur.RiskScore))
+	}
+	if prev.RiskGrade != cur.RiskGrade {
+		d.Changes = append(d.Changes, fmt.Sprintf("Grade %s → %s", prev.RiskGrade, cur.RiskGrade))
+	}
+	if n := cur.TxCount - prev.TxCount; n > 0 {
+		d.NewTxs = n
+		d.Changes = append(d.Changes, fmt.Sprintf("%d New Txs (%d Total)", n, cur.TxCount))
+	}
+	if prev.Balance != cur.Balance && cur.Balance != "" {
+		d.Changes = append(d.Changes, fmt.Sprintf("Balance %s → %s", prev.Balance, cur.Balance))
+	}
+
+	seen := map[string]bool{}
+	for _, a := range prev.Alerts {
+		seen[a.Trigger] = true
+	}
+	for _, a := range cur.Alerts {
+		if !seen[a.Trigger] {
+			d.NewAlerts = append(d.NewAlerts, a)
+		}
+	}
+	d.Changes = append(d.Changes, alertChanges(d.NewAlerts)...)
+	d.Critical = len(criticalAlerts(d.NewAlerts)) > 0 ||
+		(d.Status == validator.StatusSanctioned && d.PreviousStatus != validator.StatusSanctioned)
+	return d
+}
+
+// criticalAlerts returns the CRITICAL alerts (sanctions hits).
+func criticalAlerts(alerts []validator.Alert) []validator.Alert {
+	var out []validator.Alert
+	for _, a := range alerts {
+		if a.Severity == "CRITICAL" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func alertChanges(alerts []validator.Alert) []string {
+	var out []string
+	for _, a := range alerts {
+		out = append(out, fmt.Sprintf("New Alert %s %s: %s", a.Severity, a.Trigger, a.Message))
+	}
+	return out
+}
+
+// printDelta writes one check's changes to stdout.
+func printDelta(d watchDelta, human bool) {
+	if !human {
+		data, err := json.Marshal(d)
+		if err != nil {
+			slog.Error("Error encoding JSON", "err", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+	icon := "🔄"
+	if d.Critical {
+		icon = "🚨"
+	}
+	fmt.Printf("%s %s %s\n", d.At.Local().Format("15:04:05"), icon, strings.Join(d.Changes, " | "))
+}
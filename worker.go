@@ -0,0 +1,342 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/piyushdaiya/crypto-profiler/internal/config"
+	"github.com/piyushdaiya/crypto-profiler/internal/logging"
+	"github.com/piyushdaiya/crypto-profiler/internal/validator"
+)
+
+// ---------------------------------------------------------
+// QUEUE WORKER (screening requests from a NATS JetStream stream)
+// ---------------------------------------------------------
+
+const (
+	defaultQueueStream     = "SCREENING"
+	defaultQueueConsumer   = "profiler"
+	defaultProfilesSubject = "screening.profiles"
+	defaultAlertsSubject   = "screening.alerts"
+	defaultDeadSubject     = "screening.dead"
+	// Deliveries of a failing screening before it is dead-lettered
+	defaultQueueMaxDeliver = 5
+	// Wait before the first retry; the nth waits n times as long
+	defaultQueueRetryDelay = 30 * time.Second
+	// How long one pull waits for messages
+	queueFetchWait = 30 * time.Second
+)
+
+// queueWorker screens the addresses published to a JetStream stream: a
+// durable pull consumer hands them to the batch worker pool, and each is
+// acked only once its profile and alerts are published, so a crash
+// redelivers rather than loses them (at-least-once). Screenings with
+// provider errors are retried with a growing delay, then dead-lettered.
+type queueWorker struct {
+	url        string
+	stream     string
+	consumer   string
+	filter     string // Subject filter within the stream; empty = all
+	profiles   string // Output subjects
+	alerts     string
+	dead       string
+	maxDeliver int
+	retryDelay time.Duration
+
+	probe   bool
+	testnet bool
+	chain   validator.ChainStrategy // --chain; a message's "chain" overrides it
+	evm     *validator.EVMStrategy
+	workers int
+	timeout time.Duration // Per address
+
+	stored map[string]bool // Output subjects a stream stores, per connection
+}
+
+// queueAlert is one alert of a profile, published on its own so alerts
+// can be routed without reading whole profiles.
+type queueAlert struct {
+	Address string `json:"address"`
+	Network string `json:"network"`
+	validator.Alert
+}
+
+// deadLetter is a message that will not be screened: malformed, or still
+// failing after the last delivery.
+type deadLetter struct {
+	Message    string                   `json:"message"` // As received
+	Sequence   uint64                   `json:"stream_sequence"`
+	Deliveries int                      `json:"deliveries"`
+	Error      string                   `json:"error"`
+	Profile    *validator.WalletProfile `json:"profile,omitempty"` // The last, incomplete attempt
+	At         time.Time                `json:"at"`
+}
+
+// newQueueWorker takes QUEUE_URL (required) and the other QUEUE_* settings.
+func newQueueWorker(cfg *config.Config) *queueWorker {
+	q := cfg.Queue
+	w := &queueWorker{
+		url:        q.URL,
+		stream:     q.Stream,
+		consumer:   q.Consumer,
+		filter:     q.Subject,
+		profiles:   q.ProfilesSubject,
+		alerts:     q.AlertsSubject,
+		dead:       q.DeadSubject,
+		maxDeliver: q.MaxDeliver,
+		retryDelay: q.RetryDelay,
+	}
+	if w.url == "" {
+		logging.Fatal("worker needs QUEUE_URL (nats://host:4222)")
+	}
+	if w.maxDeliver < 1 {
+		logging.Fatal("Invalid QUEUE_MAX_DELIVER", "value", w.maxDeliver)
+	}
+	if w.retryDelay <= 0 {
+		logging.Fatal("Invalid QUEUE_RETRY_DELAY", "value", w.retryDelay)
+	}
+	return w
+}
+
+// runWorker consumes until SIGINT/SIGTERM, redialing with a growing
+// backoff whenever the connection fails. On a signal it stops pulling
+// and lets the screenings in flight finish and be acked.
+func runWorker(w *queueWorker) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backoff := time.Second
+	for {
+		connected, err := w.consume(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if connected {
+			backoff = time.Second
+		}
+		slog.Warn("⚠️ [QUEUE] Disconnected, retrying", "err", err, "in", backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+	slog.Info("🛑 [QUEUE] Worker stopped")
+}
+
+// consume connects, makes sure of the consumer and screens messages
+// until ctx is done or the connection fails. connected reports whether
+// it got as far as consuming.
+func (w *queueWorker) consume(ctx context.Context) (connected bool, err error) {
+	// runWorker redials, so a lost connection closes for good
+	closed := make(chan struct{})
+	nc, err := nats.Connect(w.url, nats.Name("crypto-profiler"), nats.Timeout(10*time.Second), nats.NoReconnect(),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }))
+	if err != nil {
+		return false, err
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return false, err
+	}
+	cons, err := js.CreateOrUpdateConsumer(ctx, w.stream, jetstream.ConsumerConfig{
+		Durable:       w.consumer,
+		FilterSubject: w.filter,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       w.timeout + time.Minute, // Screening, then publishing
+		MaxDeliver:    w.maxDeliver,
+	})
+	if err != nil {
+		return false, err
+	}
+	w.stored = map[string]bool{}
+	for _, subject := range []string{w.profiles, w.alerts, w.dead} {
+		_, err := js.StreamNameBySubject(ctx, subject)
+		if err != nil && !errors.Is(err, jetstream.ErrStreamNotFound) {
+			return false, err
+		}
+		w.stored[subject] = err == nil
+		if err != nil {
+			slog.Warn("⚠️ [QUEUE] No stream stores the subject: only live subscribers get its messages", "subject", subject)
+		}
+	}
+	slog.Info("✅ [QUEUE] Consuming", "stream", w.stream, "consumer", w.consumer, "workers", w.workers, "profiles", w.profiles, "alerts", w.alerts, "dead", w.dead)
+
+	// Screenings in flight finish after a signal, so they can be acked
+	work := context.WithoutCancel(ctx)
+	slots := make(chan struct{}, w.workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return true, nil
+		case <-closed:
+			return true, cmp.Or(nc.LastError(), nats.ErrConnectionClosed)
+		}
+		n := 1
+	fill:
+		for n < w.workers {
+			select {
+			case slots <- struct{}{}:
+				n++
+			default:
+				break fill
+			}
+		}
+
+		fctx, cancel := context.WithTimeout(ctx, queueFetchWait)
+		batch, err := cons.Fetch(n, jetstream.FetchContext(fctx))
+		got := 0
+		if err == nil {
+			for m := range batch.Messages() {
+				got++
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-slots }()
+					w.handle(work, js, m)
+				}()
+			}
+			err = batch.Error()
+		}
+		cancel()
+		for range n - got {
+			<-slots
+		}
+		// A pull that ends without messages isn't an error
+		if err != nil && ctx.Err() == nil && !errors.Is(err, context.DeadlineExceeded) {
+			return true, err
+		}
+	}
+}
+
+// handle screens one message and acks, retries or dead-letters it.
+func (w *queueWorker) handle(ctx context.Context, js jetstream.JetStream, m jetstream.Msg) {
+	meta, err := m.Metadata()
+	if err != nil {
+		slog.Warn("⚠️ [QUEUE] Unexpected message", "err", err)
+		return
+	}
+	seq, deliveries := meta.Sequence.Stream, int(meta.NumDelivered)
+	req, err := parseQueueMessage(m.Data())
+	if err != nil {
+		w.deadLetter(ctx, js, m, seq, deliveries, err.Error(), nil)
+		return
+	}
+	chain := w.chain
+	if strings.TrimSpace(req.Chain) != "" {
+		if chain, err = forceChain(req.Chain, w.evm, w.testnet); err != nil {
+			w.deadLetter(ctx, js, m, seq, deliveries, "Invalid chain: "+err.Error(), nil)
+			return
+		}
+	}
+
+	p := screenAddress(ctx, req.Address, w.probe || req.Probe, w.testnet, chain, w.timeout, slog.LevelDebug)
+	if !finished(p) {
+		errs := strings.Join(validator.ProviderErrors(p), " | ")
+		if deliveries >= w.maxDeliver {
+			w.deadLetter(ctx, js, m, seq, deliveries, fmt.Sprintf("Provider errors after %d deliveries: %s", deliveries, errs), p)
+			return
+		}
+		delay := w.retryDelay * time.Duration(deliveries)
+		slog.Warn("🔁 [QUEUE] Screening incomplete, retrying", "address", req.Address, "delivery", deliveries, "in", delay, "errors", errs)
+		if err := m.NakWithDelay(delay); err != nil {
+			slog.Warn("⚠️ [QUEUE] Nak failed (redelivered after the ack wait)", "err", err)
+		}
+		return
+	}
+
+	id := fmt.Sprintf("%s-%d", w.stream, seq)
+	err = w.publish(ctx, js, w.profiles, id+"-profile", p)
+	for i, a := range p.Alerts {
+		if err == nil {
+			err = w.publish(ctx, js, w.alerts, fmt.Sprintf("%s-alert-%d", id, i), queueAlert{Address: p.Address, Network: p.Network, Alert: a})
+		}
+	}
+	if err != nil {
+		slog.Warn("⚠️ [QUEUE] Publish failed, retrying", "address", req.Address, "err", err)
+		m.NakWithDelay(w.retryDelay)
+		return
+	}
+	if err := m.Ack(); err != nil {
+		slog.Warn("⚠️ [QUEUE] Ack failed (redelivered after the ack wait)", "address", req.Address, "err", err)
+		return
+	}
+	slog.Info("📤 [QUEUE] Screened", "address", req.Address, "network", p.Network, "score", p.RiskScore, "grade", p.RiskGrade, "alerts", len(p.Alerts))
+}
+
+// deadLetter publishes a message that won't be screened to the dead
+// subject and stops its redelivery; if that publish fails, it is retried
+// like a failed screening.
+func (w *queueWorker) deadLetter(ctx context.Context, js jetstream.JetStream, m jetstream.Msg, seq uint64, deliveries int, reason string, p *validator.WalletProfile) {
+	d := deadLetter{Message: string(m.Data()), Sequence: seq, Deliveries: deliveries, Error: reason, Profile: p, At: time.Now().UTC()}
+	if err := w.publish(ctx, js, w.dead, fmt.Sprintf("%s-%d-dead", w.stream, seq), d); err != nil {
+		slog.Warn("⚠️ [QUEUE] Dead-letter publish failed, retrying", "err", err)
+		m.NakWithDelay(w.retryDelay)
+		return
+	}
+	m.Term()
+	slog.Warn("☠️ [QUEUE] Dead-lettered", "sequence", seq, "deliveries", deliveries, "reason", reason)
+}
+
+// publish sends v as JSON to subject and waits until the stream storing
+// it has (id lets the stream drop the copy a redelivery publishes), or,
+// when no stream does, until the server has passed it on.
+func (w *queueWorker) publish(ctx context.Context, js jetstream.JetStream, subject, id string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(jetstream.MsgIDHeader, id)
+	msg.Header.Set("Content-Type", "application/json")
+	if w.stored[subject] {
+		_, err := js.PublishMsg(ctx, msg)
+		return err
+	}
+	if err := js.Conn().PublishMsg(msg); err != nil {
+		return err
+	}
+	return js.Conn().FlushWithContext(ctx)
+}
+
+// parseQueueMessage reads a message: an address or name as plain text, or
+// a JSON object like POST /v1/profile's.
+func parseQueueMessage(data []byte) (profileRequest, error) {
+	var req profileRequest
+	s := strings.TrimSpace(string(data))
+	if strings.HasPrefix(s, "{") {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return req, fmt.Errorf("Invalid JSON message: %v", err)
+		}
+	} else {
+		req.Address = s
+	}
+	if req.Address = strings.TrimSpace(req.Address); req.Address == "" {
+		return req, errors.New("Missing address")
+	}
+	return req, nil
+}